@@ -0,0 +1,112 @@
+// Command migrate applies, rolls back and inspects the database migrations, reading the
+// database settings from the environment (and .env) like the server does.
+//
+//	go run ./cmd/migrate [-path migrations] up|down|version
+//	go run ./cmd/migrate [-path migrations] -confirm force <version>
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+
+	"online-order-management-system/config"
+	"online-order-management-system/internal/infra/db"
+
+	"github.com/joho/godotenv"
+)
+
+const usage = `Usage: migrate [-path dir] [-confirm] <command>
+
+Commands:
+  up             apply all pending migrations
+  down           roll back the latest migration
+  version        print the current migration version
+  force <v>      record version v and clear the dirty flag without running any
+                 migration (-1 for none); requires -confirm
+
+Flags:
+`
+
+func main() {
+	_ = godotenv.Load() // Optional, as for the server
+
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	path := flags.String("path", config.GetEnvString("MIGRATIONS_PATH", "migrations"), "migrations directory")
+	confirm := flags.Bool("confirm", false, "confirm a force, after repairing the schema by hand")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	_ = flags.Parse(os.Args[1:])
+
+	command, args := flags.Arg(0), flags.Args()
+	if flags.NArg() == 0 || (command == "force") != (len(args) == 2) {
+		flags.Usage()
+		os.Exit(2)
+	}
+	forceVersion := 0
+	if command == "force" {
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "migrate: invalid version %q\n", args[1])
+			os.Exit(2)
+		}
+		if !*confirm {
+			fmt.Fprintln(os.Stderr, "migrate: force rewrites the recorded schema version; repair the schema first, then rerun with -confirm")
+			os.Exit(2)
+		}
+		forceVersion = version
+	}
+	if err := run(command, *path, forceVersion); err != nil {
+		fmt.Fprintln(os.Stderr, "migrate:", err)
+		os.Exit(1)
+	}
+}
+
+func run(command, path string, forceVersion int) error {
+	switch command {
+	case "up", "down", "version", "force":
+	default:
+		return fmt.Errorf("unknown command %q (want up, down, version or force)", command)
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return err
+	}
+	dbConfig, err := db.GetDatabaseConfig(cfg.PostgresDSN)
+	if err != nil {
+		return err
+	}
+	database, err := db.NewPostgresDBWithConfig(dbConfig)
+	if err != nil {
+		return err
+	}
+	defer database.Close()
+
+	manager := db.NewMigrationManager(database)
+	switch command {
+	case "up":
+		err = manager.RunMigrations(path)
+	case "down":
+		err = manager.RollbackMigration(path)
+	case "force":
+		err = manager.ForceVersion(path, forceVersion)
+	}
+	if err != nil && !errors.Is(err, db.ErrDirtyMigration) {
+		return err
+	}
+
+	version, dirty, versionErr := manager.GetMigrationVersion(path)
+	if versionErr != nil {
+		return versionErr
+	}
+	if dirty {
+		return db.NewDirtyMigrationError(version)
+	}
+	fmt.Printf("version %d\n", version)
+	return nil
+}
@@ -2,12 +2,183 @@ package config
 
 // Application configuration struct and loader.
 
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"online-order-management-system/pkg/logger"
+)
+
+// ValidSSLModes lists the supported values for POSTGRES_SSLMODE
+var ValidSSLModes = []string{"disable", "require", "verify-ca", "verify-full"}
+
+// Config holds the settings the server needs before it can start. Feature settings are
+// still read where they are used, with GetEnvString and friends.
 type Config struct {
+	// PostgresDSN is DATABASE_URL, or a URL built from the POSTGRES_* variables
 	PostgresDSN string
-	// Add more config fields as needed
+	Port        string
+	LogLevel    logger.LogLevel
+
+	// Server timeouts; 0 disables a timeout. Reads and writes are unbounded by default so
+	// that imports and exports can stream for as long as they need.
+	ReadHeaderTimeout time.Duration
+	ReadTimeout       time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+	// ShutdownTimeout bounds how long in-flight requests may finish on shutdown
+	ShutdownTimeout time.Duration
+	// DBQueryTimeout bounds each repository call (see db.WithQueryTimeout)
+	DBQueryTimeout time.Duration
+}
+
+// Error reports every configuration problem found by LoadConfig at once
+type Error struct {
+	// Missing lists the required variables that are not set
+	Missing []string
+	// Invalid describes the variables whose values cannot be used
+	Invalid []string
+}
+
+func (e *Error) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing required configuration: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		problems = append(problems, "invalid configuration: "+strings.Join(e.Invalid, "; "))
+	}
+	return strings.Join(problems, "; ")
 }
 
+// LoadConfig reads the configuration from the environment. The database is taken from
+// DATABASE_URL when it is set; otherwise POSTGRES_HOST, POSTGRES_USER and POSTGRES_DBNAME
+// are required. All missing and invalid variables are reported together in an *Error.
 func LoadConfig() (*Config, error) {
-	// Load config from env, file, etc.
-	return &Config{}, nil
+	l := &loader{}
+
+	cfg := &Config{
+		PostgresDSN:       l.postgresDSN(),
+		Port:              l.port("PORT", "8080"),
+		LogLevel:          l.logLevel("LOG_LEVEL"),
+		ReadHeaderTimeout: l.duration("SERVER_READ_HEADER_TIMEOUT", 10*time.Second),
+		ReadTimeout:       l.duration("SERVER_READ_TIMEOUT", 0),
+		WriteTimeout:      l.duration("SERVER_WRITE_TIMEOUT", 0),
+		IdleTimeout:       l.duration("SERVER_IDLE_TIMEOUT", 120*time.Second),
+		ShutdownTimeout:   l.duration("SHUTDOWN_TIMEOUT", 10*time.Second),
+		DBQueryTimeout:    l.duration("DB_QUERY_TIMEOUT", 5*time.Second),
+	}
+
+	if len(l.err.Missing) > 0 || len(l.err.Invalid) > 0 {
+		return nil, &l.err
+	}
+	return cfg, nil
+}
+
+// loader reads variables, collecting every problem instead of stopping at the first
+type loader struct {
+	err Error
+}
+
+func (l *loader) required(key string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		l.err.Missing = append(l.err.Missing, key)
+	}
+	return value
+}
+
+func (l *loader) invalid(format string, args ...interface{}) {
+	l.err.Invalid = append(l.err.Invalid, fmt.Sprintf(format, args...))
+}
+
+func (l *loader) duration(key string, defaultValue time.Duration) time.Duration {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		l.invalid("%s %q is not a non-negative duration such as 30s", key, value)
+		return defaultValue
+	}
+	return duration
+}
+
+func (l *loader) port(key, defaultValue string) string {
+	value := strings.TrimSpace(os.Getenv(key))
+	if value == "" {
+		return defaultValue
+	}
+	if port, err := strconv.Atoi(value); err != nil || port < 1 || port > 65535 {
+		l.invalid("%s %q is not a port number", key, value)
+	}
+	return value
+}
+
+func (l *loader) logLevel(key string) logger.LogLevel {
+	level, err := logger.ParseLevel(os.Getenv(key))
+	if err != nil {
+		l.invalid("%s: %v", key, err)
+	}
+	return level
+}
+
+// postgresDSN returns DATABASE_URL, or a URL built from the POSTGRES_* variables
+func (l *loader) postgresDSN() string {
+	if databaseURL := strings.TrimSpace(os.Getenv("DATABASE_URL")); databaseURL != "" {
+		if parsed, err := url.Parse(databaseURL); err != nil || (parsed.Scheme != "postgres" && parsed.Scheme != "postgresql") {
+			l.invalid("DATABASE_URL is not a postgres:// URL")
+		}
+		return databaseURL
+	}
+
+	host := l.required("POSTGRES_HOST")
+	user := l.required("POSTGRES_USER")
+	dbName := l.required("POSTGRES_DBNAME")
+	port := l.port("POSTGRES_PORT", "5432")
+
+	params := url.Values{}
+	sslMode := strings.ToLower(strings.TrimSpace(GetEnvString("POSTGRES_SSLMODE", "disable")))
+	if !contains(ValidSSLModes, sslMode) {
+		l.invalid("POSTGRES_SSLMODE %q must be one of %s", sslMode, strings.Join(ValidSSLModes, ", "))
+	}
+	params.Set("sslmode", sslMode)
+
+	rootCert := GetEnvString("POSTGRES_SSLROOTCERT", "")
+	cert := GetEnvString("POSTGRES_SSLCERT", "")
+	key := GetEnvString("POSTGRES_SSLKEY", "")
+	if (cert == "") != (key == "") {
+		l.invalid("POSTGRES_SSLCERT and POSTGRES_SSLKEY must be set together")
+	}
+	if sslMode == "disable" && (rootCert != "" || cert != "") {
+		l.invalid("SSL certificates are configured but POSTGRES_SSLMODE is disable")
+	}
+	for name, value := range map[string]string{"sslrootcert": rootCert, "sslcert": cert, "sslkey": key} {
+		if value != "" {
+			params.Set(name, value)
+		}
+	}
+
+	dsn := url.URL{
+		Scheme:   "postgres",
+		User:     url.UserPassword(user, os.Getenv("POSTGRES_PASSWORD")),
+		Host:     host + ":" + port,
+		Path:     "/" + dbName,
+		RawQuery: params.Encode(),
+	}
+	return dsn.String()
+}
+
+func contains(values []string, value string) bool {
+	for _, candidate := range values {
+		if candidate == value {
+			return true
+		}
+	}
+	return false
 }
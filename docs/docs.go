@@ -26,7 +26,7 @@ const docTemplate = `{
     "paths": {
         "/orders": {
             "get": {
-                "description": "Retrieve a paginated list of orders using page number and limit",
+                "description": "Retrieve a paginated list of orders using page number and limit. When cursor is given, keyset pagination is used instead: orders are returned by descending ID and the response carries next_cursor in place of page metadata.",
                 "consumes": [
                     "application/json"
                 ],
@@ -40,28 +40,94 @@ const docTemplate = `{
                 "parameters": [
                     {
                         "type": "integer",
-                        "description": "Page number (default: 1, min: 1)",
+                        "description": "Page number (default: 1, min: 1, max: 1000000)",
                         "name": "page",
                         "in": "query"
                     },
+                    {
+                        "type": "integer",
+                        "description": "Keyset cursor from a previous next_cursor (0 starts from the newest order); bypasses page",
+                        "name": "cursor",
+                        "in": "query"
+                    },
                     {
                         "type": "integer",
                         "description": "Number of orders to return (default: 10, max: 100)",
                         "name": "limit",
                         "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders whose customer name contains this text (case-insensitive)",
+                        "name": "customer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or after this RFC3339 timestamp or date",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or before this RFC3339 timestamp or date (a date covers the whole day)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted orders (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Alias of include_deleted",
+                        "name": "include_archived",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort field: created_at (default), total_amount or id; not allowed with cursor",
+                        "name": "sort",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Sort direction: desc (default) or asc; not allowed with cursor",
+                        "name": "order",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Orders retrieved successfully",
+                        "description": "Orders retrieved successfully (dto.ListOrdersCursorResponse when cursor is given)",
                         "schema": {
                             "$ref": "#/definitions/dto.ListOrdersResponse"
                         }
                     },
+                    "400": {
+                        "description": "Page out of range (strict pagination only), invalid cursor, sort or order given with cursor, unknown status, invalid date range or invalid sort",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "include_deleted requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     }
                 }
@@ -87,33 +153,127 @@ const docTemplate = `{
                         "schema": {
                             "$ref": "#/definitions/dto.CreateOrderRequest"
                         }
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Create the order already paid, for prepaid checkouts",
+                        "name": "paid",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Replays the order created with this key until IDEMPOTENCY_KEY_TTL passes (max 255 characters)",
+                        "name": "Idempotency-Key",
+                        "in": "header"
                     }
                 ],
                 "responses": {
+                    "200": {
+                        "description": "Order replayed for a reused Idempotency-Key, or a dry run",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
                     "201": {
                         "description": "Order created successfully",
                         "schema": {
                             "$ref": "#/definitions/dto.OrderResponse"
+                        },
+                        "headers": {
+                            "X-DB-Retries": {
+                                "type": "integer",
+                                "description": "Database retries needed, when enabled and non-zero"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body or idempotency key",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "409": {
+                        "description": "Client reference already used by another order",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Order violates a business rule",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/bulk": {
+            "post": {
+                "description": "Create up to 100 orders in one request. Each order succeeds or fails on its own; results are reported per index.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Create several orders",
+                "parameters": [
+                    {
+                        "description": "Orders to create",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkCreateOrdersRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "All orders created",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkCreateOrdersResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some orders failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.BulkCreateOrdersResponse"
                         }
                     },
                     "400": {
                         "description": "Invalid request body",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Bulk rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/orders/{id}": {
+        "/orders/by-reference/{ref}": {
             "get": {
-                "description": "Retrieve a specific order by its ID",
+                "description": "Retrieve the most recent order created with the given client reference (e.g. a PO number)",
                 "consumes": [
                     "application/json"
                 ],
@@ -123,47 +283,666 @@ const docTemplate = `{
                 "tags": [
                     "orders"
                 ],
-                "summary": "Get an order by ID",
+                "summary": "Get an order by client reference",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Order ID",
-                        "name": "id",
+                        "type": "string",
+                        "description": "Client reference",
+                        "name": "ref",
                         "in": "path",
                         "required": true
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order retrieved successfully",
+                        "description": "Order retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid client reference",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/count": {
+            "get": {
+                "description": "Return how many orders match the same filters as the list endpoint, without loading them. Meant for dashboards that only need totals.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Count orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only orders with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders whose customer name contains this text (case-insensitive)",
+                        "name": "customer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or after this RFC3339 timestamp or date",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or before this RFC3339 timestamp or date (a date covers the whole day)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted orders (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Alias of include_deleted",
+                        "name": "include_archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Number of matching orders",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderCountResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown status or invalid date range",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "include_deleted requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/export": {
+            "get": {
+                "description": "Download every matching order, newest first, as CSV with the columns id, customer_name, status, total_amount, created_at and item_count. Rows are streamed from a database cursor as they are read, so exports of any size use constant memory. A failure after streaming has started truncates the file.",
+                "produces": [
+                    "text/csv"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Export orders as CSV",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only orders with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders whose customer name contains this text (case-insensitive)",
+                        "name": "customer",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or after this RFC3339 timestamp or date",
+                        "name": "created_from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Only orders created at or before this RFC3339 timestamp or date (a date covers the whole day)",
+                        "name": "created_to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted orders (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "CSV attachment",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Unknown status or invalid date range",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "include_deleted requires the admin role",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/import": {
+            "post": {
+                "description": "Create one order per line of a newline-delimited JSON body, where each line is a create order request. The body is read line by line, so imports use constant memory; the whole body is capped by MAX_IMPORT_BYTES (100 MiB by default). Blank lines are skipped. Lines that fail to decode, validate or save do not stop the import; the first 100 are reported by line number and the rest only counted; a line longer than 1 MiB, a body over the cap or a broken body ends it.",
+                "consumes": [
+                    "application/x-ndjson"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Import orders from NDJSON",
+                "parameters": [
+                    {
+                        "description": "One create order request per line",
+                        "name": "orders",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Every line was imported",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportOrdersResponse"
+                        }
+                    },
+                    "207": {
+                        "description": "Some lines failed",
+                        "schema": {
+                            "$ref": "#/definitions/dto.ImportOrdersResponse"
+                        }
+                    },
+                    "429": {
+                        "description": "Rate limit exceeded",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/statuses": {
+            "post": {
+                "description": "Return only the status of each requested order, keyed by order ID. Unknown IDs are omitted. Much cheaper than fetching full orders for status polling.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Look up the statuses of many orders",
+                "parameters": [
+                    {
+                        "description": "Order IDs (up to 1000)",
+                        "name": "ids",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.GetOrderStatusesRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Statuses keyed by order ID",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderStatusesResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request body",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/stream": {
+            "get": {
+                "description": "Stream every matching order, newest first, as newline-delimited JSON. A failure after streaming has started is reported as a final {\"error\": ...} line. Clients that stop reading for longer than STREAM_WRITE_TIMEOUT are disconnected.",
+                "produces": [
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Stream orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Only orders with this status",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Include soft-deleted orders (admin only)",
+                        "name": "include_deleted",
+                        "in": "query"
+                    },
+                    {
+                        "type": "boolean",
+                        "description": "Alias of include_deleted",
+                        "name": "include_archived",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "One order per line",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Invalid status",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/summary": {
+            "get": {
+                "description": "Return the number of orders and their revenue in every status, plus grand totals. Every status is present, with zeros when it has no orders. Soft-deleted orders are excluded.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Summarize orders by status",
+                "responses": {
+                    "200": {
+                        "description": "Per-status counts and revenue",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderSummaryResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}": {
+            "get": {
+                "description": "Retrieve a specific order by its ID",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Get an order by ID",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order retrieved successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Mark an order as deleted. The order is hidden from lookups and default listings but kept for recovery, unless ORDER_HARD_DELETE removes it permanently. Completed orders cannot be deleted. Requires the admin role.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Delete an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order deleted successfully",
+                        "schema": {
+                            "$ref": "#/definitions/dto.SuccessResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid order ID",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "403": {
+                        "description": "Admin role required",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Completed orders cannot be deleted",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            },
+            "patch": {
+                "description": "Apply a JSON Merge Patch (RFC 7396) to the mutable order fields. Only status can be patched. The response carries only the changed fields plus updated_at.",
+                "consumes": [
+                    "application/merge-patch+json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Partially update an order",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Fields to change",
+                        "name": "patch",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderPatchRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Changed fields",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderPatchResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid patch or non-mutable field",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "415": {
+                        "description": "Content-Type is not application/merge-patch+json",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Status transition not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/items": {
+            "put": {
+                "description": "Replace all items of a pending order. The new items follow the same rules as a new order and total_amount is recomputed from their line totals.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Replace order items",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "New items",
+                        "name": "items",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateOrderItemsRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order with its new items",
+                        "schema": {
+                            "$ref": "#/definitions/dto.OrderResponse"
+                        }
+                    },
+                    "400": {
+                        "description": "Invalid request",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "404": {
+                        "description": "Order not found",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Order is no longer pending or the items violate a business rule",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal server error",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    }
+                }
+            }
+        },
+        "/orders/{id}/status": {
+            "put": {
+                "description": "Update the status of an existing order. Requesting the current status succeeds without a change and reports changed=false.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "orders"
+                ],
+                "summary": "Update order status",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Order ID",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status update request",
+                        "name": "status",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/dto.UpdateOrderStatusRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "Order status updated successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.OrderResponse"
+                            "$ref": "#/definitions/dto.UpdateOrderStatusResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid order ID",
+                        "description": "Invalid request",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Order not found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
+                        }
+                    },
+                    "422": {
+                        "description": "Status transition not allowed",
+                        "schema": {
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     }
                 }
             }
         },
-        "/orders/{id}/status": {
-            "put": {
-                "description": "Update the status of an existing order",
+        "/orders/{id}/timeline": {
+            "get": {
+                "description": "Retrieve every recorded event of an order (creation and status changes) in chronological order",
                 "consumes": [
                     "application/json"
                 ],
@@ -173,7 +952,7 @@ const docTemplate = `{
                 "tags": [
                     "orders"
                 ],
-                "summary": "Update order status",
+                "summary": "Get an order's lifecycle timeline",
                 "parameters": [
                     {
                         "type": "integer",
@@ -181,40 +960,31 @@ const docTemplate = `{
                         "name": "id",
                         "in": "path",
                         "required": true
-                    },
-                    {
-                        "description": "Status update request",
-                        "name": "status",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/dto.UpdateOrderStatusRequest"
-                        }
                     }
                 ],
                 "responses": {
                     "200": {
-                        "description": "Order status updated successfully",
+                        "description": "Timeline retrieved successfully",
                         "schema": {
-                            "$ref": "#/definitions/dto.SuccessResponse"
+                            "$ref": "#/definitions/dto.OrderTimelineResponse"
                         }
                     },
                     "400": {
-                        "description": "Invalid request",
+                        "description": "Invalid order ID",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
                     "404": {
                         "description": "Order not found",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     },
                     "500": {
                         "description": "Internal server error",
                         "schema": {
-                            "$ref": "#/definitions/dto.ErrorResponse"
+                            "$ref": "#/definitions/errors.ErrorResponse"
                         }
                     }
                 }
@@ -222,6 +992,64 @@ const docTemplate = `{
         }
     },
     "definitions": {
+        "dto.BulkCreateOrdersRequest": {
+            "type": "object",
+            "required": [
+                "orders"
+            ],
+            "properties": {
+                "orders": {
+                    "type": "array",
+                    "maxItems": 100,
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.CreateOrderRequest"
+                    }
+                }
+            }
+        },
+        "dto.BulkCreateOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "concurrency": {
+                    "type": "integer",
+                    "example": 4
+                },
+                "duration_ms": {
+                    "type": "number",
+                    "example": 42.5
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 1
+                },
+                "results": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.BulkOrderResultResponse"
+                    }
+                },
+                "succeeded": {
+                    "type": "integer",
+                    "example": 9
+                }
+            }
+        },
+        "dto.BulkOrderResultResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/errors.ErrorInfo"
+                },
+                "index": {
+                    "type": "integer",
+                    "example": 0
+                },
+                "order": {
+                    "$ref": "#/definitions/dto.OrderResponse"
+                }
+            }
+        },
         "dto.CreateOrderItemRequest": {
             "type": "object",
             "required": [
@@ -230,17 +1058,35 @@ const docTemplate = `{
                 "unit_price"
             ],
             "properties": {
+                "discount_percent": {
+                    "type": "number",
+                    "maximum": 100,
+                    "minimum": 0,
+                    "example": 10
+                },
                 "product_name": {
                     "type": "string",
+                    "maxLength": 100,
                     "example": "Laptop Computer"
                 },
                 "quantity": {
-                    "type": "integer",
-                    "minimum": 1,
+                    "type": "number",
+                    "maximum": 10000,
                     "example": 2
                 },
+                "sku": {
+                    "type": "string",
+                    "maxLength": 64,
+                    "example": "LAP-15-BLK"
+                },
+                "unit": {
+                    "type": "string",
+                    "maxLength": 20,
+                    "example": "kg"
+                },
                 "unit_price": {
                     "type": "number",
+                    "maximum": 1000000,
                     "minimum": 0,
                     "example": 999.99
                 }
@@ -253,8 +1099,19 @@ const docTemplate = `{
                 "items"
             ],
             "properties": {
+                "client_reference": {
+                    "type": "string",
+                    "maxLength": 100,
+                    "example": "PO-2023-0042"
+                },
+                "customer_email": {
+                    "type": "string",
+                    "maxLength": 254,
+                    "example": "john.doe@example.com"
+                },
                 "customer_name": {
                     "type": "string",
+                    "maxLength": 100,
                     "example": "John Doe"
                 },
                 "items": {
@@ -266,12 +1123,59 @@ const docTemplate = `{
                 }
             }
         },
-        "dto.ErrorResponse": {
+        "dto.GetOrderStatusesRequest": {
+            "type": "object",
+            "required": [
+                "ids"
+            ],
+            "properties": {
+                "ids": {
+                    "type": "array",
+                    "maxItems": 1000,
+                    "minItems": 1,
+                    "items": {
+                        "type": "integer"
+                    },
+                    "example": [
+                        1,
+                        2,
+                        3
+                    ]
+                }
+            }
+        },
+        "dto.ImportLineError": {
             "type": "object",
             "properties": {
                 "error": {
-                    "type": "string",
-                    "example": "Invalid request parameters"
+                    "$ref": "#/definitions/errors.ErrorInfo"
+                },
+                "line": {
+                    "type": "integer",
+                    "example": 3
+                }
+            }
+        },
+        "dto.ImportOrdersResponse": {
+            "type": "object",
+            "properties": {
+                "errors": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.ImportLineError"
+                    }
+                },
+                "errors_truncated": {
+                    "type": "boolean",
+                    "example": false
+                },
+                "failed": {
+                    "type": "integer",
+                    "example": 2
+                },
+                "imported": {
+                    "type": "integer",
+                    "example": 998
                 }
             }
         },
@@ -286,12 +1190,55 @@ const docTemplate = `{
                 },
                 "pagination": {
                     "$ref": "#/definitions/dto.PaginationResponse"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "limit 500 exceeds the maximum of 100; at most 100 orders are returned"
+                    ]
+                }
+            }
+        },
+        "dto.OrderBreakdownResponse": {
+            "type": "object",
+            "properties": {
+                "discount": {
+                    "type": "number",
+                    "example": 0
+                },
+                "grand_total": {
+                    "type": "number",
+                    "example": 1999.98
+                },
+                "subtotal": {
+                    "type": "number",
+                    "example": 1999.98
+                },
+                "tax": {
+                    "type": "number",
+                    "example": 0
+                }
+            }
+        },
+        "dto.OrderCountResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 42
                 }
             }
         },
         "dto.OrderItemResponse": {
             "type": "object",
             "properties": {
+                "discount_percent": {
+                    "type": "number",
+                    "example": 10
+                },
                 "id": {
                     "type": "integer",
                     "example": 67890
@@ -305,12 +1252,20 @@ const docTemplate = `{
                     "example": "Laptop Computer"
                 },
                 "quantity": {
-                    "type": "integer",
+                    "type": "number",
                     "example": 2
                 },
+                "sku": {
+                    "type": "string",
+                    "example": "LAP-15-BLK"
+                },
                 "total_price": {
                     "type": "number",
-                    "example": 1999.98
+                    "example": 1799.98
+                },
+                "unit": {
+                    "type": "string",
+                    "example": "kg"
                 },
                 "unit_price": {
                     "type": "number",
@@ -318,17 +1273,58 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.OrderPatchRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "processing"
+                }
+            }
+        },
+        "dto.OrderPatchResponse": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string",
+                    "example": "processing"
+                },
+                "updated_at": {
+                    "type": "string",
+                    "example": "2023-01-01T00:00:00Z"
+                }
+            }
+        },
         "dto.OrderResponse": {
             "type": "object",
             "properties": {
+                "breakdown": {
+                    "$ref": "#/definitions/dto.OrderBreakdownResponse"
+                },
+                "client_reference": {
+                    "type": "string",
+                    "example": "PO-2023-0042"
+                },
                 "created_at": {
                     "type": "string",
                     "example": "2023-06-15T10:30:00Z"
                 },
+                "customer_email": {
+                    "type": "string",
+                    "example": "john.doe@example.com"
+                },
                 "customer_name": {
                     "type": "string",
                     "example": "John Doe"
                 },
+                "deleted_at": {
+                    "type": "string",
+                    "example": "2023-06-16T08:00:00Z"
+                },
+                "estimated_ship_date": {
+                    "type": "string",
+                    "example": "2023-06-19T00:00:00Z"
+                },
                 "id": {
                     "type": "integer",
                     "example": 12345
@@ -339,10 +1335,15 @@ const docTemplate = `{
                         "$ref": "#/definitions/dto.OrderItemResponse"
                     }
                 },
+                "order_number": {
+                    "type": "string",
+                    "example": "ORD-2024-000123"
+                },
                 "status": {
                     "type": "string",
                     "enum": [
                         "pending",
+                        "paid",
                         "processing",
                         "completed",
                         "cancelled"
@@ -356,6 +1357,50 @@ const docTemplate = `{
                 "updated_at": {
                     "type": "string",
                     "example": "2023-06-15T10:30:00Z"
+                },
+                "warnings": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    },
+                    "example": [
+                        "stored total did not match the sum of item totals; total_amount was recomputed from the items"
+                    ]
+                }
+            }
+        },
+        "dto.OrderStatusesResponse": {
+            "type": "object",
+            "additionalProperties": {
+                "type": "string"
+            }
+        },
+        "dto.OrderSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "statuses": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/dto.StatusSummaryResponse"
+                    }
+                },
+                "total": {
+                    "$ref": "#/definitions/dto.StatusSummaryResponse"
+                }
+            }
+        },
+        "dto.OrderTimelineResponse": {
+            "type": "object",
+            "properties": {
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/dto.TimelineEventResponse"
+                    }
+                },
+                "order_id": {
+                    "type": "integer",
+                    "example": 12345
                 }
             }
         },
@@ -380,6 +1425,19 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.StatusSummaryResponse": {
+            "type": "object",
+            "properties": {
+                "count": {
+                    "type": "integer",
+                    "example": 12
+                },
+                "revenue": {
+                    "type": "number",
+                    "example": 1234.5
+                }
+            }
+        },
         "dto.SuccessResponse": {
             "type": "object",
             "properties": {
@@ -389,6 +1447,42 @@ const docTemplate = `{
                 }
             }
         },
+        "dto.TimelineEventResponse": {
+            "type": "object",
+            "properties": {
+                "payload": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "timestamp": {
+                    "type": "string",
+                    "example": "2023-06-15T10:30:00Z"
+                },
+                "type": {
+                    "type": "string",
+                    "enum": [
+                        "created",
+                        "status_changed"
+                    ],
+                    "example": "status_changed"
+                }
+            }
+        },
+        "dto.UpdateOrderItemsRequest": {
+            "type": "object",
+            "required": [
+                "items"
+            ],
+            "properties": {
+                "items": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/dto.CreateOrderItemRequest"
+                    }
+                }
+            }
+        },
         "dto.UpdateOrderStatusRequest": {
             "type": "object",
             "required": [
@@ -399,13 +1493,105 @@ const docTemplate = `{
                     "type": "string",
                     "enum": [
                         "pending",
+                        "paid",
                         "processing",
+                        "shipped",
                         "completed",
                         "cancelled"
                     ],
                     "example": "processing"
                 }
             }
+        },
+        "dto.UpdateOrderStatusResponse": {
+            "type": "object",
+            "properties": {
+                "changed": {
+                    "type": "boolean",
+                    "example": true
+                },
+                "message": {
+                    "type": "string",
+                    "example": "Order status updated successfully"
+                }
+            }
+        },
+        "errors.ErrorCode": {
+            "type": "string",
+            "enum": [
+                "INVALID_ENTITY",
+                "BUSINESS_RULE_VIOLATION",
+                "NOT_FOUND",
+                "ALREADY_EXISTS",
+                "INVALID_OPERATION",
+                "PERMISSION_DENIED",
+                "DATABASE_CONNECTION",
+                "DATABASE_QUERY",
+                "DATABASE_TRANSACTION",
+                "EXTERNAL_SERVICE",
+                "TIMEOUT",
+                "CLIENT_CLOSED_REQUEST",
+                "NETWORK_ERROR",
+                "SERVICE_UNAVAILABLE",
+                "VALIDATION",
+                "AUTHENTICATION",
+                "AUTHORIZATION",
+                "RATE_LIMIT",
+                "BAD_REQUEST",
+                "INTERNAL_ERROR"
+            ],
+            "x-enum-varnames": [
+                "ErrCodeInvalidEntity",
+                "ErrCodeBusinessRuleViolation",
+                "ErrCodeNotFound",
+                "ErrCodeAlreadyExists",
+                "ErrCodeInvalidOperation",
+                "ErrCodePermissionDenied",
+                "ErrCodeDatabaseConnection",
+                "ErrCodeDatabaseQuery",
+                "ErrCodeDatabaseTransaction",
+                "ErrCodeExternalService",
+                "ErrCodeTimeout",
+                "ErrCodeClientClosedRequest",
+                "ErrCodeNetworkError",
+                "ErrCodeServiceUnavailable",
+                "ErrCodeValidation",
+                "ErrCodeAuthentication",
+                "ErrCodeAuthorization",
+                "ErrCodeRateLimit",
+                "ErrCodeBadRequest",
+                "ErrCodeInternalError"
+            ]
+        },
+        "errors.ErrorInfo": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "$ref": "#/definitions/errors.ErrorCode"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "message": {
+                    "type": "string"
+                },
+                "retryable": {
+                    "description": "Retryable tells clients whether sending the same request again may succeed",
+                    "type": "boolean"
+                }
+            }
+        },
+        "errors.ErrorResponse": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "$ref": "#/definitions/errors.ErrorInfo"
+                },
+                "trace_id": {
+                    "type": "string"
+                }
+            }
         }
     },
     "securityDefinitions": {
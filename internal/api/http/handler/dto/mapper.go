@@ -1,8 +1,14 @@
 package dto
 
 import (
+	"math"
+
+	apivalidation "online-order-management-system/internal/api/validation"
 	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
 	"online-order-management-system/internal/usecase/order"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/validation"
 )
 
 // ToUseCaseCreateOrderRequest converts API DTO to usecase request
@@ -10,18 +16,39 @@ func (req *CreateOrderRequest) ToUseCaseCreateOrderRequest() order.CreateOrderRe
 	items := make([]order.CreateOrderItemRequest, len(req.Items))
 	for i, item := range req.Items {
 		items[i] = order.CreateOrderItemRequest{
-			ProductName: item.ProductName,
-			Quantity:    item.Quantity,
-			UnitPrice:   item.UnitPrice,
+			ProductName:     item.ProductName,
+			SKU:             item.SKU,
+			Quantity:        item.Quantity,
+			Unit:            item.Unit,
+			UnitPrice:       item.UnitPrice,
+			DiscountPercent: item.DiscountPercent,
 		}
 	}
 
 	return order.CreateOrderRequest{
-		CustomerName: req.CustomerName,
-		Items:        items,
+		CustomerName:    req.CustomerName,
+		CustomerEmail:   req.CustomerEmail,
+		ClientReference: req.ClientReference,
+		Items:           items,
 	}
 }
 
+// ToUseCaseItems converts the API item DTOs to usecase items
+func (req *UpdateOrderItemsRequest) ToUseCaseItems() []order.CreateOrderItemRequest {
+	items := make([]order.CreateOrderItemRequest, len(req.Items))
+	for i, item := range req.Items {
+		items[i] = order.CreateOrderItemRequest{
+			ProductName:     item.ProductName,
+			SKU:             item.SKU,
+			Quantity:        item.Quantity,
+			Unit:            item.Unit,
+			UnitPrice:       item.UnitPrice,
+			DiscountPercent: item.DiscountPercent,
+		}
+	}
+	return items
+}
+
 // ToUseCaseUpdateOrderStatusRequest converts API DTO to usecase request
 func (req *UpdateOrderStatusRequest) ToUseCaseUpdateOrderStatusRequest() order.UpdateOrderStatusRequest {
 	return order.UpdateOrderStatusRequest{
@@ -32,28 +59,61 @@ func (req *UpdateOrderStatusRequest) ToUseCaseUpdateOrderStatusRequest() order.U
 // FromDomainOrder converts domain entity to API DTO
 func FromDomainOrder(domainOrder *entity.Order) OrderResponse {
 	items := make([]OrderItemResponse, len(domainOrder.Items))
+	var subtotal entity.Money
 	for i, item := range domainOrder.Items {
+		subtotal += item.TotalPrice
 		items[i] = OrderItemResponse{
-			ID:          item.ID,
-			OrderID:     item.OrderID,
-			ProductName: item.ProductName,
-			Quantity:    item.Quantity,
-			UnitPrice:   item.UnitPrice,
-			TotalPrice:  item.TotalPrice,
+			ID:              item.ID,
+			OrderID:         item.OrderID,
+			ProductName:     item.ProductName,
+			SKU:             item.SKU,
+			Quantity:        item.Quantity,
+			Unit:            item.Unit,
+			UnitPrice:       item.UnitPrice.Float64(),
+			DiscountPercent: item.DiscountPercent,
+			TotalPrice:      item.TotalPrice.Float64(),
 		}
 	}
 
 	return OrderResponse{
-		ID:           domainOrder.ID,
-		CustomerName: domainOrder.CustomerName,
-		Status:       domainOrder.Status,
-		TotalAmount:  domainOrder.TotalAmount,
-		Items:        items,
-		CreatedAt:    domainOrder.CreatedAt,
-		UpdatedAt:    domainOrder.UpdatedAt,
+		ID:              domainOrder.ID,
+		OrderNumber:     domainOrder.OrderNumber,
+		CustomerName:    domainOrder.CustomerName,
+		CustomerEmail:   domainOrder.CustomerEmail,
+		ClientReference: domainOrder.ClientReference,
+		Status:          domainOrder.Status,
+		TotalAmount:     domainOrder.TotalAmount.Float64(),
+		Items:           items,
+		CreatedAt:       domainOrder.CreatedAt,
+		UpdatedAt:       domainOrder.UpdatedAt,
+		DeletedAt:       domainOrder.DeletedAt,
+
+		EstimatedShipDate: domainOrder.EstimatedShipDate,
+
+		// Orders carry no discount or tax yet
+		Breakdown: newOrderBreakdown(subtotal.Float64(), 0, 0),
+	}
+}
+
+// newOrderBreakdown builds a breakdown whose grand total is subtotal - discount + tax,
+// with every amount rounded to cents
+func newOrderBreakdown(subtotal, discount, tax float64) OrderBreakdownResponse {
+	subtotal = roundCents(subtotal)
+	discount = roundCents(discount)
+	tax = roundCents(tax)
+	return OrderBreakdownResponse{
+		Subtotal:   subtotal,
+		Discount:   discount,
+		Tax:        tax,
+		GrandTotal: roundCents(subtotal - discount + tax),
 	}
 }
 
+// roundCents rounds an amount to two decimal places
+func roundCents(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
 // FromDomainOrders converts multiple domain entities to API DTOs
 func FromDomainOrders(domainOrders []*entity.Order) []OrderResponse {
 	orders := make([]OrderResponse, len(domainOrders))
@@ -70,3 +130,105 @@ func FromUseCaseListOrdersResponse(useCaseResponse *order.ListOrdersResponse) Li
 		Pagination: FromDomainPaginationInfo(useCaseResponse.Pagination),
 	}
 }
+
+// FromUseCaseTimeline converts usecase timeline events to API DTO
+func FromUseCaseTimeline(orderID int64, events []order.TimelineEvent) OrderTimelineResponse {
+	response := OrderTimelineResponse{
+		OrderID: orderID,
+		Events:  make([]TimelineEventResponse, len(events)),
+	}
+	for i, event := range events {
+		response.Events[i] = TimelineEventResponse{
+			Type:      event.Type,
+			Timestamp: event.Timestamp,
+			Payload:   event.Payload,
+		}
+	}
+	return response
+}
+
+// FromUseCaseOrderSummary converts a usecase order summary to API DTO
+func FromUseCaseOrderSummary(summary *order.OrderSummary) OrderSummaryResponse {
+	response := OrderSummaryResponse{
+		Statuses: make(map[string]StatusSummaryResponse, len(summary.ByStatus)),
+		Total:    fromStatusSummary(summary.Total),
+	}
+	for status, stats := range summary.ByStatus {
+		response.Statuses[status] = fromStatusSummary(stats)
+	}
+	return response
+}
+
+func fromStatusSummary(summary repository.StatusSummary) StatusSummaryResponse {
+	return StatusSummaryResponse{Count: summary.Count, Revenue: summary.Revenue.Float64()}
+}
+
+// ToUseCaseBulkCreateOrdersRequest converts API DTO to usecase request
+func (req *BulkCreateOrdersRequest) ToUseCaseBulkCreateOrdersRequest() order.BulkCreateOrdersRequest {
+	orders := make([]order.CreateOrderRequest, len(req.Orders))
+	for i := range req.Orders {
+		orders[i] = req.Orders[i].ToUseCaseCreateOrderRequest()
+	}
+	return order.BulkCreateOrdersRequest{Orders: orders}
+}
+
+// FromUseCaseBulkCreateOrdersResponse converts usecase response to API DTO.
+// Failed orders carry the same error shape as single-order error responses.
+func FromUseCaseBulkCreateOrdersResponse(useCaseResponse *order.BulkCreateOrdersResponse, traceID string) BulkCreateOrdersResponse {
+	response := BulkCreateOrdersResponse{
+		Results:     make([]BulkOrderResultResponse, len(useCaseResponse.Results)),
+		Succeeded:   useCaseResponse.Succeeded,
+		Failed:      useCaseResponse.Failed,
+		DurationMs:  useCaseResponse.DurationMs,
+		Concurrency: useCaseResponse.Concurrency,
+	}
+	for i, result := range useCaseResponse.Results {
+		response.Results[i] = BulkOrderResultResponse{Index: result.Index}
+		if result.Error != nil {
+			errorInfo := apperrors.ToErrorResponse(result.Error, traceID).Error
+			response.Results[i].Error = &errorInfo
+			continue
+		}
+		created := FromDomainOrder(result.Order)
+		response.Results[i].Order = &created
+	}
+	return response
+}
+
+// Validate runs the order and per-item field rules, collecting every failure
+func (req *CreateOrderRequest) Validate() *validation.ValidationResult {
+	items := make([]interface{}, len(req.Items))
+	for i := range req.Items {
+		items[i] = req.Items[i]
+	}
+	result := apivalidation.ValidateOrderFields(req.CustomerName, items)
+	for _, err := range apivalidation.ValidateCustomerEmail(req.CustomerEmail).Errors {
+		result.AddError(err)
+	}
+
+	for i, item := range req.Items {
+		itemResult := apivalidation.ValidateOrderItemFields(i, item.ProductName, item.Quantity, item.Unit, item.UnitPrice)
+		for _, err := range itemResult.Errors {
+			result.AddError(err)
+		}
+		for _, err := range apivalidation.ValidateDiscountPercent(i, item.DiscountPercent).Errors {
+			result.AddError(err)
+		}
+	}
+	return result
+}
+
+// Validate runs the per-item field rules, collecting every failure
+func (req *UpdateOrderItemsRequest) Validate() *validation.ValidationResult {
+	result := validation.NewValidationResult()
+	for i, item := range req.Items {
+		itemResult := apivalidation.ValidateOrderItemFields(i, item.ProductName, item.Quantity, item.Unit, item.UnitPrice)
+		for _, err := range itemResult.Errors {
+			result.AddError(err)
+		}
+		for _, err := range apivalidation.ValidateDiscountPercent(i, item.DiscountPercent).Errors {
+			result.AddError(err)
+		}
+	}
+	return result
+}
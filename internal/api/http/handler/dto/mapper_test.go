@@ -0,0 +1,39 @@
+package dto
+
+import (
+	"testing"
+
+	"online-order-management-system/internal/domain/entity"
+)
+
+func TestFromDomainOrder_BreakdownWithoutDiscountOrTax(t *testing.T) {
+	domainOrder, err := entity.NewOrder("Jane Doe", []entity.OrderItem{
+		{ProductName: "Keyboard", Quantity: 3, UnitPrice: entity.NewMoney(0.1)},
+		{ProductName: "Mouse", Quantity: 2, UnitPrice: entity.NewMoney(24.99)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating order: %v", err)
+	}
+
+	breakdown := FromDomainOrder(domainOrder).Breakdown
+	if breakdown.Subtotal != 50.28 {
+		t.Errorf("expected subtotal 50.28, got %v", breakdown.Subtotal)
+	}
+	if breakdown.Discount != 0 || breakdown.Tax != 0 {
+		t.Errorf("expected no discount or tax, got %+v", breakdown)
+	}
+	if breakdown.GrandTotal != breakdown.Subtotal {
+		t.Errorf("expected grand total to equal subtotal, got %+v", breakdown)
+	}
+}
+
+func TestNewOrderBreakdown_WithDiscountAndTax(t *testing.T) {
+	breakdown := newOrderBreakdown(100, 15.5, 5.925)
+
+	if breakdown.Tax != 5.93 {
+		t.Errorf("expected tax rounded to 5.93, got %v", breakdown.Tax)
+	}
+	if breakdown.GrandTotal != 90.43 {
+		t.Errorf("expected grand total 90.43, got %v", breakdown.GrandTotal)
+	}
+}
@@ -0,0 +1,103 @@
+package dto
+
+import (
+	"bytes"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"online-order-management-system/internal/usecase/order"
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// MergePatchContentType is the media type of JSON Merge Patch documents (RFC 7396)
+const MergePatchContentType = "application/merge-patch+json"
+
+// immutableOrderFields are order response fields that exist but cannot be patched
+var immutableOrderFields = map[string]bool{
+	"id":                  true,
+	"order_number":        true,
+	"customer_name":       true,
+	"customer_email":      true,
+	"client_reference":    true,
+	"total_amount":        true,
+	"items":               true,
+	"created_at":          true,
+	"updated_at":          true,
+	"deleted_at":          true,
+	"estimated_ship_date": true,
+}
+
+// OrderPatchRequest documents the members accepted in an order merge patch
+type OrderPatchRequest struct {
+	Status *string `json:"status,omitempty" example:"processing"`
+}
+
+// OrderPatchResponse carries only the fields changed by a merge patch, plus updated_at
+type OrderPatchResponse struct {
+	Status    *string   `json:"status,omitempty" example:"processing"`
+	UpdatedAt time.Time `json:"updated_at" example:"2023-01-01T00:00:00Z"`
+}
+
+// ParseOrderMergePatch parses a JSON Merge Patch document for an order. Omitted members are
+// left unchanged; an explicit null asks to remove the field, which no mutable field allows.
+// Members naming immutable or unknown fields are rejected rather than silently ignored.
+func ParseOrderMergePatch(body []byte) (order.OrderPatch, error) {
+	var patch order.OrderPatch
+
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(body, &members); err != nil || members == nil {
+		return patch, apperrors.NewValidationError("Merge patch must be a JSON object")
+	}
+
+	var immutable, unknown []string
+	for name, raw := range members {
+		switch {
+		case name == "status":
+			if bytes.Equal(bytes.TrimSpace(raw), []byte("null")) {
+				return patch, apperrors.NewValidationError("Status cannot be removed").WithDetails(map[string]interface{}{
+					"field": name,
+				})
+			}
+			var status string
+			if err := json.Unmarshal(raw, &status); err != nil {
+				return patch, apperrors.NewValidationError("Status must be a string").WithDetails(map[string]interface{}{
+					"field": name,
+				})
+			}
+			patch.Status = &status
+		case immutableOrderFields[name]:
+			immutable = append(immutable, name)
+		default:
+			unknown = append(unknown, name)
+		}
+	}
+
+	if len(immutable) > 0 || len(unknown) > 0 {
+		sort.Strings(immutable)
+		sort.Strings(unknown)
+		details := map[string]interface{}{"mutable_fields": []string{"status"}}
+		if len(immutable) > 0 {
+			details["immutable_fields"] = immutable
+		}
+		if len(unknown) > 0 {
+			details["unknown_fields"] = unknown
+		}
+		return patch, apperrors.NewValidationError("Merge patch contains fields that cannot be changed").WithDetails(details)
+	}
+
+	return patch, nil
+}
+
+// FromPatchOrderResult builds a response holding only the changed fields
+func FromPatchOrderResult(result *order.PatchOrderResult) OrderPatchResponse {
+	response := OrderPatchResponse{UpdatedAt: result.Order.UpdatedAt}
+	for _, field := range result.ChangedFields {
+		switch field {
+		case "status":
+			status := result.Order.Status
+			response.Status = &status
+		}
+	}
+	return response
+}
@@ -2,46 +2,88 @@ package dto
 
 import (
 	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
 	"time"
 )
 
 // CreateOrderRequest represents the API request for creating an order
 type CreateOrderRequest struct {
-	CustomerName string                   `json:"customer_name" binding:"required,max=100" example:"John Doe" validate:"required,max=100"`
-	Items        []CreateOrderItemRequest `json:"items" binding:"required,min=1,dive" validate:"required,min=1,dive"`
+	CustomerName    string                   `json:"customer_name" binding:"required,max=100" example:"John Doe" validate:"required,max=100"`
+	CustomerEmail   string                   `json:"customer_email,omitempty" binding:"omitempty,max=254" example:"john.doe@example.com" validate:"omitempty,max=254"`
+	ClientReference string                   `json:"client_reference,omitempty" binding:"omitempty,max=100" example:"PO-2023-0042" validate:"omitempty,max=100"`
+	Items           []CreateOrderItemRequest `json:"items" binding:"required,min=1,dive" validate:"required,min=1,dive"`
 }
 
 // CreateOrderItemRequest represents an order item in the create request
 type CreateOrderItemRequest struct {
-	ProductName string  `json:"product_name" binding:"required,max=100" example:"Laptop Computer" validate:"required,max=100"`
-	Quantity    int     `json:"quantity" binding:"required,min=1" example:"2" validate:"required,min=1"`
-	UnitPrice   float64 `json:"unit_price" binding:"required,min=0" example:"999.99" validate:"required,min=0"`
+	ProductName     string  `json:"product_name" binding:"required,max=100" example:"Laptop Computer" validate:"required,max=100"`
+	SKU             string  `json:"sku,omitempty" binding:"omitempty,max=64" example:"LAP-15-BLK" validate:"omitempty,max=64"`
+	Quantity        float64 `json:"quantity" binding:"required,gt=0,max=10000" example:"2" validate:"required,gt=0,max=10000"`
+	Unit            string  `json:"unit,omitempty" binding:"omitempty,max=20" example:"kg" validate:"omitempty,max=20"`
+	UnitPrice       float64 `json:"unit_price" binding:"required,min=0,max=1000000" example:"999.99" validate:"required,min=0,max=1000000"`
+	DiscountPercent float64 `json:"discount_percent,omitempty" binding:"omitempty,min=0,max=100" example:"10" validate:"omitempty,min=0,max=100"`
+}
+
+// BulkCreateOrdersRequest represents the API request for creating several orders at once
+type BulkCreateOrdersRequest struct {
+	Orders []CreateOrderRequest `json:"orders" binding:"required,min=1,max=100" validate:"required,min=1,max=100"`
+}
+
+// GetOrderStatusesRequest represents the API request for looking up many order statuses
+type GetOrderStatusesRequest struct {
+	IDs []int64 `json:"ids" binding:"required,min=1,max=1000" example:"1,2,3" validate:"required,min=1,max=1000"`
 }
 
 // UpdateOrderStatusRequest represents the API request for updating order status
 type UpdateOrderStatusRequest struct {
-	Status string `json:"status" binding:"required,oneof=pending processing completed cancelled" example:"processing" validate:"required,oneof=pending processing completed cancelled"`
+	Status string `json:"status" binding:"required,oneof=pending paid processing shipped completed cancelled" example:"processing" validate:"required,oneof=pending paid processing shipped completed cancelled"`
+}
+
+// UpdateOrderItemsRequest represents the API request for replacing the items of a pending order
+type UpdateOrderItemsRequest struct {
+	Items []CreateOrderItemRequest `json:"items" binding:"required,min=1,dive" validate:"required,min=1,dive"`
 }
 
 // OrderResponse represents the API response for a single order
 type OrderResponse struct {
-	ID           int64               `json:"id" example:"12345"`
-	CustomerName string              `json:"customer_name" example:"John Doe"`
-	Status       string              `json:"status" example:"pending" enums:"pending,processing,completed,cancelled"`
-	TotalAmount  float64             `json:"total_amount" example:"1999.98"`
-	Items        []OrderItemResponse `json:"items"`
-	CreatedAt    time.Time           `json:"created_at" example:"2023-06-15T10:30:00Z"`
-	UpdatedAt    time.Time           `json:"updated_at" example:"2023-06-15T10:30:00Z"`
+	ID              int64               `json:"id" example:"12345"`
+	OrderNumber     string              `json:"order_number,omitempty" example:"ORD-2024-000123"`
+	CustomerName    string              `json:"customer_name" example:"John Doe"`
+	CustomerEmail   string              `json:"customer_email,omitempty" example:"john.doe@example.com"`
+	ClientReference string              `json:"client_reference,omitempty" example:"PO-2023-0042"`
+	Status          string              `json:"status" example:"pending" enums:"pending,paid,processing,completed,cancelled"`
+	TotalAmount     float64             `json:"total_amount" example:"1999.98"`
+	Items           []OrderItemResponse `json:"items"`
+	CreatedAt       time.Time           `json:"created_at" example:"2023-06-15T10:30:00Z"`
+	UpdatedAt       time.Time           `json:"updated_at" example:"2023-06-15T10:30:00Z"`
+	DeletedAt       *time.Time          `json:"deleted_at,omitempty" example:"2023-06-16T08:00:00Z"`
+
+	EstimatedShipDate *time.Time `json:"estimated_ship_date,omitempty" example:"2023-06-19T00:00:00Z"`
+
+	Breakdown OrderBreakdownResponse `json:"breakdown"`
+
+	Warnings []string `json:"warnings,omitempty" example:"stored total did not match the sum of item totals; total_amount was recomputed from the items"`
+}
+
+// OrderBreakdownResponse decomposes the order total for receipts
+type OrderBreakdownResponse struct {
+	Subtotal   float64 `json:"subtotal" example:"1999.98"`
+	Discount   float64 `json:"discount" example:"0"`
+	Tax        float64 `json:"tax" example:"0"`
+	GrandTotal float64 `json:"grand_total" example:"1999.98"`
 }
 
 // OrderItemResponse represents an order item in the API response
 type OrderItemResponse struct {
-	ID          int64   `json:"id" example:"67890"`
-	OrderID     int64   `json:"order_id" example:"12345"`
-	ProductName string  `json:"product_name" example:"Laptop Computer"`
-	Quantity    int     `json:"quantity" example:"2"`
-	UnitPrice   float64 `json:"unit_price" example:"999.99"`
-	TotalPrice  float64 `json:"total_price" example:"1999.98"`
+	ID              int64   `json:"id" example:"67890"`
+	OrderID         int64   `json:"order_id" example:"12345"`
+	ProductName     string  `json:"product_name" example:"Laptop Computer"`
+	SKU             string  `json:"sku,omitempty" example:"LAP-15-BLK"`
+	Quantity        float64 `json:"quantity" example:"2"`
+	Unit            string  `json:"unit,omitempty" example:"kg"`
+	UnitPrice       float64 `json:"unit_price" example:"999.99"`
+	DiscountPercent float64 `json:"discount_percent,omitempty" example:"10"`
+	TotalPrice      float64 `json:"total_price" example:"1799.98"`
 }
 
 // PaginationResponse represents pagination metadata in API responses
@@ -56,11 +98,84 @@ type PaginationResponse struct {
 type ListOrdersResponse struct {
 	Orders     []OrderResponse    `json:"orders"`
 	Pagination PaginationResponse `json:"pagination"`
+	Warnings   []string           `json:"warnings,omitempty" example:"limit 500 exceeds the maximum of 100; at most 100 orders are returned"`
+}
+
+// CursorPaginationResponse represents keyset pagination metadata in API responses
+type CursorPaginationResponse struct {
+	NextCursor *int64 `json:"next_cursor" example:"1042"`
+	Limit      int    `json:"limit" example:"10"`
+}
+
+// ListOrdersCursorResponse represents the API response for listing orders by cursor
+type ListOrdersCursorResponse struct {
+	Orders     []OrderResponse          `json:"orders"`
+	Pagination CursorPaginationResponse `json:"pagination"`
+	Warnings   []string                 `json:"warnings,omitempty"`
+}
+
+// TimelineEventResponse represents a single event in an order's timeline
+type TimelineEventResponse struct {
+	Type      string                 `json:"type" example:"status_changed" enums:"created,status_changed"`
+	Timestamp time.Time              `json:"timestamp" example:"2023-06-15T10:30:00Z"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
 }
 
-// ErrorResponse represents the API error response
-type ErrorResponse struct {
-	Error string `json:"error" example:"Invalid request parameters"`
+// OrderTimelineResponse represents the API response for an order's lifecycle timeline
+type OrderTimelineResponse struct {
+	OrderID int64                   `json:"order_id" example:"12345"`
+	Events  []TimelineEventResponse `json:"events"`
+}
+
+// BulkOrderResultResponse represents the outcome of one order in a bulk create
+type BulkOrderResultResponse struct {
+	Index int                  `json:"index" example:"0"`
+	Order *OrderResponse       `json:"order,omitempty"`
+	Error *apperrors.ErrorInfo `json:"error,omitempty"`
+}
+
+// BulkCreateOrdersResponse represents the API response for a bulk create, in request order
+type BulkCreateOrdersResponse struct {
+	Results     []BulkOrderResultResponse `json:"results"`
+	Succeeded   int                       `json:"succeeded" example:"9"`
+	Failed      int                       `json:"failed" example:"1"`
+	DurationMs  float64                   `json:"duration_ms" example:"42.5"`
+	Concurrency int                       `json:"concurrency" example:"4"`
+}
+
+// ImportLineError reports why one NDJSON line of an import was not imported
+type ImportLineError struct {
+	Line  int                 `json:"line" example:"3"`
+	Error apperrors.ErrorInfo `json:"error"`
+}
+
+// ImportOrdersResponse summarizes an NDJSON import; errors are in line order. Only the first
+// failures are listed; Failed counts all of them and ErrorsTruncated reports the cut.
+type ImportOrdersResponse struct {
+	Imported        int               `json:"imported" example:"998"`
+	Failed          int               `json:"failed" example:"2"`
+	Errors          []ImportLineError `json:"errors"`
+	ErrorsTruncated bool              `json:"errors_truncated" example:"false"`
+}
+
+// OrderStatusesResponse maps order IDs to their status; unknown IDs are omitted
+type OrderStatusesResponse map[int64]string
+
+// OrderCountResponse carries the number of orders matching a filter
+type OrderCountResponse struct {
+	Count int64 `json:"count" example:"42"`
+}
+
+// StatusSummaryResponse is the number of orders in a status and their summed totals
+type StatusSummaryResponse struct {
+	Count   int64   `json:"count" example:"12"`
+	Revenue float64 `json:"revenue" example:"1234.5"`
+}
+
+// OrderSummaryResponse breaks orders down by status; every status is present
+type OrderSummaryResponse struct {
+	Statuses map[string]StatusSummaryResponse `json:"statuses"`
+	Total    StatusSummaryResponse            `json:"total"`
 }
 
 // SuccessResponse represents a generic success response
@@ -68,6 +183,12 @@ type SuccessResponse struct {
 	Message string `json:"message" example:"Operation completed successfully"`
 }
 
+// UpdateOrderStatusResponse reports whether a status update changed the order
+type UpdateOrderStatusResponse struct {
+	Message string `json:"message" example:"Order status updated successfully"`
+	Changed bool   `json:"changed" example:"true"`
+}
+
 // FromDomainPaginationInfo converts repository.PaginationInfo to PaginationResponse
 func FromDomainPaginationInfo(info *repository.PaginationInfo) PaginationResponse {
 	return PaginationResponse{
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"encoding/csv"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"online-order-management-system/internal/infra/memory"
+)
+
+func TestExportOrders_WritesCSV(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	seedOrders(t, repo, 2)
+	router := newTestRouter(repo)
+
+	w := doRequest(router, http.MethodGet, "/orders/export?status=pending", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != CSVContentType {
+		t.Errorf("expected content type %s, got %s", CSVContentType, got)
+	}
+	disposition := w.Header().Get("Content-Disposition")
+	if !regexp.MustCompile(`^attachment; filename="orders-\d{8}T\d{6}Z\.csv"$`).MatchString(disposition) {
+		t.Errorf("expected a timestamped attachment, got %q", disposition)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(w.Body.String())).ReadAll()
+	if err != nil {
+		t.Fatalf("failed to parse CSV %q: %v", w.Body.String(), err)
+	}
+	if len(records) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %v", records)
+	}
+	if got := strings.Join(records[0], ","); got != "id,customer_name,status,total_amount,created_at,item_count" {
+		t.Errorf("unexpected header %q", got)
+	}
+	row := records[1]
+	if row[0] != "2" || row[1] != "Customer 1" || row[2] != "pending" || row[3] != "0.20" || row[5] != "1" {
+		t.Errorf("unexpected row %v", row)
+	}
+}
+
+func TestExportOrders_RejectsUnknownStatus(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequest(router, http.MethodGet, "/orders/export?status=bogus", "")
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400, got %d", w.Code)
+	}
+}
+
+func TestCSVText_NeutralizesFormulas(t *testing.T) {
+	for input, want := range map[string]string{
+		"Acme Corp":   "Acme Corp",
+		"=SUM(A1:A2)": "'=SUM(A1:A2)",
+		"@cmd":        "'@cmd",
+		"":            "",
+	} {
+		if got := csvText(input); got != want {
+			t.Errorf("csvText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
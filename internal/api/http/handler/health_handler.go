@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HealthChecker verifies that a dependency is reachable; *sql.DB satisfies it
+type HealthChecker interface {
+	PingContext(ctx context.Context) error
+}
+
+// MigrationVersionSource reports the applied schema version and whether it is dirty;
+// *db.MigrationManager satisfies it
+type MigrationVersionSource interface {
+	GetMigrationVersion(migrationsPath string) (uint, bool, error)
+}
+
+// SchemaCheck makes readiness also require the database schema to be at ExpectedVersion
+// or newer. A newer schema is accepted so instances of the previous release stay ready
+// while a rollout migrates ahead of them.
+type SchemaCheck struct {
+	Source          MigrationVersionSource
+	MigrationsPath  string
+	ExpectedVersion uint
+}
+
+// ReadinessHandler handles GET /ready. Unlike /health, which only shows the process is alive,
+// it pings the database within timeout and answers 503 while the database is unreachable,
+// so load balancers stop routing to an instance that cannot serve orders. With a schema
+// check it also answers 503 while the schema is behind the expected version or dirty,
+// since the code would fail on queries against it. A nil schema skips that check.
+// A non-positive timeout defaults to 2 seconds.
+func ReadinessHandler(database HealthChecker, timeout time.Duration, schema *SchemaCheck) gin.HandlerFunc {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	log := logger.New("readiness-check", "1.0.0")
+
+	return func(c *gin.Context) {
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		if err := database.PingContext(ctx); err != nil {
+			reason := err.Error()
+			if errors.Is(err, context.DeadlineExceeded) {
+				reason = "ping timed out after " + timeout.String()
+			}
+			log.WithError(err).Warn("Readiness check failed: database is unreachable")
+			appErr := apperrors.NewServiceUnavailableError("The database is unreachable").WithDetails(map[string]interface{}{
+				"check":  "database",
+				"reason": reason,
+			}).WithCause(err)
+			c.JSON(http.StatusServiceUnavailable, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+			return
+		}
+
+		checks := gin.H{"database": "ok"}
+		if schema != nil {
+			schemaStatus, appErr := schema.check()
+			if appErr != nil {
+				log.WithFields(appErr.Details).Warn("Readiness check failed: " + appErr.Message)
+				c.JSON(http.StatusServiceUnavailable, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+				return
+			}
+			checks["schema"] = schemaStatus
+		}
+
+		c.JSON(http.StatusOK, gin.H{
+			"status": "ready",
+			"checks": checks,
+		})
+	}
+}
+
+// check compares the applied schema version with the expected one, returning the versions
+// when the schema is usable and a service unavailable error when it is not
+func (s *SchemaCheck) check() (gin.H, *apperrors.AppError) {
+	current, dirty, err := s.Source.GetMigrationVersion(s.MigrationsPath)
+	if err != nil {
+		return nil, apperrors.NewServiceUnavailableError("The database schema version is unknown").WithDetails(map[string]interface{}{
+			"check":            "schema",
+			"reason":           err.Error(),
+			"expected_version": s.ExpectedVersion,
+		}).WithCause(err)
+	}
+
+	versions := map[string]interface{}{
+		"current_version":  current,
+		"expected_version": s.ExpectedVersion,
+		"dirty":            dirty,
+	}
+	var message string
+	switch {
+	case dirty:
+		message = "The database schema is dirty"
+	case current < s.ExpectedVersion:
+		message = "The database schema is behind the expected version"
+	default:
+		return gin.H(versions), nil
+	}
+	versions["check"] = "schema"
+	return nil, apperrors.NewServiceUnavailableError(message).WithDetails(versions)
+}
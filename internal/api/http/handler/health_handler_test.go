@@ -0,0 +1,114 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// fakeHealthChecker fails its ping with err, or blocks until the context ends when block is set
+type fakeHealthChecker struct {
+	err   error
+	block bool
+}
+
+func (f *fakeHealthChecker) PingContext(ctx context.Context) error {
+	if f.block {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	return f.err
+}
+
+func newReadinessRouter(checker HealthChecker, timeout time.Duration) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/ready", ReadinessHandler(checker, timeout, nil))
+	return router
+}
+
+func TestReadinessHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		checker    *fakeHealthChecker
+		wantStatus int
+		wantBody   string
+	}{
+		{name: "database reachable", checker: &fakeHealthChecker{}, wantStatus: http.StatusOK, wantBody: `"ready"`},
+		{name: "ping fails", checker: &fakeHealthChecker{err: errors.New("connection refused")}, wantStatus: http.StatusServiceUnavailable, wantBody: "connection refused"},
+		{name: "ping times out", checker: &fakeHealthChecker{block: true}, wantStatus: http.StatusServiceUnavailable, wantBody: "timed out"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doRequest(newReadinessRouter(tt.checker, 20*time.Millisecond), http.MethodGet, "/ready", "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), tt.wantBody) {
+				t.Errorf("expected the body to contain %s, got %s", tt.wantBody, w.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK && !strings.Contains(w.Body.String(), "SERVICE_UNAVAILABLE") {
+				t.Errorf("expected a SERVICE_UNAVAILABLE error, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+// fakeVersionSource reports a fixed schema version, standing in for the MigrationManager
+type fakeVersionSource struct {
+	version uint
+	dirty   bool
+	err     error
+}
+
+func (f *fakeVersionSource) GetMigrationVersion(string) (uint, bool, error) {
+	return f.version, f.dirty, f.err
+}
+
+func TestReadinessHandler_SchemaVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     *fakeVersionSource
+		wantStatus int
+		wantBody   []string
+	}{
+		{name: "matching version", source: &fakeVersionSource{version: 3}, wantStatus: http.StatusOK,
+			wantBody: []string{`"current_version":3`, `"expected_version":3`}},
+		{name: "schema ahead", source: &fakeVersionSource{version: 4}, wantStatus: http.StatusOK,
+			wantBody: []string{`"current_version":4`, `"expected_version":3`}},
+		{name: "schema behind", source: &fakeVersionSource{version: 2}, wantStatus: http.StatusServiceUnavailable,
+			wantBody: []string{"behind", `"current_version":2`, `"expected_version":3`}},
+		{name: "dirty schema", source: &fakeVersionSource{version: 3, dirty: true}, wantStatus: http.StatusServiceUnavailable,
+			wantBody: []string{"dirty", `"current_version":3`, `"expected_version":3`}},
+		{name: "version unknown", source: &fakeVersionSource{err: errors.New("relation does not exist")}, wantStatus: http.StatusServiceUnavailable,
+			wantBody: []string{"relation does not exist", `"expected_version":3`}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gin.SetMode(gin.TestMode)
+			router := gin.New()
+			router.GET("/ready", ReadinessHandler(&fakeHealthChecker{}, time.Second, &SchemaCheck{
+				Source:          tt.source,
+				MigrationsPath:  "migrations",
+				ExpectedVersion: 3,
+			}))
+
+			w := doRequest(router, http.MethodGet, "/ready", "")
+			if w.Code != tt.wantStatus {
+				t.Fatalf("expected %d, got %d: %s", tt.wantStatus, w.Code, w.Body.String())
+			}
+			for _, want := range tt.wantBody {
+				if !strings.Contains(w.Body.String(), want) {
+					t.Errorf("expected the body to contain %s, got %s", want, w.Body.String())
+				}
+			}
+		})
+	}
+}
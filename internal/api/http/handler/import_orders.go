@@ -0,0 +1,123 @@
+package handler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"online-order-management-system/internal/api/http/handler/dto"
+	"online-order-management-system/internal/api/validation"
+	"online-order-management-system/internal/middleware"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MaxImportLineBytes caps one NDJSON line of an import; longer lines end the import
+const MaxImportLineBytes = 1 << 20
+
+// MaxImportErrors caps the line failures listed in an import response, so a body of invalid
+// lines cannot grow the response without bound; further failures are only counted
+const MaxImportErrors = 100
+
+// ImportOrders handles POST /orders/import
+// @Summary      Import orders from NDJSON
+// @Description  Create one order per line of a newline-delimited JSON body, where each line is a create order request. The body is read line by line, so imports use constant memory; the whole body is capped by MAX_IMPORT_BYTES (100 MiB by default). Blank lines are skipped. Lines that fail to decode, validate or save do not stop the import; the first 100 are reported by line number and the rest only counted; a line longer than 1 MiB, a body over the cap or a broken body ends it.
+// @Tags         orders
+// @Accept       application/x-ndjson
+// @Produce      json
+// @Param        orders  body      dto.CreateOrderRequest     true  "One create order request per line"
+// @Success      200     {object}  dto.ImportOrdersResponse  "Every line was imported"
+// @Success      207     {object}  dto.ImportOrdersResponse  "Some lines failed"
+// @Failure      429     {object}  apperrors.ErrorResponse   "Rate limit exceeded"
+// @Router       /orders/import [post]
+func (h *OrderHandler) ImportOrders(c *gin.Context) {
+	traceID := getTraceID(c)
+	baseCtx := logger.ContextWithTraceID(c.Request.Context(), traceID)
+
+	response := dto.ImportOrdersResponse{Errors: []dto.ImportLineError{}}
+	fail := func(line int, err error) {
+		response.Failed++
+		if len(response.Errors) >= MaxImportErrors {
+			response.ErrorsTruncated = true
+			return
+		}
+		response.Errors = append(response.Errors, dto.ImportLineError{
+			Line:  line,
+			Error: apperrors.ToErrorResponse(err, traceID).Error,
+		})
+	}
+
+	scanner := bufio.NewScanner(c.Request.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), MaxImportLineBytes)
+
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		if err := h.importOrderLine(baseCtx, raw); err != nil {
+			fail(line, err)
+			continue
+		}
+		response.Imported++
+	}
+
+	if err := scanner.Err(); err != nil {
+		line++
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"line":     line,
+		}).Warn("Stopped reading order import")
+		readErr := apperrors.NewValidationError(fmt.Sprintf("Failed to read line: %v", err)).WithDetails(map[string]interface{}{
+			"max_line_bytes": MaxImportLineBytes,
+		})
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			readErr = middleware.BodyTooLargeError(tooLarge.Limit)
+		}
+		fail(line, readErr)
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id": traceID,
+		"imported": response.Imported,
+		"failed":   response.Failed,
+	}).Info("Finished order import")
+
+	statusCode := http.StatusOK
+	if response.Failed > 0 {
+		statusCode = http.StatusMultiStatus
+	}
+	c.JSON(statusCode, response)
+}
+
+// importOrderLine decodes, validates and creates the order of one NDJSON line
+func (h *OrderHandler) importOrderLine(ctx context.Context, raw []byte) error {
+	var req dto.CreateOrderRequest
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	if err := decoder.Decode(&req); err != nil {
+		return apperrors.NewValidationError(validation.GetOrderValidationMessage(err))
+	}
+	if decoder.More() {
+		return apperrors.NewValidationError("Each line must hold exactly one JSON object")
+	}
+	if validationErr := validation.ToValidationError(req.Validate()); validationErr != nil {
+		return validationErr
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	_, err := h.createOrderUC.Execute(ctx, req.ToUseCaseCreateOrderRequest())
+	return err
+}
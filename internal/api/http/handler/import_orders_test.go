@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"online-order-management-system/internal/api/http/handler/dto"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/infra/memory"
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func TestImportOrders_ReportsFailuresByLine(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	router := newTestRouter(repo)
+
+	body := strings.Join([]string{
+		createOrderBody(""),
+		`{"customer_name": "No Items", "items": []}`,
+		"",
+		`{not json`,
+		createOrderBody(""),
+	}, "\n")
+
+	w := doRequest(router, http.MethodPost, "/orders/import", body)
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("expected 207, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response dto.ImportOrdersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Imported != 2 || response.Failed != 2 {
+		t.Errorf("expected 2 imported and 2 failed, got %+v", response)
+	}
+	if len(response.Errors) != 2 || response.Errors[0].Line != 2 || response.Errors[1].Line != 4 {
+		t.Fatalf("expected failures on lines 2 and 4, got %+v", response.Errors)
+	}
+	for _, lineErr := range response.Errors {
+		if lineErr.Error.Code != apperrors.ErrCodeValidation {
+			t.Errorf("expected a validation error on line %d, got %+v", lineErr.Line, lineErr.Error)
+		}
+	}
+
+	count, err := repo.CountOrders(context.Background(), repository.OrderFilter{})
+	if err != nil || count != 2 {
+		t.Errorf("expected 2 stored orders, got %d, %v", count, err)
+	}
+}
+
+func TestImportOrders_AllValidReturnsOK(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequest(router, http.MethodPost, "/orders/import", createOrderBody("")+"\n"+createOrderBody("")+"\n")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if !strings.Contains(w.Body.String(), `"imported":2`) || !strings.Contains(w.Body.String(), `"errors":[]`) {
+		t.Errorf("unexpected summary %s", w.Body.String())
+	}
+}
+
+func TestImportOrders_CapsReportedErrors(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	lines := make([]string, MaxImportErrors+50)
+	for i := range lines {
+		lines[i] = `{not json`
+	}
+	w := doRequest(router, http.MethodPost, "/orders/import", strings.Join(lines, "\n"))
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("expected 207, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response dto.ImportOrdersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Failed != len(lines) {
+		t.Errorf("expected every failed line to be counted, got %d", response.Failed)
+	}
+	if len(response.Errors) != MaxImportErrors || !response.ErrorsTruncated {
+		t.Errorf("expected %d listed errors and errors_truncated, got %d (truncated: %v)",
+			MaxImportErrors, len(response.Errors), response.ErrorsTruncated)
+	}
+}
@@ -2,18 +2,31 @@ package handler
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
 	"online-order-management-system/internal/api/http/handler/dto"
 	"online-order-management-system/internal/api/validation"
 	"online-order-management-system/internal/domain/entity"
+	domainerrors "online-order-management-system/internal/domain/errors"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/middleware"
 	"online-order-management-system/internal/usecase/order"
+	"online-order-management-system/pkg/dryrun"
+	"online-order-management-system/pkg/errorlog"
 	apperrors "online-order-management-system/pkg/errors"
 	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/retryutil"
+	"online-order-management-system/pkg/tracing"
+	"online-order-management-system/pkg/warnings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
 // Use case interfaces for better testability
@@ -21,41 +34,171 @@ type CreateOrderUseCase interface {
 	Execute(ctx context.Context, req order.CreateOrderRequest) (*entity.Order, error)
 }
 
+type BulkCreateOrdersUseCase interface {
+	Execute(ctx context.Context, req order.BulkCreateOrdersRequest) (*order.BulkCreateOrdersResponse, error)
+}
+
 type GetOrderUseCase interface {
 	Execute(ctx context.Context, id int64) (*entity.Order, error)
 }
 
+type GetOrderStatusesUseCase interface {
+	Execute(ctx context.Context, ids []int64) (map[int64]string, error)
+}
+
 type ListOrdersUseCase interface {
-	Execute(ctx context.Context, page int, limit int) (*order.ListOrdersResponse, error)
+	Execute(ctx context.Context, page int, limit int, filter repository.OrderFilter) (*order.ListOrdersResponse, error)
+	ExecuteAfter(ctx context.Context, cursor int64, limit int, filter repository.OrderFilter) (*order.ListOrdersCursorResponse, error)
+}
+
+type CountOrdersUseCase interface {
+	Execute(ctx context.Context, filter repository.OrderFilter) (int64, error)
+}
+
+type SummarizeOrdersUseCase interface {
+	Execute(ctx context.Context) (*order.OrderSummary, error)
 }
 
 type UpdateOrderStatusUseCase interface {
-	Execute(ctx context.Context, id int64, status string) error
+	Execute(ctx context.Context, id int64, status string) (bool, error)
+}
+
+type PatchOrderUseCase interface {
+	Execute(ctx context.Context, id int64, patch order.OrderPatch) (*order.PatchOrderResult, error)
+}
+
+type GetOrderByReferenceUseCase interface {
+	Execute(ctx context.Context, reference string) (*entity.Order, error)
+}
+
+type DeleteOrderUseCase interface {
+	Execute(ctx context.Context, id int64) error
+}
+
+type GetOrderTimelineUseCase interface {
+	Execute(ctx context.Context, id int64) ([]order.TimelineEvent, error)
+}
+
+type StreamOrdersUseCase interface {
+	Execute(ctx context.Context, filter repository.OrderFilter) (<-chan *entity.Order, <-chan error, error)
+}
+
+// OrderUseCases groups the use cases served by OrderHandler
+type OrderUseCases struct {
+	CreateOrder         *order.CreateOrderUseCase
+	BulkCreateOrders    *order.BulkCreateOrdersUseCase
+	GetOrder            *order.GetOrderUseCase
+	GetOrderByReference *order.GetOrderByReferenceUseCase
+	GetOrderStatuses    *order.GetOrderStatusesUseCase
+	ListOrders          *order.ListOrdersUseCase
+	CountOrders         *order.CountOrdersUseCase
+	SummarizeOrders     *order.SummarizeOrdersUseCase
+	UpdateOrderStatus   *order.UpdateOrderStatusUseCase
+	UpdateOrderItems    *order.UpdateOrderItemsUseCase
+	PatchOrder          *order.PatchOrderUseCase
+	DeleteOrder         *order.DeleteOrderUseCase
+	GetOrderTimeline    *order.GetOrderTimelineUseCase
+	StreamOrders        *order.StreamOrdersUseCase
 }
 
 // OrderHandler handles HTTP requests for order operations
 type OrderHandler struct {
-	createOrderUC       *order.CreateOrderUseCase
-	getOrderUC          *order.GetOrderUseCase
-	listOrdersUC        *order.ListOrdersUseCase
-	updateOrderStatusUC *order.UpdateOrderStatusUseCase
-	logger              *logger.Logger
+	createOrderUC         *order.CreateOrderUseCase
+	bulkCreateOrdersUC    *order.BulkCreateOrdersUseCase
+	getOrderUC            *order.GetOrderUseCase
+	getOrderByReferenceUC *order.GetOrderByReferenceUseCase
+	getOrderStatusesUC    *order.GetOrderStatusesUseCase
+	listOrdersUC          *order.ListOrdersUseCase
+	countOrdersUC         *order.CountOrdersUseCase
+	summarizeOrdersUC     *order.SummarizeOrdersUseCase
+	updateOrderStatusUC   *order.UpdateOrderStatusUseCase
+	updateOrderItemsUC    *order.UpdateOrderItemsUseCase
+	patchOrderUC          *order.PatchOrderUseCase
+	deleteOrderUC         *order.DeleteOrderUseCase
+	getOrderTimelineUC    *order.GetOrderTimelineUseCase
+	streamOrdersUC        *order.StreamOrdersUseCase
+	exposeRetryHeader     bool
+	strictPagination      bool
+	bulkRateLimiter       *middleware.IPRateLimiter
+	streamWriteTimeout    time.Duration
+	errorLog              *errorlog.Ring
+	logger                *logger.Logger
+}
+
+// RetriesHeader reports how many database retries a successful create needed
+const RetriesHeader = "X-DB-Retries"
+
+// IdempotencyKeyHeader carries a client-chosen key that makes order creation safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+// MaxIdempotencyKeyLength caps the accepted Idempotency-Key header
+const MaxIdempotencyKeyLength = 255
+
+// HandlerOption configures optional behavior of OrderHandler
+type HandlerOption func(*OrderHandler)
+
+// WithRetryHeader sets the X-DB-Retries header on creates that succeeded only after retries
+func WithRetryHeader(enabled bool) HandlerOption {
+	return func(h *OrderHandler) {
+		h.exposeRetryHeader = enabled
+	}
+}
+
+// WithStrictPagination rejects out-of-range page numbers with 400 instead of clamping them
+func WithStrictPagination(enabled bool) HandlerOption {
+	return func(h *OrderHandler) {
+		h.strictPagination = enabled
+	}
+}
+
+// WithBulkRateLimiter applies a dedicated per-client rate limit to POST /orders/bulk and
+// POST /orders/import. It has its own buckets; exempt these routes from the API-wide limiter
+// so bulk requests are only counted here.
+func WithBulkRateLimiter(limiter *middleware.IPRateLimiter) HandlerOption {
+	return func(h *OrderHandler) {
+		h.bulkRateLimiter = limiter
+	}
+}
+
+// WithStreamWriteTimeout aborts a streamed response when writing one chunk to the client
+// takes longer than timeout, so slow readers cannot hold a database cursor open (0 disables it)
+func WithStreamWriteTimeout(timeout time.Duration) HandlerOption {
+	return func(h *OrderHandler) {
+		h.streamWriteTimeout = timeout
+	}
+}
+
+// WithErrorLog records every error response (code, message, trace ID) in ring for
+// GET /debug/errors. A nil ring disables recording.
+func WithErrorLog(ring *errorlog.Ring) HandlerOption {
+	return func(h *OrderHandler) {
+		h.errorLog = ring
+	}
 }
 
 // NewOrderHandler creates a new OrderHandler
-func NewOrderHandler(
-	createOrderUC *order.CreateOrderUseCase,
-	getOrderUC *order.GetOrderUseCase,
-	listOrdersUC *order.ListOrdersUseCase,
-	updateOrderStatusUC *order.UpdateOrderStatusUseCase,
-) *OrderHandler {
-	return &OrderHandler{
-		createOrderUC:       createOrderUC,
-		getOrderUC:          getOrderUC,
-		listOrdersUC:        listOrdersUC,
-		updateOrderStatusUC: updateOrderStatusUC,
-		logger:              logger.New("order-handler", "1.0.0"),
+func NewOrderHandler(useCases OrderUseCases, opts ...HandlerOption) *OrderHandler {
+	h := &OrderHandler{
+		createOrderUC:         useCases.CreateOrder,
+		bulkCreateOrdersUC:    useCases.BulkCreateOrders,
+		getOrderUC:            useCases.GetOrder,
+		getOrderByReferenceUC: useCases.GetOrderByReference,
+		getOrderStatusesUC:    useCases.GetOrderStatuses,
+		listOrdersUC:          useCases.ListOrders,
+		countOrdersUC:         useCases.CountOrders,
+		summarizeOrdersUC:     useCases.SummarizeOrders,
+		updateOrderStatusUC:   useCases.UpdateOrderStatus,
+		updateOrderItemsUC:    useCases.UpdateOrderItems,
+		patchOrderUC:          useCases.PatchOrder,
+		deleteOrderUC:         useCases.DeleteOrder,
+		getOrderTimelineUC:    useCases.GetOrderTimeline,
+		streamOrdersUC:        useCases.StreamOrders,
+		logger:                logger.New("order-handler", "1.0.0"),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // RegisterRoutes registers all order routes to the Gin router
@@ -63,20 +206,46 @@ func (h *OrderHandler) RegisterRoutes(router gin.IRouter) {
 	orders := router.Group("/orders")
 	{
 		orders.POST("", h.CreateOrder)
+		orders.POST("/bulk", h.bulkRateLimiter.Middleware(), h.BulkCreateOrders)
+		orders.POST("/import", h.bulkRateLimiter.Middleware(), h.ImportOrders)
+		orders.POST("/statuses", h.GetOrderStatuses)
 		orders.GET("", h.ListOrders)
+		orders.GET("/count", h.CountOrders)
+		orders.GET("/summary", h.SummarizeOrders)
+		orders.GET("/stream", h.StreamOrders)
+		orders.GET("/export", h.ExportOrders)
+		orders.GET("/by-reference/:ref", h.GetOrderByReference)
 		orders.GET("/:id", h.GetOrder)
+		orders.GET("/:id/timeline", h.GetOrderTimeline)
 		orders.PUT("/:id/status", h.UpdateOrderStatus)
+		orders.PUT("/:id/items", h.UpdateOrderItems)
+		orders.PATCH("/:id", h.PatchOrder)
+		orders.DELETE("/:id", h.DeleteOrder)
 	}
 }
 
-// getTraceID extracts trace ID from gin context
+// getTraceID returns the request's trace ID: the one stored in the gin context by the
+// trace ID or tracing middleware, otherwise the OTel trace ID of an active span
 func getTraceID(c *gin.Context) string {
 	if traceID, exists := c.Get("trace_id"); exists {
-		if str, ok := traceID.(string); ok {
+		if str, ok := traceID.(string); ok && str != "" {
 			return str
 		}
 	}
-	return ""
+	return tracing.TraceID(c.Request.Context())
+}
+
+// errorResponse builds an error response localized to the request's Accept-Language header
+// and records it in the error log, if enabled
+func (h *OrderHandler) errorResponse(c *gin.Context, err error, traceID string) apperrors.ErrorResponse {
+	response := apperrors.ToLocalizedErrorResponse(err, traceID, c.GetHeader("Accept-Language"))
+	h.errorLog.Record(string(response.Error.Code), response.Error.Message, traceID)
+	return response
+}
+
+// errorStatus returns the HTTP status of an error for the API version pinned by the request
+func errorStatus(c *gin.Context, err error) int {
+	return middleware.StatusForError(c, err)
 }
 
 // CreateOrder handles POST /orders
@@ -86,29 +255,75 @@ func getTraceID(c *gin.Context) string {
 // @Accept       json
 // @Produce      json
 // @Param        order  body      dto.CreateOrderRequest  true  "Order creation request"
+// @Param        paid   query     bool                    false "Create the order already paid, for prepaid checkouts"
+// @Param        Idempotency-Key  header  string        false "Replays the order created with this key until IDEMPOTENCY_KEY_TTL passes (max 255 characters)"
 // @Success      201    {object}  dto.OrderResponse       "Order created successfully"
-// @Failure      400    {object}  apperrors.ErrorResponse       "Invalid request body"
+// @Success      200    {object}  dto.OrderResponse       "Order replayed for a reused Idempotency-Key, or a dry run"
+// @Header       201    {integer} X-DB-Retries            "Database retries needed, when enabled and non-zero"
+// @Failure      400    {object}  apperrors.ErrorResponse       "Invalid request body or idempotency key"
+// @Failure      409    {object}  apperrors.ErrorResponse       "Client reference already used by another order"
+// @Failure      422    {object}  apperrors.ErrorResponse       "Order violates a business rule"
 // @Failure      500    {object}  apperrors.ErrorResponse       "Internal server error"
 // @Router       /orders [post]
 func (h *OrderHandler) CreateOrder(c *gin.Context) {
 	traceID := getTraceID(c)
 
+	paid, err := strconv.ParseBool(c.DefaultQuery("paid", "false"))
+	if err != nil {
+		h.logger.WithFields(map[string]interface{}{
+			"trace_id":   traceID,
+			"paid_param": c.Query("paid"),
+		}).Warn("Invalid paid parameter")
+
+		validationErr := apperrors.NewValidationError("Invalid paid parameter. Must be true or false")
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	idempotencyKey := strings.TrimSpace(c.GetHeader(IdempotencyKeyHeader))
+	if len(idempotencyKey) > MaxIdempotencyKeyLength {
+		h.logger.WithField("trace_id", traceID).Warn("Idempotency key too long")
+
+		validationErr := apperrors.NewValidationError(fmt.Sprintf("%s must be at most %d characters", IdempotencyKeyHeader, MaxIdempotencyKeyLength))
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
 	var req dto.CreateOrderRequest
-	if err := c.ShouldBindJSON(&req); err != nil {
+	bindErr := c.ShouldBindJSON(&req)
+
+	// Field rules give precise, structured details (item index, limits), so they take
+	// precedence over binding messages whenever the body itself decoded
+	var fieldErrs validator.ValidationErrors
+	if bindErr == nil || errors.As(bindErr, &fieldErrs) {
+		if validationErr := validation.ToValidationError(req.Validate()); validationErr != nil {
+			h.logger.WithError(validationErr).WithField("trace_id", traceID).Warn("Order failed field validation")
+			response := h.errorResponse(c, validationErr, traceID)
+			c.JSON(validationErr.HTTPStatus, response)
+			return
+		}
+	}
+
+	if err := bindErr; err != nil {
 		h.logger.WithError(err).WithField("trace_id", traceID).Warn("Invalid request body")
-		friendlyError := validation.GetOrderValidationMessage(err)
-		validationErr := apperrors.NewValidationError(friendlyError)
-		response := apperrors.ToErrorResponse(validationErr, traceID)
+		validationErr := validation.BindingError(err, &req)
+		response := h.errorResponse(c, validationErr, traceID)
 		c.JSON(validationErr.HTTPStatus, response)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
+	ctx, retries := retryutil.WithRetryCounter(ctx)
+	ctx, collected := warnings.WithCollector(ctx)
 
 	// Convert DTO to usecase request
 	useCaseReq := req.ToUseCaseCreateOrderRequest()
-	createdOrder, err := h.createOrderUC.Execute(ctx, useCaseReq)
+	useCaseReq.Paid = paid
+	useCaseReq.IdempotencyKey = idempotencyKey
+	createdOrder, replayed, err := h.createOrderUC.ExecuteWithReplay(ctx, useCaseReq)
 	if err != nil {
 		h.logger.WithError(err).WithFields(map[string]interface{}{
 			"trace_id":      traceID,
@@ -116,8 +331,8 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 			"items_count":   len(req.Items),
 		}).Error("Failed to create order")
 
-		response := apperrors.ToErrorResponse(err, traceID)
-		statusCode := apperrors.GetHTTPStatus(err)
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
 		c.JSON(statusCode, response)
 		return
 	}
@@ -127,11 +342,79 @@ func (h *OrderHandler) CreateOrder(c *gin.Context) {
 		"order_id":      createdOrder.ID,
 		"customer_name": createdOrder.CustomerName,
 		"total_amount":  createdOrder.TotalAmount,
+		"db_retries":    retries.Count(),
 	}).Info("Successfully created order")
 
+	if h.exposeRetryHeader && retries.Count() > 0 {
+		c.Header(RetriesHeader, strconv.Itoa(retries.Count()))
+	}
+
 	// Convert domain entity to DTO response
 	response := dto.FromDomainOrder(createdOrder)
-	c.JSON(http.StatusCreated, response)
+	response.Warnings = collected.List()
+	statusCode := http.StatusCreated
+	if replayed || dryrun.Enabled(ctx) {
+		// Nothing was created: the order was replayed for its idempotency key, or rolled back
+		statusCode = http.StatusOK
+	}
+	c.JSON(statusCode, response)
+}
+
+// BulkCreateOrders handles POST /orders/bulk
+// @Summary      Create several orders
+// @Description  Create up to 100 orders in one request. Each order succeeds or fails on its own; results are reported per index.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        orders  body      dto.BulkCreateOrdersRequest   true  "Orders to create"
+// @Success      201     {object}  dto.BulkCreateOrdersResponse  "All orders created"
+// @Success      207     {object}  dto.BulkCreateOrdersResponse  "Some orders failed"
+// @Failure      400     {object}  apperrors.ErrorResponse       "Invalid request body"
+// @Failure      429     {object}  apperrors.ErrorResponse       "Bulk rate limit exceeded"
+// @Failure      500     {object}  apperrors.ErrorResponse       "Internal server error"
+// @Router       /orders/bulk [post]
+func (h *OrderHandler) BulkCreateOrders(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	var req dto.BulkCreateOrdersRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).WithField("trace_id", traceID).Warn("Invalid bulk request body")
+		validationErr := validation.BindingError(err, &req)
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(logger.ContextWithTraceID(c.Request.Context(), traceID), 30*time.Second)
+	defer cancel()
+
+	result, err := h.bulkCreateOrdersUC.Execute(ctx, req.ToUseCaseBulkCreateOrdersRequest())
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id":     traceID,
+			"orders_count": len(req.Orders),
+		}).Error("Failed to bulk create orders")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id":  traceID,
+		"succeeded": result.Succeeded,
+		"failed":    result.Failed,
+	}).Info("Finished bulk order creation")
+
+	statusCode := http.StatusCreated
+	if dryrun.Enabled(ctx) {
+		statusCode = http.StatusOK
+	}
+	if result.Failed > 0 {
+		statusCode = http.StatusMultiStatus
+	}
+	c.JSON(statusCode, dto.FromUseCaseBulkCreateOrdersResponse(result, traceID))
 }
 
 // GetOrder handles GET /orders/:id
@@ -158,13 +441,14 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 		}).Warn("Invalid order ID parameter")
 
 		validationErr := apperrors.NewValidationError("Invalid order ID. Must be a valid number")
-		response := apperrors.ToErrorResponse(validationErr, traceID)
+		response := h.errorResponse(c, validationErr, traceID)
 		c.JSON(validationErr.HTTPStatus, response)
 		return
 	}
 
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
+	ctx, collected := warnings.WithCollector(ctx)
 
 	domainOrder, err := h.getOrderUC.Execute(ctx, id)
 	if err != nil {
@@ -173,8 +457,8 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 			"order_id": id,
 		}).Error("Failed to get order")
 
-		response := apperrors.ToErrorResponse(err, traceID)
-		statusCode := apperrors.GetHTTPStatus(err)
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
 		c.JSON(statusCode, response)
 		return
 	}
@@ -186,42 +470,241 @@ func (h *OrderHandler) GetOrder(c *gin.Context) {
 
 	// Convert domain entity to DTO response
 	response := dto.FromDomainOrder(domainOrder)
+	response.Warnings = collected.List()
 	c.JSON(http.StatusOK, response)
 }
 
+// GetOrderByReference handles GET /orders/by-reference/:ref
+// @Summary      Get an order by client reference
+// @Description  Retrieve the most recent order created with the given client reference (e.g. a PO number)
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        ref  path      string                    true  "Client reference"
+// @Success      200  {object}  dto.OrderResponse         "Order retrieved successfully"
+// @Failure      400  {object}  apperrors.ErrorResponse   "Invalid client reference"
+// @Failure      404  {object}  apperrors.ErrorResponse   "Order not found"
+// @Failure      500  {object}  apperrors.ErrorResponse   "Internal server error"
+// @Router       /orders/by-reference/{ref} [get]
+func (h *OrderHandler) GetOrderByReference(c *gin.Context) {
+	traceID := getTraceID(c)
+	reference := c.Param("ref")
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	ctx, collected := warnings.WithCollector(ctx)
+
+	domainOrder, err := h.getOrderByReferenceUC.Execute(ctx, reference)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id":         traceID,
+			"client_reference": reference,
+		}).Error("Failed to get order by client reference")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id":         traceID,
+		"order_id":         domainOrder.ID,
+		"client_reference": reference,
+	}).Debug("Successfully retrieved order by client reference")
+
+	response := dto.FromDomainOrder(domainOrder)
+	response.Warnings = collected.List()
+	c.JSON(http.StatusOK, response)
+}
+
+// GetOrderStatuses handles POST /orders/statuses
+// @Summary      Look up the statuses of many orders
+// @Description  Return only the status of each requested order, keyed by order ID. Unknown IDs are omitted. Much cheaper than fetching full orders for status polling.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        ids  body      dto.GetOrderStatusesRequest  true  "Order IDs (up to 1000)"
+// @Success      200  {object}  dto.OrderStatusesResponse    "Statuses keyed by order ID"
+// @Failure      400  {object}  apperrors.ErrorResponse      "Invalid request body"
+// @Failure      500  {object}  apperrors.ErrorResponse      "Internal server error"
+// @Router       /orders/statuses [post]
+func (h *OrderHandler) GetOrderStatuses(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	var req dto.GetOrderStatusesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		h.logger.WithError(err).WithField("trace_id", traceID).Warn("Invalid status lookup request body")
+		validationErr := apperrors.NewValidationError("ids must be a list of 1 to 1000 order IDs")
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			validationErr = middleware.BodyTooLargeError(tooLarge.Limit)
+		}
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	statuses, err := h.getOrderStatusesUC.Execute(ctx, req.IDs)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id":  traceID,
+			"ids_count": len(req.IDs),
+		}).Error("Failed to get order statuses")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.OrderStatusesResponse(statuses))
+}
+
+// GetOrderTimeline handles GET /orders/:id/timeline
+// @Summary      Get an order's lifecycle timeline
+// @Description  Retrieve every recorded event of an order (creation and status changes) in chronological order
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int                         true  "Order ID"
+// @Success      200  {object}  dto.OrderTimelineResponse   "Timeline retrieved successfully"
+// @Failure      400  {object}  apperrors.ErrorResponse     "Invalid order ID"
+// @Failure      404  {object}  apperrors.ErrorResponse     "Order not found"
+// @Failure      500  {object}  apperrors.ErrorResponse     "Internal server error"
+// @Router       /orders/{id}/timeline [get]
+func (h *OrderHandler) GetOrderTimeline(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"id_param": idStr,
+		}).Warn("Invalid order ID parameter")
+
+		validationErr := apperrors.NewValidationError("Invalid order ID. Must be a valid number")
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	events, err := h.getOrderTimelineUC.Execute(ctx, id)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"order_id": id,
+		}).Error("Failed to get order timeline")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id":     traceID,
+		"order_id":     id,
+		"events_count": len(events),
+	}).Debug("Successfully retrieved order timeline")
+
+	c.JSON(http.StatusOK, dto.FromUseCaseTimeline(id, events))
+}
+
 // ListOrders handles GET /orders
 // @Summary      List orders with pagination
-// @Description  Retrieve a paginated list of orders using page number and limit
+// @Description  Retrieve a paginated list of orders using page number and limit. When cursor is given, keyset pagination is used instead: orders are returned by descending ID and the response carries next_cursor in place of page metadata.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
-// @Param        page    query     int     false  "Page number (default: 1, min: 1)"
+// @Param        page    query     int     false  "Page number (default: 1, min: 1, max: 1000000)"
+// @Param        cursor  query     int     false  "Keyset cursor from a previous next_cursor (0 starts from the newest order); bypasses page"
 // @Param        limit   query     int     false  "Number of orders to return (default: 10, max: 100)"
-// @Success      200     {object}  dto.ListOrdersResponse  "Orders retrieved successfully"
+// @Param        status  query     string  false  "Only orders with this status"
+// @Param        customer  query   string  false  "Only orders whose customer name contains this text (case-insensitive)"
+// @Param        created_from  query  string  false  "Only orders created at or after this RFC3339 timestamp or date"
+// @Param        created_to    query  string  false  "Only orders created at or before this RFC3339 timestamp or date (a date covers the whole day)"
+// @Param        include_deleted  query  bool  false  "Include soft-deleted orders (admin only)"
+// @Param        include_archived  query  bool  false  "Alias of include_deleted"
+// @Param        sort    query     string  false  "Sort field: created_at (default), total_amount or id; not allowed with cursor"
+// @Param        order   query     string  false  "Sort direction: desc (default) or asc; not allowed with cursor"
+// @Success      200     {object}  dto.ListOrdersResponse  "Orders retrieved successfully (dto.ListOrdersCursorResponse when cursor is given)"
+// @Failure      400     {object}  apperrors.ErrorResponse       "Page out of range (strict pagination only), invalid cursor, sort or order given with cursor, unknown status, invalid date range or invalid sort"
+// @Failure      403     {object}  apperrors.ErrorResponse       "include_deleted requires the admin role"
 // @Failure      500     {object}  apperrors.ErrorResponse       "Internal server error"
 // @Router       /orders [get]
 func (h *OrderHandler) ListOrders(c *gin.Context) {
 	traceID := getTraceID(c)
 
+	// A cursor switches to keyset pagination and bypasses the page/offset path
+	if cursorStr, ok := c.GetQuery("cursor"); ok {
+		h.listOrdersByCursor(c, traceID, cursorStr)
+		return
+	}
+
 	// Parse query parameters
 	page := 1
+	pageClamped := false
 	if pageStr := c.Query("page"); pageStr != "" {
-		if p, err := strconv.Atoi(pageStr); err == nil && p > 0 {
+		p, err := strconv.Atoi(pageStr)
+		if errors.Is(err, strconv.ErrRange) || p > repository.MaxPage {
+			if h.strictPagination {
+				h.logger.WithFields(map[string]interface{}{
+					"trace_id":   traceID,
+					"page_param": pageStr,
+				}).Warn("Page parameter out of range")
+
+				validationErr := domainerrors.NewPageOutOfRangeError(pageStr, repository.MaxPage)
+				response := h.errorResponse(c, validationErr, traceID)
+				c.JSON(validationErr.HTTPStatus, response)
+				return
+			}
+			// Lenient mode: clamp huge pages to the last allowed page, negative ones to the first
+			if !strings.HasPrefix(pageStr, "-") {
+				page = repository.MaxPage
+				pageClamped = true
+			}
+		} else if err == nil && p > 0 {
 			page = p
 		}
 	}
 
-	limit := 10
-	if limitStr := c.Query("limit"); limitStr != "" {
-		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
-			limit = l
-		}
+	limit := parseListLimit(c)
+
+	filter, ok := h.parseListFilter(c, traceID)
+	if !ok {
+		return
 	}
 
+	sort, err := repository.ParseOrderSort(c.Query("sort"), c.Query("order"))
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id":    traceID,
+			"sort_param":  c.Query("sort"),
+			"order_param": c.Query("order"),
+		}).Warn("Invalid sort parameters")
+
+		response := h.errorResponse(c, err, traceID)
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+	filter.Sort = sort
+
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
+	ctx, collected := warnings.WithCollector(ctx)
+	if pageClamped {
+		warnings.Add(ctx, "page exceeds the maximum of %d; the last allowed page is returned", repository.MaxPage)
+	}
 
-	result, err := h.listOrdersUC.Execute(ctx, page, limit)
+	result, err := h.listOrdersUC.Execute(ctx, page, limit, filter)
 	if err != nil {
 		h.logger.WithError(err).WithFields(map[string]interface{}{
 			"trace_id": traceID,
@@ -229,8 +712,8 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 			"limit":    limit,
 		}).Error("Failed to list orders")
 
-		response := apperrors.ToErrorResponse(err, traceID)
-		statusCode := apperrors.GetHTTPStatus(err)
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
 		c.JSON(statusCode, response)
 		return
 	}
@@ -252,23 +735,375 @@ func (h *OrderHandler) ListOrders(c *gin.Context) {
 	for i, order := range result.Orders {
 		response.Orders[i] = dto.FromDomainOrder(order)
 	}
+	response.Warnings = collected.List()
+
+	c.JSON(http.StatusOK, response)
+}
+
+// CountOrders handles GET /orders/count
+// @Summary      Count orders
+// @Description  Return how many orders match the same filters as the list endpoint, without loading them. Meant for dashboards that only need totals.
+// @Tags         orders
+// @Produce      json
+// @Param        status  query     string  false  "Only orders with this status"
+// @Param        customer  query   string  false  "Only orders whose customer name contains this text (case-insensitive)"
+// @Param        created_from  query  string  false  "Only orders created at or after this RFC3339 timestamp or date"
+// @Param        created_to    query  string  false  "Only orders created at or before this RFC3339 timestamp or date (a date covers the whole day)"
+// @Param        include_deleted  query  bool  false  "Include soft-deleted orders (admin only)"
+// @Param        include_archived  query  bool  false  "Alias of include_deleted"
+// @Success      200     {object}  dto.OrderCountResponse  "Number of matching orders"
+// @Failure      400     {object}  apperrors.ErrorResponse  "Unknown status or invalid date range"
+// @Failure      403     {object}  apperrors.ErrorResponse  "include_deleted requires the admin role"
+// @Failure      500     {object}  apperrors.ErrorResponse  "Internal server error"
+// @Router       /orders/count [get]
+func (h *OrderHandler) CountOrders(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	filter, ok := h.parseListFilter(c, traceID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	count, err := h.countOrdersUC.Execute(ctx, filter)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"status":   filter.Status,
+		}).Error("Failed to count orders")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.OrderCountResponse{Count: count})
+}
+
+// SummarizeOrders handles GET /orders/summary
+// @Summary      Summarize orders by status
+// @Description  Return the number of orders and their revenue in every status, plus grand totals. Every status is present, with zeros when it has no orders. Soft-deleted orders are excluded.
+// @Tags         orders
+// @Produce      json
+// @Success      200     {object}  dto.OrderSummaryResponse  "Per-status counts and revenue"
+// @Failure      500     {object}  apperrors.ErrorResponse   "Internal server error"
+// @Router       /orders/summary [get]
+func (h *OrderHandler) SummarizeOrders(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	summary, err := h.summarizeOrdersUC.Execute(ctx)
+	if err != nil {
+		h.logger.WithError(err).WithField("trace_id", traceID).Error("Failed to summarize orders")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromUseCaseOrderSummary(summary))
+}
+
+// listOrdersByCursor serves GET /orders?cursor=... using keyset pagination. Cursors walk
+// the orders by descending ID only, so sort and order are rejected rather than ignored.
+func (h *OrderHandler) listOrdersByCursor(c *gin.Context, traceID string, cursorStr string) {
+	if c.Query("sort") != "" || c.Query("order") != "" {
+		h.logger.WithFields(map[string]interface{}{
+			"trace_id":    traceID,
+			"sort_param":  c.Query("sort"),
+			"order_param": c.Query("order"),
+		}).Warn("Sort parameters given with cursor")
+
+		validationErr := apperrors.NewValidationError("sort and order cannot be combined with cursor; cursor pages are ordered by descending ID")
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	cursor, err := strconv.ParseInt(cursorStr, 10, 64)
+	if err != nil || cursor < 0 {
+		h.logger.WithFields(map[string]interface{}{
+			"trace_id":     traceID,
+			"cursor_param": cursorStr,
+		}).Warn("Invalid cursor parameter")
+
+		validationErr := apperrors.NewValidationError("Invalid cursor. Must be a non-negative number")
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	limit := parseListLimit(c)
+
+	filter, ok := h.parseListFilter(c, traceID)
+	if !ok {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+	ctx, collected := warnings.WithCollector(ctx)
+
+	result, err := h.listOrdersUC.ExecuteAfter(ctx, cursor, limit, filter)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"cursor":   cursor,
+			"limit":    limit,
+		}).Error("Failed to list orders")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id":     traceID,
+		"cursor":       cursor,
+		"limit":        limit,
+		"orders_count": len(result.Orders),
+		"next_cursor":  result.NextCursor,
+	}).Debug("Successfully listed orders by cursor")
+
+	response := dto.ListOrdersCursorResponse{
+		Orders:     make([]dto.OrderResponse, len(result.Orders)),
+		Pagination: dto.CursorPaginationResponse{Limit: result.Limit},
+	}
+	if result.NextCursor > 0 {
+		response.Pagination.NextCursor = &result.NextCursor
+	}
+	for i, order := range result.Orders {
+		response.Orders[i] = dto.FromDomainOrder(order)
+	}
+	response.Warnings = collected.List()
 
 	c.JSON(http.StatusOK, response)
 }
 
-// UpdateOrderStatus handles PATCH /orders/:id/status
+// parseListLimit reads the limit query parameter, defaulting to 10 when absent or invalid
+func parseListLimit(c *gin.Context) int {
+	limit := 10
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if l, err := strconv.Atoi(limitStr); err == nil && l > 0 {
+			limit = l
+		}
+	}
+	return limit
+}
+
+// dateOnlyLayout is accepted for created_from/created_to in addition to RFC3339
+const dateOnlyLayout = "2006-01-02"
+
+// parseCreatedRange parses the optional created_from/created_to bounds. Both accept RFC3339
+// or a plain date (UTC); a plain created_to covers its whole day.
+func parseCreatedRange(fromStr string, toStr string) (*time.Time, *time.Time, *apperrors.AppError) {
+	parse := func(name string, value string, endOfDay bool) (*time.Time, *apperrors.AppError) {
+		if value == "" {
+			return nil, nil
+		}
+		if t, err := time.Parse(time.RFC3339, value); err == nil {
+			return &t, nil
+		}
+		day, err := time.Parse(dateOnlyLayout, value)
+		if err != nil {
+			return nil, apperrors.NewValidationError("Invalid " + name + ". Must be an RFC3339 timestamp or a YYYY-MM-DD date").WithDetails(map[string]interface{}{
+				"field": name,
+				"value": value,
+			})
+		}
+		if endOfDay {
+			// Postgres keeps microseconds, so stop at the last one of the day
+			day = day.AddDate(0, 0, 1).Add(-time.Microsecond)
+		}
+		return &day, nil
+	}
+
+	from, err := parse("created_from", fromStr, false)
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := parse("created_to", toStr, true)
+	if err != nil {
+		return nil, nil, err
+	}
+	if from != nil && to != nil && from.After(*to) {
+		return nil, nil, apperrors.NewValidationError("created_from must not be after created_to").WithDetails(map[string]interface{}{
+			"created_from": fromStr,
+			"created_to":   toStr,
+		})
+	}
+	return from, to, nil
+}
+
+// parseListFilter builds the listing filter from the query. It writes a 400 for an unknown
+// status or an invalid date range, or a 403 when a non-admin asks for deleted orders, and
+// reports false.
+func (h *OrderHandler) parseListFilter(c *gin.Context, traceID string) (repository.OrderFilter, bool) {
+	var filter repository.OrderFilter
+	if status := c.Query("status"); status != "" {
+		if !entity.IsValidStatus(status) {
+			h.logger.WithFields(map[string]interface{}{
+				"trace_id":     traceID,
+				"status_param": status,
+			}).Warn("Invalid status filter")
+
+			validationErr := apperrors.NewValidationError("Invalid status. Must be one of: " + strings.Join(entity.ValidStatuses, ", ")).WithDetails(map[string]interface{}{
+				"status":         status,
+				"valid_statuses": entity.ValidStatuses,
+			})
+			response := h.errorResponse(c, validationErr, traceID)
+			c.JSON(validationErr.HTTPStatus, response)
+			return filter, false
+		}
+		filter.Status = status
+	}
+	filter.Customer = strings.TrimSpace(c.Query("customer"))
+
+	createdFrom, createdTo, err := parseCreatedRange(c.Query("created_from"), c.Query("created_to"))
+	if err != nil {
+		h.logger.WithError(err).WithField("trace_id", traceID).Warn("Invalid created_at range")
+		response := h.errorResponse(c, err, traceID)
+		c.JSON(err.HTTPStatus, response)
+		return filter, false
+	}
+	filter.CreatedFrom, filter.CreatedTo = createdFrom, createdTo
+
+	includeDeleted, ok := h.parseIncludeDeleted(c, traceID)
+	if !ok {
+		return filter, false
+	}
+	filter.IncludeDeleted = includeDeleted
+	return filter, true
+}
+
+// parseIncludeDeleted reads include_deleted, or its alias include_archived. Only admins may
+// see soft-deleted orders: for anyone else asking, it writes a 403 and reports false.
+func (h *OrderHandler) parseIncludeDeleted(c *gin.Context, traceID string) (bool, bool) {
+	param := "include_deleted"
+	value, ok := c.GetQuery(param)
+	if !ok {
+		param = "include_archived"
+		value = c.Query(param)
+	}
+	if include, err := strconv.ParseBool(value); err != nil || !include {
+		return false, true
+	}
+
+	if !middleware.HasRole(c, middleware.RoleAdmin) {
+		h.logger.WithField("trace_id", traceID).Warn("Non-admin requested deleted orders")
+
+		authErr := apperrors.NewAuthorizationError(param + " requires the admin role")
+		response := h.errorResponse(c, authErr, traceID)
+		c.JSON(authErr.HTTPStatus, response)
+		return false, false
+	}
+	return true, true
+}
+
+// StreamOrders handles GET /orders/stream
+// @Summary      Stream orders
+// @Description  Stream every matching order, newest first, as newline-delimited JSON. A failure after streaming has started is reported as a final {"error": ...} line. Clients that stop reading for longer than STREAM_WRITE_TIMEOUT are disconnected.
+// @Tags         orders
+// @Produce      application/x-ndjson
+// @Param        status           query     string  false  "Only orders with this status"
+// @Param        include_deleted  query     bool    false  "Include soft-deleted orders (admin only)"
+// @Param        include_archived  query    bool    false  "Alias of include_deleted"
+// @Success      200  {object}  dto.OrderResponse        "One order per line"
+// @Failure      403  {object}  apperrors.ErrorResponse  "Admin role required"
+// @Failure      422  {object}  apperrors.ErrorResponse  "Invalid status"
+// @Router       /orders/stream [get]
+func (h *OrderHandler) StreamOrders(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	filter := repository.OrderFilter{Status: c.Query("status")}
+	includeDeleted, ok := h.parseIncludeDeleted(c, traceID)
+	if !ok {
+		return
+	}
+	filter.IncludeDeleted = includeDeleted
+
+	// Cancelling stops the repository and closes its cursor, including when the client stalls
+	ctx, cancel := context.WithCancel(c.Request.Context())
+	defer cancel()
+
+	orders, errs, err := h.streamOrdersUC.Execute(ctx, filter)
+	if err != nil {
+		h.logger.WithError(err).WithField("trace_id", traceID).Warn("Failed to start order stream")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	c.Header("Content-Type", NDJSONContentType)
+	c.Status(http.StatusOK)
+	stream := newChunkWriter(c.Writer, h.streamWriteTimeout)
+
+	streamed := 0
+	for domainOrder := range orders {
+		line, err := json.Marshal(dto.FromDomainOrder(domainOrder))
+		if err != nil {
+			h.logger.WithError(err).WithField("trace_id", traceID).Error("Failed to encode streamed order")
+			continue
+		}
+		if err := stream.WriteChunk(append(line, '\n')); err != nil {
+			h.logger.WithError(err).WithFields(map[string]interface{}{
+				"trace_id":       traceID,
+				"streamed_count": streamed,
+				"write_timeout":  h.streamWriteTimeout.String(),
+			}).Warn("Aborting order stream, client is not accepting data")
+			return
+		}
+		streamed++
+	}
+
+	if err := <-errs; err != nil {
+		if c.Request.Context().Err() != nil {
+			h.logger.WithFields(map[string]interface{}{
+				"trace_id":       traceID,
+				"streamed_count": streamed,
+			}).Warn("Client disconnected during order stream")
+			return
+		}
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id":       traceID,
+			"streamed_count": streamed,
+		}).Error("Order stream failed")
+
+		line, _ := json.Marshal(apperrors.ToErrorResponse(err, traceID))
+		_ = stream.WriteChunk(append(line, '\n'))
+		return
+	}
+	stream.Close()
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id":       traceID,
+		"streamed_count": streamed,
+	}).Debug("Successfully streamed orders")
+}
+
+// UpdateOrderStatus handles PUT /orders/:id/status
 // @Summary      Update order status
-// @Description  Update the status of an existing order
+// @Description  Update the status of an existing order. Requesting the current status succeeds without a change and reports changed=false.
 // @Tags         orders
 // @Accept       json
 // @Produce      json
 // @Param        id      path      int                            true  "Order ID"
 // @Param        status  body      dto.UpdateOrderStatusRequest  true  "Status update request"
-// @Success      200     {object}  dto.SuccessResponse            "Order status updated successfully"
+// @Success      200     {object}  dto.UpdateOrderStatusResponse  "Order status updated successfully"
 // @Failure      400     {object}  apperrors.ErrorResponse              "Invalid request"
 // @Failure      404     {object}  apperrors.ErrorResponse              "Order not found"
+// @Failure      422     {object}  apperrors.ErrorResponse              "Status transition not allowed"
 // @Failure      500     {object}  apperrors.ErrorResponse              "Internal server error"
-// @Router       /orders/{id}/status [patch]
+// @Router       /orders/{id}/status [put]
 func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 	traceID := getTraceID(c)
 
@@ -281,7 +1116,7 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		}).Warn("Invalid order ID parameter")
 
 		validationErr := apperrors.NewValidationError("Invalid order ID. Must be a valid number")
-		response := apperrors.ToErrorResponse(validationErr, traceID)
+		response := h.errorResponse(c, validationErr, traceID)
 		c.JSON(validationErr.HTTPStatus, response)
 		return
 	}
@@ -293,9 +1128,8 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 			"order_id": id,
 		}).Warn("Invalid request body for status update")
 
-		friendlyError := validation.GetOrderValidationMessage(err)
-		validationErr := apperrors.NewValidationError(friendlyError)
-		response := apperrors.ToErrorResponse(validationErr, traceID)
+		validationErr := validation.BindingError(err, &req)
+		response := h.errorResponse(c, validationErr, traceID)
 		c.JSON(validationErr.HTTPStatus, response)
 		return
 	}
@@ -303,7 +1137,7 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
 	defer cancel()
 
-	err = h.updateOrderStatusUC.Execute(ctx, id, req.Status)
+	changed, err := h.updateOrderStatusUC.Execute(ctx, id, req.Status)
 	if err != nil {
 		h.logger.WithError(err).WithFields(map[string]interface{}{
 			"trace_id": traceID,
@@ -311,8 +1145,8 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 			"status":   req.Status,
 		}).Error("Failed to update order status")
 
-		response := apperrors.ToErrorResponse(err, traceID)
-		statusCode := apperrors.GetHTTPStatus(err)
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
 		c.JSON(statusCode, response)
 		return
 	}
@@ -321,7 +1155,234 @@ func (h *OrderHandler) UpdateOrderStatus(c *gin.Context) {
 		"trace_id": traceID,
 		"order_id": id,
 		"status":   req.Status,
+		"changed":  changed,
 	}).Info("Successfully updated order status")
 
-	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Order status updated successfully"})
+	message := "Order status updated successfully"
+	if !changed {
+		message = "Order already has the requested status"
+	}
+	c.JSON(http.StatusOK, dto.UpdateOrderStatusResponse{Message: message, Changed: changed})
+}
+
+// UpdateOrderItems handles PUT /orders/:id/items
+// @Summary      Replace order items
+// @Description  Replace all items of a pending order. The new items follow the same rules as a new order and total_amount is recomputed from their line totals.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id     path      int                          true  "Order ID"
+// @Param        items  body      dto.UpdateOrderItemsRequest  true  "New items"
+// @Success      200    {object}  dto.OrderResponse            "Order with its new items"
+// @Failure      400    {object}  apperrors.ErrorResponse      "Invalid request"
+// @Failure      404    {object}  apperrors.ErrorResponse      "Order not found"
+// @Failure      422    {object}  apperrors.ErrorResponse      "Order is no longer pending or the items violate a business rule"
+// @Failure      500    {object}  apperrors.ErrorResponse      "Internal server error"
+// @Router       /orders/{id}/items [put]
+func (h *OrderHandler) UpdateOrderItems(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"id_param": idStr,
+		}).Warn("Invalid order ID parameter")
+
+		validationErr := apperrors.NewValidationError("Invalid order ID. Must be a valid number")
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	var req dto.UpdateOrderItemsRequest
+	bindErr := c.ShouldBindJSON(&req)
+
+	// As with creates, field rules give more precise details than binding messages
+	var fieldErrs validator.ValidationErrors
+	if bindErr == nil || errors.As(bindErr, &fieldErrs) {
+		if validationErr := validation.ToValidationError(req.Validate()); validationErr != nil {
+			h.logger.WithError(validationErr).WithField("trace_id", traceID).Warn("Order items failed field validation")
+			response := h.errorResponse(c, validationErr, traceID)
+			c.JSON(validationErr.HTTPStatus, response)
+			return
+		}
+	}
+
+	if err := bindErr; err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"order_id": id,
+		}).Warn("Invalid request body for item update")
+
+		validationErr := validation.BindingError(err, &req)
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	updated, err := h.updateOrderItemsUC.Execute(ctx, id, req.ToUseCaseItems())
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id":    traceID,
+			"order_id":    id,
+			"items_count": len(req.Items),
+		}).Error("Failed to update order items")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id":     traceID,
+		"order_id":     id,
+		"items_count":  len(updated.Items),
+		"total_amount": updated.TotalAmount,
+	}).Info("Successfully updated order items")
+
+	c.JSON(http.StatusOK, dto.FromDomainOrder(updated))
+}
+
+// PatchOrder handles PATCH /orders/:id
+// @Summary      Partially update an order
+// @Description  Apply a JSON Merge Patch (RFC 7396) to the mutable order fields. Only status can be patched. The response carries only the changed fields plus updated_at.
+// @Tags         orders
+// @Accept       application/merge-patch+json
+// @Produce      json
+// @Param        id     path      int                       true  "Order ID"
+// @Param        patch  body      dto.OrderPatchRequest     true  "Fields to change"
+// @Success      200    {object}  dto.OrderPatchResponse    "Changed fields"
+// @Failure      400    {object}  apperrors.ErrorResponse   "Invalid patch or non-mutable field"
+// @Failure      404    {object}  apperrors.ErrorResponse   "Order not found"
+// @Failure      415    {object}  apperrors.ErrorResponse   "Content-Type is not application/merge-patch+json"
+// @Failure      422    {object}  apperrors.ErrorResponse   "Status transition not allowed"
+// @Failure      500    {object}  apperrors.ErrorResponse   "Internal server error"
+// @Router       /orders/{id} [patch]
+func (h *OrderHandler) PatchOrder(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	if c.ContentType() != dto.MergePatchContentType {
+		mediaErr := apperrors.NewBadRequestError("Content-Type must be " + dto.MergePatchContentType)
+		response := h.errorResponse(c, mediaErr, traceID)
+		c.JSON(http.StatusUnsupportedMediaType, response)
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		validationErr := apperrors.NewValidationError("Invalid order ID. Must be a valid number")
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	body, err := c.GetRawData()
+	if err != nil {
+		validationErr := apperrors.NewValidationError("Failed to read request body")
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			validationErr = middleware.BodyTooLargeError(tooLarge.Limit)
+		}
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	patch, err := dto.ParseOrderMergePatch(body)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"order_id": id,
+		}).Warn("Invalid merge patch")
+
+		response := h.errorResponse(c, err, traceID)
+		c.JSON(errorStatus(c, err), response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	result, err := h.patchOrderUC.Execute(ctx, id, patch)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"order_id": id,
+		}).Error("Failed to patch order")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, dto.FromPatchOrderResult(result))
+}
+
+// DeleteOrder handles DELETE /orders/:id
+// @Summary      Delete an order
+// @Description  Mark an order as deleted. The order is hidden from lookups and default listings but kept for recovery, unless ORDER_HARD_DELETE removes it permanently. Completed orders cannot be deleted. Requires the admin role.
+// @Tags         orders
+// @Accept       json
+// @Produce      json
+// @Param        id   path      int                       true  "Order ID"
+// @Success      200  {object}  dto.SuccessResponse       "Order deleted successfully"
+// @Failure      400  {object}  apperrors.ErrorResponse   "Invalid order ID"
+// @Failure      403  {object}  apperrors.ErrorResponse   "Admin role required"
+// @Failure      404  {object}  apperrors.ErrorResponse   "Order not found"
+// @Failure      422  {object}  apperrors.ErrorResponse   "Completed orders cannot be deleted"
+// @Failure      500  {object}  apperrors.ErrorResponse   "Internal server error"
+// @Router       /orders/{id} [delete]
+func (h *OrderHandler) DeleteOrder(c *gin.Context) {
+	traceID := getTraceID(c)
+
+	if !middleware.HasRole(c, middleware.RoleAdmin) {
+		authErr := apperrors.NewAuthorizationError("deleting orders requires the admin role")
+		response := h.errorResponse(c, authErr, traceID)
+		c.JSON(authErr.HTTPStatus, response)
+		return
+	}
+
+	idStr := c.Param("id")
+	id, err := strconv.ParseInt(idStr, 10, 64)
+	if err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"id_param": idStr,
+		}).Warn("Invalid order ID parameter")
+
+		validationErr := apperrors.NewValidationError("Invalid order ID. Must be a valid number")
+		response := h.errorResponse(c, validationErr, traceID)
+		c.JSON(validationErr.HTTPStatus, response)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	if err := h.deleteOrderUC.Execute(ctx, id); err != nil {
+		h.logger.WithError(err).WithFields(map[string]interface{}{
+			"trace_id": traceID,
+			"order_id": id,
+		}).Error("Failed to delete order")
+
+		response := h.errorResponse(c, err, traceID)
+		statusCode := errorStatus(c, err)
+		c.JSON(statusCode, response)
+		return
+	}
+
+	h.logger.WithFields(map[string]interface{}{
+		"trace_id": traceID,
+		"order_id": id,
+	}).Info("Successfully deleted order")
+
+	c.JSON(http.StatusOK, dto.SuccessResponse{Message: "Order deleted successfully"})
 }
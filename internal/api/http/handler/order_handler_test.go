@@ -0,0 +1,1713 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/api/http/handler/dto"
+	"online-order-management-system/internal/api/validation"
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/infra/memory"
+	"online-order-management-system/internal/infra/webhook"
+	"online-order-management-system/internal/middleware"
+	"online-order-management-system/internal/usecase/order"
+	"online-order-management-system/pkg/errorlog"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/events"
+	"online-order-management-system/pkg/retryutil"
+
+	"github.com/gin-gonic/gin"
+)
+
+const testAdminKey = "test-admin-key"
+
+// newTestRouter wires an OrderHandler to an in-memory repository
+func newTestRouter(repo repository.OrderRepository, opts ...HandlerOption) *gin.Engine {
+	return newTestRouterWithUseCases(newTestUseCases(repo), opts...)
+}
+
+// newTestUseCases builds every use case on repo with default options
+func newTestUseCases(repo repository.OrderRepository) OrderUseCases {
+	createOrderUC := order.NewCreateOrderUseCase(repo)
+	return OrderUseCases{
+		CreateOrder:         createOrderUC,
+		BulkCreateOrders:    order.NewBulkCreateOrdersUseCase(createOrderUC),
+		GetOrder:            order.NewGetOrderUseCase(repo),
+		GetOrderByReference: order.NewGetOrderByReferenceUseCase(repo),
+		GetOrderStatuses:    order.NewGetOrderStatusesUseCase(repo),
+		ListOrders:          order.NewListOrdersUseCase(repo),
+		CountOrders:         order.NewCountOrdersUseCase(repo),
+		SummarizeOrders:     order.NewSummarizeOrdersUseCase(repo),
+		UpdateOrderStatus:   order.NewUpdateOrderStatusUseCase(repo),
+		UpdateOrderItems:    order.NewUpdateOrderItemsUseCase(repo),
+		PatchOrder:          order.NewPatchOrderUseCase(repo),
+		DeleteOrder:         order.NewDeleteOrderUseCase(repo),
+		GetOrderTimeline:    order.NewGetOrderTimelineUseCase(repo),
+		StreamOrders:        order.NewStreamOrdersUseCase(repo),
+	}
+}
+
+// newTestRouterWithUseCases routes to useCases through the middleware of newTestRouter
+func newTestRouterWithUseCases(useCases OrderUseCases, opts ...HandlerOption) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewOrderHandler(useCases, opts...)
+	router := gin.New()
+	router.Use(middleware.APIVersionMiddleware())
+	router.Use(middleware.AdminKeyMiddleware(testAdminKey))
+	router.Use(middleware.DryRunMiddleware())
+	h.RegisterRoutes(router)
+	return router
+}
+
+func doRequest(router *gin.Engine, method, path, body string) *httptest.ResponseRecorder {
+	return doRequestWithHeaders(router, method, path, body, nil)
+}
+
+func doRequestWithHeaders(router *gin.Engine, method, path, body string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	if body != "" {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func createOrderBody(reference string) string {
+	return `{"customer_name":"Acme Corp","client_reference":"` + reference + `",` +
+		`"items":[{"product_name":"Widget","quantity":2,"unit_price":10}]}`
+}
+
+func TestCreateOrder_EchoesClientReference(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-1001"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var created dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.ClientReference != "PO-1001" {
+		t.Errorf("expected client_reference PO-1001, got %q", created.ClientReference)
+	}
+
+	w = doRequest(router, http.MethodGet, "/orders/1", "")
+	var fetched dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &fetched); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if fetched.ClientReference != "PO-1001" {
+		t.Errorf("expected stored client_reference PO-1001, got %q", fetched.ClientReference)
+	}
+}
+
+func TestCreateOrder_MeasuredQuantities(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	body := `{"customer_name":"Acme Corp","items":[` +
+		`{"product_name":"Coffee beans","quantity":1.5,"unit":"kg","unit_price":24},` +
+		`{"product_name":"Mug","quantity":2,"unit_price":8.5}]}`
+	w := doRequest(router, http.MethodPost, "/orders", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Items[0].Quantity != 1.5 || created.Items[0].Unit != "kg" || created.Items[0].TotalPrice != 36 {
+		t.Errorf("expected 1.5 kg totalling 36, got %+v", created.Items[0])
+	}
+	if created.Items[1].Unit != "" || created.TotalAmount != 53 {
+		t.Errorf("expected a counted second item and total 53, got %+v (total %v)", created.Items[1], created.TotalAmount)
+	}
+
+	t.Run("fractional quantity needs a unit", func(t *testing.T) {
+		body := `{"customer_name":"Acme Corp","items":[{"product_name":"Mug","quantity":1.5,"unit_price":8.5}]}`
+		w := doRequest(router, http.MethodPost, "/orders", body)
+		if w.Code != http.StatusBadRequest {
+			t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "whole number") {
+			t.Errorf("expected a whole-number message, got %s", w.Body.String())
+		}
+	})
+}
+
+func TestCreateOrder_Paid(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequest(router, http.MethodPost, "/orders?paid=true", createOrderBody(""))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Status != "paid" {
+		t.Errorf("expected status paid, got %s", created.Status)
+	}
+
+	w = doRequest(router, http.MethodGet, "/orders/1/timeline", "")
+	if !strings.Contains(w.Body.String(), `"to_status":"paid"`) {
+		t.Errorf("expected a paid transition in the timeline, got %s", w.Body.String())
+	}
+
+	if w := doRequest(router, http.MethodPost, "/orders?paid=maybe", createOrderBody("")); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an invalid paid parameter, got %d", w.Code)
+	}
+}
+
+func TestGetOrderByReference(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-2001"))
+	doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-2002"))
+
+	w := doRequest(router, http.MethodGet, "/orders/by-reference/PO-2002", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var found dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &found); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if found.ID != 2 || found.ClientReference != "PO-2002" {
+		t.Errorf("expected order 2 with PO-2002, got order %d with %q", found.ID, found.ClientReference)
+	}
+
+	w = doRequest(router, http.MethodGet, "/orders/by-reference/PO-missing", "")
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expected 404 for unknown reference, got %d", w.Code)
+	}
+}
+
+func TestCreateOrder_DuplicateClientReference(t *testing.T) {
+	t.Run("allowed by default", func(t *testing.T) {
+		router := newTestRouter(memory.NewInMemoryOrderRepository())
+		doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-3001"))
+
+		w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-3001"))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+
+	t.Run("rejected when unique", func(t *testing.T) {
+		router := newTestRouter(memory.NewInMemoryOrderRepository(memory.WithUniqueClientReference(true)))
+		doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-3001"))
+
+		w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-3001"))
+		if w.Code != http.StatusConflict {
+			t.Fatalf("expected 409, got %d: %s", w.Code, w.Body.String())
+		}
+
+		var response apperrors.ErrorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Error.Code != apperrors.ErrCodeAlreadyExists {
+			t.Errorf("expected %s, got %s", apperrors.ErrCodeAlreadyExists, response.Error.Code)
+		}
+		if existing, _ := response.Error.Details["existing_order_id"].(float64); existing != 1 {
+			t.Errorf("expected existing_order_id 1, got %v", response.Error.Details["existing_order_id"])
+		}
+	})
+}
+
+// faultyOrderRepository fails the first creates with a retryable connection error,
+// retrying through RetryWithBackoff the way the Postgres repository does
+type faultyOrderRepository struct {
+	*memory.InMemoryOrderRepository
+	failures int
+}
+
+func (r *faultyOrderRepository) CreateOrderWithItems(ctx context.Context, o *entity.Order) (*entity.Order, error) {
+	config := retryutil.DefaultRetryConfig()
+	config.BaseDelay = time.Millisecond
+	config.OnRetry = func(int, error) { retryutil.RecordRetry(ctx) }
+
+	var created *entity.Order
+	err := retryutil.RetryWithBackoff(ctx, config, func() error {
+		if r.failures > 0 {
+			r.failures--
+			return errors.New("dial tcp: connection refused")
+		}
+		var err error
+		created, err = r.InMemoryOrderRepository.CreateOrderWithItems(ctx, o)
+		return err
+	})
+	return created, err
+}
+
+func TestCreateOrder_RetriesHeader(t *testing.T) {
+	t.Run("reports retries when enabled", func(t *testing.T) {
+		repo := &faultyOrderRepository{InMemoryOrderRepository: memory.NewInMemoryOrderRepository(), failures: 1}
+		router := newTestRouter(repo, WithRetryHeader(true))
+
+		w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-5001"))
+		if w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+		if got := w.Header().Get(RetriesHeader); got != "1" {
+			t.Errorf("expected %s: 1, got %q", RetriesHeader, got)
+		}
+	})
+
+	t.Run("omitted without retries", func(t *testing.T) {
+		repo := &faultyOrderRepository{InMemoryOrderRepository: memory.NewInMemoryOrderRepository()}
+		router := newTestRouter(repo, WithRetryHeader(true))
+
+		w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-5002"))
+		if got := w.Header().Get(RetriesHeader); got != "" {
+			t.Errorf("expected no %s header, got %q", RetriesHeader, got)
+		}
+	})
+
+	t.Run("omitted when disabled", func(t *testing.T) {
+		repo := &faultyOrderRepository{InMemoryOrderRepository: memory.NewInMemoryOrderRepository(), failures: 1}
+		router := newTestRouter(repo)
+
+		w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-5003"))
+		if got := w.Header().Get(RetriesHeader); got != "" {
+			t.Errorf("expected no %s header, got %q", RetriesHeader, got)
+		}
+	})
+}
+
+// contextErrorRepository fails every order lookup with err, the way the Postgres
+// repository reports an ended request context
+type contextErrorRepository struct {
+	*memory.InMemoryOrderRepository
+	err error
+}
+
+func (r *contextErrorRepository) GetOrderByID(ctx context.Context, id int64) (*entity.Order, error) {
+	return nil, r.err
+}
+
+func TestGetOrder_ContextErrorStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		status int
+		code   apperrors.ErrorCode
+	}{
+		{"client went away", apperrors.ContextError(context.Canceled, "cancelled"), apperrors.StatusClientClosedRequest, apperrors.ErrCodeClientClosedRequest},
+		{"deadline exceeded", apperrors.ContextError(context.DeadlineExceeded, "timed out"), http.StatusGatewayTimeout, apperrors.ErrCodeTimeout},
+		{"bare deadline", fmt.Errorf("query: %w", context.DeadlineExceeded), http.StatusGatewayTimeout, apperrors.ErrCodeTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &contextErrorRepository{InMemoryOrderRepository: memory.NewInMemoryOrderRepository(), err: tt.err}
+			router := newTestRouter(repo)
+
+			w := doRequest(router, http.MethodGet, "/orders/1", "")
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			var response apperrors.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Error.Code != tt.code {
+				t.Errorf("expected code %s, got %s", tt.code, response.Error.Code)
+			}
+		})
+	}
+}
+
+func TestListOrders_PageOverflow(t *testing.T) {
+	const overflowingPage = "99999999999999999999"
+
+	t.Run("strict mode rejects", func(t *testing.T) {
+		router := newTestRouter(memory.NewInMemoryOrderRepository(), WithStrictPagination(true))
+
+		for _, page := range []string{overflowingPage, "-" + overflowingPage, "1000001"} {
+			w := doRequest(router, http.MethodGet, "/orders?page="+page, "")
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("page %s: expected 400, got %d", page, w.Code)
+			}
+		}
+	})
+
+	t.Run("lenient mode clamps", func(t *testing.T) {
+		router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+		w := doRequest(router, http.MethodGet, "/orders?page="+overflowingPage, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response dto.ListOrdersResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Pagination.CurrentPage != repository.MaxPage {
+			t.Errorf("expected page clamped to %d, got %d", repository.MaxPage, response.Pagination.CurrentPage)
+		}
+
+		w = doRequest(router, http.MethodGet, "/orders?page=-"+overflowingPage, "")
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if response.Pagination.CurrentPage != 1 {
+			t.Errorf("expected negative overflow clamped to page 1, got %d", response.Pagination.CurrentPage)
+		}
+	})
+}
+
+func TestListOrders_Cursor(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	for _, ref := range []string{"PO-6101", "PO-6102", "PO-6103", "PO-6104", "PO-6105"} {
+		if w := doRequest(router, http.MethodPost, "/orders", createOrderBody(ref)); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	var seen []int64
+	path := "/orders?cursor=0&limit=2"
+	for pages := 0; pages < 5; pages++ {
+		w := doRequest(router, http.MethodGet, path, "")
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response dto.ListOrdersCursorResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		for _, o := range response.Orders {
+			seen = append(seen, o.ID)
+		}
+		if response.Pagination.NextCursor == nil {
+			break
+		}
+		path = fmt.Sprintf("/orders?cursor=%d&limit=2", *response.Pagination.NextCursor)
+	}
+
+	if want := []int64{5, 4, 3, 2, 1}; !reflect.DeepEqual(seen, want) {
+		t.Errorf("expected orders %v across cursor pages, got %v", want, seen)
+	}
+
+	t.Run("page path kept without cursor", func(t *testing.T) {
+		w := doRequest(router, http.MethodGet, "/orders?limit=2", "")
+		var body struct {
+			Pagination map[string]json.RawMessage `json:"pagination"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		if _, ok := body.Pagination["total_count"]; !ok {
+			t.Errorf("expected page-based pagination, got %v", body.Pagination)
+		}
+	})
+
+	t.Run("invalid cursor rejected", func(t *testing.T) {
+		for _, cursor := range []string{"abc", "-1"} {
+			w := doRequest(router, http.MethodGet, "/orders?cursor="+cursor, "")
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("cursor %s: expected 400, got %d", cursor, w.Code)
+			}
+		}
+	})
+
+	t.Run("sort with cursor rejected", func(t *testing.T) {
+		for _, query := range []string{"sort=total_amount", "order=asc", "sort=id&order=desc"} {
+			w := doRequest(router, http.MethodGet, "/orders?cursor=0&"+query, "")
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s: expected 400, got %d", query, w.Code)
+			}
+		}
+	})
+}
+
+func TestListOrders_StatusFilter(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	for _, ref := range []string{"PO-6201", "PO-6202", "PO-6203"} {
+		doRequest(router, http.MethodPost, "/orders", createOrderBody(ref))
+	}
+	if w := doRequest(router, http.MethodPut, "/orders/2/status", `{"status":"cancelled"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status update to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		query     string
+		wantIDs   []int64
+		wantTotal int64
+	}{
+		{query: "", wantIDs: []int64{3, 2, 1}, wantTotal: 3},
+		{query: "?status=pending", wantIDs: []int64{3, 1}, wantTotal: 2},
+		{query: "?status=cancelled", wantIDs: []int64{2}, wantTotal: 1},
+		{query: "?status=completed", wantIDs: []int64{}, wantTotal: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := doRequest(router, http.MethodGet, "/orders"+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response dto.ListOrdersResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			ids := make([]int64, 0, len(response.Orders))
+			for _, o := range response.Orders {
+				ids = append(ids, o.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || response.Pagination.TotalCount != tt.wantTotal {
+				t.Errorf("expected orders %v (total %d), got %v (total %d)", tt.wantIDs, tt.wantTotal, ids, response.Pagination.TotalCount)
+			}
+		})
+	}
+
+	t.Run("unknown status rejected", func(t *testing.T) {
+		w := doRequest(router, http.MethodGet, "/orders?status=refunded", "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestCountOrders(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	for _, ref := range []string{"PO-6301", "PO-6302", "PO-6303"} {
+		doRequest(router, http.MethodPost, "/orders", createOrderBody(ref))
+	}
+	if w := doRequest(router, http.MethodPut, "/orders/2/status", `{"status":"cancelled"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status update to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		query string
+		want  int64
+	}{
+		{query: "", want: 3},
+		{query: "?status=pending", want: 2},
+		{query: "?status=cancelled", want: 1},
+		{query: "?status=completed", want: 0},
+		{query: "?customer=nobody", want: 0},
+		{query: "?created_to=2000-01-01", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := doRequest(router, http.MethodGet, "/orders/count"+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response dto.OrderCountResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Count != tt.want {
+				t.Errorf("expected count %d, got %d", tt.want, response.Count)
+			}
+		})
+	}
+
+	t.Run("unknown status rejected", func(t *testing.T) {
+		w := doRequest(router, http.MethodGet, "/orders/count?status=refunded", "")
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("expected 400, got %d: %s", w.Code, w.Body.String())
+		}
+	})
+}
+
+func TestSummarizeOrders(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	bodies := []string{
+		createOrderBody("PO-6401"), // 20.00
+		createOrderBody("PO-6402"),
+		`{"customer_name":"Globex","items":[{"product_name":"Gadget","quantity":3,"unit_price":0.1}]}`, // 0.30
+	}
+	for _, body := range bodies {
+		if w := doRequest(router, http.MethodPost, "/orders", body); w.Code != http.StatusCreated {
+			t.Fatalf("expected create to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	if w := doRequest(router, http.MethodPut, "/orders/2/status", `{"status":"cancelled"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected status update to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodGet, "/orders/summary", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response dto.OrderSummaryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	want := map[string]dto.StatusSummaryResponse{
+		"pending":    {Count: 2, Revenue: 20.3},
+		"paid":       {},
+		"processing": {},
+		"shipped":    {},
+		"completed":  {},
+		"cancelled":  {Count: 1, Revenue: 20},
+	}
+	if !reflect.DeepEqual(response.Statuses, want) {
+		t.Errorf("expected per-status summary %v, got %v", want, response.Statuses)
+	}
+	if response.Total != (dto.StatusSummaryResponse{Count: 3, Revenue: 40.3}) {
+		t.Errorf("expected totals of 3 orders and 40.30, got %+v", response.Total)
+	}
+}
+
+func TestSummarizeOrders_EmptyKeepsEveryStatus(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequest(router, http.MethodGet, "/orders/summary", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response dto.OrderSummaryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(response.Statuses) != len(entity.ValidStatuses) {
+		t.Errorf("expected an entry for each of %v, got %v", entity.ValidStatuses, response.Statuses)
+	}
+	if response.Total != (dto.StatusSummaryResponse{}) {
+		t.Errorf("expected zero totals, got %+v", response.Total)
+	}
+}
+
+func TestListOrders_CreatedRange(t *testing.T) {
+	// Orders are created on consecutive days at noon UTC: Mar 1, 2, 3 and 4
+	day := time.Date(2024, 2, 29, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		day = day.AddDate(0, 0, 1)
+		return day
+	}
+	router := newTestRouter(memory.NewInMemoryOrderRepository(memory.WithClock(clock)))
+	for _, ref := range []string{"PO-6301", "PO-6302", "PO-6303", "PO-6304"} {
+		if w := doRequest(router, http.MethodPost, "/orders", createOrderBody(ref)); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	tests := []struct {
+		query   string
+		wantIDs []int64
+	}{
+		{query: "", wantIDs: []int64{4, 3, 2, 1}},
+		{query: "?created_from=2024-03-02T00:00:00Z", wantIDs: []int64{4, 3, 2}},
+		{query: "?created_to=2024-03-02T12:00:00Z", wantIDs: []int64{2, 1}},
+		{query: "?created_from=2024-03-02&created_to=2024-03-03", wantIDs: []int64{3, 2}},
+		{query: "?created_to=2024-03-03T11:59:59Z", wantIDs: []int64{2, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := doRequest(router, http.MethodGet, "/orders"+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response dto.ListOrdersResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			ids := make([]int64, 0, len(response.Orders))
+			for _, o := range response.Orders {
+				ids = append(ids, o.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) || response.Pagination.TotalCount != int64(len(tt.wantIDs)) {
+				t.Errorf("expected orders %v, got %v (total %d)", tt.wantIDs, ids, response.Pagination.TotalCount)
+			}
+		})
+	}
+
+	for _, query := range []string{"?created_from=2024-03-03&created_to=2024-03-02", "?created_from=yesterday"} {
+		if w := doRequest(router, http.MethodGet, "/orders"+query, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestCreateOrder_IdempotencyKeyTooLong(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	headers := map[string]string{IdempotencyKeyHeader: strings.Repeat("k", MaxIdempotencyKeyLength+1)}
+	if w := doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-6501"), headers); w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for an oversized key, got %d: %s", w.Code, w.Body.String())
+	}
+
+	headers[IdempotencyKeyHeader] = strings.Repeat("k", MaxIdempotencyKeyLength)
+	if w := doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-6502"), headers); w.Code != http.StatusCreated {
+		t.Errorf("expected 201 for a key at the limit, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateOrder_IdempotencyKeyReplaysWith200(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	h := NewOrderHandler(OrderUseCases{
+		CreateOrder: order.NewCreateOrderUseCase(repo, order.WithIdempotencyKeyTTL(time.Hour)),
+	})
+	router := gin.New()
+	h.RegisterRoutes(router)
+	headers := map[string]string{IdempotencyKeyHeader: "checkout-7f3a"}
+
+	w := doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-6503"), headers)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201 for the first request, got %d: %s", w.Code, w.Body.String())
+	}
+	var first dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &first); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	w = doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-6503"), headers)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a replay, got %d: %s", w.Code, w.Body.String())
+	}
+	var replayed dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &replayed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if replayed.ID != first.ID {
+		t.Errorf("expected the original order %d, got %d", first.ID, replayed.ID)
+	}
+}
+
+func TestUpdateOrderItems(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-6601")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	body := `{"items":[{"product_name":"Widget","quantity":3,"unit_price":10},` +
+		`{"product_name":"Rope","quantity":2.5,"unit":"m","unit_price":4}]}`
+	w := doRequest(router, http.MethodPut, "/orders/1/items", body)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var updated dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &updated); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(updated.Items) != 2 || updated.TotalAmount != 40 || updated.Breakdown.Subtotal != updated.TotalAmount {
+		t.Errorf("expected 2 items totalling 40, got %d items totalling %v", len(updated.Items), updated.TotalAmount)
+	}
+
+	tests := []struct {
+		name string
+		path string
+		body string
+		want int
+	}{
+		{name: "no items", path: "/orders/1/items", body: `{"items":[]}`, want: http.StatusBadRequest},
+		{name: "fractional counted quantity", path: "/orders/1/items", body: `{"items":[{"product_name":"Widget","quantity":1.5,"unit_price":10}]}`, want: http.StatusBadRequest},
+		{name: "invalid id", path: "/orders/abc/items", body: body, want: http.StatusBadRequest},
+		{name: "unknown order", path: "/orders/42/items", body: body, want: http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := doRequest(router, http.MethodPut, tt.path, tt.body); w.Code != tt.want {
+				t.Errorf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+		})
+	}
+
+	if w := doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"processing"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := doRequest(router, http.MethodPut, "/orders/1/items", body); w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 once the order is processing, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestErrorResponses_UseAppErrorEnvelope(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-6701")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"cancelled"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		want   int
+	}{
+		{http.MethodPost, "/orders", `{"customer_name":""}`, http.StatusBadRequest},
+		{http.MethodPost, "/orders", `not json`, http.StatusBadRequest},
+		{http.MethodPost, "/orders?paid=maybe", createOrderBody("PO-6702"), http.StatusBadRequest},
+		{http.MethodPost, "/orders/bulk", `{"orders":[]}`, http.StatusBadRequest},
+		{http.MethodPost, "/orders/statuses", `{"ids":[]}`, http.StatusBadRequest},
+		{http.MethodGet, "/orders?cursor=-1", "", http.StatusBadRequest},
+		{http.MethodGet, "/orders?status=refunded", "", http.StatusBadRequest},
+		{http.MethodGet, "/orders?sort=customer_name", "", http.StatusBadRequest},
+		{http.MethodGet, "/orders?include_deleted=true", "", http.StatusForbidden},
+		{http.MethodGet, "/orders/abc", "", http.StatusBadRequest},
+		{http.MethodGet, "/orders/42", "", http.StatusNotFound},
+		{http.MethodGet, "/orders/42/timeline", "", http.StatusNotFound},
+		{http.MethodGet, "/orders/by-reference/PO-0000", "", http.StatusNotFound},
+		{http.MethodPut, "/orders/1/status", `{"status":"refunded"}`, http.StatusBadRequest},
+		{http.MethodPut, "/orders/1/status", `{"status":"processing"}`, http.StatusUnprocessableEntity},
+		{http.MethodPut, "/orders/1/items", `{"items":[{"product_name":"Widget","quantity":1,"unit_price":1}]}`, http.StatusUnprocessableEntity},
+		{http.MethodPatch, "/orders/1", `{"status":"processing"}`, http.StatusUnsupportedMediaType},
+		{http.MethodDelete, "/orders/1", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			w := doRequest(router, tt.method, tt.path, tt.body)
+			if w.Code != tt.want {
+				t.Fatalf("expected %d, got %d: %s", tt.want, w.Code, w.Body.String())
+			}
+			var response apperrors.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("expected the error envelope, got %s: %v", w.Body.String(), err)
+			}
+			if response.Error.Code == "" || response.Error.Message == "" {
+				t.Errorf("expected an error code and message, got %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestCreateOrder_CustomerEmail(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	body := `{"customer_name":"Acme Corp","customer_email":" buyer@acme.example ",` +
+		`"items":[{"product_name":"Widget","quantity":1,"unit_price":10}]}`
+	w := doRequest(router, http.MethodPost, "/orders", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.CustomerEmail != "buyer@acme.example" {
+		t.Errorf("expected the trimmed email, got %q", created.CustomerEmail)
+	}
+
+	for _, email := range []string{"buyer", "buyer@acme", "Buyer <buyer@acme.example>"} {
+		body := `{"customer_name":"Acme Corp","customer_email":"` + email + `",` +
+			`"items":[{"product_name":"Widget","quantity":1,"unit_price":10}]}`
+		w := doRequest(router, http.MethodPost, "/orders", body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected 400, got %d", email, w.Code)
+			continue
+		}
+		if !strings.Contains(w.Body.String(), "customer_email") {
+			t.Errorf("%q: expected the error to name customer_email, got %s", email, w.Body.String())
+		}
+	}
+}
+
+func TestCreateOrder_ItemDiscount(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	body := `{"customer_name":"Acme Corp","items":[` +
+		`{"product_name":"Widget","quantity":3,"unit_price":19.99,"discount_percent":12.5},` +
+		`{"product_name":"Gadget","quantity":1,"unit_price":10}]}`
+	w := doRequest(router, http.MethodPost, "/orders", body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.Items[0].DiscountPercent != 12.5 || created.Items[0].TotalPrice != 52.47 {
+		t.Errorf("expected a discounted line total of 52.47, got %+v", created.Items[0])
+	}
+	if created.TotalAmount != 62.47 {
+		t.Errorf("expected the order total to use discounted lines, got %v", created.TotalAmount)
+	}
+
+	for _, discount := range []string{"-1", "100.5", "12.345"} {
+		body := `{"customer_name":"Acme Corp","items":[` +
+			`{"product_name":"Widget","quantity":1,"unit_price":10,"discount_percent":` + discount + `}]}`
+		w := doRequest(router, http.MethodPost, "/orders", body)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", discount, w.Code)
+			continue
+		}
+		if !strings.Contains(w.Body.String(), "discount_percent") {
+			t.Errorf("%s: expected the error to name the discount, got %s", discount, w.Body.String())
+		}
+	}
+}
+
+func TestListOrders_Sort(t *testing.T) {
+	// The clock runs backwards so that creation order and ID order disagree
+	day := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time {
+		day = day.AddDate(0, 0, -1)
+		return day
+	}
+	router := newTestRouter(memory.NewInMemoryOrderRepository(memory.WithClock(clock)))
+	// Orders 1 and 3 share a total, so total_amount has to fall back to ID
+	for i, price := range []int{30, 10, 30, 20} {
+		body := fmt.Sprintf(`{"customer_name":"Acme Corp","client_reference":"PO-64%02d",`+
+			`"items":[{"product_name":"Widget","quantity":1,"unit_price":%d}]}`, i, price)
+		if w := doRequest(router, http.MethodPost, "/orders", body); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+
+	tests := []struct {
+		query   string
+		wantIDs []int64
+	}{
+		{query: "", wantIDs: []int64{1, 2, 3, 4}},
+		{query: "?sort=created_at&order=asc", wantIDs: []int64{4, 3, 2, 1}},
+		{query: "?sort=total_amount", wantIDs: []int64{3, 1, 4, 2}},
+		{query: "?sort=total_amount&order=asc", wantIDs: []int64{2, 4, 1, 3}},
+		{query: "?sort=id", wantIDs: []int64{4, 3, 2, 1}},
+		{query: "?sort=id&order=ASC", wantIDs: []int64{1, 2, 3, 4}},
+		{query: "?sort=total_amount&limit=2&page=2", wantIDs: []int64{4, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			w := doRequest(router, http.MethodGet, "/orders"+tt.query, "")
+			if w.Code != http.StatusOK {
+				t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+			}
+			var response dto.ListOrdersResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			ids := make([]int64, 0, len(response.Orders))
+			for _, o := range response.Orders {
+				ids = append(ids, o.ID)
+			}
+			if !reflect.DeepEqual(ids, tt.wantIDs) {
+				t.Errorf("expected orders %v, got %v", tt.wantIDs, ids)
+			}
+		})
+	}
+
+	for _, query := range []string{"?sort=customer_name", "?sort=id%3BDROP%20TABLE%20orders", "?order=up"} {
+		if w := doRequest(router, http.MethodGet, "/orders"+query, ""); w.Code != http.StatusBadRequest {
+			t.Errorf("%s: expected 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestErrorLog_RecordsFailedRequests(t *testing.T) {
+	ring := errorlog.NewRing(2)
+	router := newTestRouter(memory.NewInMemoryOrderRepository(), WithErrorLog(ring))
+
+	doRequest(router, http.MethodGet, "/orders/abc", "")
+	doRequest(router, http.MethodGet, "/orders/42", "")
+	if w := doRequest(router, http.MethodGet, "/orders?status=refunded", ""); w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	doRequest(router, http.MethodGet, "/orders", "") // Successes are not recorded
+
+	entries := ring.Entries()
+	if len(entries) != 2 {
+		t.Fatalf("expected the log to cap at 2 entries, got %+v", entries)
+	}
+	if entries[0].Code != string(apperrors.ErrCodeNotFound) || entries[1].Code != string(apperrors.ErrCodeValidation) {
+		t.Errorf("expected the not-found then validation errors, oldest first, got %+v", entries)
+	}
+	if !strings.Contains(entries[1].Message, "Invalid status") {
+		t.Errorf("expected the surfaced message to be recorded, got %q", entries[1].Message)
+	}
+}
+
+func TestListOrders_IncludeDeleted(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	admin := map[string]string{middleware.AdminKeyHeader: testAdminKey}
+	for _, ref := range []string{"PO-6001", "PO-6002", "PO-6003"} {
+		doRequest(router, http.MethodPost, "/orders", createOrderBody(ref))
+	}
+	if w := doRequestWithHeaders(router, http.MethodDelete, "/orders/2", "", admin); w.Code != http.StatusOK {
+		t.Fatalf("expected delete to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	listOrders := func(path string, headers map[string]string) dto.ListOrdersResponse {
+		t.Helper()
+		w := doRequestWithHeaders(router, http.MethodGet, path, "", headers)
+		if w.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response dto.ListOrdersResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("failed to decode response: %v", err)
+		}
+		return response
+	}
+
+	t.Run("excluded by default", func(t *testing.T) {
+		response := listOrders("/orders", admin)
+		if len(response.Orders) != 2 || response.Pagination.TotalCount != 2 {
+			t.Fatalf("expected 2 live orders, got %d (total %d)", len(response.Orders), response.Pagination.TotalCount)
+		}
+		for _, o := range response.Orders {
+			if o.ID == 2 {
+				t.Error("expected deleted order to be excluded")
+			}
+		}
+	})
+
+	t.Run("included for admins", func(t *testing.T) {
+		response := listOrders("/orders?include_deleted=true&limit=2", admin)
+		if response.Pagination.TotalCount != 3 || response.Pagination.TotalPages != 2 {
+			t.Errorf("expected 3 orders over 2 pages, got total %d over %d pages",
+				response.Pagination.TotalCount, response.Pagination.TotalPages)
+		}
+
+		response = listOrders("/orders?include_deleted=true", admin)
+		for _, o := range response.Orders {
+			if (o.ID == 2) != (o.DeletedAt != nil) {
+				t.Errorf("order %d: expected deleted_at only on the deleted order, got %v", o.ID, o.DeletedAt)
+			}
+		}
+	})
+
+	t.Run("forbidden for non-admins", func(t *testing.T) {
+		w := doRequest(router, http.MethodGet, "/orders?include_deleted=true", "")
+		if w.Code != http.StatusForbidden {
+			t.Errorf("expected 403, got %d", w.Code)
+		}
+	})
+
+	t.Run("deleted orders are hidden from lookups", func(t *testing.T) {
+		w := doRequest(router, http.MethodGet, "/orders/2", "")
+		if w.Code != http.StatusNotFound {
+			t.Errorf("expected 404, got %d", w.Code)
+		}
+	})
+
+	t.Run("include_archived is an alias", func(t *testing.T) {
+		response := listOrders("/orders?include_archived=true", admin)
+		if response.Pagination.TotalCount != 3 {
+			t.Errorf("expected 3 orders, got total %d", response.Pagination.TotalCount)
+		}
+		if w := doRequest(router, http.MethodGet, "/orders?include_archived=true", ""); w.Code != http.StatusForbidden {
+			t.Errorf("expected 403 for non-admins, got %d", w.Code)
+		}
+	})
+
+	t.Run("counts exclude deleted orders by default", func(t *testing.T) {
+		for path, want := range map[string]string{
+			"/orders/count":                       `{"count":2}`,
+			"/orders/count?include_archived=true": `{"count":3}`,
+		} {
+			w := doRequestWithHeaders(router, http.MethodGet, path, "", admin)
+			if w.Code != http.StatusOK || w.Body.String() != want {
+				t.Errorf("%s: expected 200 %s, got %d %s", path, want, w.Code, w.Body.String())
+			}
+		}
+	})
+}
+
+func TestErrorStatusCodes_BusinessRuleVersusValidation(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-7001"))
+
+	if w := doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"cancelled"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected cancel to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"processing"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for a transition out of cancelled, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(router, http.MethodPost, "/orders", `{"items":[{"product_name":"Widget","quantity":1,"unit_price":1}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("expected 400 for a missing customer_name, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestUpdateOrderStatus_ReportsWhetherStatusChanged(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-7101"))
+
+	for _, tt := range []struct {
+		name    string
+		changed bool
+	}{
+		{name: "real transition", changed: true},
+		{name: "redundant retry", changed: false},
+	} {
+		w := doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"processing"}`)
+		if w.Code != http.StatusOK {
+			t.Fatalf("%s: expected 200, got %d: %s", tt.name, w.Code, w.Body.String())
+		}
+		var body dto.UpdateOrderStatusResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: failed to decode response: %v", tt.name, err)
+		}
+		if body.Changed != tt.changed {
+			t.Errorf("%s: expected changed=%v, got %v", tt.name, tt.changed, body.Changed)
+		}
+	}
+}
+
+func TestAPIVersion_SelectsBusinessRuleStatus(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-8001"))
+	doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"cancelled"}`)
+
+	tests := []struct {
+		version string
+		status  int
+		echoed  string
+	}{
+		{version: "", status: http.StatusUnprocessableEntity, echoed: middleware.LatestAPIVersion},
+		{version: "2024-06", status: http.StatusUnprocessableEntity, echoed: "2024-06"},
+		{version: "2024-01", status: http.StatusBadRequest, echoed: "2024-01"},
+		{version: "1999-01", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		headers := map[string]string{}
+		if tt.version != "" {
+			headers[middleware.APIVersionHeader] = tt.version
+		}
+		w := doRequestWithHeaders(router, http.MethodPut, "/orders/1/status", `{"status":"pending"}`, headers)
+		if w.Code != tt.status {
+			t.Errorf("version %q: expected %d, got %d", tt.version, tt.status, w.Code)
+		}
+		if got := w.Header().Get(middleware.APIVersionHeader); got != tt.echoed {
+			t.Errorf("version %q: expected %s header %q, got %q", tt.version, middleware.APIVersionHeader, tt.echoed, got)
+		}
+	}
+}
+
+func TestBulkCreateOrders_PartialFailure(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	body := `{"orders":[` + createOrderBody("PO-1") + `,` +
+		`{"customer_name":"Acme Corp","items":[{"product_name":"Widget","quantity":1,"unit_price":-5}]}]}`
+	w := doRequest(router, http.MethodPost, "/orders/bulk", body)
+	if w.Code != http.StatusMultiStatus {
+		t.Fatalf("expected 207, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp dto.BulkCreateOrdersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Succeeded != 1 || resp.Failed != 1 {
+		t.Fatalf("expected 1 succeeded and 1 failed, got %+v", resp)
+	}
+	if resp.Results[0].Order == nil || resp.Results[1].Error == nil {
+		t.Errorf("expected first order created and second failed, got %+v", resp.Results)
+	}
+}
+
+func TestBulkCreateOrders_RateLimited(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository(),
+		WithBulkRateLimiter(middleware.NewIPRateLimiter(0.001, 1)))
+
+	body := `{"orders":[` + createOrderBody("PO-1") + `]}`
+	if w := doRequest(router, http.MethodPost, "/orders/bulk", body); w.Code != http.StatusCreated {
+		t.Fatalf("expected first bulk request to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodPost, "/orders/bulk", body)
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected Retry-After header on 429")
+	}
+
+	// Single creates from the same client are not affected by the bulk limit
+	for i := 0; i < 5; i++ {
+		if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+			t.Fatalf("expected single create to succeed, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+}
+
+func doMergePatch(router *gin.Engine, path, body string) *httptest.ResponseRecorder {
+	return doRequestWithHeaders(router, http.MethodPatch, path, body, map[string]string{
+		"Content-Type": dto.MergePatchContentType,
+	})
+}
+
+func TestPatchOrder_SingleField(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doMergePatch(router, "/orders/1", `{"status":"processing"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if body["status"] != "processing" {
+		t.Errorf("expected status processing, got %v", body["status"])
+	}
+	if _, ok := body["updated_at"]; !ok {
+		t.Error("expected updated_at in response")
+	}
+	if len(body) != 2 {
+		t.Errorf("expected only changed fields plus updated_at, got %v", body)
+	}
+}
+
+func TestPatchOrder_CompletedStatusReachesWebhookQueue(t *testing.T) {
+	notifications := make(chan webhook.StatusNotification, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var notification webhook.StatusNotification
+		if err := json.NewDecoder(r.Body).Decode(&notification); err != nil {
+			t.Errorf("failed to decode notification: %v", err)
+		}
+		notifications <- notification
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	// Wired as in main: status events go through the background webhook queue
+	queue := events.NewChannelPublisher(10)
+	defer queue.Close()
+	queue.Subscribe(events.TypeOrderStatusChanged, webhook.NewWebhookNotifier(server.URL, "s3cret").Publish)
+
+	repo := memory.NewInMemoryOrderRepository()
+	useCases := newTestUseCases(repo)
+	useCases.PatchOrder = order.NewPatchOrderUseCase(repo, order.WithPatchEventPublisher(queue))
+	router := newTestRouterWithUseCases(useCases)
+
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	for _, status := range []string{"processing", "completed"} {
+		if w := doMergePatch(router, "/orders/1", `{"status":"`+status+`"}`); w.Code != http.StatusOK {
+			t.Fatalf("expected 200 patching to %s, got %d: %s", status, w.Code, w.Body.String())
+		}
+	}
+
+	select {
+	case notification := <-notifications:
+		if notification.OrderID != 1 || notification.Status != "completed" {
+			t.Errorf("expected a completed notification for order 1, got %+v", notification)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the completed patch to reach the webhook queue")
+	}
+}
+
+func TestPatchOrder_RejectsNonMutableFields(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"immutable field", `{"status":"processing","total_amount":1}`},
+		{"explicit null", `{"status":null}`},
+		{"unknown field", `{"priority":"high"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doMergePatch(router, "/orders/1", tt.body)
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+			}
+		})
+	}
+
+	w := doRequest(router, http.MethodGet, "/orders/1", "")
+	var got dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &got); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if got.Status != "pending" {
+		t.Errorf("expected rejected patches to leave status pending, got %s", got.Status)
+	}
+}
+
+func TestPatchOrder_RequiresMergePatchContentType(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequest(router, http.MethodPatch, "/orders/1", `{"status":"processing"}`)
+	if w.Code != http.StatusUnsupportedMediaType {
+		t.Fatalf("expected 415, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateOrder_FieldValidationDetails(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	longName := strings.Repeat("x", 120)
+	body := `{"customer_name":"Acme Corp","items":[` +
+		`{"product_name":"Widget","quantity":1,"unit_price":10},` +
+		`{"product_name":"` + longName + `","quantity":1,"unit_price":10}]}`
+	w := doRequest(router, http.MethodPost, "/orders", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp apperrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	details := resp.Error.Details
+	if details["field"] != "product_name" {
+		t.Errorf("expected field product_name, got %v", details["field"])
+	}
+	if details["item_index"] != float64(1) {
+		t.Errorf("expected item_index 1, got %v", details["item_index"])
+	}
+	if details["max_length"] != float64(100) {
+		t.Errorf("expected max_length 100, got %v", details["max_length"])
+	}
+	if details["current_length"] != float64(len(longName)) {
+		t.Errorf("expected current_length %d, got %v", len(longName), details["current_length"])
+	}
+}
+
+func TestCreateOrder_MaxUnitPrice(t *testing.T) {
+	validation.MaxUnitPrice = 1000
+	defer func() { validation.MaxUnitPrice = 0 }()
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	body := `{"customer_name":"Acme Corp","items":[` +
+		`{"product_name":"Widget","quantity":1,"unit_price":1000},` +
+		`{"product_name":"Gadget","quantity":1,"unit_price":999000}]}`
+	w := doRequest(router, http.MethodPost, "/orders", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp apperrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	details := resp.Error.Details
+	if details["field"] != "unit_price" || details["item_index"] != float64(1) {
+		t.Errorf("expected unit_price of item 1 to be reported, got %v", details)
+	}
+	if details["max_value"] != float64(1000) {
+		t.Errorf("expected max_value 1000, got %v", details["max_value"])
+	}
+}
+
+func TestCreateOrder_MoneyScale(t *testing.T) {
+	defer func() { validation.MoneyScale = validation.DefaultMoneyScale }()
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	itemBody := func(price string) string {
+		return `{"customer_name":"Acme Corp","items":[{"product_name":"Widget","quantity":1,"unit_price":` + price + `}]}`
+	}
+
+	tests := []struct {
+		name     string
+		scale    int
+		price    string
+		wantCode int
+	}{
+		{"default scale accepts cents", validation.DefaultMoneyScale, "10.99", http.StatusCreated},
+		{"default scale rejects fractions of a cent", validation.DefaultMoneyScale, "10.999", http.StatusBadRequest},
+		{"zero scale rejects decimals", 0, "10.5", http.StatusBadRequest},
+		{"wider scale accepts three places", 3, "10.999", http.StatusCreated},
+		{"negative scale disables the check", -1, "10.12345", http.StatusCreated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			validation.MoneyScale = tt.scale
+			w := doRequest(router, http.MethodPost, "/orders", itemBody(tt.price))
+			if w.Code != tt.wantCode {
+				t.Fatalf("expected %d, got %d: %s", tt.wantCode, w.Code, w.Body.String())
+			}
+			if tt.wantCode != http.StatusBadRequest {
+				return
+			}
+			var resp apperrors.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if resp.Error.Details["field"] != "unit_price" || resp.Error.Details["max_scale"] != float64(tt.scale) {
+				t.Errorf("expected unit_price scale violation, got %v", resp.Error.Details)
+			}
+		})
+	}
+
+	validation.MoneyScale = validation.DefaultMoneyScale
+	w := doRequest(router, http.MethodPut, "/orders/1/items",
+		`{"items":[{"product_name":"Widget","quantity":1,"unit_price":5.005}]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 when editing items, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestGetOrderStatuses_OmitsMissingIDs(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	for i := 0; i < 2; i++ {
+		if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+			t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+		}
+	}
+	if w := doRequest(router, http.MethodPut, "/orders/2/status", `{"status":"processing"}`); w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodPost, "/orders/statuses", `{"ids":[1,2,99]}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var statuses map[string]string
+	if err := json.Unmarshal(w.Body.Bytes(), &statuses); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	expected := map[string]string{"1": "pending", "2": "processing"}
+	if len(statuses) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, statuses)
+	}
+	for id, status := range expected {
+		if statuses[id] != status {
+			t.Errorf("expected order %s to be %s, got %q", id, status, statuses[id])
+		}
+	}
+}
+
+func TestGetOrderStatuses_RejectsEmptyIDs(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequest(router, http.MethodPost, "/orders/statuses", `{"ids":[]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestReadOnlyMode_BlocksWritesOnly(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	if w := doRequest(newTestRouter(repo), http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	h := NewOrderHandler(OrderUseCases{
+		CreateOrder:      order.NewCreateOrderUseCase(repo),
+		GetOrder:         order.NewGetOrderUseCase(repo),
+		GetOrderStatuses: order.NewGetOrderStatusesUseCase(repo),
+	})
+	readOnlyRouter := gin.New()
+	readOnlyRouter.Use(middleware.ReadOnlyMiddleware(true, "/orders/statuses"))
+	h.RegisterRoutes(readOnlyRouter)
+
+	w := doRequest(readOnlyRouter, http.MethodPost, "/orders", createOrderBody(""))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("expected 503 for a create in read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+
+	if w := doRequest(readOnlyRouter, http.MethodGet, "/orders/1", ""); w.Code != http.StatusOK {
+		t.Errorf("expected get to succeed in read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := doRequest(readOnlyRouter, http.MethodPost, "/orders/statuses", `{"ids":[1]}`); w.Code != http.StatusOK {
+		t.Errorf("expected status lookup to succeed in read-only mode, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestListOrders_WarnsWhenLimitCapped(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodGet, "/orders?limit=500", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var capped dto.ListOrdersResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &capped); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if len(capped.Warnings) != 1 || !strings.Contains(capped.Warnings[0], "limit 500") {
+		t.Errorf("expected a capped-limit warning, got %v", capped.Warnings)
+	}
+
+	w = doRequest(router, http.MethodGet, "/orders?limit=10", "")
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if _, ok := body["warnings"]; ok {
+		t.Errorf("expected warnings to be omitted when nothing was adjusted, got %s", body["warnings"])
+	}
+}
+
+func TestDryRun_CreateReturnsComputedOrderWithoutStoringIt(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	router := newTestRouter(repo)
+	dryRun := map[string]string{middleware.AdminKeyHeader: testAdminKey, middleware.DryRunHeader: "true"}
+
+	w := doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-DRY"), dryRun)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200 for a dry run, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Header().Get(middleware.DryRunHeader) != "true" {
+		t.Error("expected the response to be marked as a dry run")
+	}
+
+	var computed dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &computed); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if computed.ID != 0 {
+		t.Errorf("expected no id for a dry run, got %d", computed.ID)
+	}
+	if computed.TotalAmount != 20 || computed.Status != "pending" {
+		t.Errorf("expected a computed pending order totalling 20, got %+v", computed)
+	}
+
+	orders, pagination, err := repo.ListOrders(context.Background(), 1, 10, repository.OrderFilter{})
+	if err != nil {
+		t.Fatalf("failed to list orders: %v", err)
+	}
+	if len(orders) != 0 || pagination.TotalCount != 0 {
+		t.Errorf("expected nothing to be persisted, found %d orders", len(orders))
+	}
+
+	// A real create afterwards still gets the first ID
+	w = doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-REAL"))
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &created); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if created.ID != 1 {
+		t.Errorf("expected the dry run to leave the ID sequence alone, got id %d", created.ID)
+	}
+}
+
+func TestDryRun_DeleteAndStatusUpdateLeaveOrderUnchanged(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	dryRun := map[string]string{middleware.AdminKeyHeader: testAdminKey, middleware.DryRunHeader: "true"}
+
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("PO-1")); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequestWithHeaders(router, http.MethodPut, "/orders/1/status", `{"status":"paid"}`, dryRun)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	w = doRequestWithHeaders(router, http.MethodDelete, "/orders/1", "", dryRun)
+	if w.Code >= http.StatusBadRequest {
+		t.Fatalf("expected the dry-run delete to succeed, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequest(router, http.MethodGet, "/orders/1", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected the order to still exist, got %d: %s", w.Code, w.Body.String())
+	}
+	var fetched dto.OrderResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &fetched); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if fetched.Status != "pending" {
+		t.Errorf("expected status to stay pending, got %q", fetched.Status)
+	}
+
+	// Business rules still run: an invalid transition is rejected as usual
+	w = doRequestWithHeaders(router, http.MethodPut, "/orders/1/status", `{"status":"delivered"}`, dryRun)
+	if w.Code < http.StatusBadRequest {
+		t.Errorf("expected an invalid transition to fail in a dry run, got %d", w.Code)
+	}
+}
+
+func TestDryRun_RequiresAdminRole(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	w := doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-1"), map[string]string{middleware.DryRunHeader: "true"})
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("expected 403, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w = doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-1"), map[string]string{middleware.DryRunHeader: "maybe"})
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for an unparseable header, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateOrder_ReportsEveryInvalidField(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	body := `{"customer_name":"","items":[{"product_name":"","quantity":0,"unit_price":10}]}`
+	w := doRequest(router, http.MethodPost, "/orders", body)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp struct {
+		Error struct {
+			Message string `json:"message"`
+			Details struct {
+				Errors []struct {
+					Field   string `json:"field"`
+					Tag     string `json:"tag"`
+					Message string `json:"message"`
+				} `json:"errors"`
+			} `json:"details"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Message == "" {
+		t.Error("expected a human-readable top-level message")
+	}
+	fields := map[string]string{}
+	for _, fieldErr := range resp.Error.Details.Errors {
+		fields[fieldErr.Field] = fieldErr.Tag
+		if fieldErr.Message == "" {
+			t.Errorf("expected a message for %s", fieldErr.Field)
+		}
+	}
+	for _, field := range []string{"customer_name", "product_name", "quantity"} {
+		if _, ok := fields[field]; !ok {
+			t.Errorf("expected %s in the field errors, got %v", field, fields)
+		}
+	}
+}
+
+func TestUpdateOrderStatus_ReportsBindingFieldDetails(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	router := newTestRouter(repo)
+	if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+		t.Fatalf("failed to create order: %d %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"refunded"}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var resp apperrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if !strings.HasPrefix(resp.Error.Message, "Invalid status") {
+		t.Errorf("expected the friendly status message, got %q", resp.Error.Message)
+	}
+	if resp.Error.Details["field"] != "status" || resp.Error.Details["tag"] != "oneof" {
+		t.Errorf("expected the status oneof failure in the details, got %v", resp.Error.Details)
+	}
+}
+
+func TestCreateOrder_QuantityUpperBound(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	itemBody := func(quantity string) string {
+		return `{"customer_name":"Acme Corp","items":[{"product_name":"Widget","quantity":` + quantity + `,"unit_price":1}]}`
+	}
+
+	if w := doRequest(router, http.MethodPost, "/orders", itemBody("10000")); w.Code != http.StatusCreated {
+		t.Fatalf("expected quantity 10000 to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodPost, "/orders", itemBody("10001"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 for quantity 10001, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp apperrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	details := resp.Error.Details
+	if details["field"] != "quantity" || details["tag"] != "max" || details["max_value"] != float64(10000) {
+		t.Errorf("expected the quantity limit in the details, got %v", details)
+	}
+}
+
+func TestCreateOrder_UnitPriceUpperBound(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	itemBody := func(price string) string {
+		return `{"customer_name":"Acme Corp","items":[{"product_name":"Widget","quantity":1,"unit_price":` + price + `}]}`
+	}
+
+	if w := doRequest(router, http.MethodPost, "/orders", itemBody("1000000")); w.Code != http.StatusCreated {
+		t.Fatalf("expected unit price 1000000 to be accepted, got %d: %s", w.Code, w.Body.String())
+	}
+
+	w := doRequest(router, http.MethodPost, "/orders", itemBody("1000000.01"))
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400 above the cap, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp apperrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Details["field"] != "unit_price" || resp.Error.Details["max_value"] != float64(1000000) {
+		t.Errorf("expected the unit price limit in the details, got %v", resp.Error.Details)
+	}
+}
+
+func TestUpdateOrderStatus_ShippedLifecycle(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+	for i := 0; i < 2; i++ {
+		if w := doRequest(router, http.MethodPost, "/orders", createOrderBody("")); w.Code != http.StatusCreated {
+			t.Fatalf("failed to create order: %d %s", w.Code, w.Body.String())
+		}
+	}
+
+	for _, status := range []string{"paid", "shipped", "completed"} {
+		if w := doRequest(router, http.MethodPut, "/orders/1/status", `{"status":"`+status+`"}`); w.Code != http.StatusOK {
+			t.Fatalf("expected the move to %s to succeed, got %d: %s", status, w.Code, w.Body.String())
+		}
+	}
+
+	// Shipped orders can no longer be cancelled
+	for _, status := range []string{"paid", "shipped"} {
+		if w := doRequest(router, http.MethodPut, "/orders/2/status", `{"status":"`+status+`"}`); w.Code != http.StatusOK {
+			t.Fatalf("expected the move to %s to succeed, got %d: %s", status, w.Code, w.Body.String())
+		}
+	}
+	w := doRequest(router, http.MethodPut, "/orders/2/status", `{"status":"cancelled"}`)
+	if w.Code != http.StatusUnprocessableEntity {
+		t.Errorf("expected cancelling a shipped order to be rejected with 422, got %d: %s", w.Code, w.Body.String())
+	}
+}
+
+func TestCreateOrder_OversizedBodyIs413(t *testing.T) {
+	router := newTestRouter(memory.NewInMemoryOrderRepository())
+
+	// The body as BodyLimitMiddleware hands it on: cut off once the limit is passed
+	body := `{"customer_name":"` + strings.Repeat("x", 2048) + `","items":[]}`
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	req.Body = http.MaxBytesReader(w, req.Body, 1024)
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Fatalf("expected 413, got %d: %s", w.Code, w.Body.String())
+	}
+	var resp apperrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if resp.Error.Code != apperrors.ErrCodeBadRequest || resp.Error.Details["max_bytes"] != float64(1024) {
+		t.Errorf("expected a BAD_REQUEST error naming the limit, got %+v", resp.Error)
+	}
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/api/http/handler/dto"
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/infra/memory"
+)
+
+func seedOrders(t *testing.T, repo *memory.InMemoryOrderRepository, count int) {
+	t.Helper()
+	for i := 0; i < count; i++ {
+		order, err := entity.NewOrder(fmt.Sprintf("Customer %d", i), []entity.OrderItem{
+			{ProductName: "Widget", Quantity: 2, UnitPrice: 10},
+		})
+		if err != nil {
+			t.Fatalf("failed to build order: %v", err)
+		}
+		if _, err := repo.CreateOrderWithItems(context.Background(), order); err != nil {
+			t.Fatalf("failed to seed order: %v", err)
+		}
+	}
+}
+
+func TestStreamOrders_WritesNDJSON(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	seedOrders(t, repo, 3)
+	router := newTestRouter(repo)
+
+	w := doRequest(router, http.MethodGet, "/orders/stream", "")
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != NDJSONContentType {
+		t.Errorf("expected content type %s, got %s", NDJSONContentType, got)
+	}
+
+	var ids []int64
+	scanner := bufio.NewScanner(strings.NewReader(w.Body.String()))
+	for scanner.Scan() {
+		var order dto.OrderResponse
+		if err := json.Unmarshal(scanner.Bytes(), &order); err != nil {
+			t.Fatalf("failed to decode line %q: %v", scanner.Text(), err)
+		}
+		ids = append(ids, order.ID)
+	}
+	if len(ids) != 3 || ids[0] != 3 || ids[2] != 1 {
+		t.Errorf("expected orders 3, 2, 1 newest first, got %v", ids)
+	}
+}
+
+func TestStreamOrders_AbortsSlowReader(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	seedOrders(t, repo, 1000)
+	router := newTestRouter(repo, WithStreamWriteTimeout(100*time.Millisecond))
+
+	finished := make(chan struct{})
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		router.ServeHTTP(w, r)
+		close(finished)
+	}))
+	// Small socket buffers make a client that never reads block the server's writes quickly
+	srv.Config.ConnState = func(conn net.Conn, state http.ConnState) {
+		if tcpConn, ok := conn.(*net.TCPConn); ok && state == http.StateNew {
+			_ = tcpConn.SetWriteBuffer(4096)
+		}
+	}
+	srv.Start()
+	defer srv.Close()
+
+	conn, err := net.Dial("tcp", srv.Listener.Addr().String())
+	if err != nil {
+		t.Fatalf("failed to connect: %v", err)
+	}
+	defer conn.Close()
+	_ = conn.(*net.TCPConn).SetReadBuffer(4096)
+
+	// Request the stream and never read the response
+	if _, err := fmt.Fprint(conn, "GET /orders/stream HTTP/1.1\r\nHost: test\r\n\r\n"); err != nil {
+		t.Fatalf("failed to send request: %v", err)
+	}
+
+	select {
+	case <-finished:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the stream to abort for a client that stopped reading")
+	}
+}
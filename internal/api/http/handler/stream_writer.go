@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+)
+
+// NDJSONContentType is the media type of newline-delimited JSON streams
+const NDJSONContentType = "application/x-ndjson"
+
+// chunkWriter writes a streamed response one flushed chunk at a time. With a timeout, each
+// chunk gets a fresh write deadline so a client that stops reading fails the write instead of
+// blocking the handler, and the cursor feeding it, indefinitely.
+type chunkWriter struct {
+	w       http.ResponseWriter
+	rc      *http.ResponseController
+	timeout time.Duration
+}
+
+func newChunkWriter(w http.ResponseWriter, timeout time.Duration) *chunkWriter {
+	// Control the innermost writer: wrappers such as gin's implement http.Flusher, which
+	// would make ResponseController.Flush swallow write errors
+	base := w
+	for {
+		unwrapper, ok := base.(interface{ Unwrap() http.ResponseWriter })
+		if !ok {
+			break
+		}
+		base = unwrapper.Unwrap()
+	}
+	return &chunkWriter{w: w, rc: http.NewResponseController(base), timeout: timeout}
+}
+
+// WriteChunk writes and flushes p, failing if the client does not accept it within the timeout
+func (s *chunkWriter) WriteChunk(p []byte) error {
+	if s.timeout > 0 {
+		if err := s.rc.SetWriteDeadline(time.Now().Add(s.timeout)); err != nil && !errors.Is(err, http.ErrNotSupported) {
+			return err
+		}
+	}
+	if _, err := s.w.Write(p); err != nil {
+		return err
+	}
+	return s.rc.Flush()
+}
+
+// Close clears the write deadline once the stream has been written completely
+func (s *chunkWriter) Close() {
+	if s.timeout > 0 {
+		_ = s.rc.SetWriteDeadline(time.Time{})
+	}
+}
@@ -0,0 +1,144 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"online-order-management-system/internal/infra/memory"
+	"online-order-management-system/internal/middleware"
+	"online-order-management-system/internal/usecase/order"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// recordSpans installs a tracer provider that keeps finished spans in memory for the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func newTracedRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	h := NewOrderHandler(OrderUseCases{
+		CreateOrder: order.NewCreateOrderUseCase(memory.NewInMemoryOrderRepository()),
+	})
+	router := gin.New()
+	router.Use(middleware.TracingMiddleware())
+	h.RegisterRoutes(router)
+	return router
+}
+
+func TestTracing_CreateOrderSpanTree(t *testing.T) {
+	recorder := recordSpans(t)
+	const parentTraceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	headers := map[string]string{"traceparent": "00-" + parentTraceID + "-00f067aa0ba902b7-01"}
+
+	w := doRequestWithHeaders(newTracedRouter(), http.MethodPost, "/orders", createOrderBody("PO-TRACE"), headers)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	server, ok := spans["POST /orders"]
+	if !ok {
+		t.Fatalf("expected a server span for the request, got %v", spans)
+	}
+	if server.SpanKind() != trace.SpanKindServer {
+		t.Errorf("expected a server span, got %s", server.SpanKind())
+	}
+	if got := server.SpanContext().TraceID().String(); got != parentTraceID {
+		t.Errorf("expected the incoming traceparent to be continued, got trace %s", got)
+	}
+	if !server.Parent().IsRemote() {
+		t.Error("expected the server span's parent to be the remote caller")
+	}
+
+	usecase, ok := spans["CreateOrderUseCase.Execute"]
+	if !ok {
+		t.Fatalf("expected a use case span, got %v", spans)
+	}
+	if usecase.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Error("expected the use case span to be a child of the server span")
+	}
+	if usecase.SpanContext().TraceID() != server.SpanContext().TraceID() {
+		t.Error("expected the use case span to share the request's trace")
+	}
+}
+
+func TestTracing_ErrorResponsesCarryTheOTelTraceID(t *testing.T) {
+	recorder := recordSpans(t)
+
+	w := doRequest(newTracedRouter(), http.MethodPost, "/orders", `{"customer_name":"","items":[]}`)
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d: %s", w.Code, w.Body.String())
+	}
+	var response apperrors.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	ended := recorder.Ended()
+	if len(ended) == 0 {
+		t.Fatal("expected the request to be traced")
+	}
+	if want := ended[0].SpanContext().TraceID().String(); response.TraceID != want {
+		t.Errorf("expected the response trace_id %q to match the span's trace %q", response.TraceID, want)
+	}
+}
+
+func TestTraceID_EveryRequestLogLineCarriesIt(t *testing.T) {
+	var out bytes.Buffer
+	logger.SetOutput(&out)
+	defer logger.SetOutput(nil)
+
+	h := NewOrderHandler(OrderUseCases{
+		CreateOrder: order.NewCreateOrderUseCase(memory.NewInMemoryOrderRepository()),
+		GetOrder:    order.NewGetOrderUseCase(memory.NewInMemoryOrderRepository()),
+	})
+	router := gin.New()
+	router.Use(middleware.TraceIDMiddleware())
+	h.RegisterRoutes(router)
+
+	headers := map[string]string{middleware.RequestIDHeader: "req-log-1"}
+	if w := doRequestWithHeaders(router, http.MethodPost, "/orders", createOrderBody("PO-LOG"), headers); w.Code != http.StatusCreated {
+		t.Fatalf("expected 201, got %d: %s", w.Code, w.Body.String())
+	}
+	if w := doRequestWithHeaders(router, http.MethodGet, "/orders/99", "", headers); w.Code != http.StatusNotFound {
+		t.Fatalf("expected 404, got %d: %s", w.Code, w.Body.String())
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) == 0 || lines[0] == "" {
+		t.Fatal("expected the requests to be logged")
+	}
+	for _, line := range lines {
+		var entry logger.LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to decode log line %q: %v", line, err)
+		}
+		if entry.Fields["trace_id"] != "req-log-1" {
+			t.Errorf("expected %q from %s to carry the trace ID, got %v", entry.Message, entry.Service, entry.Fields)
+		}
+	}
+}
@@ -1,10 +1,17 @@
 package validation
 
 import (
+	"errors"
 	"fmt"
+	"math"
+	"net/http"
 	"reflect"
+	"strconv"
 	"strings"
 
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/middleware"
+	apperrors "online-order-management-system/pkg/errors"
 	"online-order-management-system/pkg/validation"
 
 	"github.com/gin-gonic/gin/binding"
@@ -47,7 +54,7 @@ func GetOrderValidationMessage(err error) string {
 
 	// Handle order status validation errors
 	if strings.Contains(errStr, "oneof") && strings.Contains(errStr, "Status") {
-		return "Invalid status. Must be one of: pending, processing, completed, cancelled"
+		return "Invalid status. Must be one of: " + strings.Join(entity.ValidStatuses, ", ")
 	}
 
 	// Handle order-specific required fields
@@ -78,6 +85,12 @@ func GetOrderValidationMessage(err error) string {
 		if strings.Contains(errStr, "ProductName") {
 			return "Product name must not exceed 100 characters"
 		}
+		if strings.Contains(errStr, "Quantity") {
+			return fmt.Sprintf("Quantity cannot exceed %d", MaxQuantity)
+		}
+		if strings.Contains(errStr, "UnitPrice") {
+			return fmt.Sprintf("Unit price cannot exceed %.2f", MaxUnitPriceCap)
+		}
 		return "Field exceeds maximum allowed length"
 	}
 
@@ -107,12 +120,70 @@ func GetOrderValidationMessage(err error) string {
 // Order field validation constants
 const (
 	MinQuantity     = 1
+	MaxQuantity     = entity.MaxItemQuantity
 	MinUnitPrice    = 0.0
+	MaxUnitPriceCap = 1_000_000.0 // Hard cap on unit prices; MaxUnitPrice may only lower it
 	MinItems        = 1
 	MaxCustomerName = 100
 	MaxProductName  = 100
+	MaxUnit         = entity.MaxUnitLength
 )
 
+// MaxUnitPrice lowers the unit price cap of each item below MaxUnitPriceCap; 0 leaves the
+// hard cap alone. It is set from configuration at startup.
+var MaxUnitPrice float64
+
+// maxUnitPrice returns the unit price cap in effect
+func maxUnitPrice() float64 {
+	if MaxUnitPrice > 0 && MaxUnitPrice < MaxUnitPriceCap {
+		return MaxUnitPrice
+	}
+	return MaxUnitPriceCap
+}
+
+// DefaultMoneyScale is the number of decimal places money inputs may carry by default (cents)
+const DefaultMoneyScale = 2
+
+// MoneyScale is the most decimal places any money input (unit_price, and every other amount
+// a client sends) may carry; a negative scale disables the check. It is set from
+// configuration at startup.
+var MoneyScale = DefaultMoneyScale
+
+// decimalPlaces counts the decimal places of the shortest representation of value, which is
+// the literal the client sent for any amount parsed from JSON
+func decimalPlaces(value float64) int {
+	formatted := strconv.FormatFloat(value, 'f', -1, 64)
+	if dot := strings.IndexByte(formatted, '.'); dot >= 0 {
+		return len(formatted) - dot - 1
+	}
+	return 0
+}
+
+// validateMoney checks the precision of a money input against MoneyScale. Every money field
+// goes through it so the whole money model shares one rule; range checks stay with each field.
+func validateMoney(field string, value float64) *validation.FieldValidationError {
+	if MoneyScale < 0 || decimalPlaces(value) <= MoneyScale {
+		return nil
+	}
+	label, ok := moneyFieldLabels[field]
+	if !ok {
+		label = field
+	}
+	return validation.NewFieldValidationError(
+		field,
+		"scale",
+		fmt.Sprintf("%s cannot have more than %d decimal places", label, MoneyScale),
+		value,
+	).WithDetails(map[string]interface{}{
+		"max_scale": MoneyScale,
+	})
+}
+
+// moneyFieldLabels names money fields in validation messages
+var moneyFieldLabels = map[string]string{
+	"unit_price": "Unit price",
+}
+
 // ValidateOrderFields performs order-specific field validation
 func ValidateOrderFields(customerName string, items []interface{}) *validation.ValidationResult {
 	result := validation.NewValidationResult()
@@ -151,8 +222,56 @@ func ValidateOrderFields(customerName string, items []interface{}) *validation.V
 	return result
 }
 
+// ValidateCustomerEmail applies the entity email rules so a malformed address is reported as
+// a field error instead of surfacing from order creation
+func ValidateCustomerEmail(email string) *validation.ValidationResult {
+	result := validation.NewValidationResult()
+	if err := entity.ValidateEmail(strings.TrimSpace(email)); err != nil {
+		result.AddError(validation.NewFieldValidationError(
+			"customer_email",
+			"email",
+			"Customer email must be a valid address such as name@example.com",
+			email,
+		).WithDetails(map[string]interface{}{
+			"max_length": entity.MaxEmailLength,
+		}))
+	}
+	return result
+}
+
+// MaxDiscountScale is the most decimal places a discount percentage may carry, as stored
+const MaxDiscountScale = 2
+
+// ValidateDiscountPercent checks that an item's discount is a percentage between 0 and 100
+func ValidateDiscountPercent(itemIndex int, discount float64) *validation.ValidationResult {
+	result := validation.NewValidationResult()
+	if !(discount >= 0 && discount <= entity.MaxDiscountPercent) {
+		result.AddError(validation.NewFieldValidationError(
+			"discount_percent",
+			"range",
+			"Discount must be between 0 and 100 percent",
+			discount,
+		).WithDetails(map[string]interface{}{
+			"item_index": itemIndex,
+			"min_value":  0,
+			"max_value":  entity.MaxDiscountPercent,
+		}))
+	} else if decimalPlaces(discount) > MaxDiscountScale {
+		result.AddError(validation.NewFieldValidationError(
+			"discount_percent",
+			"scale",
+			fmt.Sprintf("Discount cannot have more than %d decimal places", MaxDiscountScale),
+			discount,
+		).WithDetails(map[string]interface{}{
+			"item_index": itemIndex,
+			"max_scale":  MaxDiscountScale,
+		}))
+	}
+	return result
+}
+
 // ValidateOrderItemFields performs order item specific validation
-func ValidateOrderItemFields(itemIndex int, productName string, quantity int, unitPrice float64) *validation.ValidationResult {
+func ValidateOrderItemFields(itemIndex int, productName string, quantity float64, unit string, unitPrice float64) *validation.ValidationResult {
 	result := validation.NewValidationResult()
 
 	// Validate product name
@@ -179,16 +298,62 @@ func ValidateOrderItemFields(itemIndex int, productName string, quantity int, un
 		}))
 	}
 
-	// Validate quantity
-	if quantity < MinQuantity {
+	// Validate quantity: counted items take whole numbers, measured items (with a unit) any positive amount
+	trimmedUnit := strings.TrimSpace(unit)
+	if trimmedUnit == "" {
+		if quantity < MinQuantity {
+			result.AddError(validation.NewFieldValidationError(
+				"quantity",
+				"min",
+				"Quantity must be at least 1",
+				quantity,
+			).WithDetails(map[string]interface{}{
+				"item_index": itemIndex,
+				"min_value":  MinQuantity,
+			}))
+		} else if quantity != math.Trunc(quantity) {
+			result.AddError(validation.NewFieldValidationError(
+				"quantity",
+				"integer",
+				"Quantity must be a whole number unless a unit is given",
+				quantity,
+			).WithDetails(map[string]interface{}{
+				"item_index": itemIndex,
+			}))
+		}
+	} else if quantity <= 0 {
 		result.AddError(validation.NewFieldValidationError(
 			"quantity",
-			"min",
-			"Quantity must be at least 1",
+			"gt",
+			"Quantity must be greater than 0",
 			quantity,
 		).WithDetails(map[string]interface{}{
 			"item_index": itemIndex,
-			"min_value":  MinQuantity,
+			"unit":       trimmedUnit,
+		}))
+	}
+	if quantity > MaxQuantity {
+		result.AddError(validation.NewFieldValidationError(
+			"quantity",
+			"max",
+			fmt.Sprintf("Quantity cannot exceed %d", MaxQuantity),
+			quantity,
+		).WithDetails(map[string]interface{}{
+			"item_index": itemIndex,
+			"max_value":  MaxQuantity,
+		}))
+	}
+
+	// Validate unit
+	if len(trimmedUnit) > MaxUnit {
+		result.AddError(validation.NewFieldValidationError(
+			"unit",
+			"max",
+			fmt.Sprintf("Unit cannot exceed %d characters", MaxUnit),
+			unit,
+		).WithDetails(map[string]interface{}{
+			"item_index": itemIndex,
+			"max_length": MaxUnit,
 		}))
 	}
 
@@ -203,7 +368,113 @@ func ValidateOrderItemFields(itemIndex int, productName string, quantity int, un
 			"item_index": itemIndex,
 			"min_value":  MinUnitPrice,
 		}))
+	} else if max := maxUnitPrice(); unitPrice > max {
+		result.AddError(validation.NewFieldValidationError(
+			"unit_price",
+			"max",
+			fmt.Sprintf("Unit price cannot exceed %.2f", max),
+			unitPrice,
+		).WithDetails(map[string]interface{}{
+			"item_index": itemIndex,
+			"max_value":  max,
+		}))
+	}
+	if err := validateMoney("unit_price", unitPrice); err != nil {
+		result.AddError(err.WithDetails(map[string]interface{}{
+			"item_index": itemIndex,
+		}))
 	}
 
 	return result
 }
+
+// ToValidationError converts a failed ValidationResult into a validation AppError whose
+// details carry the first failing field's details plus every field error.
+// It returns nil when the result has no errors.
+func ToValidationError(result *validation.ValidationResult) *apperrors.AppError {
+	if result == nil || !result.HasErrors() {
+		return nil
+	}
+
+	first := result.GetFirstError()
+	details := map[string]interface{}{
+		"field":  first.Field,
+		"tag":    first.Tag,
+		"errors": result.Errors,
+	}
+	for k, v := range first.Details {
+		details[k] = v
+	}
+	return apperrors.NewValidationError(first.Message).WithDetails(details)
+}
+
+// BindingError converts a request binding failure into a validation AppError. The message
+// stays the friendly GetOrderValidationMessage text; when the failure came from field rules
+// (validator.ValidationErrors), the details carry every failing field in the same shape as
+// ToValidationError, with fields named by their JSON path in req (e.g. orders[0].items).
+// A body cut off by BodyLimitMiddleware becomes its 413 error instead.
+func BindingError(err error, req interface{}) *apperrors.AppError {
+	var tooLarge *http.MaxBytesError
+	if errors.As(err, &tooLarge) {
+		return middleware.BodyTooLargeError(tooLarge.Limit)
+	}
+
+	validationErr := apperrors.NewValidationError(GetOrderValidationMessage(err))
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) || len(fieldErrs) == 0 {
+		return validationErr
+	}
+
+	result := validation.NewValidationResult()
+	for _, fieldErr := range fieldErrs {
+		result.AddError(validation.NewFieldValidationError(
+			jsonFieldPath(reflect.TypeOf(req), fieldErr.StructNamespace()),
+			fieldErr.Tag(),
+			GetOrderValidationMessage(fieldErr),
+			fieldErr.Value(),
+		))
+	}
+
+	first := result.GetFirstError()
+	return validationErr.WithDetails(map[string]interface{}{
+		"field":  first.Field,
+		"tag":    first.Tag,
+		"errors": result.Errors,
+	})
+}
+
+// jsonFieldPath maps a validator struct namespace such as
+// "BulkCreateOrdersRequest.Orders[0].CustomerName" onto the JSON names of t's fields,
+// "orders[0].customer_name". Segments it cannot resolve are kept as they are.
+func jsonFieldPath(t reflect.Type, namespace string) string {
+	segments := strings.Split(namespace, ".")[1:] // The first segment is the type name
+	path := make([]string, 0, len(segments))
+	for _, segment := range segments {
+		name, index := segment, ""
+		if bracket := strings.IndexByte(segment, '['); bracket >= 0 {
+			name, index = segment[:bracket], segment[bracket:]
+		}
+
+		for t != nil && (t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array) {
+			t = t.Elem()
+		}
+		if t == nil || t.Kind() != reflect.Struct {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		field, ok := t.FieldByName(name)
+		if !ok {
+			path = append(path, segment)
+			t = nil
+			continue
+		}
+		if tag := strings.Split(field.Tag.Get("json"), ",")[0]; tag != "" && tag != "-" {
+			name = tag
+		}
+		path = append(path, name+index)
+		t = field.Type
+	}
+	return strings.Join(path, ".")
+}
@@ -0,0 +1,119 @@
+package validation_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"online-order-management-system/internal/api/http/handler/dto"
+	"online-order-management-system/internal/api/validation"
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/usecase/order"
+	pkgvalidation "online-order-management-system/pkg/validation"
+
+	"github.com/go-playground/validator/v10"
+)
+
+func TestBindingError_NamesFieldsByJSONPath(t *testing.T) {
+	v := validator.New()
+	v.SetTagName("binding")
+
+	req := dto.CreateOrderRequest{
+		Items: []dto.CreateOrderItemRequest{{ProductName: "Widget", Quantity: 0, UnitPrice: 10}},
+	}
+	err := v.Struct(&req)
+	if err == nil {
+		t.Fatal("expected the order to fail validation")
+	}
+
+	appErr := validation.BindingError(err, &req)
+	if appErr.Message == "" {
+		t.Error("expected a human-readable message")
+	}
+	fieldErrs, ok := appErr.Details["errors"].([]*pkgvalidation.FieldValidationError)
+	if !ok {
+		t.Fatalf("expected field errors in the details, got %v", appErr.Details)
+	}
+
+	got := map[string]string{}
+	for _, fieldErr := range fieldErrs {
+		got[fieldErr.Field] = fieldErr.Tag
+	}
+	want := map[string]string{"customer_name": "required", "items[0].quantity": "required"}
+	for field, tag := range want {
+		if got[field] != tag {
+			t.Errorf("expected %s to fail %q, got %v", field, tag, got)
+		}
+	}
+	if appErr.Details["field"] != fieldErrs[0].Field {
+		t.Errorf("expected the first failing field on top, got %v", appErr.Details["field"])
+	}
+}
+
+func TestBindingError_KeepsMessageForMalformedBodies(t *testing.T) {
+	var req dto.CreateOrderRequest
+	appErr := validation.BindingError(json.Unmarshal([]byte(`{"customer_name":`), &req), &req)
+	if appErr.Message == "" || appErr.Details != nil {
+		t.Errorf("expected a plain validation error without field details, got %+v", appErr)
+	}
+}
+
+func TestGetOrderValidationMessage_UpperBounds(t *testing.T) {
+	v := validator.New()
+	v.SetTagName("binding")
+
+	req := dto.CreateOrderRequest{
+		CustomerName: "Acme Corp",
+		Items:        []dto.CreateOrderItemRequest{{ProductName: "Widget", Quantity: 10001, UnitPrice: 10}},
+	}
+	if got := validation.GetOrderValidationMessage(v.Struct(&req)); got != "Quantity cannot exceed 10000" {
+		t.Errorf("unexpected quantity message %q", got)
+	}
+
+	req.Items[0] = dto.CreateOrderItemRequest{ProductName: "Widget", Quantity: 1, UnitPrice: 1000000.01}
+	if got := validation.GetOrderValidationMessage(v.Struct(&req)); got != "Unit price cannot exceed 1000000.00" {
+		t.Errorf("unexpected unit price message %q", got)
+	}
+}
+
+func TestValidStatuses_SingleSourceOfTruth(t *testing.T) {
+	want := strings.Join(entity.ValidStatuses, " ")
+
+	requests := map[string]interface{}{
+		"dto.UpdateOrderStatusRequest":   dto.UpdateOrderStatusRequest{},
+		"order.UpdateOrderStatusRequest": order.UpdateOrderStatusRequest{},
+	}
+	for name, req := range requests {
+		field, ok := reflect.TypeOf(req).FieldByName("Status")
+		if !ok {
+			t.Fatalf("%s has no Status field", name)
+		}
+		for _, key := range []string{"binding", "validate"} {
+			tag := field.Tag.Get(key)
+			if tag == "" {
+				continue
+			}
+			if got := oneOf(tag); got != want {
+				t.Errorf("%s %s tag allows %q, expected entity.ValidStatuses %q", name, key, got, want)
+			}
+		}
+	}
+
+	v := validator.New()
+	v.SetTagName("binding")
+	message := validation.GetOrderValidationMessage(v.Struct(dto.UpdateOrderStatusRequest{Status: "unknown"}))
+	if message != "Invalid status. Must be one of: "+strings.Join(entity.ValidStatuses, ", ") {
+		t.Errorf("expected the message to list entity.ValidStatuses, got %q", message)
+	}
+}
+
+// oneOf returns the values of the oneof rule in a validator tag
+func oneOf(tag string) string {
+	for _, rule := range strings.Split(tag, ",") {
+		if values, ok := strings.CutPrefix(rule, "oneof="); ok {
+			return values
+		}
+	}
+	return ""
+}
@@ -0,0 +1,36 @@
+package entity
+
+import (
+	"errors"
+	"math"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// MaxDiscountPercent is the largest line discount; 100% makes the line free
+const MaxDiscountPercent = 100
+
+// ErrInvalidDiscount is the cause of errors returned for a discount outside 0-100%
+var ErrInvalidDiscount = errors.New("item discount must be between 0 and 100 percent")
+
+// validateItemDiscount checks that the discount of the item at index is a percentage
+func validateItemDiscount(index int, item OrderItem) error {
+	if item.DiscountPercent >= 0 && item.DiscountPercent <= MaxDiscountPercent {
+		return nil
+	}
+	return apperrors.NewInvalidEntityError("item discount must be between 0 and 100 percent").WithDetails(map[string]interface{}{
+		"item_index":       index,
+		"discount_percent": item.DiscountPercent,
+	}).WithCause(ErrInvalidDiscount)
+}
+
+// lineTotal is quantity * unit price less the item's discount, rounded to the nearest cent
+// for fractional quantities and discounts. It reports false when the total does not fit Money.
+func (i OrderItem) lineTotal() (Money, bool) {
+	cents := math.Round(i.Quantity * float64(i.UnitPrice) * (MaxDiscountPercent - i.DiscountPercent) / MaxDiscountPercent)
+	// float64(math.MaxInt64) rounds up to 2^63, which is already out of range
+	if math.IsNaN(cents) || cents >= math.MaxInt64 || cents <= math.MinInt64 {
+		return 0, false
+	}
+	return Money(cents), true
+}
@@ -0,0 +1,81 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func TestNewOrder_Discount(t *testing.T) {
+	tests := []struct {
+		name      string
+		item      OrderItem
+		wantLine  Money
+		wantTotal Money
+	}{
+		{
+			name:      "no discount charges the full line",
+			item:      OrderItem{ProductName: "Widget", Quantity: 3, UnitPrice: NewMoney(19.99)},
+			wantLine:  NewMoney(59.97),
+			wantTotal: NewMoney(59.97 + 10),
+		},
+		{
+			name:      "full discount makes the line free",
+			item:      OrderItem{ProductName: "Widget", Quantity: 3, UnitPrice: NewMoney(19.99), DiscountPercent: 100},
+			wantLine:  NewMoney(0),
+			wantTotal: NewMoney(10),
+		},
+		{
+			name:      "fractional discount rounds down to the cent",
+			item:      OrderItem{ProductName: "Widget", Quantity: 3, UnitPrice: NewMoney(19.99), DiscountPercent: 12.5}, // 52.47375
+			wantLine:  NewMoney(52.47),
+			wantTotal: NewMoney(52.47 + 10),
+		},
+		{
+			name:      "fractional discount rounds up to the cent",
+			item:      OrderItem{ProductName: "Widget", Quantity: 1, UnitPrice: NewMoney(9.99), DiscountPercent: 33.33}, // 6.660333
+			wantLine:  NewMoney(6.66),
+			wantTotal: NewMoney(6.66 + 10),
+		},
+		{
+			name:      "half a cent rounds away from zero",
+			item:      OrderItem{ProductName: "Widget", Quantity: 1, UnitPrice: NewMoney(0.25), DiscountPercent: 10}, // 0.225
+			wantLine:  NewMoney(0.23),
+			wantTotal: NewMoney(0.23 + 10),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := []OrderItem{tt.item, {ProductName: "Gadget", Quantity: 1, UnitPrice: NewMoney(10)}}
+			order, err := NewOrder("Acme", items)
+			if err != nil {
+				t.Fatalf("expected order to be accepted, got %v", err)
+			}
+			if order.Items[0].TotalPrice != tt.wantLine {
+				t.Errorf("expected line total %v, got %v", tt.wantLine, order.Items[0].TotalPrice)
+			}
+			if order.Items[0].DiscountPercent != tt.item.DiscountPercent {
+				t.Errorf("expected discount %v to be kept, got %v", tt.item.DiscountPercent, order.Items[0].DiscountPercent)
+			}
+			if order.TotalAmount != tt.wantTotal {
+				t.Errorf("expected total %v, got %v", tt.wantTotal, order.TotalAmount)
+			}
+		})
+	}
+}
+
+func TestNewOrder_DiscountOutOfRange(t *testing.T) {
+	for _, discount := range []float64{-5, 100.01, 250} {
+		items := []OrderItem{{ProductName: "Widget", Quantity: 1, UnitPrice: NewMoney(10), DiscountPercent: discount}}
+		_, err := NewOrder("Acme", items)
+		if !errors.Is(err, ErrInvalidDiscount) {
+			t.Fatalf("discount %v: expected ErrInvalidDiscount, got %v", discount, err)
+		}
+		appErr := apperrors.GetAppError(err)
+		if appErr == nil || appErr.Details["discount_percent"] != discount {
+			t.Errorf("discount %v: expected it to be reported, got %v", discount, err)
+		}
+	}
+}
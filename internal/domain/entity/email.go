@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"errors"
+	"net/mail"
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// MaxEmailLength is the longest customer email accepted (RFC 5321 path limit)
+const MaxEmailLength = 254
+
+// ErrInvalidEmail is the cause of errors returned for a malformed customer email
+var ErrInvalidEmail = errors.New("invalid customer email")
+
+// WithCustomerEmail sets the customer email of the new order. The address is trimmed and
+// must be a bare address such as jane@example.com; an empty email leaves the order without one.
+func WithCustomerEmail(email string) OrderOption {
+	return func(o *orderOptions) {
+		o.customerEmail = strings.TrimSpace(email)
+	}
+}
+
+// ValidateEmail checks that email is a single bare address (no display name or angle
+// brackets) with a dotted domain. An empty email is valid, since the field is optional.
+func ValidateEmail(email string) error {
+	if email == "" {
+		return nil
+	}
+
+	invalid := func(reason string) error {
+		return apperrors.NewInvalidEntityError("invalid customer email").WithDetails(map[string]interface{}{
+			"customer_email": email,
+			"reason":         reason,
+		}).WithCause(ErrInvalidEmail)
+	}
+
+	if len(email) > MaxEmailLength {
+		return invalid("longer than 254 characters")
+	}
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email {
+		return invalid("not a valid address")
+	}
+	at := strings.LastIndex(email, "@")
+	domain := email[at+1:]
+	if !strings.Contains(domain, ".") || strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") {
+		return invalid("domain must contain a dot")
+	}
+	return nil
+}
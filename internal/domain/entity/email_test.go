@@ -0,0 +1,50 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestNewOrder_CustomerEmail(t *testing.T) {
+	items := []OrderItem{{ProductName: "Mug", Quantity: 1, UnitPrice: NewMoney(8.5)}}
+
+	order, err := NewOrder("Jane Doe", items, WithCustomerEmail("  jane.doe+orders@example.co.uk "))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.CustomerEmail != "jane.doe+orders@example.co.uk" {
+		t.Errorf("expected the trimmed email, got %q", order.CustomerEmail)
+	}
+
+	order, err = NewOrder("Jane Doe", items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.CustomerEmail != "" {
+		t.Errorf("expected no email, got %q", order.CustomerEmail)
+	}
+}
+
+func TestValidateEmail_RejectsMalformedAddresses(t *testing.T) {
+	malformed := []string{
+		"plainaddress",
+		"@example.com",
+		"jane@",
+		"jane@@example.com",
+		"jane doe@example.com",
+		"jane@example",
+		"jane@.example.com",
+		"jane@example.com.",
+		"Jane Doe <jane@example.com>",
+		"<jane@example.com>",
+		"jane@example.com, john@example.com",
+		strings.Repeat("a", 250) + "@example.com",
+	}
+	for _, email := range malformed {
+		_, err := NewOrder("Jane Doe", []OrderItem{{ProductName: "Mug", Quantity: 1, UnitPrice: NewMoney(8.5)}}, WithCustomerEmail(email))
+		if !errors.Is(err, ErrInvalidEmail) {
+			t.Errorf("%q: expected ErrInvalidEmail, got %v", email, err)
+		}
+	}
+}
@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"math"
+	"time"
+)
+
+// LeadTimeModel describes how long an order takes to ship
+type LeadTimeModel struct {
+	BaseDays     int     // Days every order needs regardless of size
+	PerItemDays  float64 // Extra days per line item; the total is rounded up to whole days
+	SkipWeekends bool    // Count only business days (Monday to Friday)
+}
+
+// DefaultLeadTimeModel is used when no lead-time model is configured
+var DefaultLeadTimeModel = LeadTimeModel{
+	BaseDays:     2,
+	PerItemDays:  0.5,
+	SkipWeekends: true,
+}
+
+// EstimateShipDate returns the date (midnight, in placedAt's location) an order with itemCount
+// line items placed at placedAt is expected to ship. With SkipWeekends, weekend days are not
+// counted and an estimate never lands on a weekend.
+func EstimateShipDate(placedAt time.Time, itemCount int, model LeadTimeModel) time.Time {
+	days := model.BaseDays + int(math.Ceil(model.PerItemDays*float64(itemCount)))
+	if days < 0 {
+		days = 0
+	}
+
+	date := time.Date(placedAt.Year(), placedAt.Month(), placedAt.Day(), 0, 0, 0, 0, placedAt.Location())
+	if !model.SkipWeekends {
+		return date.AddDate(0, 0, days)
+	}
+
+	for date = nextBusinessDay(date, false); days > 0; days-- {
+		date = nextBusinessDay(date, true)
+	}
+	return date
+}
+
+// nextBusinessDay returns the first weekday on or (when advance is set) after date
+func nextBusinessDay(date time.Time, advance bool) time.Time {
+	if advance {
+		date = date.AddDate(0, 0, 1)
+	}
+	for date.Weekday() == time.Saturday || date.Weekday() == time.Sunday {
+		date = date.AddDate(0, 0, 1)
+	}
+	return date
+}
@@ -0,0 +1,67 @@
+package entity
+
+import (
+	"testing"
+	"time"
+)
+
+// monday is 2024-01-15, a Monday
+var monday = time.Date(2024, time.January, 15, 14, 30, 0, 0, time.UTC)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestEstimateShipDate_WeekdayBase(t *testing.T) {
+	model := LeadTimeModel{BaseDays: 2, SkipWeekends: true}
+
+	got := EstimateShipDate(monday, 0, model)
+	if want := date(2024, time.January, 17); !got.Equal(want) {
+		t.Errorf("expected %s, got %s", want.Format(time.DateOnly), got.Format(time.DateOnly))
+	}
+}
+
+func TestEstimateShipDate_SpansWeekend(t *testing.T) {
+	model := LeadTimeModel{BaseDays: 3, SkipWeekends: true}
+	thursday := monday.AddDate(0, 0, 3)
+
+	got := EstimateShipDate(thursday, 0, model)
+	if want := date(2024, time.January, 23); !got.Equal(want) {
+		t.Errorf("expected Tuesday %s, got %s", want.Format(time.DateOnly), got.Format(time.DateOnly))
+	}
+
+	// Calendar days are used when weekends are not skipped
+	model.SkipWeekends = false
+	got = EstimateShipDate(thursday, 0, model)
+	if want := date(2024, time.January, 21); !got.Equal(want) {
+		t.Errorf("expected Sunday %s, got %s", want.Format(time.DateOnly), got.Format(time.DateOnly))
+	}
+
+	// Orders placed on a weekend start counting from Monday
+	saturday := monday.AddDate(0, 0, 5)
+	got = EstimateShipDate(saturday, 0, LeadTimeModel{BaseDays: 0, SkipWeekends: true})
+	if want := date(2024, time.January, 22); !got.Equal(want) {
+		t.Errorf("expected Monday %s, got %s", want.Format(time.DateOnly), got.Format(time.DateOnly))
+	}
+}
+
+func TestEstimateShipDate_ItemCounts(t *testing.T) {
+	model := LeadTimeModel{BaseDays: 1, PerItemDays: 0.5, SkipWeekends: true}
+
+	tests := []struct {
+		items int
+		want  time.Time
+	}{
+		{items: 1, want: date(2024, time.January, 17)},  // 1 + ceil(0.5) = 2 days
+		{items: 2, want: date(2024, time.January, 17)},  // 1 + 1 = 2 days
+		{items: 3, want: date(2024, time.January, 18)},  // 1 + ceil(1.5) = 3 days
+		{items: 10, want: date(2024, time.January, 23)}, // 1 + 5 = 6 business days
+	}
+
+	for _, tt := range tests {
+		got := EstimateShipDate(monday, tt.items, model)
+		if !got.Equal(tt.want) {
+			t.Errorf("%d items: expected %s, got %s", tt.items, tt.want.Format(time.DateOnly), got.Format(time.DateOnly))
+		}
+	}
+}
@@ -0,0 +1,152 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// DefaultMaxAmount is the largest amount the orders tables can store (DECIMAL(10,2))
+const DefaultMaxAmount = 99999999.99
+
+// ErrAmountOverflow is the cause of errors returned when an item or order total does not fit
+// the money type
+var ErrAmountOverflow = errors.New("order amount overflows the money type")
+
+// Money is an amount in whole cents. Prices and totals are kept as Money so that summing
+// many lines is exact; float64 amounts such as 0.1 drift when added up.
+type Money int64
+
+// NewMoney converts a decimal amount such as 19.99 to Money, rounding to the nearest cent.
+// Amounts beyond the int64 range saturate, so the overflow checks of NewOrder reject them.
+func NewMoney(amount float64) Money {
+	cents := math.Round(amount * 100)
+	switch {
+	case math.IsNaN(cents):
+		return 0
+	// float64(math.MaxInt64) rounds up to 2^63, which is already out of range
+	case cents >= math.MaxInt64:
+		return math.MaxInt64
+	case cents <= math.MinInt64:
+		return math.MinInt64
+	}
+	return Money(cents)
+}
+
+// ParseMoney converts decimal text such as "1999.98" to Money. Up to two decimal places
+// are converted exactly; longer or exponent forms are rounded to the nearest cent.
+func ParseMoney(text string) (Money, error) {
+	text = strings.TrimSpace(text)
+	whole, fraction, hasDot := strings.Cut(text, ".")
+	if len(fraction) <= 2 && !strings.ContainsAny(text, "eE") {
+		negative := strings.HasPrefix(whole, "-")
+		units, err := strconv.ParseInt(whole, 10, 64)
+		if err == nil && (!hasDot || fraction != "") {
+			fraction += strings.Repeat("0", 2-len(fraction))
+			cents, fracErr := strconv.ParseUint(fraction, 10, 8)
+			if fracErr == nil && units <= math.MaxInt64/100 && units >= math.MinInt64/100 {
+				if negative {
+					return Money(units*100 - int64(cents)), nil
+				}
+				return Money(units*100 + int64(cents)), nil
+			}
+		}
+	}
+
+	amount, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid money amount %q: %w", text, err)
+	}
+	return NewMoney(amount), nil
+}
+
+// Cents returns the amount in whole cents
+func (m Money) Cents() int64 {
+	return int64(m)
+}
+
+// Float64 returns the amount in currency units (e.g. 19.99), for API responses and events
+func (m Money) Float64() float64 {
+	return float64(m) / 100
+}
+
+// String formats the amount with two decimal places, e.g. "1999.98"
+func (m Money) String() string {
+	sign := ""
+	cents := uint64(m)
+	if m < 0 {
+		sign = "-"
+		cents = -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// MarshalJSON writes the amount as a decimal number, e.g. 1999.98
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON reads a decimal number such as 1999.98
+func (m *Money) UnmarshalJSON(data []byte) error {
+	parsed, err := ParseMoney(string(data))
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// WithMaxAmount caps every line and order total, so amounts the money type cannot hold are
+// rejected instead of failing or wrapping when stored. A non-positive max only guards
+// against overflowing int64 cents (default).
+func WithMaxAmount(max float64) OrderOption {
+	return func(o *orderOptions) {
+		o.maxAmount = 0
+		if max > 0 {
+			o.maxAmount = NewMoney(max)
+		}
+	}
+}
+
+// computeTotals fills in each item's total price and returns the order total. Each line and
+// the running total are rejected with ErrAmountOverflow when they do not fit in int64 cents
+// or exceed maxAmount (when positive).
+func computeTotals(items []OrderItem, maxAmount Money) (Money, error) {
+	maxTotal := Money(math.MaxInt64)
+	if maxAmount > 0 {
+		maxTotal = maxAmount
+	}
+
+	var total Money
+	for i := range items {
+		line, ok := items[i].lineTotal()
+		if !ok || line > maxTotal {
+			return 0, amountOverflowError(i, items[i], maxAmount)
+		}
+		// Lines are non-negative, so this cannot overflow and catches totals past the max
+		if total > maxTotal-line {
+			return 0, amountOverflowError(i, items[i], maxAmount)
+		}
+		items[i].TotalPrice = line
+		total += line
+	}
+	return total, nil
+}
+
+// amountOverflowError reports the item whose total pushed an amount past the money type
+func amountOverflowError(index int, item OrderItem, maxAmount Money) error {
+	details := map[string]interface{}{
+		"item_index": index,
+		"quantity":   item.Quantity,
+		"unit_price": item.UnitPrice.Float64(),
+	}
+	if maxAmount > 0 {
+		details["max_amount"] = maxAmount.Float64()
+	}
+	return apperrors.NewInvalidEntityError("order amount exceeds the maximum the money type can hold").
+		WithDetails(details).WithCause(ErrAmountOverflow)
+}
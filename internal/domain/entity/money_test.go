@@ -0,0 +1,153 @@
+package entity
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// NewOrder's quantity and unit price bounds keep orders far from these limits, so the
+// overflow checks are exercised on computeTotals directly
+func TestComputeTotals_AmountOverflow(t *testing.T) {
+	tests := []struct {
+		name      string
+		items     []OrderItem
+		maxAmount float64
+		wantIndex int // -1 when the order is accepted
+		wantTotal Money
+	}{
+		{
+			name:      "normal values",
+			items:     []OrderItem{{Quantity: 3, UnitPrice: NewMoney(0.1)}, {Quantity: 2, UnitPrice: NewMoney(24.99)}},
+			wantIndex: -1,
+			wantTotal: NewMoney(50.28),
+		},
+		{
+			name:      "near-max quantity and price",
+			items:     []OrderItem{{Quantity: 1, UnitPrice: NewMoney(5)}, {Quantity: math.MaxInt64, UnitPrice: math.MaxInt64}},
+			wantIndex: 1,
+		},
+		{
+			name:      "line total beyond int64 cents",
+			items:     []OrderItem{{Quantity: math.MaxInt64 / 2, UnitPrice: NewMoney(100)}},
+			wantIndex: 0,
+		},
+		{
+			name:      "lines fit but the order total overflows",
+			items:     []OrderItem{{Quantity: 60_000_000_000, UnitPrice: NewMoney(1_000_000)}, {Quantity: 60_000_000_000, UnitPrice: NewMoney(1_000_000)}},
+			wantIndex: 1,
+		},
+		{
+			name:      "at the configured maximum",
+			items:     []OrderItem{{Quantity: 1, UnitPrice: NewMoney(60)}, {Quantity: 2, UnitPrice: NewMoney(20)}},
+			maxAmount: 100,
+			wantIndex: -1,
+			wantTotal: NewMoney(100),
+		},
+		{
+			name:      "over the configured maximum",
+			items:     []OrderItem{{Quantity: 1, UnitPrice: NewMoney(60)}, {Quantity: 2, UnitPrice: NewMoney(20.01)}},
+			maxAmount: 100,
+			wantIndex: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			total, err := computeTotals(tt.items, NewMoney(tt.maxAmount))
+			if tt.wantIndex < 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if total != tt.wantTotal {
+					t.Errorf("expected total %v, got %v", tt.wantTotal, total)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrAmountOverflow) {
+				t.Fatalf("expected ErrAmountOverflow, got %v", err)
+			}
+			appErr := apperrors.GetAppError(err)
+			if appErr == nil || appErr.Code != apperrors.ErrCodeInvalidEntity {
+				t.Fatalf("expected an invalid entity error, got %v", err)
+			}
+			if appErr.Details["item_index"] != tt.wantIndex {
+				t.Errorf("expected item_index %d, got %v", tt.wantIndex, appErr.Details["item_index"])
+			}
+		})
+	}
+}
+
+func TestNewOrder_SumsCentsExactly(t *testing.T) {
+	items := make([]OrderItem, 100)
+	for i := range items {
+		items[i] = OrderItem{ProductName: fmt.Sprintf("Sticker %d", i), Quantity: 1, UnitPrice: NewMoney(0.1)}
+	}
+
+	order, err := NewOrder("Jane Doe", items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.TotalAmount != NewMoney(10) || order.TotalAmount.String() != "10.00" {
+		t.Errorf("expected a total of exactly 10.00, got %s", order.TotalAmount)
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		text string
+		want Money
+	}{
+		{"1999.98", 199998},
+		{"10", 1000},
+		{"10.5", 1050},
+		{"0.07", 7},
+		{"-0.50", -50},
+		{"-12.3", -1230},
+		{"24.995", 2500}, // More than two places rounds to the cent
+		{"1e2", 10000},
+	}
+	for _, tt := range tests {
+		got, err := ParseMoney(tt.text)
+		if err != nil {
+			t.Errorf("%q: unexpected error: %v", tt.text, err)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("%q: expected %d cents, got %d", tt.text, tt.want, got)
+		}
+	}
+
+	for _, text := range []string{"", "abc", "1.2.3"} {
+		if _, err := ParseMoney(text); err == nil {
+			t.Errorf("%q: expected an error", text)
+		}
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{Amount: NewMoney(1999.98)})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(data) != `{"amount":1999.98}` {
+		t.Errorf("expected a decimal amount, got %s", data)
+	}
+
+	var decoded struct {
+		Amount Money `json:"amount"`
+	}
+	if err := json.Unmarshal([]byte(`{"amount":0.1}`), &decoded); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if decoded.Amount != 10 {
+		t.Errorf("expected 10 cents, got %d", decoded.Amount)
+	}
+}
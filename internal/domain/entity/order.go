@@ -8,27 +8,59 @@ import (
 
 // Order represents the order domain entity
 type Order struct {
-	ID           int64       `json:"id"`
-	CustomerName string      `json:"customer_name"`
-	Status       string      `json:"status"`
-	TotalAmount  float64     `json:"total_amount"`
-	Items        []OrderItem `json:"items"`
-	CreatedAt    time.Time   `json:"created_at"`
-	UpdatedAt    time.Time   `json:"updated_at"`
+	ID              int64       `json:"id"`
+	OrderNumber     string      `json:"order_number,omitempty"`
+	CustomerName    string      `json:"customer_name"`
+	CustomerEmail   string      `json:"customer_email,omitempty"`
+	ClientReference string      `json:"client_reference,omitempty"`
+	Status          string      `json:"status"`
+	TotalAmount     Money       `json:"total_amount"`
+	Items           []OrderItem `json:"items"`
+	CreatedAt       time.Time   `json:"created_at"`
+	UpdatedAt       time.Time   `json:"updated_at"`
+	DeletedAt       *time.Time  `json:"deleted_at,omitempty"`
+
+	EstimatedShipDate *time.Time `json:"estimated_ship_date,omitempty"`
 }
 
 // OrderItem represents an order item domain entity
 type OrderItem struct {
-	ID          int64   `json:"id"`
-	OrderID     int64   `json:"order_id"`
-	ProductName string  `json:"product_name"`
-	Quantity    int     `json:"quantity"`
-	UnitPrice   float64 `json:"unit_price"`
-	TotalPrice  float64 `json:"total_price"`
+	ID              int64   `json:"id"`
+	OrderID         int64   `json:"order_id"`
+	ProductName     string  `json:"product_name"`
+	SKU             string  `json:"sku,omitempty"`
+	Quantity        float64 `json:"quantity"`       // Whole for counted items; fractional with a Unit
+	Unit            string  `json:"unit,omitempty"` // Unit of measure (e.g. kg) for items sold by weight or length
+	UnitPrice       Money   `json:"unit_price"`
+	DiscountPercent float64 `json:"discount_percent,omitempty"` // Percentage (0-100) taken off the line
+	TotalPrice      Money   `json:"total_price"`                // Discounted line amount
+}
+
+// StatusChange represents a recorded transition of an order's status
+type StatusChange struct {
+	ID         int64     `json:"id"`
+	OrderID    int64     `json:"order_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	ChangedAt  time.Time `json:"changed_at"`
 }
 
-// ValidStatuses defines the valid order statuses
-var ValidStatuses = []string{"pending", "processing", "completed", "cancelled"}
+// ValidStatuses is the one authoritative list of order statuses. The status binding tags,
+// validation messages and the orders status check constraint must list exactly these.
+var ValidStatuses = []string{"pending", "paid", "processing", "shipped", "completed", "cancelled"}
+
+// allowedTransitions lists the statuses each status may move to. Orders go
+// pending → paid → shipped → completed, optionally through processing after pending or paid,
+// which may also complete directly. Orders can be cancelled until they ship; completed and
+// cancelled orders are final.
+var allowedTransitions = map[string][]string{
+	"pending":    {"paid", "processing", "cancelled"},
+	"paid":       {"processing", "shipped", "cancelled"},
+	"processing": {"shipped", "completed", "cancelled"},
+	"shipped":    {"completed"},
+	"completed":  {},
+	"cancelled":  {},
+}
 
 // Domain errors
 var (
@@ -36,52 +68,109 @@ var (
 	ErrEmptyItems          = errors.New("order must have at least one item")
 	ErrInvalidQuantity     = errors.New("item quantity must be greater than 0")
 	ErrInvalidUnitPrice    = errors.New("item unit price cannot be negative")
+	ErrUnitPriceTooHigh    = errors.New("item unit price exceeds the maximum allowed")
 	ErrInvalidStatus       = errors.New("invalid order status")
+	ErrInvalidTransition   = errors.New("invalid order status transition")
+	ErrItemsNotEditable    = errors.New("order items can only be changed while the order is pending")
 )
 
+// MaxItemUnitPrice is the highest unit price any item may carry, whatever WithMaxUnitPrice allows
+const MaxItemUnitPrice Money = 1_000_000 * 100
+
+// WithMaxUnitPrice rejects items priced above max, catching slips such as a misplaced
+// decimal point. A non-positive max leaves only the MaxItemUnitPrice cap (default).
+func WithMaxUnitPrice(max float64) OrderOption {
+	return func(o *orderOptions) {
+		o.maxUnitPrice = 0
+		if max > 0 {
+			o.maxUnitPrice = NewMoney(max)
+		}
+	}
+}
+
 // NewOrder creates a new order with validation
-func NewOrder(customerName string, items []OrderItem) (*Order, error) {
+func NewOrder(customerName string, items []OrderItem, opts ...OrderOption) (*Order, error) {
+	options := orderOptions{duplicateSKUPolicy: DuplicateSKUAllow, duplicateProductPolicy: DuplicateProductReject}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	if customerName == "" {
 		return nil, apperrors.NewInvalidEntityError("customer name is required").WithCause(ErrInvalidCustomerName)
 	}
 	if len(items) == 0 {
 		return nil, apperrors.NewInvalidEntityError("order must have at least one item").WithCause(ErrEmptyItems)
 	}
+	if err := ValidateEmail(options.customerEmail); err != nil {
+		return nil, err
+	}
 
-	// Calculate total amount
-	var totalAmount float64
 	for i := range items {
 		if items[i].ProductName == "" {
 			return nil, apperrors.NewInvalidEntityError("product name is required").WithDetails(map[string]interface{}{
 				"item_index": i,
 			})
 		}
-		if items[i].Quantity <= 0 {
-			return nil, apperrors.NewInvalidEntityError("item quantity must be greater than 0").WithDetails(map[string]interface{}{
-				"item_index": i,
-				"quantity":   items[i].Quantity,
-			}).WithCause(ErrInvalidQuantity)
+		if err := validateItemQuantity(i, items[i]); err != nil {
+			return nil, err
 		}
-		if items[i].UnitPrice < 0 {
-			return nil, apperrors.NewInvalidEntityError("item unit price cannot be negative").WithDetails(map[string]interface{}{
-				"item_index": i,
-				"unit_price": items[i].UnitPrice,
-			}).WithCause(ErrInvalidUnitPrice)
+		if err := validateItemUnitPrice(i, items[i], options.maxUnitPrice); err != nil {
+			return nil, err
+		}
+		if err := validateItemDiscount(i, items[i]); err != nil {
+			return nil, err
 		}
-		items[i].TotalPrice = float64(items[i].Quantity) * items[i].UnitPrice
-		totalAmount += items[i].TotalPrice
+	}
+
+	items, err := applyDuplicateSKUPolicy(items, options.duplicateSKUPolicy)
+	if err != nil {
+		return nil, err
+	}
+	// SKU merges run first, so lines they combine are not reported as duplicate products
+	items, err = applyDuplicateProductPolicy(items, options.duplicateProductPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	totalAmount, err := computeTotals(items, options.maxAmount)
+	if err != nil {
+		return nil, err
 	}
 
 	return &Order{
-		CustomerName: customerName,
-		Status:       "pending",
-		TotalAmount:  totalAmount,
-		Items:        items,
-		CreatedAt:    time.Now(),
-		UpdatedAt:    time.Now(),
+		CustomerName:  customerName,
+		CustomerEmail: options.customerEmail,
+		Status:        "pending",
+		TotalAmount:   totalAmount,
+		Items:         items,
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
 	}, nil
 }
 
+// validateItemUnitPrice checks the unit price of the item at index against 0 and the lower of
+// max (when positive) and MaxItemUnitPrice
+func validateItemUnitPrice(index int, item OrderItem, max Money) error {
+	if item.UnitPrice < 0 {
+		return apperrors.NewInvalidEntityError("item unit price cannot be negative").WithDetails(map[string]interface{}{
+			"item_index": index,
+			"unit_price": item.UnitPrice.Float64(),
+		}).WithCause(ErrInvalidUnitPrice)
+	}
+
+	if max <= 0 || max > MaxItemUnitPrice {
+		max = MaxItemUnitPrice
+	}
+	if item.UnitPrice > max {
+		return apperrors.NewInvalidEntityError("item unit price exceeds the maximum allowed").WithDetails(map[string]interface{}{
+			"item_index":     index,
+			"unit_price":     item.UnitPrice.Float64(),
+			"max_unit_price": max.Float64(),
+		}).WithCause(ErrUnitPriceTooHigh)
+	}
+	return nil
+}
+
 // UpdateStatus updates the order status with validation
 func (o *Order) UpdateStatus(status string) error {
 	if !isValidStatus(status) {
@@ -90,11 +179,44 @@ func (o *Order) UpdateStatus(status string) error {
 			"valid_statuses":  ValidStatuses,
 		}).WithCause(ErrInvalidStatus)
 	}
+	if err := ValidateStatusTransition(o.Status, status); err != nil {
+		return err
+	}
 	o.Status = status
 	o.UpdatedAt = time.Now()
 	return nil
 }
 
+// ValidateStatusTransition checks that an order may move from one status to another
+func ValidateStatusTransition(from string, to string) error {
+	for _, allowed := range allowedTransitions[from] {
+		if allowed == to {
+			return nil
+		}
+	}
+	return apperrors.NewBusinessRuleViolationError("invalid order status transition").WithDetails(map[string]interface{}{
+		"from":             from,
+		"to":               to,
+		"allowed_statuses": allowedTransitions[from],
+	}).WithCause(ErrInvalidTransition)
+}
+
+// ValidateItemsEditable checks that an order in the given status may still have its items
+// replaced. Only pending orders can change; once paid, processing or shipped the items are fixed.
+func ValidateItemsEditable(status string) error {
+	if status == "pending" {
+		return nil
+	}
+	return apperrors.NewBusinessRuleViolationError("order items can only be changed while the order is pending").WithDetails(map[string]interface{}{
+		"current_status": status,
+	}).WithCause(ErrItemsNotEditable)
+}
+
+// IsDeleted reports whether the order has been soft-deleted
+func (o *Order) IsDeleted() bool {
+	return o.DeletedAt != nil
+}
+
 // IsValidStatus checks if the status is valid (public for external validation)
 func IsValidStatus(status string) bool {
 	return isValidStatus(status)
@@ -112,7 +234,7 @@ func isValidStatus(status string) bool {
 
 // CalculateTotalAmount recalculates the total amount based on items
 func (o *Order) CalculateTotalAmount() {
-	var total float64
+	var total Money
 	for _, item := range o.Items {
 		total += item.TotalPrice
 	}
@@ -143,17 +265,14 @@ func (o *Order) Validate() error {
 				"item_index": i,
 			})
 		}
-		if item.Quantity <= 0 {
-			return apperrors.NewInvalidEntityError("item quantity must be greater than 0").WithDetails(map[string]interface{}{
-				"item_index": i,
-				"quantity":   item.Quantity,
-			}).WithCause(ErrInvalidQuantity)
+		if err := validateItemQuantity(i, item); err != nil {
+			return err
 		}
-		if item.UnitPrice < 0 {
-			return apperrors.NewInvalidEntityError("item unit price cannot be negative").WithDetails(map[string]interface{}{
-				"item_index": i,
-				"unit_price": item.UnitPrice,
-			}).WithCause(ErrInvalidUnitPrice)
+		if err := validateItemUnitPrice(i, item, 0); err != nil {
+			return err
+		}
+		if err := validateItemDiscount(i, item); err != nil {
+			return err
 		}
 	}
 
@@ -0,0 +1,29 @@
+package entity
+
+import (
+	"fmt"
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// DefaultOrderNumberFormat renders the year and the per-year sequence, e.g. ORD-2024-000123
+const DefaultOrderNumberFormat = "ORD-%d-%06d"
+
+// FormatOrderNumber renders an order number from a fmt template taking the year and the
+// sequence number within that year, in that order
+func FormatOrderNumber(format string, year int, sequence int64) string {
+	return fmt.Sprintf(format, year, sequence)
+}
+
+// ValidateOrderNumberFormat checks that a template consumes exactly the year and sequence
+func ValidateOrderNumberFormat(format string) error {
+	if rendered := FormatOrderNumber(format, 2024, 1); strings.Contains(rendered, "%!") {
+		return apperrors.NewValidationError("invalid order number format").WithDetails(map[string]interface{}{
+			"format":   format,
+			"rendered": rendered,
+			"example":  DefaultOrderNumberFormat,
+		})
+	}
+	return nil
+}
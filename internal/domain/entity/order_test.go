@@ -0,0 +1,130 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func TestNewOrder_MaxUnitPrice(t *testing.T) {
+	const maxPrice = 1000.0
+
+	tests := []struct {
+		name      string
+		prices    []float64
+		wantIndex int // -1 when the order is accepted
+	}{
+		{name: "all under the cap", prices: []float64{9.99, 999.99, 0}, wantIndex: -1},
+		{name: "exactly at the cap", prices: []float64{9.99, maxPrice}, wantIndex: -1},
+		{name: "second item over the cap", prices: []float64{9.99, 999000, 5}, wantIndex: 1},
+		{name: "last item over the cap", prices: []float64{9.99, 5, 1000.01}, wantIndex: 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			items := make([]OrderItem, len(tt.prices))
+			for i, price := range tt.prices {
+				items[i] = OrderItem{ProductName: fmt.Sprintf("Item %d", i), Quantity: 1, UnitPrice: NewMoney(price)}
+			}
+
+			_, err := NewOrder("Jane Doe", items, WithMaxUnitPrice(maxPrice))
+			if tt.wantIndex < 0 {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+
+			if !errors.Is(err, ErrUnitPriceTooHigh) {
+				t.Fatalf("expected ErrUnitPriceTooHigh, got %v", err)
+			}
+			appErr := apperrors.GetAppError(err)
+			if appErr == nil {
+				t.Fatalf("expected an AppError, got %v", err)
+			}
+			if appErr.Details["item_index"] != tt.wantIndex {
+				t.Errorf("expected item_index %d, got %v", tt.wantIndex, appErr.Details["item_index"])
+			}
+			if appErr.Details["max_unit_price"] != maxPrice {
+				t.Errorf("expected max_unit_price %v, got %v", maxPrice, appErr.Details["max_unit_price"])
+			}
+		})
+	}
+}
+
+func TestNewOrder_MaxUnitPriceDisabledByDefault(t *testing.T) {
+	if _, err := NewOrder("Jane Doe", []OrderItem{{ProductName: "Item", Quantity: 1, UnitPrice: 999000}}); err != nil {
+		t.Fatalf("expected no cap by default, got %v", err)
+	}
+}
+
+func TestNewOrder_HardUnitPriceCap(t *testing.T) {
+	atCap := []OrderItem{{ProductName: "Item", Quantity: 1, UnitPrice: MaxItemUnitPrice}}
+	if _, err := NewOrder("Jane Doe", atCap); err != nil {
+		t.Fatalf("expected a unit price at the cap to be accepted, got %v", err)
+	}
+
+	// A configured cap above the hard one does not lift it
+	overCap := []OrderItem{{ProductName: "Item", Quantity: 1, UnitPrice: MaxItemUnitPrice + 1}}
+	_, err := NewOrder("Jane Doe", overCap, WithMaxUnitPrice(5_000_000))
+	if !errors.Is(err, ErrUnitPriceTooHigh) {
+		t.Fatalf("expected ErrUnitPriceTooHigh, got %v", err)
+	}
+	if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Details["max_unit_price"] != MaxItemUnitPrice.Float64() {
+		t.Errorf("expected max_unit_price %v in the details, got %v", MaxItemUnitPrice.Float64(), err)
+	}
+}
+
+func TestValidateStatusTransition(t *testing.T) {
+	allowed := map[string]bool{
+		"pending->paid":         true,
+		"pending->processing":   true,
+		"pending->cancelled":    true,
+		"paid->processing":      true,
+		"paid->shipped":         true,
+		"paid->cancelled":       true,
+		"processing->shipped":   true,
+		"processing->completed": true,
+		"processing->cancelled": true,
+		"shipped->completed":    true,
+	}
+
+	for _, from := range ValidStatuses {
+		for _, to := range ValidStatuses {
+			edge := from + "->" + to
+			t.Run(edge, func(t *testing.T) {
+				order := &Order{Status: from}
+				err := order.UpdateStatus(to)
+
+				if allowed[edge] {
+					if err != nil {
+						t.Fatalf("expected %s to be allowed, got %v", edge, err)
+					}
+					if order.Status != to {
+						t.Errorf("expected status %s, got %s", to, order.Status)
+					}
+					return
+				}
+
+				if !errors.Is(err, ErrInvalidTransition) {
+					t.Fatalf("expected %s to be rejected with ErrInvalidTransition, got %v", edge, err)
+				}
+				appErr := apperrors.GetAppError(err)
+				if appErr == nil || appErr.Code != apperrors.ErrCodeBusinessRuleViolation {
+					t.Fatalf("expected a business rule violation, got %v", err)
+				}
+				if appErr.Details["from"] != from || appErr.Details["to"] != to {
+					t.Errorf("expected from=%s to=%s in details, got %v", from, to, appErr.Details)
+				}
+				if _, ok := appErr.Details["current_status"]; ok {
+					t.Errorf("expected only from/to to name the statuses, got %v", appErr.Details)
+				}
+				if order.Status != from {
+					t.Errorf("expected rejected transition to keep status %s, got %s", from, order.Status)
+				}
+			})
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// DuplicateProductPolicy decides what happens when several items of one order name the same
+// product. Names are compared trimmed and case-insensitively.
+type DuplicateProductPolicy string
+
+// Supported duplicate product policies
+const (
+	DuplicateProductReject DuplicateProductPolicy = "reject" // Fail the order (default)
+	DuplicateProductMerge  DuplicateProductPolicy = "merge"  // Sum quantities into the first item, keeping its price
+	DuplicateProductAllow  DuplicateProductPolicy = "allow"  // Keep every item as submitted
+)
+
+// ErrDuplicateProduct is the cause of errors returned under DuplicateProductReject
+var ErrDuplicateProduct = errors.New("duplicate item product name")
+
+// ParseDuplicateProductPolicy validates a policy name, treating an empty name as DuplicateProductReject
+func ParseDuplicateProductPolicy(name string) (DuplicateProductPolicy, error) {
+	switch policy := DuplicateProductPolicy(strings.ToLower(strings.TrimSpace(name))); policy {
+	case "":
+		return DuplicateProductReject, nil
+	case DuplicateProductReject, DuplicateProductMerge, DuplicateProductAllow:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid duplicate product policy %q: must be one of reject, merge, allow", name)
+	}
+}
+
+// WithDuplicateProductPolicy sets how NewOrder handles items naming the same product (default reject)
+func WithDuplicateProductPolicy(policy DuplicateProductPolicy) OrderOption {
+	return func(o *orderOptions) {
+		o.duplicateProductPolicy = policy
+	}
+}
+
+// productKey normalizes a product name for duplicate detection
+func productKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// applyDuplicateProductPolicy enforces the policy on items naming the same product
+func applyDuplicateProductPolicy(items []OrderItem, policy DuplicateProductPolicy) ([]OrderItem, error) {
+	if policy == DuplicateProductAllow {
+		return items, nil
+	}
+
+	firstIndex := make(map[string]int, len(items))
+	result := make([]OrderItem, 0, len(items))
+	for i, item := range items {
+		key := productKey(item.ProductName)
+		first, seen := firstIndex[key]
+		if !seen {
+			firstIndex[key] = len(result)
+			result = append(result, item)
+			continue
+		}
+
+		if policy != DuplicateProductMerge {
+			return nil, apperrors.NewInvalidEntityError("order contains the same product more than once; increase its quantity instead").WithDetails(map[string]interface{}{
+				"product_name":       item.ProductName,
+				"item_index":         i,
+				"first_product_name": result[first].ProductName,
+			}).WithCause(ErrDuplicateProduct)
+		}
+
+		// Quantities in different units cannot be added up
+		if item.Unit != result[first].Unit {
+			return nil, apperrors.NewInvalidEntityError("items naming the same product must use the same unit").WithDetails(map[string]interface{}{
+				"product_name": item.ProductName,
+				"item_index":   i,
+				"unit":         item.Unit,
+				"first_unit":   result[first].Unit,
+			}).WithCause(ErrDuplicateProduct)
+		}
+
+		// Merge: the first occurrence's name, SKU, price and discount win
+		result[first].Quantity += item.Quantity
+	}
+
+	return result, nil
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func TestNewOrder_DuplicateProductReject(t *testing.T) {
+	tests := []struct {
+		name      string
+		duplicate string
+	}{
+		{name: "exact duplicate", duplicate: "Widget"},
+		{name: "case-only difference", duplicate: "WIDGET"},
+		{name: "whitespace-only difference", duplicate: "  Widget "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewOrder("Jane Doe", []OrderItem{
+				{ProductName: "Widget", Quantity: 2, UnitPrice: NewMoney(10)},
+				{ProductName: "Gadget", Quantity: 1, UnitPrice: NewMoney(5)},
+				{ProductName: tt.duplicate, Quantity: 1, UnitPrice: NewMoney(10)},
+			})
+			if !errors.Is(err, ErrDuplicateProduct) {
+				t.Fatalf("expected ErrDuplicateProduct, got %v", err)
+			}
+			appErr := apperrors.GetAppError(err)
+			if appErr == nil || appErr.Code != apperrors.ErrCodeInvalidEntity {
+				t.Fatalf("expected an invalid entity error, got %v", err)
+			}
+			if appErr.Details["product_name"] != tt.duplicate || appErr.Details["item_index"] != 2 {
+				t.Errorf("expected details to point at the duplicate, got %v", appErr.Details)
+			}
+		})
+	}
+}
+
+func TestNewOrder_DuplicateProductMerge(t *testing.T) {
+	order, err := NewOrder("Jane Doe", []OrderItem{
+		{ProductName: "Widget", Quantity: 2, UnitPrice: NewMoney(10)},
+		{ProductName: "Gadget", Quantity: 1, UnitPrice: NewMoney(5)},
+		{ProductName: " widget", Quantity: 3, UnitPrice: NewMoney(12)},
+	}, WithDuplicateProductPolicy(DuplicateProductMerge))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order.Items) != 2 || order.Items[0].ProductName != "Widget" || order.Items[0].Quantity != 5 {
+		t.Fatalf("expected the widgets merged into the first line, got %+v", order.Items)
+	}
+	if order.TotalAmount != NewMoney(55) {
+		t.Errorf("expected 5 x 10 + 5 = 55 at the first line's price, got %v", order.TotalAmount)
+	}
+
+	_, err = NewOrder("Jane Doe", []OrderItem{
+		{ProductName: "Coffee", Quantity: 1, Unit: "kg", UnitPrice: NewMoney(20)},
+		{ProductName: "coffee", Quantity: 2, UnitPrice: NewMoney(8)},
+	}, WithDuplicateProductPolicy(DuplicateProductMerge))
+	if !errors.Is(err, ErrDuplicateProduct) {
+		t.Errorf("expected lines in different units not to be merged, got %v", err)
+	}
+}
+
+func TestParseDuplicateProductPolicy(t *testing.T) {
+	if policy, err := ParseDuplicateProductPolicy(""); err != nil || policy != DuplicateProductReject {
+		t.Errorf("expected an empty name to mean reject, got %q, %v", policy, err)
+	}
+	if policy, err := ParseDuplicateProductPolicy(" Merge "); err != nil || policy != DuplicateProductMerge {
+		t.Errorf("expected merge, got %q, %v", policy, err)
+	}
+	if _, err := ParseDuplicateProductPolicy("sum"); err == nil {
+		t.Error("expected an unknown policy to be rejected")
+	}
+}
@@ -0,0 +1,71 @@
+package entity
+
+import (
+	"errors"
+	"math"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// MaxUnitLength is the longest unit of measure an item may carry
+const MaxUnitLength = 20
+
+// MaxItemQuantity is the largest quantity of a single item, counted or measured. It keeps
+// typos such as 9999999999 from producing absurd totals.
+const MaxItemQuantity = 10000
+
+// ErrInvalidUnit is the cause of errors returned for an over-long unit of measure
+var ErrInvalidUnit = errors.New("item unit is too long")
+
+// ErrQuantityTooHigh is the cause of errors returned for a quantity above MaxItemQuantity
+var ErrQuantityTooHigh = errors.New("item quantity exceeds the maximum allowed")
+
+// IsMeasured reports whether the item is sold by a unit of measure (e.g. kg) rather than counted
+func (i OrderItem) IsMeasured() bool {
+	return i.Unit != ""
+}
+
+// validateItemQuantity checks the quantity of the item at index. Counted items need a whole
+// quantity of at least 1; measured items need a unit and any quantity greater than 0.
+// Neither may exceed MaxItemQuantity.
+func validateItemQuantity(index int, item OrderItem) error {
+	if item.Quantity > MaxItemQuantity {
+		return apperrors.NewInvalidEntityError("item quantity exceeds the maximum allowed").WithDetails(map[string]interface{}{
+			"item_index":   index,
+			"quantity":     item.Quantity,
+			"max_quantity": MaxItemQuantity,
+		}).WithCause(ErrQuantityTooHigh)
+	}
+
+	if !item.IsMeasured() {
+		if item.Quantity <= 0 {
+			return apperrors.NewInvalidEntityError("item quantity must be greater than 0").WithDetails(map[string]interface{}{
+				"item_index": index,
+				"quantity":   item.Quantity,
+			}).WithCause(ErrInvalidQuantity)
+		}
+		if item.Quantity != math.Trunc(item.Quantity) {
+			return apperrors.NewInvalidEntityError("item quantity must be a whole number unless the item has a unit").WithDetails(map[string]interface{}{
+				"item_index": index,
+				"quantity":   item.Quantity,
+			}).WithCause(ErrInvalidQuantity)
+		}
+		return nil
+	}
+
+	if len(item.Unit) > MaxUnitLength {
+		return apperrors.NewInvalidEntityError("item unit is too long").WithDetails(map[string]interface{}{
+			"item_index": index,
+			"unit":       item.Unit,
+			"max_length": MaxUnitLength,
+		}).WithCause(ErrInvalidUnit)
+	}
+	if !(item.Quantity > 0) {
+		return apperrors.NewInvalidEntityError("item quantity must be greater than 0").WithDetails(map[string]interface{}{
+			"item_index": index,
+			"quantity":   item.Quantity,
+			"unit":       item.Unit,
+		}).WithCause(ErrInvalidQuantity)
+	}
+	return nil
+}
@@ -0,0 +1,63 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNewOrder_MeasuredAndCountedItems(t *testing.T) {
+	order, err := NewOrder("Jane Doe", []OrderItem{
+		{ProductName: "Coffee beans", Quantity: 1.5, Unit: "kg", UnitPrice: NewMoney(24)},
+		{ProductName: "Mug", Quantity: 2, UnitPrice: NewMoney(8.5)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := order.Items[0].TotalPrice; got != NewMoney(36) {
+		t.Errorf("expected 1.5 kg x 24 = 36, got %v", got)
+	}
+	if got := order.Items[1].TotalPrice; got != NewMoney(17) {
+		t.Errorf("expected 2 x 8.5 = 17, got %v", got)
+	}
+	if order.TotalAmount != NewMoney(53) {
+		t.Errorf("expected total 53, got %v", order.TotalAmount)
+	}
+	if !order.Items[0].IsMeasured() || order.Items[1].IsMeasured() {
+		t.Errorf("expected only the first item to be measured")
+	}
+}
+
+func TestNewOrder_QuantityValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    OrderItem
+		wantErr error
+	}{
+		{name: "fractional counted item", item: OrderItem{Quantity: 1.5}, wantErr: ErrInvalidQuantity},
+		{name: "zero counted item", item: OrderItem{Quantity: 0}, wantErr: ErrInvalidQuantity},
+		{name: "zero measured item", item: OrderItem{Quantity: 0, Unit: "kg"}, wantErr: ErrInvalidQuantity},
+		{name: "negative measured item", item: OrderItem{Quantity: -0.5, Unit: "kg"}, wantErr: ErrInvalidQuantity},
+		{name: "over-long unit", item: OrderItem{Quantity: 1, Unit: "kilograms-of-finest-beans"}, wantErr: ErrInvalidUnit},
+		{name: "small measured item", item: OrderItem{Quantity: 0.125, Unit: "kg"}},
+		{name: "counted item at the maximum", item: OrderItem{Quantity: MaxItemQuantity}},
+		{name: "counted item over the maximum", item: OrderItem{Quantity: MaxItemQuantity + 1}, wantErr: ErrQuantityTooHigh},
+		{name: "measured item over the maximum", item: OrderItem{Quantity: MaxItemQuantity + 0.5, Unit: "kg"}, wantErr: ErrQuantityTooHigh},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.item.ProductName = "Item"
+			_, err := NewOrder("Jane Doe", []OrderItem{tt.item})
+			if tt.wantErr == nil {
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
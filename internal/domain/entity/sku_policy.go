@@ -0,0 +1,101 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// DuplicateSKUPolicy decides what happens when several items of one order share a SKU
+type DuplicateSKUPolicy string
+
+// Supported duplicate SKU policies
+const (
+	DuplicateSKUAllow  DuplicateSKUPolicy = "allow"  // Keep every item as submitted
+	DuplicateSKUReject DuplicateSKUPolicy = "reject" // Fail the order
+	DuplicateSKUMerge  DuplicateSKUPolicy = "merge"  // Sum quantities into the first item, keeping its price
+)
+
+// ErrDuplicateSKU is the cause of errors returned under DuplicateSKUReject
+var ErrDuplicateSKU = errors.New("duplicate item SKU")
+
+// ParseDuplicateSKUPolicy validates a policy name, treating an empty name as DuplicateSKUAllow
+func ParseDuplicateSKUPolicy(name string) (DuplicateSKUPolicy, error) {
+	switch policy := DuplicateSKUPolicy(strings.ToLower(strings.TrimSpace(name))); policy {
+	case "":
+		return DuplicateSKUAllow, nil
+	case DuplicateSKUAllow, DuplicateSKUReject, DuplicateSKUMerge:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid duplicate SKU policy %q: must be one of allow, reject, merge", name)
+	}
+}
+
+// orderOptions holds the optional settings of NewOrder
+type orderOptions struct {
+	duplicateSKUPolicy     DuplicateSKUPolicy
+	duplicateProductPolicy DuplicateProductPolicy
+	maxUnitPrice           Money
+	maxAmount              Money
+	customerEmail          string
+}
+
+// OrderOption configures optional behavior of NewOrder
+type OrderOption func(*orderOptions)
+
+// WithDuplicateSKUPolicy sets how NewOrder handles items sharing a SKU (default allow)
+func WithDuplicateSKUPolicy(policy DuplicateSKUPolicy) OrderOption {
+	return func(o *orderOptions) {
+		o.duplicateSKUPolicy = policy
+	}
+}
+
+// applyDuplicateSKUPolicy enforces the policy on items sharing a SKU.
+// Items without a SKU are never considered duplicates.
+func applyDuplicateSKUPolicy(items []OrderItem, policy DuplicateSKUPolicy) ([]OrderItem, error) {
+	if policy == DuplicateSKUAllow || policy == "" {
+		return items, nil
+	}
+
+	firstIndex := make(map[string]int, len(items))
+	result := make([]OrderItem, 0, len(items))
+	for i, item := range items {
+		if item.SKU == "" {
+			result = append(result, item)
+			continue
+		}
+
+		first, seen := firstIndex[item.SKU]
+		if !seen {
+			firstIndex[item.SKU] = len(result)
+			result = append(result, item)
+			continue
+		}
+
+		if policy == DuplicateSKUReject {
+			return nil, apperrors.NewBusinessRuleViolationError("order contains duplicate item SKUs").WithDetails(map[string]interface{}{
+				"sku":              item.SKU,
+				"item_index":       i,
+				"unit_price":       item.UnitPrice.Float64(),
+				"first_unit_price": result[first].UnitPrice.Float64(),
+			}).WithCause(ErrDuplicateSKU)
+		}
+
+		// Quantities in different units cannot be added up
+		if item.Unit != result[first].Unit {
+			return nil, apperrors.NewBusinessRuleViolationError("items sharing a SKU must use the same unit").WithDetails(map[string]interface{}{
+				"sku":        item.SKU,
+				"item_index": i,
+				"unit":       item.Unit,
+				"first_unit": result[first].Unit,
+			}).WithCause(ErrDuplicateSKU)
+		}
+
+		// Merge: the first occurrence's price and discount win
+		result[first].Quantity += item.Quantity
+	}
+
+	return result, nil
+}
@@ -0,0 +1,75 @@
+package entity
+
+import (
+	"errors"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func duplicateSKUItems() []OrderItem {
+	return []OrderItem{
+		{ProductName: "Widget", SKU: "W-1", Quantity: 2, UnitPrice: NewMoney(10)},
+		{ProductName: "Gadget", SKU: "G-1", Quantity: 1, UnitPrice: NewMoney(5)},
+		{ProductName: "Widget", SKU: "W-1", Quantity: 3, UnitPrice: NewMoney(12)},
+		{ProductName: "Loose item", Quantity: 1, UnitPrice: NewMoney(1)},
+	}
+}
+
+func TestNewOrder_DuplicateSKUAllow(t *testing.T) {
+	// The duplicate SKU lines also name the same product, which is rejected by default
+	order, err := NewOrder("Jane Doe", duplicateSKUItems(), WithDuplicateProductPolicy(DuplicateProductAllow))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order.Items) != 4 {
+		t.Errorf("expected all 4 items to be kept, got %d", len(order.Items))
+	}
+	if order.TotalAmount != NewMoney(62) {
+		t.Errorf("expected total 62, got %v", order.TotalAmount)
+	}
+}
+
+func TestNewOrder_DuplicateSKUReject(t *testing.T) {
+	_, err := NewOrder("Jane Doe", duplicateSKUItems(), WithDuplicateSKUPolicy(DuplicateSKUReject))
+	if !errors.Is(err, ErrDuplicateSKU) {
+		t.Fatalf("expected ErrDuplicateSKU, got %v", err)
+	}
+	appErr := apperrors.GetAppError(err)
+	if appErr == nil || appErr.Code != apperrors.ErrCodeBusinessRuleViolation {
+		t.Fatalf("expected a business rule violation, got %v", err)
+	}
+	if appErr.Details["sku"] != "W-1" || appErr.Details["item_index"] != 2 {
+		t.Errorf("expected details to point at the duplicate, got %v", appErr.Details)
+	}
+}
+
+func TestNewOrder_DuplicateSKUMerge(t *testing.T) {
+	order, err := NewOrder("Jane Doe", duplicateSKUItems(), WithDuplicateSKUPolicy(DuplicateSKUMerge))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(order.Items) != 3 {
+		t.Fatalf("expected duplicates merged into 3 items, got %d", len(order.Items))
+	}
+	merged := order.Items[0]
+	if merged.Quantity != 5 || merged.UnitPrice != NewMoney(10) || merged.TotalPrice != NewMoney(50) {
+		t.Errorf("expected 5 x 10 = 50 keeping the first price, got %v x %v = %v",
+			merged.Quantity, merged.UnitPrice, merged.TotalPrice)
+	}
+	if order.TotalAmount != NewMoney(56) {
+		t.Errorf("expected total 56, got %v", order.TotalAmount)
+	}
+}
+
+func TestParseDuplicateSKUPolicy(t *testing.T) {
+	if policy, err := ParseDuplicateSKUPolicy(""); err != nil || policy != DuplicateSKUAllow {
+		t.Errorf("expected empty name to mean allow, got %q (err %v)", policy, err)
+	}
+	if policy, err := ParseDuplicateSKUPolicy(" Merge "); err != nil || policy != DuplicateSKUMerge {
+		t.Errorf("expected merge, got %q (err %v)", policy, err)
+	}
+	if _, err := ParseDuplicateSKUPolicy("drop"); err == nil {
+		t.Error("expected an error for an unknown policy")
+	}
+}
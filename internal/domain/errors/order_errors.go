@@ -57,8 +57,22 @@ func NewInvalidOrderStatusError(status string, validStatuses []string) *apperror
 	})
 }
 
+func NewCompletedOrderDeletionError(orderID int64) *apperrors.AppError {
+	return apperrors.NewBusinessRuleViolationError("completed orders cannot be deleted").WithDetails(map[string]interface{}{
+		"order_id": orderID,
+		"status":   "completed",
+	})
+}
+
 func NewInvalidOrderIDError(orderID int64) *apperrors.AppError {
 	return apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
 		"provided_id": orderID,
 	})
 }
+
+func NewPageOutOfRangeError(page string, maxPage int) *apperrors.AppError {
+	return apperrors.NewValidationError("page is out of range").WithDetails(map[string]interface{}{
+		"provided_page": page,
+		"max_page":      maxPage,
+	})
+}
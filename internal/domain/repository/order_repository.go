@@ -2,6 +2,8 @@ package repository
 
 import (
 	"context"
+	"time"
+
 	"online-order-management-system/internal/domain/entity"
 )
 
@@ -13,17 +15,89 @@ type PaginationInfo struct {
 	ItemsPerPage int   `json:"items_per_page"`
 }
 
+// StatusSummary aggregates the orders in one status
+type StatusSummary struct {
+	Count   int64
+	Revenue entity.Money // Sum of the orders' total amounts
+}
+
+// OrderFilter narrows which orders are returned by list-style queries.
+// Zero values mean "no filter".
+type OrderFilter struct {
+	Status         string
+	Customer       string     // Case-insensitive substring of the customer name
+	CreatedFrom    *time.Time // Only orders created at or after this instant
+	CreatedTo      *time.Time // Only orders created at or before this instant
+	IncludeDeleted bool       // Include soft-deleted orders, which are excluded by default
+	Sort           OrderSort  // Order of ListOrders results; other queries ignore it
+}
+
 // OrderRepository defines the contract for order data access operations
 type OrderRepository interface {
 	// CreateOrderWithItems creates a new order with its items in a single transaction
 	CreateOrderWithItems(ctx context.Context, order *entity.Order) (*entity.Order, error)
 
+	// CreatePaidOrder creates an order with status paid and records its pending → paid
+	// transition, atomically, for checkouts that are paid up front
+	CreatePaidOrder(ctx context.Context, order *entity.Order) (*entity.Order, error)
+
+	// CreateOrderIdempotent creates an order like CreateOrderWithItems, or CreatePaidOrder when
+	// paid, and records key against it in the same transaction. If key was recorded less than
+	// ttl ago nothing is created; the order it recorded is returned with replayed set instead.
+	CreateOrderIdempotent(ctx context.Context, order *entity.Order, paid bool, key string, ttl time.Duration) (created *entity.Order, replayed bool, err error)
+
 	// GetOrderByID retrieves an order by its ID including its items
 	GetOrderByID(ctx context.Context, id int64) (*entity.Order, error)
 
-	// ListOrders retrieves orders with pagination using page number and limit
-	ListOrders(ctx context.Context, page int, limit int) ([]*entity.Order, *PaginationInfo, error)
+	// GetOrderByClientReference retrieves the most recent order carrying the client reference
+	GetOrderByClientReference(ctx context.Context, reference string) (*entity.Order, error)
+
+	// GetStatuses retrieves only the statuses of the given orders, keyed by ID.
+	// Unknown and soft-deleted IDs are omitted from the result.
+	GetStatuses(ctx context.Context, ids []int64) (map[int64]string, error)
+
+	// ListOrders retrieves orders matching the filter with pagination using page number and limit
+	ListOrders(ctx context.Context, page int, limit int, filter OrderFilter) ([]*entity.Order, *PaginationInfo, error)
+
+	// ListOrdersAfter retrieves up to limit orders with an ID below cursor (0 starts from the
+	// newest), ordered by ID descending. It returns the cursor for the next page, or 0 when
+	// no orders remain.
+	ListOrdersAfter(ctx context.Context, cursor int64, limit int, filter OrderFilter) ([]*entity.Order, int64, error)
+
+	// CountOrders returns how many orders match the filter without loading any of them.
+	// filter.Sort is ignored.
+	CountOrders(ctx context.Context, filter OrderFilter) (int64, error)
+
+	// SummarizeOrders returns the order count and revenue of every status that has orders,
+	// keyed by status, in a single grouped query. Soft-deleted orders are excluded.
+	SummarizeOrders(ctx context.Context) (map[string]StatusSummary, error)
+
+	// SoftDeleteOrder marks an order as deleted, hiding it from lookups and default listings.
+	// Completed orders are left untouched and reported as a business rule violation.
+	SoftDeleteOrder(ctx context.Context, id int64) error
+
+	// DeleteOrder permanently removes an order and its items. Completed orders are left
+	// untouched and reported as a business rule violation.
+	DeleteOrder(ctx context.Context, id int64) error
+
+	// PurgeDeletedOrders permanently removes orders soft-deleted before the cutoff, together
+	// with their items and status history, and returns how many orders were removed
+	PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error)
+
+	// UpdateOrderStatus updates the status of an existing order and records the transition.
+	// It reports whether the status changed; requesting the current status writes nothing.
+	UpdateOrderStatus(ctx context.Context, id int64, status string) (bool, error)
+
+	// UpdateOrderItems replaces the items of a pending order and stores the recomputed total
+	// in one transaction, returning the updated order. Items must already be validated, with
+	// their line totals set.
+	UpdateOrderItems(ctx context.Context, orderID int64, items []entity.OrderItem) (*entity.Order, error)
+
+	// StreamOrders emits orders (newest first, with items) as they are scanned instead of
+	// materializing the whole result set. Both channels are closed when streaming ends;
+	// at most one error is sent. Cancelling ctx stops emission.
+	StreamOrders(ctx context.Context, filter OrderFilter) (<-chan *entity.Order, <-chan error)
 
-	// UpdateOrderStatus updates the status of an existing order
-	UpdateOrderStatus(ctx context.Context, id int64, status string) error
+	// GetStatusHistory retrieves the recorded status transitions of an order, oldest first
+	GetStatusHistory(ctx context.Context, orderID int64) ([]entity.StatusChange, error)
 }
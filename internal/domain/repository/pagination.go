@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"math"
+	"strconv"
+
+	domainerrors "online-order-management-system/internal/domain/errors"
+)
+
+// MaxPage is the highest page number accepted by paginated listings
+const MaxPage = 1_000_000
+
+// PageOffset computes the row offset of a 1-based page, rejecting pages whose
+// offset would overflow an int instead of wrapping to a bogus value
+func PageOffset(page int, limit int) (int, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit > 0 && page-1 > math.MaxInt/limit {
+		return 0, domainerrors.NewPageOutOfRangeError(strconv.Itoa(page), MaxPage)
+	}
+	return (page - 1) * limit, nil
+}
@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"math"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func TestPageOffset(t *testing.T) {
+	offset, err := PageOffset(3, 10)
+	if err != nil || offset != 20 {
+		t.Errorf("expected offset 20, got %d (err %v)", offset, err)
+	}
+
+	offset, err = PageOffset(0, 10)
+	if err != nil || offset != 0 {
+		t.Errorf("expected offset 0 for page 0, got %d (err %v)", offset, err)
+	}
+
+	_, err = PageOffset(math.MaxInt, 100)
+	if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != apperrors.ErrCodeValidation {
+		t.Errorf("expected a validation error for an overflowing offset, got %v", err)
+	}
+}
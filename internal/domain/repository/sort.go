@@ -0,0 +1,52 @@
+package repository
+
+import (
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// Sort fields accepted by paginated listings
+const (
+	SortByCreatedAt   = "created_at"
+	SortByTotalAmount = "total_amount"
+	SortByID          = "id"
+)
+
+// SortFields lists the accepted sort fields, for validation messages
+var SortFields = []string{SortByCreatedAt, SortByTotalAmount, SortByID}
+
+// OrderSort orders a paginated listing. Ties are always broken by ID in the same
+// direction, so pages never overlap. The zero value is newest first.
+type OrderSort struct {
+	Field     string // One of SortFields; empty means SortByCreatedAt
+	Ascending bool
+}
+
+// ParseOrderSort validates a sort field and an asc/desc direction against the allow-list.
+// Empty values keep the default of created_at descending.
+func ParseOrderSort(field string, direction string) (OrderSort, error) {
+	var sort OrderSort
+
+	switch field = strings.ToLower(strings.TrimSpace(field)); field {
+	case "", SortByCreatedAt, SortByTotalAmount, SortByID:
+		sort.Field = field
+	default:
+		return OrderSort{}, apperrors.NewValidationError("Invalid sort. Must be one of: " + strings.Join(SortFields, ", ")).WithDetails(map[string]interface{}{
+			"sort":         field,
+			"valid_fields": SortFields,
+		})
+	}
+
+	switch direction = strings.ToLower(strings.TrimSpace(direction)); direction {
+	case "", "desc":
+	case "asc":
+		sort.Ascending = true
+	default:
+		return OrderSort{}, apperrors.NewValidationError("Invalid order. Must be asc or desc").WithDetails(map[string]interface{}{
+			"order": direction,
+		})
+	}
+
+	return sort, nil
+}
@@ -0,0 +1,204 @@
+package db
+
+import (
+	"container/list"
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+)
+
+// CacheStats reports how GetOrderByID lookups were served by a CachingOrderRepository
+type CacheStats struct {
+	Hits    int64 `json:"hits"`
+	Misses  int64 `json:"misses"`
+	Entries int   `json:"entries"`
+	Size    int   `json:"size"`
+}
+
+// cachedOrder is an LRU entry; order is never handed out, only copies of it
+type cachedOrder struct {
+	id        int64
+	order     *entity.Order
+	expiresAt time.Time
+}
+
+// CachingOrderRepository serves GetOrderByID from an in-process LRU cache whose entries
+// expire after a TTL. Writes through this repository evict the orders they change, so a
+// single instance never serves its own stale reads; writes made by other instances are
+// only picked up once the TTL expires. Every other call is passed through unchanged.
+type CachingOrderRepository struct {
+	repository.OrderRepository
+	size int
+	ttl  time.Duration
+	now  func() time.Time
+
+	mu      sync.Mutex
+	entries map[int64]*list.Element
+	lru     *list.List // Front is the most recently used
+	// generation is bumped by every eviction, so a lookup that raced with a write does not
+	// store the order it read before the write
+	generation uint64
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// NewCachingOrderRepository wraps repo with a cache of up to size orders kept for ttl.
+// A non-positive size defaults to 1000 and a non-positive ttl to 30 seconds.
+func NewCachingOrderRepository(repo repository.OrderRepository, size int, ttl time.Duration) *CachingOrderRepository {
+	if size <= 0 {
+		size = 1000
+	}
+	if ttl <= 0 {
+		ttl = 30 * time.Second
+	}
+	return &CachingOrderRepository{
+		OrderRepository: repo,
+		size:            size,
+		ttl:             ttl,
+		now:             time.Now,
+		entries:         make(map[int64]*list.Element, size),
+		lru:             list.New(),
+	}
+}
+
+// Stats returns the hit and miss counters and the current occupancy of the cache
+func (r *CachingOrderRepository) Stats() CacheStats {
+	r.mu.Lock()
+	entries := r.lru.Len()
+	r.mu.Unlock()
+
+	return CacheStats{
+		Hits:    r.hits.Load(),
+		Misses:  r.misses.Load(),
+		Entries: entries,
+		Size:    r.size,
+	}
+}
+
+// GetOrderByID returns a cached copy of the order, loading and caching it on a miss.
+// Errors, including not found, are never cached.
+func (r *CachingOrderRepository) GetOrderByID(ctx context.Context, id int64) (*entity.Order, error) {
+	r.mu.Lock()
+	if elem, ok := r.entries[id]; ok {
+		entry := elem.Value.(*cachedOrder)
+		if r.now().Before(entry.expiresAt) {
+			r.lru.MoveToFront(elem)
+			order := cloneOrder(entry.order)
+			r.mu.Unlock()
+			r.hits.Add(1)
+			return order, nil
+		}
+		r.remove(elem)
+	}
+	generation := r.generation
+	r.mu.Unlock()
+	r.misses.Add(1)
+
+	order, err := r.OrderRepository.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	if r.generation == generation {
+		r.store(id, cloneOrder(order))
+	}
+	r.mu.Unlock()
+	return order, nil
+}
+
+// UpdateOrderStatus updates an order status and evicts the cached order
+func (r *CachingOrderRepository) UpdateOrderStatus(ctx context.Context, id int64, status string) (bool, error) {
+	defer r.evict(id)
+	return r.OrderRepository.UpdateOrderStatus(ctx, id, status)
+}
+
+// UpdateOrderItems replaces order items and evicts the cached order
+func (r *CachingOrderRepository) UpdateOrderItems(ctx context.Context, orderID int64, items []entity.OrderItem) (*entity.Order, error) {
+	defer r.evict(orderID)
+	return r.OrderRepository.UpdateOrderItems(ctx, orderID, items)
+}
+
+// SoftDeleteOrder soft-deletes an order and evicts the cached order
+func (r *CachingOrderRepository) SoftDeleteOrder(ctx context.Context, id int64) error {
+	defer r.evict(id)
+	return r.OrderRepository.SoftDeleteOrder(ctx, id)
+}
+
+// DeleteOrder deletes an order and evicts the cached order
+func (r *CachingOrderRepository) DeleteOrder(ctx context.Context, id int64) error {
+	defer r.evict(id)
+	return r.OrderRepository.DeleteOrder(ctx, id)
+}
+
+// PurgeDeletedOrders purges soft-deleted orders and empties the cache, since the purged
+// IDs are not known
+func (r *CachingOrderRepository) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer r.evictAll()
+	return r.OrderRepository.PurgeDeletedOrders(ctx, deletedBefore)
+}
+
+// evict drops the cached order with id. It runs after the write, whether or not the write
+// succeeded, since a failed write may still have changed the order.
+func (r *CachingOrderRepository) evict(id int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	if elem, ok := r.entries[id]; ok {
+		r.remove(elem)
+	}
+}
+
+// evictAll empties the cache
+func (r *CachingOrderRepository) evictAll() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.generation++
+	r.entries = make(map[int64]*list.Element, r.size)
+	r.lru.Init()
+}
+
+// store caches order as the most recently used entry, dropping the least recently used
+// one when full. Callers must hold the lock.
+func (r *CachingOrderRepository) store(id int64, order *entity.Order) {
+	entry := &cachedOrder{id: id, order: order, expiresAt: r.now().Add(r.ttl)}
+	if elem, ok := r.entries[id]; ok {
+		elem.Value = entry
+		r.lru.MoveToFront(elem)
+		return
+	}
+
+	r.entries[id] = r.lru.PushFront(entry)
+	if r.lru.Len() > r.size {
+		r.remove(r.lru.Back())
+	}
+}
+
+// remove drops an entry. Callers must hold the lock.
+func (r *CachingOrderRepository) remove(elem *list.Element) {
+	r.lru.Remove(elem)
+	delete(r.entries, elem.Value.(*cachedOrder).id)
+}
+
+// cloneOrder deep-copies an order so callers cannot modify the cached one
+func cloneOrder(order *entity.Order) *entity.Order {
+	cloned := *order
+	cloned.Items = make([]entity.OrderItem, len(order.Items))
+	copy(cloned.Items, order.Items)
+	if order.DeletedAt != nil {
+		deletedAt := *order.DeletedAt
+		cloned.DeletedAt = &deletedAt
+	}
+	if order.EstimatedShipDate != nil {
+		shipDate := *order.EstimatedShipDate
+		cloned.EstimatedShipDate = &shipDate
+	}
+	return &cloned
+}
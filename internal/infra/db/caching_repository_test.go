@@ -0,0 +1,107 @@
+package db
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/infra/memory"
+)
+
+// countingRepository counts the GetOrderByID calls reaching the wrapped repository
+type countingRepository struct {
+	*memory.InMemoryOrderRepository
+	gets int
+}
+
+func (r *countingRepository) GetOrderByID(ctx context.Context, id int64) (*entity.Order, error) {
+	r.gets++
+	return r.InMemoryOrderRepository.GetOrderByID(ctx, id)
+}
+
+func newCachedRepo(t *testing.T, orders int) (*CachingOrderRepository, *countingRepository) {
+	t.Helper()
+	inner := &countingRepository{InMemoryOrderRepository: memory.NewInMemoryOrderRepository()}
+	for i := 0; i < orders; i++ {
+		order, err := entity.NewOrder("Cached Customer", []entity.OrderItem{{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(10)}})
+		if err != nil {
+			t.Fatalf("failed to build order: %v", err)
+		}
+		if _, err := inner.CreateOrderWithItems(context.Background(), order); err != nil {
+			t.Fatalf("failed to seed order: %v", err)
+		}
+	}
+	return NewCachingOrderRepository(inner, 2, time.Minute), inner
+}
+
+func TestCachingOrderRepository_ServesRepeatReadsFromCache(t *testing.T) {
+	repo, inner := newCachedRepo(t, 1)
+	ctx := context.Background()
+
+	first, err := repo.GetOrderByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	first.Status = "tampered" // Callers must not be able to change the cached copy
+
+	second, err := repo.GetOrderByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if inner.gets != 1 {
+		t.Errorf("expected the second read to be served from cache, got %d repository reads", inner.gets)
+	}
+	if second.Status != "pending" {
+		t.Errorf("expected the cached order to be unaffected by callers, got status %q", second.Status)
+	}
+	if stats := repo.Stats(); stats.Hits != 1 || stats.Misses != 1 || stats.Entries != 1 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestCachingOrderRepository_StatusUpdateEvicts(t *testing.T) {
+	repo, inner := newCachedRepo(t, 1)
+	ctx := context.Background()
+
+	if _, err := repo.GetOrderByID(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.UpdateOrderStatus(ctx, 1, "paid"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	order, err := repo.GetOrderByID(ctx, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if order.Status != "paid" || inner.gets != 2 {
+		t.Errorf("expected a fresh read with status paid, got %q after %d reads", order.Status, inner.gets)
+	}
+}
+
+func TestCachingOrderRepository_ExpiresAndEvictsLeastRecentlyUsed(t *testing.T) {
+	repo, inner := newCachedRepo(t, 3)
+	ctx := context.Background()
+	now := time.Now()
+	repo.now = func() time.Time { return now }
+
+	for _, id := range []int64{1, 2, 1, 3} { // 2 is least recently used when 3 arrives
+		if _, err := repo.GetOrderByID(ctx, id); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	inner.gets = 0
+	_, _ = repo.GetOrderByID(ctx, 1)
+	_, _ = repo.GetOrderByID(ctx, 2)
+	if inner.gets != 1 {
+		t.Errorf("expected only the evicted order 2 to be reloaded, got %d reads", inner.gets)
+	}
+
+	now = now.Add(2 * time.Minute)
+	inner.gets = 0
+	_, _ = repo.GetOrderByID(ctx, 2)
+	if inner.gets != 1 {
+		t.Errorf("expected an expired entry to be reloaded, got %d reads", inner.gets)
+	}
+}
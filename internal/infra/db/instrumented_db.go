@@ -0,0 +1,42 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+
+	"online-order-management-system/pkg/querybudget"
+)
+
+// dbConn is the subset of *sql.DB used by the repository
+type dbConn interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error)
+}
+
+// instrumentedDB counts every statement against the request's query budget.
+// A transaction counts once, when it begins; statements inside it are not counted.
+type instrumentedDB struct {
+	db *sql.DB
+}
+
+func (i instrumentedDB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	querybudget.Record(ctx)
+	return i.db.QueryContext(ctx, query, args...)
+}
+
+func (i instrumentedDB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	querybudget.Record(ctx)
+	return i.db.QueryRowContext(ctx, query, args...)
+}
+
+func (i instrumentedDB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	querybudget.Record(ctx)
+	return i.db.ExecContext(ctx, query, args...)
+}
+
+func (i instrumentedDB) BeginTx(ctx context.Context, opts *sql.TxOptions) (*sql.Tx, error) {
+	querybudget.Record(ctx)
+	return i.db.BeginTx(ctx, opts)
+}
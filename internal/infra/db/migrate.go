@@ -1,9 +1,14 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"errors"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 
 	"online-order-management-system/pkg/logger"
 
@@ -12,6 +17,57 @@ import (
 	_ "github.com/golang-migrate/migrate/v4/source/file"
 )
 
+// ErrMigrationsNotFound is returned when the migrations directory is missing or holds no migrations
+var ErrMigrationsNotFound = errors.New("migrations not found")
+
+// checkMigrationsDir verifies that path is a directory holding at least one up migration,
+// so a bad path fails with a clear message instead of an obscure golang-migrate source error
+func checkMigrationsDir(path string) error {
+	info, err := os.Stat(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("migrations directory not found at %s: %w", path, ErrMigrationsNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to access migrations directory %s: %w", path, err)
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("migrations path %s is not a directory: %w", path, ErrMigrationsNotFound)
+	}
+
+	ups, err := filepath.Glob(filepath.Join(path, "*.up.sql"))
+	if err != nil {
+		return fmt.Errorf("failed to list migrations in %s: %w", path, err)
+	}
+	if len(ups) == 0 {
+		return fmt.Errorf("no migration files found in %s: %w", path, ErrMigrationsNotFound)
+	}
+	return nil
+}
+
+// LatestMigrationVersion returns the version of the newest up migration in path, the schema
+// version the code shipped with those migrations expects
+func LatestMigrationVersion(path string) (uint, error) {
+	if err := checkMigrationsDir(path); err != nil {
+		return 0, err
+	}
+	ups, err := filepath.Glob(filepath.Join(path, "*.up.sql"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to list migrations in %s: %w", path, err)
+	}
+
+	var latest uint
+	for _, up := range ups {
+		version, err := strconv.ParseUint(strings.SplitN(filepath.Base(up), "_", 2)[0], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("unexpected migration file name %s", up)
+		}
+		if uint(version) > latest {
+			latest = uint(version)
+		}
+	}
+	return latest, nil
+}
+
 // MigrationManager handles database migrations
 type MigrationManager struct {
 	db     *sql.DB
@@ -26,12 +82,32 @@ func NewMigrationManager(db *sql.DB) *MigrationManager {
 	}
 }
 
-// RunMigrations runs all pending migrations
-func (m *MigrationManager) RunMigrations(migrationsPath string) error {
-	driver, err := postgres.WithInstance(m.db, &postgres.Config{})
+// ErrDirtyMigration is returned when a previous migration failed halfway, leaving the schema
+// at a version that is only partially applied. It must be repaired by hand and the version
+// forced before migrations can run again.
+var ErrDirtyMigration = errors.New("database schema is dirty")
+
+// newMigration opens golang-migrate on a connection of its own, checked out of the pool.
+// Closing the returned instance releases that connection only: handing golang-migrate the
+// *sql.DB itself would close the application's pool.
+func (m *MigrationManager) newMigration(migrationsPath string) (*migrate.Migrate, error) {
+	if err := checkMigrationsDir(migrationsPath); err != nil {
+		m.logger.WithError(err).Error("Invalid migrations directory")
+		return nil, err
+	}
+
+	ctx := context.Background()
+	conn, err := m.db.Conn(ctx)
 	if err != nil {
+		m.logger.WithError(err).Error("Failed to get a database connection for migrations")
+		return nil, fmt.Errorf("failed to get database connection: %w", err)
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
 		m.logger.WithError(err).Error("Failed to create postgres driver instance")
-		return fmt.Errorf("failed to create postgres driver: %w", err)
+		return nil, fmt.Errorf("failed to create postgres driver: %w", err)
 	}
 
 	migration, err := migrate.NewWithDatabaseInstance(
@@ -40,8 +116,33 @@ func (m *MigrationManager) RunMigrations(migrationsPath string) error {
 		driver,
 	)
 	if err != nil {
+		driver.Close()
 		m.logger.WithError(err).Error("Failed to create migration instance")
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return nil, fmt.Errorf("failed to create migration instance: %w", err)
+	}
+	return migration, nil
+}
+
+// NewDirtyMigrationError explains how to recover a schema left dirty at version
+func NewDirtyMigrationError(version uint) error {
+	return fmt.Errorf("%w at version %d: a failed migration left it partially applied; "+
+		"repair the schema by hand, then force the version before migrating again", ErrDirtyMigration, version)
+}
+
+// dirtyError explains a dirty schema version, or returns nil when err is not one
+func dirtyError(err error) error {
+	var dirty migrate.ErrDirty
+	if !errors.As(err, &dirty) {
+		return nil
+	}
+	return NewDirtyMigrationError(uint(dirty.Version))
+}
+
+// RunMigrations runs all pending migrations
+func (m *MigrationManager) RunMigrations(migrationsPath string) error {
+	migration, err := m.newMigration(migrationsPath)
+	if err != nil {
+		return err
 	}
 	defer migration.Close()
 
@@ -51,6 +152,10 @@ func (m *MigrationManager) RunMigrations(migrationsPath string) error {
 			m.logger.Info("No pending migrations to run")
 			return nil
 		}
+		if dirtyErr := dirtyError(err); dirtyErr != nil {
+			m.logger.WithError(dirtyErr).Error("Cannot run migrations on a dirty schema")
+			return dirtyErr
+		}
 		m.logger.WithError(err).Error("Failed to run migrations")
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
@@ -61,29 +166,22 @@ func (m *MigrationManager) RunMigrations(migrationsPath string) error {
 
 // RollbackMigration rolls back one migration
 func (m *MigrationManager) RollbackMigration(migrationsPath string) error {
-	driver, err := postgres.WithInstance(m.db, &postgres.Config{})
+	migration, err := m.newMigration(migrationsPath)
 	if err != nil {
-		m.logger.WithError(err).Error("Failed to create postgres driver instance")
-		return fmt.Errorf("failed to create postgres driver: %w", err)
-	}
-
-	migration, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		m.logger.WithError(err).Error("Failed to create migration instance")
-		return fmt.Errorf("failed to create migration instance: %w", err)
+		return err
 	}
 	defer migration.Close()
 
 	// Rollback one step
 	if err := migration.Steps(-1); err != nil {
-		if errors.Is(err, migrate.ErrNoChange) {
+		if errors.Is(err, migrate.ErrNoChange) || errors.Is(err, os.ErrNotExist) {
 			m.logger.Info("No migrations to rollback")
 			return nil
 		}
+		if dirtyErr := dirtyError(err); dirtyErr != nil {
+			m.logger.WithError(dirtyErr).Error("Cannot roll back a dirty schema")
+			return dirtyErr
+		}
 		m.logger.WithError(err).Error("Failed to rollback migration")
 		return fmt.Errorf("failed to rollback migration: %w", err)
 	}
@@ -92,22 +190,11 @@ func (m *MigrationManager) RollbackMigration(migrationsPath string) error {
 	return nil
 }
 
-// GetMigrationVersion returns the current migration version
+// GetMigrationVersion returns the current migration version and whether it is dirty
 func (m *MigrationManager) GetMigrationVersion(migrationsPath string) (uint, bool, error) {
-	driver, err := postgres.WithInstance(m.db, &postgres.Config{})
-	if err != nil {
-		m.logger.WithError(err).Error("Failed to create postgres driver instance")
-		return 0, false, fmt.Errorf("failed to create postgres driver: %w", err)
-	}
-
-	migration, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
+	migration, err := m.newMigration(migrationsPath)
 	if err != nil {
-		m.logger.WithError(err).Error("Failed to create migration instance")
-		return 0, false, fmt.Errorf("failed to create migration instance: %w", err)
+		return 0, false, err
 	}
 	defer migration.Close()
 
@@ -124,7 +211,49 @@ func (m *MigrationManager) GetMigrationVersion(migrationsPath string) (uint, boo
 	m.logger.WithFields(map[string]interface{}{
 		"version": version,
 		"dirty":   dirty,
-	}).Info("Current migration version")
+	}).Debug("Current migration version")
 
 	return version, dirty, nil
 }
+
+// ForceVersion sets the recorded migration version and clears the dirty flag without running
+// any migration. It is the recovery for a dirty schema once it has been repaired by hand:
+// force the last version that is fully applied. A version of -1 records that no migration
+// has been applied.
+func (m *MigrationManager) ForceVersion(migrationsPath string, version int) error {
+	if version < -1 {
+		return fmt.Errorf("invalid migration version %d: must be -1 or greater", version)
+	}
+
+	migration, err := m.newMigration(migrationsPath)
+	if err != nil {
+		return err
+	}
+	defer migration.Close()
+
+	before := map[string]interface{}{"requested_version": version}
+	if current, dirty, err := migration.Version(); err == nil {
+		before["previous_version"] = current
+		before["previous_dirty"] = dirty
+	} else if !errors.Is(err, migrate.ErrNilVersion) {
+		m.logger.WithError(err).Error("Failed to get migration version")
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+	m.logger.WithFields(before).Warn("Forcing migration version")
+
+	if err := migration.Force(version); err != nil {
+		m.logger.WithError(err).Error("Failed to force migration version")
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
+	}
+
+	current, dirty, err := migration.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		m.logger.WithError(err).Error("Failed to get migration version")
+		return fmt.Errorf("failed to get migration version: %w", err)
+	}
+	m.logger.WithFields(map[string]interface{}{
+		"version": current,
+		"dirty":   dirty,
+	}).Info("Forced migration version")
+	return nil
+}
@@ -0,0 +1,193 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunMigrations_MissingDirectory(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "does-not-exist")
+
+	// The directory check runs before the database is touched
+	err := NewMigrationManager(nil).RunMigrations(path)
+	if !errors.Is(err, ErrMigrationsNotFound) {
+		t.Fatalf("expected ErrMigrationsNotFound, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "migrations directory not found at "+path) {
+		t.Errorf("expected a friendly error naming the path, got %q", err.Error())
+	}
+}
+
+func TestCheckMigrationsDir(t *testing.T) {
+	if err := checkMigrationsDir("testdata/migrations"); err != nil {
+		t.Errorf("expected fixture directory to be valid, got %v", err)
+	}
+
+	if err := checkMigrationsDir(t.TempDir()); !errors.Is(err, ErrMigrationsNotFound) {
+		t.Errorf("expected an empty directory to be rejected, got %v", err)
+	}
+}
+
+// openThrowawayDB creates an empty database next to the one in TEST_DATABASE_URL and drops
+// it when the test ends. Tests using it are skipped when the variable is not set.
+func openThrowawayDB(t *testing.T) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping database integration test")
+	}
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { admin.Close() })
+
+	name := fmt.Sprintf("migrate_test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec(`CREATE DATABASE ` + name); err != nil {
+		t.Fatalf("failed to create throwaway database: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP DATABASE IF EXISTS ` + name + ` WITH (FORCE)`); err != nil {
+			t.Errorf("failed to drop throwaway database: %v", err)
+		}
+	})
+
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("failed to parse TEST_DATABASE_URL: %v", err)
+	}
+	parsed.Path = "/" + name
+	database, err := sql.Open("postgres", parsed.String())
+	if err != nil {
+		t.Fatalf("failed to open throwaway database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+// latestMigrationVersion returns the version of the newest up migration in path
+func latestMigrationVersion(t *testing.T, path string) uint {
+	t.Helper()
+
+	latest, err := LatestMigrationVersion(path)
+	if err != nil {
+		t.Fatalf("failed to find the latest migration: %v", err)
+	}
+	return latest
+}
+
+func TestMigrationManager_UpDownVersion(t *testing.T) {
+	database := openThrowawayDB(t)
+	manager := NewMigrationManager(database)
+
+	if version, dirty, err := manager.GetMigrationVersion(testMigrationsPath); err != nil || version != 0 || dirty {
+		t.Fatalf("expected a fresh database at version 0, got %d (dirty %v, err %v)", version, dirty, err)
+	}
+
+	if err := manager.RunMigrations(testMigrationsPath); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	latest := latestMigrationVersion(t, testMigrationsPath)
+	version, dirty, err := manager.GetMigrationVersion(testMigrationsPath)
+	if err != nil || version != latest || dirty {
+		t.Fatalf("expected version %d after migrating, got %d (dirty %v, err %v)", latest, version, dirty, err)
+	}
+
+	// Migrating must leave the application's pool open
+	if err := database.Ping(); err != nil {
+		t.Fatalf("expected the database handle to stay usable after migrating, got %v", err)
+	}
+
+	if err := manager.RollbackMigration(testMigrationsPath); err != nil {
+		t.Fatalf("failed to roll back: %v", err)
+	}
+	if version, _, err := manager.GetMigrationVersion(testMigrationsPath); err != nil || version != latest-1 {
+		t.Errorf("expected version %d after rolling back, got %d (err %v)", latest-1, version, err)
+	}
+}
+
+func TestMigrationManager_DirtyStateIsReported(t *testing.T) {
+	database := openThrowawayDB(t)
+	manager := NewMigrationManager(database)
+
+	if err := manager.RunMigrations(testMigrationsPath); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	if _, err := database.Exec(`UPDATE schema_migrations SET dirty = true`); err != nil {
+		t.Fatalf("failed to mark the schema dirty: %v", err)
+	}
+
+	err := manager.RunMigrations(testMigrationsPath)
+	if !errors.Is(err, ErrDirtyMigration) {
+		t.Fatalf("expected ErrDirtyMigration, got %v", err)
+	}
+	if !strings.Contains(err.Error(), "force the version") {
+		t.Errorf("expected the error to explain the recovery, got %q", err.Error())
+	}
+	if _, dirty, err := manager.GetMigrationVersion(testMigrationsPath); err != nil || !dirty {
+		t.Errorf("expected the version to be reported dirty, got dirty %v (err %v)", dirty, err)
+	}
+}
+
+func TestMigrationManager_ForceVersionRecoversDirtyState(t *testing.T) {
+	database := openThrowawayDB(t)
+	manager := NewMigrationManager(database)
+	latest := latestMigrationVersion(t, testMigrationsPath)
+
+	if err := manager.RunMigrations(testMigrationsPath); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+	if _, err := database.Exec(`UPDATE schema_migrations SET dirty = true`); err != nil {
+		t.Fatalf("failed to mark the schema dirty: %v", err)
+	}
+	if err := manager.RunMigrations(testMigrationsPath); !errors.Is(err, ErrDirtyMigration) {
+		t.Fatalf("expected ErrDirtyMigration before forcing, got %v", err)
+	}
+
+	if err := manager.ForceVersion(testMigrationsPath, int(latest)); err != nil {
+		t.Fatalf("failed to force the version: %v", err)
+	}
+
+	version, dirty, err := manager.GetMigrationVersion(testMigrationsPath)
+	if err != nil {
+		t.Fatalf("failed to get the version: %v", err)
+	}
+	if version != latest || dirty {
+		t.Errorf("expected clean version %d after forcing, got %d (dirty %v)", latest, version, dirty)
+	}
+	if err := manager.RunMigrations(testMigrationsPath); err != nil {
+		t.Errorf("expected migrations to run again after forcing, got %v", err)
+	}
+}
+
+func TestLatestMigrationVersion(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"000002_add_index.up.sql", "000010_add_column.up.sql", "000010_add_column.down.sql", "000003_other.up.sql"} {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if version, err := LatestMigrationVersion(dir); err != nil || version != 10 {
+		t.Errorf("expected version 10, got %d (err %v)", version, err)
+	}
+	if _, err := LatestMigrationVersion(t.TempDir()); !errors.Is(err, ErrMigrationsNotFound) {
+		t.Errorf("expected ErrMigrationsNotFound for an empty directory, got %v", err)
+	}
+}
+
+func TestMigrationManager_ForceVersionRejectsInvalidVersion(t *testing.T) {
+	manager := NewMigrationManager(nil)
+
+	if err := manager.ForceVersion(testMigrationsPath, -2); err == nil {
+		t.Error("expected an error for version -2")
+	}
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+
+	"online-order-management-system/internal/domain/entity"
+)
+
+// moneyColumn scans a NUMERIC money column into an entity.Money from its exact decimal
+// text, so stored amounts never pass through float64. NULL (e.g. a missing joined item)
+// scans as zero.
+type moneyColumn struct {
+	dest *entity.Money
+}
+
+// scanMoney returns a Scan destination that fills dest
+func scanMoney(dest *entity.Money) sql.Scanner {
+	return moneyColumn{dest: dest}
+}
+
+// Scan implements sql.Scanner
+func (c moneyColumn) Scan(src interface{}) error {
+	switch value := src.(type) {
+	case nil:
+		*c.dest = 0
+	case []byte:
+		return c.parse(string(value))
+	case string:
+		return c.parse(value)
+	case float64:
+		*c.dest = entity.NewMoney(value)
+	case int64:
+		*c.dest = entity.Money(value * 100)
+	default:
+		return fmt.Errorf("cannot scan %T into a money amount", src)
+	}
+	return nil
+}
+
+func (c moneyColumn) parse(text string) error {
+	amount, err := entity.ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*c.dest = amount
+	return nil
+}
+
+// moneyParam passes an amount as decimal text (e.g. "1999.98"), which Postgres casts to
+// NUMERIC exactly; Money's underlying int64 would otherwise be sent as a count of cents
+func moneyParam(amount entity.Money) string {
+	return amount.String()
+}
@@ -0,0 +1,41 @@
+package db
+
+import (
+	"testing"
+
+	"online-order-management-system/internal/domain/entity"
+)
+
+func TestScanMoney(t *testing.T) {
+	tests := []struct {
+		src  interface{}
+		want entity.Money
+	}{
+		{[]byte("1999.98"), 199998},
+		{"0.10", 10},
+		{nil, 0},
+		{float64(24.5), 2450},
+		{int64(3), 300},
+	}
+	for _, tt := range tests {
+		amount := entity.Money(-1)
+		if err := scanMoney(&amount).Scan(tt.src); err != nil {
+			t.Errorf("%v: unexpected error: %v", tt.src, err)
+			continue
+		}
+		if amount != tt.want {
+			t.Errorf("%v: expected %d cents, got %d", tt.src, tt.want, amount)
+		}
+	}
+
+	var amount entity.Money
+	if err := scanMoney(&amount).Scan(true); err == nil {
+		t.Error("expected an error scanning a bool")
+	}
+}
+
+func TestMoneyParam(t *testing.T) {
+	if got := moneyParam(entity.NewMoney(1999.98)); got != "1999.98" {
+		t.Errorf("expected decimal text 1999.98, got %q", got)
+	}
+}
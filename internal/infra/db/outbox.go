@@ -0,0 +1,264 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"sync"
+	"time"
+
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/events"
+	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/retryutil"
+)
+
+// outboxBatchSize caps the events claimed by one dispatcher pass
+const outboxBatchSize = 100
+
+// DefaultOutboxMaxAttempts is the number of failed passes after which an event is parked
+const DefaultOutboxMaxAttempts = 10
+
+// DefaultOutboxClaimTTL is how long a dispatcher owns the events it claimed
+const DefaultOutboxClaimTTL = 5 * time.Minute
+
+// insertOutboxEvent records event in the outbox inside tx, so it is kept if and only if
+// the write it describes commits
+func insertOutboxEvent(ctx context.Context, tx *sql.Tx, event events.Event) error {
+	payload, err := json.Marshal(event.Data)
+	if err != nil {
+		return apperrors.NewInternalError("Failed to encode outbox event").WithCause(err)
+	}
+
+	query := `
+		INSERT INTO outbox (event_id, event_type, payload, created_at)
+		VALUES ($1, $2, $3, $4)`
+	if _, err := tx.ExecContext(ctx, query, event.ID, event.Type, payload, event.Time); err != nil {
+		return apperrors.NewDatabaseQueryError("Failed to record outbox event").WithCause(err)
+	}
+	return nil
+}
+
+// outboxRow is an undelivered outbox event
+type outboxRow struct {
+	id    int64
+	event events.Event
+}
+
+// OutboxDispatcher delivers outbox events to a sink at least once and marks them sent.
+// Each pass claims a batch of pending events for a lease and delivers them outside any
+// transaction, so several instances can dispatch at once. Events are claimed oldest first,
+// but a failed event is retried on a later pass, so sinks must not rely on delivery order;
+// they can order by the event time and drop duplicates by event ID. An event that fails
+// maxAttempts passes is parked: it stays in the outbox with its last error and is no longer
+// retried.
+type OutboxDispatcher struct {
+	db          *sql.DB
+	sink        events.Sink
+	interval    time.Duration
+	retry       retryutil.RetryConfig
+	maxAttempts int
+	claimTTL    time.Duration
+	logger      *logger.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// OutboxOption configures optional behavior of OutboxDispatcher
+type OutboxOption func(*OutboxDispatcher)
+
+// WithOutboxRetry replaces the backoff used when the sink fails within one pass
+func WithOutboxRetry(config retryutil.RetryConfig) OutboxOption {
+	return func(d *OutboxDispatcher) {
+		d.retry = config
+	}
+}
+
+// WithOutboxMaxAttempts sets the failed passes after which an event is parked
+// (DefaultOutboxMaxAttempts by default; 0 retries forever)
+func WithOutboxMaxAttempts(maxAttempts int) OutboxOption {
+	return func(d *OutboxDispatcher) {
+		d.maxAttempts = maxAttempts
+	}
+}
+
+// WithOutboxClaimTTL sets how long claimed events stay with this dispatcher before another
+// one may take them over (DefaultOutboxClaimTTL by default). It should outlast a pass.
+func WithOutboxClaimTTL(ttl time.Duration) OutboxOption {
+	return func(d *OutboxDispatcher) {
+		if ttl > 0 {
+			d.claimTTL = ttl
+		}
+	}
+}
+
+// NewOutboxDispatcher creates a dispatcher sending the outbox of database to sink, polling
+// every interval (1s when not positive)
+func NewOutboxDispatcher(database *sql.DB, sink events.Sink, interval time.Duration, opts ...OutboxOption) *OutboxDispatcher {
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	// Every sink error is worth retrying; a failed pass leaves the event for the next one
+	retry := retryutil.DefaultRetryConfig()
+	retry.BaseDelay = 100 * time.Millisecond
+	retry.MaxDelay = 2 * time.Second
+	retry.RetryCondition = nil
+
+	d := &OutboxDispatcher{
+		db:          database,
+		sink:        sink,
+		interval:    interval,
+		retry:       retry,
+		maxAttempts: DefaultOutboxMaxAttempts,
+		claimTTL:    DefaultOutboxClaimTTL,
+		logger:      logger.New("outbox-dispatcher", "1.0.0"),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d
+}
+
+// DispatchOnce claims up to a batch of pending events, delivers them and returns how many
+// were sent. Claims are committed before delivery, so no transaction or row lock is held
+// while the sink is called; events left claimed by a stopped pass are retried once their
+// claim expires.
+func (d *OutboxDispatcher) DispatchOnce(ctx context.Context) (int, error) {
+	pending, err := d.claimOutboxRows(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, row := range pending {
+		sendErr := retryutil.RetryWithBackoff(ctx, d.retry, func() error {
+			return d.sink.Send(ctx, row.event)
+		})
+		if sendErr != nil {
+			if err := d.recordFailure(ctx, row, sendErr); err != nil {
+				return sent, err
+			}
+			continue
+		}
+
+		if _, err := d.db.ExecContext(ctx, `UPDATE outbox SET sent_at = NOW(), attempts = attempts + 1, last_error = NULL, claimed_until = NULL WHERE id = $1`,
+			row.id); err != nil {
+			return sent, apperrors.NewDatabaseQueryError("Failed to mark outbox event sent").WithCause(err)
+		}
+		sent++
+	}
+	return sent, nil
+}
+
+// recordFailure counts a failed pass for row, releases its claim and parks it once it has
+// used up its attempts
+func (d *OutboxDispatcher) recordFailure(ctx context.Context, row outboxRow, sendErr error) error {
+	query := `
+		UPDATE outbox
+		SET attempts = attempts + 1, last_error = $2, claimed_until = NULL,
+			parked_at = CASE WHEN $3::int > 0 AND attempts + 1 >= $3::int THEN NOW() END
+		WHERE id = $1
+		RETURNING parked_at IS NOT NULL`
+
+	var parked bool
+	if err := d.db.QueryRowContext(ctx, query, row.id, sendErr.Error(), d.maxAttempts).Scan(&parked); err != nil {
+		return apperrors.NewDatabaseQueryError("Failed to record outbox failure").WithCause(err)
+	}
+
+	log := d.logger.WithContext(ctx).WithError(sendErr).WithFields(map[string]interface{}{
+		"event_id":   row.event.ID,
+		"event_type": row.event.Type,
+	})
+	if parked {
+		log.WithField("max_attempts", d.maxAttempts).Error("Parked outbox event after repeated delivery failures")
+	} else {
+		log.Warn("Failed to deliver outbox event; will retry")
+	}
+	return nil
+}
+
+// claimOutboxRows claims the oldest pending events for claimTTL and returns them in the
+// order they were recorded. Rows another dispatcher is claiming at the same moment are
+// skipped.
+func (d *OutboxDispatcher) claimOutboxRows(ctx context.Context) ([]outboxRow, error) {
+	query := `
+		UPDATE outbox
+		SET claimed_until = NOW() + $2::float8 * INTERVAL '1 millisecond'
+		WHERE id IN (
+			SELECT id
+			FROM outbox
+			WHERE sent_at IS NULL AND parked_at IS NULL
+				AND (claimed_until IS NULL OR claimed_until < NOW())
+			ORDER BY id
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED)
+		RETURNING id, event_id, event_type, payload, created_at`
+
+	rows, err := d.db.QueryContext(ctx, query, outboxBatchSize, d.claimTTL.Milliseconds())
+	if err != nil {
+		return nil, apperrors.NewDatabaseQueryError("Failed to claim outbox events").WithCause(err)
+	}
+	defer rows.Close()
+
+	var pending []outboxRow
+	for rows.Next() {
+		var row outboxRow
+		var payload []byte
+		if err := rows.Scan(&row.id, &row.event.ID, &row.event.Type, &payload, &row.event.Time); err != nil {
+			return nil, apperrors.NewDatabaseQueryError("Failed to scan outbox event").WithCause(err)
+		}
+		row.event.Data = json.RawMessage(payload)
+		pending = append(pending, row)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseQueryError("Error iterating outbox").WithCause(err)
+	}
+
+	// RETURNING does not keep the subquery's order
+	sort.Slice(pending, func(i, j int) bool { return pending[i].id < pending[j].id })
+	return pending, nil
+}
+
+// Start dispatches immediately and then every interval until Stop is called or ctx is cancelled
+func (d *OutboxDispatcher) Start(ctx context.Context) {
+	if d == nil {
+		return
+	}
+
+	ctx, d.cancel = context.WithCancel(ctx)
+	d.done = make(chan struct{})
+
+	go func() {
+		defer close(d.done)
+
+		ticker := time.NewTicker(d.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := d.DispatchOnce(ctx); err != nil && ctx.Err() == nil {
+				d.logger.WithError(err).Error("Outbox dispatch failed")
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop and waits for an in-flight pass to finish
+func (d *OutboxDispatcher) Stop() {
+	if d == nil || d.cancel == nil {
+		return
+	}
+	d.once.Do(func() {
+		d.cancel()
+		<-d.done
+	})
+}
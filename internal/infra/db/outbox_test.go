@@ -0,0 +1,195 @@
+package db
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/pkg/events"
+	"online-order-management-system/pkg/retryutil"
+)
+
+// recordingSink records delivered events and fails with err, if set
+type recordingSink struct {
+	sent []events.Event
+	err  error
+}
+
+func (s *recordingSink) Send(ctx context.Context, event events.Event) error {
+	if s.err != nil {
+		return s.err
+	}
+	s.sent = append(s.sent, event)
+	return nil
+}
+
+func countOutboxRows(t *testing.T, repo *PostgresOrderRepository, where string) int {
+	t.Helper()
+	var count int
+	if err := repo.db.QueryRowContext(context.Background(), `SELECT COUNT(*) FROM outbox `+where).Scan(&count); err != nil {
+		t.Fatalf("failed to count outbox rows: %v", err)
+	}
+	return count
+}
+
+func newOutboxOrder(t *testing.T) *entity.Order {
+	t.Helper()
+	order, err := entity.NewOrder("Outbox Customer", []entity.OrderItem{
+		{ProductName: "Widget", Quantity: 2, UnitPrice: entity.NewMoney(10)},
+	})
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	return order
+}
+
+func TestPostgresOrderRepository_OutboxCommitsWithTheOrder(t *testing.T) {
+	database := openTestDB(t)
+	repo := NewPostgresOrderRepository(database, WithOutbox(true)).(*PostgresOrderRepository)
+	ctx := context.Background()
+
+	created, err := repo.CreateOrderWithItems(ctx, newOutboxOrder(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var eventType string
+	var payload []byte
+	if err := database.QueryRow(`SELECT event_type, payload FROM outbox WHERE sent_at IS NULL`).Scan(&eventType, &payload); err != nil {
+		t.Fatalf("expected one unsent outbox row: %v", err)
+	}
+	var data events.OrderCreated
+	if err := json.Unmarshal(payload, &data); err != nil {
+		t.Fatalf("failed to decode payload: %v", err)
+	}
+	if eventType != events.TypeOrderCreated || data.OrderID != created.ID || data.TotalAmount != 20 {
+		t.Errorf("unexpected outbox row %s %+v for order %d", eventType, data, created.ID)
+	}
+
+	// When the outbox insert fails, the order must be rolled back with it
+	if _, err := database.Exec(`ALTER TABLE outbox RENAME TO outbox_unavailable`); err != nil {
+		t.Fatalf("failed to hide the outbox: %v", err)
+	}
+	t.Cleanup(func() { database.Exec(`ALTER TABLE outbox_unavailable RENAME TO outbox`) })
+
+	if _, err := repo.CreateOrderWithItems(ctx, newOutboxOrder(t)); err == nil {
+		t.Fatal("expected the create to fail without an outbox table")
+	}
+	var orders int
+	if err := database.QueryRow(`SELECT COUNT(*) FROM orders`).Scan(&orders); err != nil {
+		t.Fatalf("failed to count orders: %v", err)
+	}
+	if orders != 1 {
+		t.Errorf("expected the failed create to leave no order, got %d orders", orders)
+	}
+}
+
+func TestOutboxDispatcher_FailingSinkLeavesTheRowForRetry(t *testing.T) {
+	database := openTestDB(t)
+	repo := NewPostgresOrderRepository(database, WithOutbox(true)).(*PostgresOrderRepository)
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if _, err := repo.CreateOrderWithItems(ctx, newOutboxOrder(t)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	sink := &recordingSink{err: errors.New("webhook unavailable")}
+	dispatcher := NewOutboxDispatcher(database, sink, time.Second, WithOutboxRetry(retryutil.RetryConfig{
+		MaxRetries: 2,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   time.Millisecond,
+	}))
+
+	sent, err := dispatcher.DispatchOnce(ctx)
+	if err != nil || sent != 0 {
+		t.Fatalf("expected nothing sent without an error, got %d, %v", sent, err)
+	}
+	if got := countOutboxRows(t, repo, `WHERE sent_at IS NULL`); got != 2 {
+		t.Fatalf("expected both rows to stay unsent, got %d", got)
+	}
+	if got := countOutboxRows(t, repo, `WHERE attempts = 1 AND last_error LIKE '%webhook unavailable%' AND claimed_until IS NULL`); got != 2 {
+		t.Errorf("expected the failure recorded and the claim released on both rows, got %d rows", got)
+	}
+
+	sink.err = nil
+	if sent, err := dispatcher.DispatchOnce(ctx); err != nil || sent != 2 {
+		t.Fatalf("expected both events delivered once the sink recovers, got %d, %v", sent, err)
+	}
+	if got := countOutboxRows(t, repo, `WHERE sent_at IS NULL`); got != 0 {
+		t.Errorf("expected every row marked sent, got %d unsent", got)
+	}
+	if len(sink.sent) != 2 || sink.sent[0].Type != events.TypeOrderCreated {
+		t.Errorf("unexpected delivered events %+v", sink.sent)
+	}
+	if sent, _ := dispatcher.DispatchOnce(ctx); sent != 0 {
+		t.Errorf("expected sent events not to be delivered again, got %d", sent)
+	}
+}
+
+func TestOutboxDispatcher_ParksEventsAfterMaxAttempts(t *testing.T) {
+	database := openTestDB(t)
+	repo := NewPostgresOrderRepository(database, WithOutbox(true)).(*PostgresOrderRepository)
+	ctx := context.Background()
+	if _, err := repo.CreateOrderWithItems(ctx, newOutboxOrder(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	sink := &recordingSink{err: errors.New("payload rejected")}
+	dispatcher := NewOutboxDispatcher(database, sink, time.Second,
+		WithOutboxRetry(retryutil.RetryConfig{MaxRetries: 1, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}),
+		WithOutboxMaxAttempts(2),
+	)
+
+	for i := 0; i < 2; i++ {
+		if _, err := dispatcher.DispatchOnce(ctx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := countOutboxRows(t, repo, `WHERE parked_at IS NOT NULL AND attempts = 2`); got != 1 {
+		t.Fatalf("expected the event parked after 2 failed passes, got %d parked rows", got)
+	}
+
+	// A parked event no longer blocks or gets retried, even once the sink accepts events
+	sink.err = nil
+	if _, err := repo.CreateOrderWithItems(ctx, newOutboxOrder(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if sent, err := dispatcher.DispatchOnce(ctx); err != nil || sent != 1 {
+		t.Fatalf("expected only the new event delivered, got %d, %v", sent, err)
+	}
+	if got := countOutboxRows(t, repo, `WHERE sent_at IS NULL`); got != 1 {
+		t.Errorf("expected the parked event to stay unsent, got %d unsent rows", got)
+	}
+}
+
+func TestOutboxDispatcher_SkipsEventsClaimedByAnotherDispatcher(t *testing.T) {
+	database := openTestDB(t)
+	repo := NewPostgresOrderRepository(database, WithOutbox(true)).(*PostgresOrderRepository)
+	ctx := context.Background()
+	if _, err := repo.CreateOrderWithItems(ctx, newOutboxOrder(t)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	other := NewOutboxDispatcher(database, &recordingSink{}, time.Second)
+	claimed, err := other.claimOutboxRows(ctx)
+	if err != nil || len(claimed) != 1 {
+		t.Fatalf("expected the other dispatcher to claim the event, got %d, %v", len(claimed), err)
+	}
+
+	sink := &recordingSink{}
+	if sent, err := NewOutboxDispatcher(database, sink, time.Second).DispatchOnce(ctx); err != nil || sent != 0 {
+		t.Fatalf("expected a claimed event to be skipped, got %d, %v", sent, err)
+	}
+
+	// Once the claim expires, for instance because its dispatcher stopped, another takes over
+	if _, err := database.Exec(`UPDATE outbox SET claimed_until = NOW() - INTERVAL '1 second'`); err != nil {
+		t.Fatalf("failed to expire the claim: %v", err)
+	}
+	if sent, err := NewOutboxDispatcher(database, sink, time.Second).DispatchOnce(ctx); err != nil || sent != 1 {
+		t.Fatalf("expected the event delivered after its claim expired, got %d, %v", sent, err)
+	}
+}
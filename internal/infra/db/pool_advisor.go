@@ -0,0 +1,207 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// Thresholds used by AdvisePoolSize
+const (
+	// highAvgWait is the average wait for a connection above which the pool is considered starved
+	highAvgWait = 10 * time.Millisecond
+	// poolHeadroom is the margin kept above the observed peak when sizing the pool
+	poolHeadroom = 1.25
+	// minPoolSize is the smallest MaxOpenConns ever recommended
+	minPoolSize = 2
+)
+
+// PoolSettings describes the pool configuration the advice is computed against
+type PoolSettings struct {
+	MaxOpenConns int
+	MaxIdleConns int
+	// ConnectionBudget is the share of the server's max_connections this instance may use (0 = unknown)
+	ConnectionBudget int
+}
+
+// PoolAdvice holds recommended pool settings and the observations behind them
+type PoolAdvice struct {
+	MaxOpenConns int      `json:"max_open_conns"`
+	MaxIdleConns int      `json:"max_idle_conns"`
+	PeakInUse    int      `json:"peak_in_use"`
+	AvgInUse     float64  `json:"avg_in_use"`
+	WaitCount    int64    `json:"wait_count"`
+	AvgWait      string   `json:"avg_wait"`
+	Samples      int      `json:"samples"`
+	Reasons      []string `json:"reasons"`
+}
+
+// AdvisePoolSize suggests MaxOpenConns/MaxIdleConns from sql.DBStats sampled over a window,
+// oldest first. WaitCount and WaitDuration are cumulative, so the window's waits are the
+// difference between the last and first samples.
+func AdvisePoolSize(samples []sql.DBStats, current PoolSettings) PoolAdvice {
+	advice := PoolAdvice{
+		MaxOpenConns: current.MaxOpenConns,
+		MaxIdleConns: current.MaxIdleConns,
+		Samples:      len(samples),
+		AvgWait:      "0s",
+	}
+	if len(samples) < 2 {
+		advice.Reasons = append(advice.Reasons, "not enough samples collected yet; keeping current settings")
+		return advice
+	}
+
+	first, last := samples[0], samples[len(samples)-1]
+	var totalInUse int
+	for _, s := range samples {
+		totalInUse += s.InUse
+		if s.InUse > advice.PeakInUse {
+			advice.PeakInUse = s.InUse
+		}
+	}
+	advice.AvgInUse = float64(totalInUse) / float64(len(samples))
+	advice.WaitCount = last.WaitCount - first.WaitCount
+
+	var avgWait time.Duration
+	if advice.WaitCount > 0 {
+		avgWait = (last.WaitDuration - first.WaitDuration) / time.Duration(advice.WaitCount)
+	}
+	advice.AvgWait = avgWait.String()
+
+	headroom := int(math.Ceil(float64(advice.PeakInUse) * poolHeadroom))
+	if headroom < minPoolSize {
+		headroom = minPoolSize
+	}
+	saturated := current.MaxOpenConns > 0 && advice.PeakInUse >= current.MaxOpenConns
+	starved := advice.WaitCount > 0 && avgWait >= highAvgWait
+
+	switch {
+	case current.ConnectionBudget > 0 && current.MaxOpenConns > current.ConnectionBudget:
+		// Opening more connections than the server allows ends in "too many clients already"
+		advice.MaxOpenConns = current.ConnectionBudget
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"MaxOpenConns exceeds the connection budget of %d; lower it and let requests queue in the pool",
+			current.ConnectionBudget))
+	case starved && saturated:
+		advice.MaxOpenConns = int(math.Ceil(float64(current.MaxOpenConns) * poolHeadroom))
+		if current.ConnectionBudget > 0 && advice.MaxOpenConns > current.ConnectionBudget {
+			advice.MaxOpenConns = current.ConnectionBudget
+		}
+		if advice.MaxOpenConns > current.MaxOpenConns {
+			advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+				"pool was exhausted with an average wait of %s; raise MaxOpenConns", avgWait))
+		} else {
+			advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+				"pool was exhausted with an average wait of %s but the connection budget is reached; "+
+					"reduce per-request connection use or add capacity", avgWait))
+		}
+	case starved:
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"requests waited %s on average without exhausting the pool; check for long-held connections", avgWait))
+	case current.MaxOpenConns == 0 || headroom < current.MaxOpenConns:
+		advice.MaxOpenConns = headroom
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"peak usage was %d connections; lower MaxOpenConns to leave server capacity for other clients",
+			advice.PeakInUse))
+	default:
+		advice.Reasons = append(advice.Reasons, "pool size matches observed demand")
+	}
+
+	// Keep enough idle connections for the average load, never more than the pool itself
+	advice.MaxIdleConns = int(math.Ceil(advice.AvgInUse))
+	if advice.MaxIdleConns < 1 {
+		advice.MaxIdleConns = 1
+	}
+	if advice.MaxIdleConns > advice.MaxOpenConns {
+		advice.MaxIdleConns = advice.MaxOpenConns
+	}
+	if advice.MaxIdleConns != current.MaxIdleConns {
+		advice.Reasons = append(advice.Reasons, fmt.Sprintf(
+			"average usage was %.1f connections; set MaxIdleConns to %d", advice.AvgInUse, advice.MaxIdleConns))
+	}
+
+	return advice
+}
+
+// PoolStatsCollector samples sql.DBStats at a fixed interval, keeping the most recent window
+type PoolStatsCollector struct {
+	db       *sql.DB
+	interval time.Duration
+	window   int
+
+	mu      sync.Mutex
+	samples []sql.DBStats
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// NewPoolStatsCollector creates a collector keeping up to window samples taken every interval
+func NewPoolStatsCollector(db *sql.DB, interval time.Duration, window int) *PoolStatsCollector {
+	return &PoolStatsCollector{
+		db:       db,
+		interval: interval,
+		window:   window,
+	}
+}
+
+// Start samples the pool until Stop is called or ctx is cancelled
+func (c *PoolStatsCollector) Start(ctx context.Context) {
+	if c == nil {
+		return
+	}
+
+	ctx, c.cancel = context.WithCancel(ctx)
+	c.done = make(chan struct{})
+
+	go func() {
+		defer close(c.done)
+
+		ticker := time.NewTicker(c.interval)
+		defer ticker.Stop()
+
+		for {
+			c.record(c.db.Stats())
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops sampling and waits for the sampling goroutine to exit
+func (c *PoolStatsCollector) Stop() {
+	if c == nil || c.cancel == nil {
+		return
+	}
+	c.once.Do(func() {
+		c.cancel()
+		<-c.done
+	})
+}
+
+// Samples returns a copy of the collected samples, oldest first
+func (c *PoolStatsCollector) Samples() []sql.DBStats {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	samples := make([]sql.DBStats, len(c.samples))
+	copy(samples, c.samples)
+	return samples
+}
+
+func (c *PoolStatsCollector) record(stats sql.DBStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.samples = append(c.samples, stats)
+	if len(c.samples) > c.window {
+		c.samples = c.samples[len(c.samples)-c.window:]
+	}
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+)
+
+// syntheticStats builds samples with the given InUse values and cumulative waits spread evenly
+func syntheticStats(maxOpen int, inUse []int, totalWaits int64, totalWait time.Duration) []sql.DBStats {
+	samples := make([]sql.DBStats, len(inUse))
+	for i, n := range inUse {
+		fraction := float64(i) / float64(len(inUse)-1)
+		samples[i] = sql.DBStats{
+			MaxOpenConnections: maxOpen,
+			InUse:              n,
+			WaitCount:          int64(float64(totalWaits) * fraction),
+			WaitDuration:       time.Duration(float64(totalWait) * fraction),
+		}
+	}
+	return samples
+}
+
+func TestAdvisePoolSize_LowersOversizedIdlePool(t *testing.T) {
+	samples := syntheticStats(300, []int{4, 6, 8, 5}, 0, 0)
+	advice := AdvisePoolSize(samples, PoolSettings{MaxOpenConns: 300, MaxIdleConns: 150})
+
+	if advice.PeakInUse != 8 {
+		t.Errorf("expected peak 8, got %d", advice.PeakInUse)
+	}
+	if advice.MaxOpenConns != 10 {
+		t.Errorf("expected MaxOpenConns lowered to 10, got %d", advice.MaxOpenConns)
+	}
+	if advice.MaxIdleConns != 6 {
+		t.Errorf("expected MaxIdleConns 6, got %d", advice.MaxIdleConns)
+	}
+}
+
+func TestAdvisePoolSize_RaisesStarvedPool(t *testing.T) {
+	samples := syntheticStats(20, []int{20, 20, 20, 20}, 1000, 50*time.Second)
+	advice := AdvisePoolSize(samples, PoolSettings{MaxOpenConns: 20, MaxIdleConns: 10})
+
+	if advice.WaitCount != 1000 || advice.AvgWait != "50ms" {
+		t.Errorf("expected 1000 waits averaging 50ms, got %d averaging %s", advice.WaitCount, advice.AvgWait)
+	}
+	if advice.MaxOpenConns != 25 {
+		t.Errorf("expected MaxOpenConns raised to 25, got %d", advice.MaxOpenConns)
+	}
+}
+
+func TestAdvisePoolSize_LowersPoolAboveConnectionBudget(t *testing.T) {
+	samples := syntheticStats(300, []int{120, 150, 150}, 5000, 5*time.Minute)
+	advice := AdvisePoolSize(samples, PoolSettings{MaxOpenConns: 300, MaxIdleConns: 150, ConnectionBudget: 100})
+
+	if advice.MaxOpenConns != 100 {
+		t.Errorf("expected MaxOpenConns lowered to the budget of 100, got %d", advice.MaxOpenConns)
+	}
+	if advice.MaxIdleConns > advice.MaxOpenConns {
+		t.Errorf("expected MaxIdleConns <= MaxOpenConns, got %d > %d", advice.MaxIdleConns, advice.MaxOpenConns)
+	}
+}
+
+func TestAdvisePoolSize_KeepsSettingsWithoutEnoughSamples(t *testing.T) {
+	advice := AdvisePoolSize(nil, PoolSettings{MaxOpenConns: 50, MaxIdleConns: 25})
+	if advice.MaxOpenConns != 50 || advice.MaxIdleConns != 25 {
+		t.Errorf("expected current settings, got %d/%d", advice.MaxOpenConns, advice.MaxIdleConns)
+	}
+}
+
+func TestPoolStatsCollector_StopEndsSampling(t *testing.T) {
+	// Stats are read from the pool without connecting, so no database is needed
+	database, err := sql.Open("postgres", "postgres://localhost/unused")
+	if err != nil {
+		t.Fatalf("failed to open pool: %v", err)
+	}
+	defer database.Close()
+
+	collector := NewPoolStatsCollector(database, time.Millisecond, 1000)
+	collector.Start(context.Background())
+	deadline := time.Now().Add(time.Second)
+	for len(collector.Samples()) < 2 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	collector.Stop()
+	collector.Stop() // Safe to call twice
+
+	stopped := len(collector.Samples())
+	if stopped < 2 {
+		t.Fatalf("expected samples before Stop, got %d", stopped)
+	}
+	time.Sleep(10 * time.Millisecond)
+	if got := len(collector.Samples()); got != stopped {
+		t.Errorf("expected no samples after Stop, went from %d to %d", stopped, got)
+	}
+
+	var disabled *PoolStatsCollector
+	disabled.Start(context.Background())
+	disabled.Stop()
+}
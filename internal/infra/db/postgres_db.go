@@ -6,21 +6,20 @@ import (
 	"database/sql"
 	"fmt"
 	"log"
-	"os"
-	"strconv"
+	"net/url"
+	"strings"
 	"time"
 
+	"online-order-management-system/config"
+
 	_ "github.com/lib/pq"
 )
 
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
-	Host            string
-	Port            string
-	User            string
-	Password        string
-	DBName          string
-	SSLMode         string
+	// DSN is the connection URL, built and validated by config.LoadConfig from DATABASE_URL
+	// or the POSTGRES_* variables
+	DSN             string
 	MaxOpenConns    int
 	MaxIdleConns    int
 	ConnMaxLifetime time.Duration
@@ -28,74 +27,74 @@ type DatabaseConfig struct {
 	PingTimeout     time.Duration
 }
 
-// getEnvInt gets an integer from environment variable with default value
-func getEnvInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
+// GetDatabaseConfig returns the configuration for connecting to dsn, with the connection
+// pool tuned by the DB_* environment variables
+func GetDatabaseConfig(dsn string) (DatabaseConfig, error) {
+	dbConfig := DatabaseConfig{
+		DSN:             dsn,
+		MaxOpenConns:    config.GetEnvInt("DB_MAX_OPEN_CONNS", 300),
+		MaxIdleConns:    config.GetEnvInt("DB_MAX_IDLE_CONNS", 150),
+		ConnMaxLifetime: config.GetEnvDuration("DB_CONN_MAX_LIFETIME", 45*time.Minute),
+		ConnMaxIdleTime: config.GetEnvDuration("DB_CONN_MAX_IDLE_TIME", 20*time.Minute),
+		PingTimeout:     config.GetEnvDuration("DB_PING_TIMEOUT", 15*time.Second),
 	}
-	return defaultValue
-}
 
-// getEnvDuration gets a duration from environment variable with default value
-func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
-	if value := os.Getenv(key); value != "" {
-		if duration, err := time.ParseDuration(value); err == nil {
-			return duration
-		}
+	if err := dbConfig.validatePool(); err != nil {
+		return DatabaseConfig{}, err
 	}
-	return defaultValue
-}
 
-// getEnvString gets a string from environment variable with default value
-func getEnvString(key string, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
-	return defaultValue
+	return dbConfig, nil
 }
 
-// GetDatabaseConfig returns database configuration from environment variables
-func GetDatabaseConfig() DatabaseConfig {
-	return DatabaseConfig{
-		Host:            getEnvString("POSTGRES_HOST", "localhost"),
-		Port:            getEnvString("POSTGRES_PORT", "5432"),
-		User:            getEnvString("POSTGRES_USER", "user"),
-		Password:        getEnvString("POSTGRES_PASSWORD", "password"),
-		DBName:          getEnvString("POSTGRES_DBNAME", "orderdb"),
-		SSLMode:         getEnvString("POSTGRES_SSLMODE", "disable"),
-		MaxOpenConns:    getEnvInt("DB_MAX_OPEN_CONNS", 300),
-		MaxIdleConns:    getEnvInt("DB_MAX_IDLE_CONNS", 150),
-		ConnMaxLifetime: getEnvDuration("DB_CONN_MAX_LIFETIME", 45*time.Minute),
-		ConnMaxIdleTime: getEnvDuration("DB_CONN_MAX_IDLE_TIME", 20*time.Minute),
-		PingTimeout:     getEnvDuration("DB_PING_TIMEOUT", 15*time.Second),
+// validatePool rejects pool sizes and lifetimes that cannot work and clamps MaxIdleConns to
+// MaxOpenConns, logging a warning, since database/sql would otherwise keep the extra idle
+// connections configured but never use them
+func (config *DatabaseConfig) validatePool() error {
+	var problems []string
+	if config.MaxOpenConns <= 0 {
+		problems = append(problems, fmt.Sprintf("DB_MAX_OPEN_CONNS must be positive, got %d", config.MaxOpenConns))
+	}
+	if config.MaxIdleConns <= 0 {
+		problems = append(problems, fmt.Sprintf("DB_MAX_IDLE_CONNS must be positive, got %d", config.MaxIdleConns))
+	}
+	if config.ConnMaxLifetime < 0 {
+		problems = append(problems, fmt.Sprintf("DB_CONN_MAX_LIFETIME cannot be negative, got %v", config.ConnMaxLifetime))
+	}
+	if config.ConnMaxIdleTime < 0 {
+		problems = append(problems, fmt.Sprintf("DB_CONN_MAX_IDLE_TIME cannot be negative, got %v", config.ConnMaxIdleTime))
+	}
+	if len(problems) > 0 {
+		return fmt.Errorf("invalid connection pool settings: %s", strings.Join(problems, "; "))
 	}
-}
 
-// buildDSN constructs the PostgreSQL DSN from individual components
-func (config DatabaseConfig) buildDSN() string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=%s",
-		config.User,
-		config.Password,
-		config.Host,
-		config.Port,
-		config.DBName,
-		config.SSLMode,
-	)
+	if config.MaxIdleConns > config.MaxOpenConns {
+		log.Printf("⚠️  DB_MAX_IDLE_CONNS (%d) exceeds DB_MAX_OPEN_CONNS (%d); using %d idle connections",
+			config.MaxIdleConns, config.MaxOpenConns, config.MaxOpenConns)
+		config.MaxIdleConns = config.MaxOpenConns
+	}
+	return nil
 }
 
-// NewPostgresDB creates a new PostgreSQL database connection using environment configuration
-func NewPostgresDB() (*sql.DB, error) {
-	config := GetDatabaseConfig()
+// NewPostgresDB creates a new PostgreSQL database connection to dsn, with the pool
+// configured from the environment
+func NewPostgresDB(dsn string) (*sql.DB, error) {
+	config, err := GetDatabaseConfig(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
+	}
 	return NewPostgresDBWithConfig(config)
 }
 
 // NewPostgresDBWithConfig creates a new PostgreSQL database connection with custom configuration
 func NewPostgresDBWithConfig(config DatabaseConfig) (*sql.DB, error) {
-	dsn := config.buildDSN()
+	if config.DSN == "" {
+		return nil, fmt.Errorf("database DSN is required")
+	}
+	if err := config.validatePool(); err != nil {
+		return nil, err
+	}
 
-	db, err := sql.Open("postgres", dsn)
+	db, err := sql.Open("postgres", config.DSN)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
@@ -113,10 +112,7 @@ func NewPostgresDBWithConfig(config DatabaseConfig) (*sql.DB, error) {
 	}
 
 	log.Printf("✅ Database connection pool configured:")
-	log.Printf("   Host: %s:%s", config.Host, config.Port)
-	log.Printf("   Database: %s", config.DBName)
-	log.Printf("   User: %s", config.User)
-	log.Printf("   SSLMode: %s", config.SSLMode)
+	log.Printf("   DSN: %s", redactDSN(config.DSN))
 	log.Printf("   MaxOpenConns: %d", config.MaxOpenConns)
 	log.Printf("   MaxIdleConns: %d", config.MaxIdleConns)
 	log.Printf("   ConnMaxLifetime: %v", config.ConnMaxLifetime)
@@ -124,3 +120,12 @@ func NewPostgresDBWithConfig(config DatabaseConfig) (*sql.DB, error) {
 
 	return db, nil
 }
+
+// redactDSN hides the password of a connection URL for logging
+func redactDSN(dsn string) string {
+	parsed, err := url.Parse(dsn)
+	if err != nil {
+		return "(unparseable)"
+	}
+	return parsed.Redacted()
+}
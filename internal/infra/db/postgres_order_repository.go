@@ -3,189 +3,712 @@ package db
 import (
 	"context"
 	"database/sql"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
 	"online-order-management-system/internal/domain/entity"
+	domainerrors "online-order-management-system/internal/domain/errors"
 	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/pkg/dryrun"
 	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/events"
 	"online-order-management-system/pkg/logger"
 	"online-order-management-system/pkg/retryutil"
+	"online-order-management-system/pkg/tracing"
+	"online-order-management-system/pkg/warnings"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PostgresOrderRepository implements the OrderRepository interface using PostgreSQL
 type PostgresOrderRepository struct {
-	db     *sql.DB
-	logger *logger.Logger
+	db                       dbConn
+	recomputeTotalOnMismatch bool
+	uniqueClientReference    bool
+	countQueries             bool
+	databaseTotals           bool
+	outbox                   bool
+	orderNumberFormat        string
+	queryTimeout             time.Duration
+	logger                   *logger.Logger
+	// createRetries counts the retries of order creation since startup
+	createRetries atomic.Int64
+}
+
+// RepositoryOption configures optional behavior of PostgresOrderRepository
+type RepositoryOption func(*PostgresOrderRepository)
+
+// WithRecomputeTotalOnMismatch makes GetOrderByID return the total recomputed from the
+// items instead of the stored total_amount when the two disagree
+func WithRecomputeTotalOnMismatch(enabled bool) RepositoryOption {
+	return func(r *PostgresOrderRepository) {
+		r.recomputeTotalOnMismatch = enabled
+	}
+}
+
+// WithUniqueClientReference rejects creating an order whose client reference is already
+// used by another order
+func WithUniqueClientReference(enabled bool) RepositoryOption {
+	return func(r *PostgresOrderRepository) {
+		r.uniqueClientReference = enabled
+	}
+}
+
+// WithDatabaseTotals makes creates and item updates store the sum of the inserted items'
+// total_price, computed once per order in SQL, instead of the total computed in Go
+func WithDatabaseTotals(enabled bool) RepositoryOption {
+	return func(r *PostgresOrderRepository) {
+		r.databaseTotals = enabled
+	}
+}
+
+// WithOrderNumbers assigns each new order a human-readable number rendered with format
+// (see entity.FormatOrderNumber). Numbers come from a per-year counter row locked for the
+// create transaction, so they are gapless but creates within a year are serialized on it.
+// An empty format leaves order numbers unassigned.
+func WithOrderNumbers(format string) RepositoryOption {
+	return func(r *PostgresOrderRepository) {
+		r.orderNumberFormat = format
+	}
+}
+
+// WithQueryBudget counts every statement against the query budget carried by the request
+// context (see querybudget.WithBudget), to catch N+1 query regressions
+func WithQueryBudget(enabled bool) RepositoryOption {
+	return func(r *PostgresOrderRepository) {
+		r.countQueries = enabled
+	}
+}
+
+// WithOutbox records an order.created event in the outbox table (migration 000014) in the
+// transaction of every create, for OutboxDispatcher to deliver
+func WithOutbox(enabled bool) RepositoryOption {
+	return func(r *PostgresOrderRepository) {
+		r.outbox = enabled
+	}
+}
+
+// WithQueryTimeout bounds each repository call by timeout, on top of the deadline of the
+// caller's context, so a slow query gives its connection back long before the request times
+// out. A create's retries share one timeout; order streams are not bounded. A non-positive
+// timeout disables it.
+func WithQueryTimeout(timeout time.Duration) RepositoryOption {
+	return func(r *PostgresOrderRepository) {
+		r.queryTimeout = timeout
+	}
 }
 
 // NewPostgresOrderRepository creates a new PostgresOrderRepository
-func NewPostgresOrderRepository(db *sql.DB) repository.OrderRepository {
-	return &PostgresOrderRepository{
+func NewPostgresOrderRepository(db *sql.DB, opts ...RepositoryOption) repository.OrderRepository {
+	r := &PostgresOrderRepository{
 		db:     db,
 		logger: logger.New("postgres-order-repository", "1.0.0"),
 	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	if r.countQueries {
+		r.db = instrumentedDB{db: db}
+	}
+	return r
+}
+
+// queryContext derives the context of one repository call, bounded by the query timeout.
+// The returned done must be deferred with the call's error: it releases the context and,
+// when the context ended, replaces the driver's error with a TIMEOUT (504) error for the
+// query timeout or the caller's deadline, or a CLIENT_CLOSED_REQUEST (499) error for the
+// caller's cancellation.
+func (r *PostgresOrderRepository) queryContext(ctx context.Context) (context.Context, func(*error)) {
+	queryCtx, cancel := ctx, context.CancelFunc(func() {})
+	if r.queryTimeout > 0 {
+		queryCtx, cancel = context.WithTimeout(ctx, r.queryTimeout)
+	}
+
+	return queryCtx, func(errp *error) {
+		defer cancel()
+		if *errp == nil || queryCtx.Err() == nil {
+			return
+		}
+		if ctx.Err() == nil {
+			*errp = apperrors.NewTimeoutError("Database query timed out").WithDetails(map[string]interface{}{
+				"timeout": r.queryTimeout.String(),
+			}).WithCause(*errp)
+			return
+		}
+		*errp = apperrors.ContextError(ctx.Err(), "Database query interrupted, the request was cancelled or timed out").WithCause(*errp)
+	}
+}
+
+// CreateRetries returns how many times order creation has been retried since startup
+func (r *PostgresOrderRepository) CreateRetries() int64 {
+	return r.createRetries.Load()
 }
 
 // CreateOrderWithItems creates a new order with its items in a single transaction
 // This method is designed to handle concurrent requests efficiently with retry logic
 func (r *PostgresOrderRepository) CreateOrderWithItems(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	ctx, span := startSpan(ctx, "CreateOrderWithItems")
+	defer span.End()
+
+	created, _, err := r.createOrder(ctx, order, false, nil)
+	return created, err
+}
+
+// CreatePaidOrder creates an order already marked paid, recording the pending → paid
+// transition in the same transaction
+func (r *PostgresOrderRepository) CreatePaidOrder(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	ctx, span := startSpan(ctx, "CreatePaidOrder")
+	defer span.End()
+
+	created, _, err := r.createOrder(ctx, order, true, nil)
+	return created, err
+}
+
+// CreateOrderIdempotent creates an order and records its idempotency key in one transaction.
+// Creates sharing a key are serialized by an advisory lock, so of two concurrent requests
+// one creates the order and the other replays it.
+func (r *PostgresOrderRepository) CreateOrderIdempotent(ctx context.Context, order *entity.Order, paid bool, key string, ttl time.Duration) (*entity.Order, bool, error) {
+	ctx, span := startSpan(ctx, "CreateOrderIdempotent")
+	defer span.End()
+
+	created, replayedID, err := r.createOrder(ctx, order, paid, &idempotencyClaim{key: key, ttl: ttl})
+	if err != nil {
+		return nil, false, err
+	}
+	if replayedID == 0 {
+		return created, false, nil
+	}
+
+	existing, err := r.GetOrderByID(ctx, replayedID)
+	if err != nil {
+		return nil, false, err
+	}
+	return existing, true, nil
+}
+
+// idempotencyClaim is an idempotency key to record against a new order, and for how long
+type idempotencyClaim struct {
+	key string
+	ttl time.Duration
+}
+
+// createOrder persists an order and its items with retries, optionally as paid. With an
+// idempotency claim whose key is still live it creates nothing and returns the ID of the
+// order recorded for the key instead.
+func (r *PostgresOrderRepository) createOrder(ctx context.Context, order *entity.Order, paid bool, idem *idempotencyClaim) (_ *entity.Order, _ int64, err error) {
+	// One query timeout covers every attempt
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
 	var createdOrder *entity.Order
+	var replayedID int64
 
 	config := retryutil.DefaultRetryConfig()
-	err := retryutil.RetryWithBackoff(ctx, config, func() error {
+	config.OnRetry = func(attempt int, err error) {
+		r.createRetries.Add(1)
+		retryutil.RecordRetry(ctx)
+		r.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
+			"customer_name": order.CustomerName,
+			"attempt":       attempt,
+		}).Debug("Retrying order creation")
+	}
+	err = retryutil.RetryWithBackoff(ctx, config, func() error {
 		var err error
-		createdOrder, err = r.createOrderWithItemsInternal(ctx, order)
+		createdOrder, replayedID, err = r.createOrderWithItemsInternal(ctx, order, paid, idem)
 		return err
 	})
 
 	if err != nil {
-		r.logger.WithError(err).WithField("customer_name", order.CustomerName).
+		// Conflicts are client errors and must keep their own status code
+		if appErr := apperrors.GetAppError(err); appErr != nil && appErr.Code == apperrors.ErrCodeAlreadyExists {
+			return nil, 0, appErr
+		}
+		r.logger.WithContext(ctx).WithError(err).WithField("customer_name", order.CustomerName).
 			Error("Failed to create order with items after retries")
-		return nil, apperrors.NewDatabaseTransactionError("Failed to create order").WithCause(err)
+		return nil, 0, apperrors.NewDatabaseTransactionError("Failed to create order").WithCause(err)
 	}
 
-	r.logger.WithFields(map[string]interface{}{
+	if replayedID != 0 {
+		r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"order_id":        replayedID,
+			"idempotency_key": idem.key,
+		}).Info("Idempotency key already used; replaying its order")
+		return nil, replayedID, nil
+	}
+
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"order_id":      createdOrder.ID,
 		"customer_name": createdOrder.CustomerName,
 		"total_amount":  createdOrder.TotalAmount,
 		"items_count":   len(createdOrder.Items),
+		"status":        createdOrder.Status,
 	}).Info("Successfully created order with items")
 
-	return createdOrder, nil
+	return createdOrder, 0, nil
 }
 
 // createOrderWithItemsInternal is the internal implementation without retry logic
-func (r *PostgresOrderRepository) createOrderWithItemsInternal(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+func (r *PostgresOrderRepository) createOrderWithItemsInternal(ctx context.Context, order *entity.Order, paid bool, idem *idempotencyClaim) (*entity.Order, int64, error) {
 	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return nil, apperrors.NewDatabaseConnectionError("Failed to begin transaction").WithCause(err)
+		return nil, 0, apperrors.NewDatabaseConnectionError("Failed to begin transaction").WithCause(err)
 	}
 	defer tx.Rollback()
 
+	if idem != nil {
+		existingID, err := claimIdempotencyKey(ctx, tx, idem.key)
+		if err != nil {
+			return nil, 0, err
+		}
+		if existingID != 0 {
+			return nil, existingID, nil
+		}
+	}
+
+	if order.ClientReference != "" && r.uniqueClientReference {
+		if err := r.ensureClientReferenceAvailable(ctx, tx, order.ClientReference); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	var orderNumber string
+	if r.orderNumberFormat != "" {
+		if orderNumber, err = r.nextOrderNumber(ctx, tx, order.CreatedAt.UTC().Year()); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	status := order.Status
+	if paid {
+		status = "paid"
+	}
+
 	// Insert order
 	orderQuery := `
-		INSERT INTO orders (customer_name, total_amount, status, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO orders (customer_name, customer_email, client_reference, total_amount, status, created_at, updated_at, estimated_ship_date, order_number)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
 		RETURNING id`
 
+	totalAmount := order.TotalAmount
+
 	var orderID int64
 	err = tx.QueryRowContext(ctx, orderQuery,
 		order.CustomerName,
-		order.TotalAmount,
-		order.Status,
+		nullableString(order.CustomerEmail),
+		nullableString(order.ClientReference),
+		moneyParam(totalAmount),
+		status,
 		order.CreatedAt,
 		order.UpdatedAt,
+		order.EstimatedShipDate,
+		nullableString(orderNumber),
 	).Scan(&orderID)
 	if err != nil {
-		return nil, apperrors.NewDatabaseQueryError("Failed to insert order").WithCause(err)
+		return nil, 0, apperrors.NewDatabaseQueryError("Failed to insert order").WithCause(err)
+	}
+
+	items, err := insertOrderItems(ctx, tx, orderID, order.Items)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	if r.databaseTotals {
+		// One aggregate per order, after all its items are in
+		query := `
+			UPDATE orders
+			SET total_amount = (SELECT COALESCE(SUM(total_price), 0) FROM order_items WHERE order_id = $1)
+			WHERE id = $1
+			RETURNING total_amount`
+		if err = tx.QueryRowContext(ctx, query, orderID).Scan(scanMoney(&totalAmount)); err != nil {
+			return nil, 0, apperrors.NewDatabaseQueryError("Failed to compute order total").WithCause(err)
+		}
+	}
+
+	if paid {
+		historyQuery := `
+			INSERT INTO order_status_history (order_id, from_status, to_status, changed_at)
+			VALUES ($1, $2, $3, $4)`
+		if _, err = tx.ExecContext(ctx, historyQuery, orderID, order.Status, status, order.CreatedAt); err != nil {
+			return nil, 0, apperrors.NewDatabaseQueryError("Failed to record status history").WithCause(err)
+		}
+	}
+
+	if idem != nil {
+		if err := recordIdempotencyKey(ctx, tx, idem, orderID); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if r.outbox {
+		event := events.NewEvent(events.TypeOrderCreated, events.OrderCreated{
+			OrderID:      orderID,
+			CustomerName: order.CustomerName,
+			Status:       status,
+			TotalAmount:  totalAmount.Float64(),
+		})
+		if err := insertOutboxEvent(ctx, tx, event); err != nil {
+			return nil, 0, err
+		}
+	}
+
+	if err = commitTx(ctx, tx); err != nil {
+		return nil, 0, apperrors.NewDatabaseTransactionError("Failed to commit transaction").WithCause(err)
+	}
+
+	// A dry run was rolled back, so its IDs and order number were never kept
+	if dryrun.Enabled(ctx) {
+		orderID, orderNumber = 0, ""
+		for i := range items {
+			items[i].ID, items[i].OrderID = 0, 0
+		}
 	}
 
-	// Insert order items
+	// Return the created order with IDs
+	createdOrder := &entity.Order{
+		ID:              orderID,
+		OrderNumber:     orderNumber,
+		CustomerName:    order.CustomerName,
+		CustomerEmail:   order.CustomerEmail,
+		ClientReference: order.ClientReference,
+		TotalAmount:     totalAmount,
+		Status:          status,
+		Items:           items,
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+
+		EstimatedShipDate: order.EstimatedShipDate,
+	}
+
+	return createdOrder, 0, nil
+}
+
+// insertOrderItems inserts items for an order inside tx and returns them with their new IDs
+func insertOrderItems(ctx context.Context, tx *sql.Tx, orderID int64, orderItems []entity.OrderItem) ([]entity.OrderItem, error) {
 	itemQuery := `
-		INSERT INTO order_items (order_id, product_name, quantity, unit_price, total_price)
-		VALUES ($1, $2, $3, $4, $5)
+		INSERT INTO order_items (order_id, product_name, sku, quantity, unit, unit_price, discount_percent, total_price)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
 		RETURNING id`
 
-	items := make([]entity.OrderItem, len(order.Items))
-	for i, item := range order.Items {
+	items := make([]entity.OrderItem, len(orderItems))
+	for i, item := range orderItems {
 		var itemID int64
-		err = tx.QueryRowContext(ctx, itemQuery,
+		err := tx.QueryRowContext(ctx, itemQuery,
 			orderID,
 			item.ProductName,
+			nullableString(item.SKU),
 			item.Quantity,
-			item.UnitPrice,
-			item.TotalPrice,
+			nullableString(item.Unit),
+			moneyParam(item.UnitPrice),
+			item.DiscountPercent,
+			moneyParam(item.TotalPrice),
 		).Scan(&itemID)
 		if err != nil {
 			return nil, apperrors.NewDatabaseQueryError("Failed to insert order item").WithCause(err)
 		}
 
 		items[i] = entity.OrderItem{
-			ID:          itemID,
-			OrderID:     orderID,
-			ProductName: item.ProductName,
-			Quantity:    item.Quantity,
-			UnitPrice:   item.UnitPrice,
-			TotalPrice:  item.TotalPrice,
+			ID:              itemID,
+			OrderID:         orderID,
+			ProductName:     item.ProductName,
+			SKU:             item.SKU,
+			Quantity:        item.Quantity,
+			Unit:            item.Unit,
+			UnitPrice:       item.UnitPrice,
+			DiscountPercent: item.DiscountPercent,
+			TotalPrice:      item.TotalPrice,
 		}
 	}
+	return items, nil
+}
 
-	if err = tx.Commit(); err != nil {
-		return nil, apperrors.NewDatabaseTransactionError("Failed to commit transaction").WithCause(err)
+// nextOrderNumber reserves the next number of the year inside the create transaction.
+// The counter row stays locked until commit and a rollback releases the number, so the
+// sequence has no gaps.
+func (r *PostgresOrderRepository) nextOrderNumber(ctx context.Context, tx *sql.Tx, year int) (string, error) {
+	query := `
+		INSERT INTO order_number_counters (year, last_value)
+		VALUES ($1, 1)
+		ON CONFLICT (year) DO UPDATE SET last_value = order_number_counters.last_value + 1
+		RETURNING last_value`
+
+	var sequence int64
+	if err := tx.QueryRowContext(ctx, query, year).Scan(&sequence); err != nil {
+		return "", apperrors.NewDatabaseQueryError("Failed to reserve order number").WithCause(err)
 	}
+	return entity.FormatOrderNumber(r.orderNumberFormat, year, sequence), nil
+}
 
-	// Return the created order with IDs
-	createdOrder := &entity.Order{
-		ID:           orderID,
-		CustomerName: order.CustomerName,
-		TotalAmount:  order.TotalAmount,
-		Status:       order.Status,
-		Items:        items,
-		CreatedAt:    order.CreatedAt,
-		UpdatedAt:    order.UpdatedAt,
+// claimIdempotencyKey serializes creates sharing an idempotency key with a transaction-scoped
+// advisory lock, then returns the ID of the order recorded for the key if it has not expired
+func claimIdempotencyKey(ctx context.Context, tx *sql.Tx, key string) (int64, error) {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('idempotency_key:' || $1))`, key); err != nil {
+		return 0, apperrors.NewDatabaseQueryError("Failed to lock idempotency key").WithCause(err)
+	}
+
+	var orderID int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT order_id FROM idempotency_keys WHERE idempotency_key = $1 AND expires_at > NOW()`, key,
+	).Scan(&orderID)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, apperrors.NewDatabaseQueryError("Failed to check idempotency key").WithCause(err)
+	}
+	return orderID, nil
+}
+
+// recordIdempotencyKey ties the key to a new order until its TTL passes. An expired row for
+// the key is taken over, so reusing a key after its TTL starts a fresh window.
+func recordIdempotencyKey(ctx context.Context, tx *sql.Tx, idem *idempotencyClaim, orderID int64) error {
+	query := `
+		INSERT INTO idempotency_keys (idempotency_key, order_id, created_at, expires_at)
+		VALUES ($1, $2, NOW(), NOW() + make_interval(secs => $3))
+		ON CONFLICT (idempotency_key) DO UPDATE
+		SET order_id = EXCLUDED.order_id, created_at = EXCLUDED.created_at, expires_at = EXCLUDED.expires_at`
+	if _, err := tx.ExecContext(ctx, query, idem.key, orderID, idem.ttl.Seconds()); err != nil {
+		return apperrors.NewDatabaseQueryError("Failed to record idempotency key").WithCause(err)
+	}
+	return nil
+}
+
+// ensureClientReferenceAvailable serializes creates sharing a client reference with a
+// transaction-scoped advisory lock, then rejects the reference if an order already uses it
+func (r *PostgresOrderRepository) ensureClientReferenceAvailable(ctx context.Context, tx *sql.Tx, reference string) error {
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, reference); err != nil {
+		return apperrors.NewDatabaseQueryError("Failed to lock client reference").WithCause(err)
+	}
+
+	var existingID int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT id FROM orders WHERE client_reference = $1 ORDER BY id DESC LIMIT 1`, reference,
+	).Scan(&existingID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return apperrors.NewDatabaseQueryError("Failed to check client reference").WithCause(err)
 	}
 
-	return createdOrder, nil
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"client_reference":  reference,
+		"existing_order_id": existingID,
+	}).Warn("Rejected duplicate client reference")
+
+	return apperrors.NewAlreadyExistsError("an order with this client reference already exists").WithDetails(map[string]interface{}{
+		"client_reference":  reference,
+		"existing_order_id": existingID,
+	})
 }
 
 // GetOrderByID retrieves an order by its ID including its items
-func (r *PostgresOrderRepository) GetOrderByID(ctx context.Context, id int64) (*entity.Order, error) {
+func (r *PostgresOrderRepository) GetOrderByID(ctx context.Context, id int64) (_ *entity.Order, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "GetOrderByID")
+	defer span.End()
+
 	// Get order
 	orderQuery := `
-		SELECT id, customer_name, total_amount, status, created_at, updated_at
+		SELECT ` + orderColumns + `
 		FROM orders
-		WHERE id = $1`
+		WHERE id = $1 AND deleted_at IS NULL`
 
-	var order entity.Order
-	err := r.db.QueryRowContext(ctx, orderQuery, id).Scan(
-		&order.ID,
-		&order.CustomerName,
-		&order.TotalAmount,
-		&order.Status,
-		&order.CreatedAt,
-		&order.UpdatedAt,
-	)
+	order, err := scanOrder(r.db.QueryRowContext(ctx, orderQuery, id))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			r.logger.WithField("order_id", id).Warn("Order not found")
+			r.logger.WithContext(ctx).WithField("order_id", id).Warn("Order not found")
 			return nil, apperrors.NewNotFoundError("order")
 		}
-		r.logger.WithError(err).WithField("order_id", id).Error("Failed to get order")
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to get order")
 		return nil, apperrors.NewDatabaseQueryError("Failed to get order").WithCause(err)
 	}
 
 	// Get order items
 	items, err := r.getOrderItems(ctx, id)
 	if err != nil {
-		r.logger.WithError(err).WithField("order_id", id).Error("Failed to get order items")
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to get order items")
 		return nil, err
 	}
 	order.Items = items
+	r.reconcileTotal(ctx, order)
 
-	r.logger.WithFields(map[string]interface{}{
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"order_id":    order.ID,
 		"items_count": len(order.Items),
 	}).Debug("Successfully retrieved order by ID")
 
-	return &order, nil
+	return order, nil
 }
 
-// ListOrders retrieves orders with pagination using page number and limit
-func (r *PostgresOrderRepository) ListOrders(ctx context.Context, page int, limit int) ([]*entity.Order, *repository.PaginationInfo, error) {
+// GetOrderByClientReference retrieves the most recent order carrying the client reference
+func (r *PostgresOrderRepository) GetOrderByClientReference(ctx context.Context, reference string) (_ *entity.Order, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "GetOrderByClientReference")
+	defer span.End()
+
+	orderQuery := `
+		SELECT ` + orderColumns + `
+		FROM orders
+		WHERE client_reference = $1 AND deleted_at IS NULL
+		ORDER BY id DESC
+		LIMIT 1`
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, orderQuery, reference))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			r.logger.WithContext(ctx).WithField("client_reference", reference).Warn("Order not found for client reference")
+			return nil, apperrors.NewNotFoundError("order")
+		}
+		r.logger.WithContext(ctx).WithError(err).WithField("client_reference", reference).Error("Failed to get order by client reference")
+		return nil, apperrors.NewDatabaseQueryError("Failed to get order").WithCause(err)
+	}
+
+	items, err := r.getOrderItems(ctx, order.ID)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", order.ID).Error("Failed to get order items")
+		return nil, err
+	}
+	order.Items = items
+	r.reconcileTotal(ctx, order)
+
+	return order, nil
+}
+
+// GetStatuses retrieves the statuses of the given orders in a single query, skipping
+// unknown and soft-deleted IDs
+func (r *PostgresOrderRepository) GetStatuses(ctx context.Context, ids []int64) (_ map[int64]string, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "GetStatuses")
+	defer span.End()
+
+	statuses := make(map[int64]string, len(ids))
+	if len(ids) == 0 {
+		return statuses, nil
+	}
+
+	query := `SELECT id, status FROM orders WHERE id = ANY($1) AND deleted_at IS NULL`
+
+	rows, err := r.db.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("ids_count", len(ids)).Error("Failed to get order statuses")
+		return nil, apperrors.NewDatabaseQueryError("Failed to get order statuses").WithCause(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id int64
+		var status string
+		if err := rows.Scan(&id, &status); err != nil {
+			return nil, apperrors.NewDatabaseQueryError("Failed to scan order status").WithCause(err)
+		}
+		statuses[id] = status
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseQueryError("Error iterating order statuses").WithCause(err)
+	}
+
+	return statuses, nil
+}
+
+// CountOrders counts the orders matching the filter with a single COUNT(*) query
+func (r *PostgresOrderRepository) CountOrders(ctx context.Context, filter repository.OrderFilter) (_ int64, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "CountOrders")
+	defer span.End()
+
+	whereClause, args := buildOrderFilter(filter)
+
+	var count int64
+	if err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM orders `+whereClause, args...).Scan(&count); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("status", filter.Status).Error("Failed to count orders")
+		return 0, apperrors.NewDatabaseQueryError("Failed to count orders").WithCause(err)
+	}
+	return count, nil
+}
+
+// SummarizeOrders counts the orders and sums their totals per status with one GROUP BY query
+func (r *PostgresOrderRepository) SummarizeOrders(ctx context.Context) (_ map[string]repository.StatusSummary, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "SummarizeOrders")
+	defer span.End()
+
+	query := `
+		SELECT status, COUNT(*), COALESCE(SUM(total_amount), 0)
+		FROM orders
+		WHERE deleted_at IS NULL
+		GROUP BY status`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).Error("Failed to summarize orders")
+		return nil, apperrors.NewDatabaseQueryError("Failed to summarize orders").WithCause(err)
+	}
+	defer rows.Close()
+
+	summaries := make(map[string]repository.StatusSummary)
+	for rows.Next() {
+		var status string
+		var summary repository.StatusSummary
+		if err := rows.Scan(&status, &summary.Count, scanMoney(&summary.Revenue)); err != nil {
+			return nil, apperrors.NewDatabaseQueryError("Failed to scan order summary").WithCause(err)
+		}
+		summaries[status] = summary
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseQueryError("Error iterating order summary").WithCause(err)
+	}
+
+	return summaries, nil
+}
+
+// ListOrders retrieves orders matching the filter with pagination using page number and limit
+func (r *PostgresOrderRepository) ListOrders(ctx context.Context, page int, limit int, filter repository.OrderFilter) (_ []*entity.Order, _ *repository.PaginationInfo, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "ListOrders")
+	defer span.End()
+
 	// Validate page number (must be >= 1)
 	if page < 1 {
 		page = 1
 	}
 
-	// Calculate offset
-	offset := (page - 1) * limit
+	// Calculate offset, guarding against int overflow for huge pages
+	offset, err := repository.PageOffset(page, limit)
+	if err != nil {
+		r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"page":  page,
+			"limit": limit,
+		}).Warn("Page offset overflows")
+		return nil, nil, err
+	}
+
+	// Count and page over the same scope so pagination reflects the filter
+	whereClause, args := buildOrderFilter(filter)
 
 	// Get total count first
-	countQuery := `SELECT COUNT(*) FROM orders`
+	countQuery := `SELECT COUNT(*) FROM orders ` + whereClause
 	var totalCount int64
-	err := r.db.QueryRowContext(ctx, countQuery).Scan(&totalCount)
+	err = r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
 	if err != nil {
-		r.logger.WithError(err).Error("Failed to get total count of orders")
+		r.logger.WithContext(ctx).WithError(err).Error("Failed to get total count of orders")
 		return nil, nil, apperrors.NewDatabaseQueryError("Failed to get total count").WithCause(err)
 	}
 
@@ -203,15 +726,16 @@ func (r *PostgresOrderRepository) ListOrders(ctx context.Context, page int, limi
 	}
 
 	// Get orders with pagination
-	query := `
-		SELECT id, customer_name, total_amount, status, created_at, updated_at
+	query := fmt.Sprintf(`
+		SELECT %s
 		FROM orders
-		ORDER BY created_at DESC, id DESC
-		LIMIT $1 OFFSET $2`
+		%s
+		ORDER BY %s
+		LIMIT $%d OFFSET $%d`, orderColumns, whereClause, orderByClause(filter.Sort), len(args)+1, len(args)+2)
 
-	rows, err := r.db.QueryContext(ctx, query, limit, offset)
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit, offset)...)
 	if err != nil {
-		r.logger.WithError(err).WithFields(map[string]interface{}{
+		r.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
 			"page":   page,
 			"limit":  limit,
 			"offset": offset,
@@ -222,37 +746,35 @@ func (r *PostgresOrderRepository) ListOrders(ctx context.Context, page int, limi
 
 	var orders []*entity.Order
 	for rows.Next() {
-		order := &entity.Order{}
-		err := rows.Scan(
-			&order.ID,
-			&order.CustomerName,
-			&order.TotalAmount,
-			&order.Status,
-			&order.CreatedAt,
-			&order.UpdatedAt,
-		)
+		order, err := scanOrder(rows)
 		if err != nil {
-			r.logger.WithError(err).Error("Failed to scan order")
+			r.logger.WithContext(ctx).WithError(err).Error("Failed to scan order")
 			return nil, nil, apperrors.NewDatabaseQueryError("Failed to scan order").WithCause(err)
 		}
 
-		// Get items for each order
-		items, err := r.getOrderItems(ctx, order.ID)
-		if err != nil {
-			r.logger.WithError(err).WithField("order_id", order.ID).Error("Failed to get order items")
-			return nil, nil, err
-		}
-		order.Items = items
-
 		orders = append(orders, order)
 	}
 
 	if err = rows.Err(); err != nil {
-		r.logger.WithError(err).Error("Error iterating orders")
+		r.logger.WithContext(ctx).WithError(err).Error("Error iterating orders")
 		return nil, nil, apperrors.NewDatabaseQueryError("Error iterating orders").WithCause(err)
 	}
 
-	r.logger.WithFields(map[string]interface{}{
+	// Load the items of the whole page in one query instead of one query per order
+	orderIDs := make([]int64, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.ID
+	}
+	itemsByOrder, err := r.getItemsForOrders(ctx, orderIDs)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).Error("Failed to get order items")
+		return nil, nil, err
+	}
+	for _, order := range orders {
+		order.Items = itemsByOrder[order.ID]
+	}
+
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"page":         page,
 		"limit":        limit,
 		"total_count":  totalCount,
@@ -263,45 +785,774 @@ func (r *PostgresOrderRepository) ListOrders(ctx context.Context, page int, limi
 	return orders, paginationInfo, nil
 }
 
-// UpdateOrderStatus updates the status of an existing order
-func (r *PostgresOrderRepository) UpdateOrderStatus(ctx context.Context, id int64, status string) error {
+// ListOrdersAfter retrieves up to limit orders with an ID below the cursor, newest first,
+// using a keyset predicate so deep pages cost the same as the first one. A cursor of 0
+// starts from the newest order. The returned cursor is the ID to pass for the next page,
+// or 0 when there are no more orders.
+func (r *PostgresOrderRepository) ListOrdersAfter(ctx context.Context, cursor int64, limit int, filter repository.OrderFilter) (_ []*entity.Order, _ int64, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "ListOrdersAfter")
+	defer span.End()
+
+	whereClause, args := buildOrderFilter(filter)
+	if cursor > 0 {
+		args = append(args, cursor)
+		if whereClause == "" {
+			whereClause = fmt.Sprintf("WHERE id < $%d", len(args))
+		} else {
+			whereClause += fmt.Sprintf(" AND id < $%d", len(args))
+		}
+	}
+
+	// Fetch one extra row to learn whether another page follows without a COUNT
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM orders
+		%s
+		ORDER BY id DESC
+		LIMIT $%d`, orderColumns, whereClause, len(args)+1)
+
+	rows, err := r.db.QueryContext(ctx, query, append(args, limit+1)...)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
+			"cursor": cursor,
+			"limit":  limit,
+		}).Error("Failed to list orders after cursor")
+		return nil, 0, apperrors.NewDatabaseQueryError("Failed to list orders").WithCause(err)
+	}
+	defer rows.Close()
+
+	var orders []*entity.Order
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			r.logger.WithContext(ctx).WithError(err).Error("Failed to scan order")
+			return nil, 0, apperrors.NewDatabaseQueryError("Failed to scan order").WithCause(err)
+		}
+
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.WithContext(ctx).WithError(err).Error("Error iterating orders")
+		return nil, 0, apperrors.NewDatabaseQueryError("Error iterating orders").WithCause(err)
+	}
+
+	var nextCursor int64
+	if len(orders) > limit {
+		orders = orders[:limit]
+		nextCursor = orders[limit-1].ID
+	}
+
+	orderIDs := make([]int64, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.ID
+	}
+	itemsByOrder, err := r.getItemsForOrders(ctx, orderIDs)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).Error("Failed to get order items")
+		return nil, 0, err
+	}
+	for _, order := range orders {
+		order.Items = itemsByOrder[order.ID]
+	}
+
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"cursor":       cursor,
+		"limit":        limit,
+		"next_cursor":  nextCursor,
+		"orders_count": len(orders),
+	}).Debug("Successfully listed orders after cursor")
+
+	return orders, nextCursor, nil
+}
+
+// UpdateOrderStatus updates the status of an existing order and records the transition
+func (r *PostgresOrderRepository) UpdateOrderStatus(ctx context.Context, id int64, status string) (_ bool, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "UpdateOrderStatus")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to begin transaction")
+		return false, apperrors.NewDatabaseConnectionError("Failed to begin transaction").WithCause(err)
+	}
+	defer tx.Rollback()
+
+	// Lock the order row so the recorded previous status cannot race with another update
+	var previousStatus string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id).Scan(&previousStatus)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			r.logger.WithContext(ctx).WithField("order_id", id).Warn("Order not found for status update")
+			return false, apperrors.NewNotFoundError("order")
+		}
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to get current order status")
+		return false, apperrors.NewDatabaseQueryError("Failed to get current order status").WithCause(err)
+	}
+
+	// Re-applying the current status is a no-op so retried requests succeed without a new history row
+	if previousStatus == status {
+		r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"order_id": id,
+			"status":   status,
+		}).Info("Order already has the requested status")
+		return false, nil
+	}
+
+	if err := entity.ValidateStatusTransition(previousStatus, status); err != nil {
+		r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"order_id":        id,
+			"previous_status": previousStatus,
+			"status":          status,
+		}).Warn("Rejected invalid status transition")
+		return false, err
+	}
+
 	query := `
 		UPDATE orders 
 		SET status = $1, updated_at = NOW()
 		WHERE id = $2`
 
-	result, err := r.db.ExecContext(ctx, query, status, id)
-	if err != nil {
-		r.logger.WithError(err).WithFields(map[string]interface{}{
+	if _, err := tx.ExecContext(ctx, query, status, id); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
 			"order_id": id,
 			"status":   status,
 		}).Error("Failed to update order status")
-		return apperrors.NewDatabaseQueryError("Failed to update order status").WithCause(err)
+		return false, apperrors.NewDatabaseQueryError("Failed to update order status").WithCause(err)
+	}
+
+	historyQuery := `
+		INSERT INTO order_status_history (order_id, from_status, to_status, changed_at)
+		VALUES ($1, $2, $3, NOW())`
+
+	if _, err := tx.ExecContext(ctx, historyQuery, id, previousStatus, status); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to record status history")
+		return false, apperrors.NewDatabaseQueryError("Failed to record status history").WithCause(err)
+	}
+
+	if err := commitTx(ctx, tx); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to commit status update")
+		return false, apperrors.NewDatabaseTransactionError("Failed to commit transaction").WithCause(err)
+	}
+
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"order_id":        id,
+		"previous_status": previousStatus,
+		"status":          status,
+	}).Info("Successfully updated order status")
+
+	return true, nil
+}
+
+// UpdateOrderItems replaces the items of a pending order in one transaction. The order row
+// is locked first, so a concurrent status change cannot slip in between the check and the write.
+func (r *PostgresOrderRepository) UpdateOrderItems(ctx context.Context, orderID int64, items []entity.OrderItem) (_ *entity.Order, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "UpdateOrderItems")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", orderID).Error("Failed to begin transaction")
+		return nil, apperrors.NewDatabaseConnectionError("Failed to begin transaction").WithCause(err)
+	}
+	defer tx.Rollback()
+
+	var status string
+	err = tx.QueryRowContext(ctx, `SELECT status FROM orders WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, orderID).Scan(&status)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			r.logger.WithContext(ctx).WithField("order_id", orderID).Warn("Order not found for item update")
+			return nil, apperrors.NewNotFoundError("order")
+		}
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", orderID).Error("Failed to get current order status")
+		return nil, apperrors.NewDatabaseQueryError("Failed to get current order status").WithCause(err)
+	}
+	if err := entity.ValidateItemsEditable(status); err != nil {
+		r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"order_id": orderID,
+			"status":   status,
+		}).Warn("Rejected item update for non-pending order")
+		return nil, err
+	}
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_id = $1`, orderID); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", orderID).Error("Failed to delete order items")
+		return nil, apperrors.NewDatabaseQueryError("Failed to delete order items").WithCause(err)
+	}
+	if _, err := insertOrderItems(ctx, tx, orderID, items); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", orderID).Error("Failed to insert order items")
+		return nil, err
+	}
+
+	// The total is the sum of the new line totals, summed in SQL with database totals
+	var totalAmount entity.Money
+	for _, item := range items {
+		totalAmount += item.TotalPrice
+	}
+	if r.databaseTotals {
+		query := `
+		UPDATE orders
+		SET total_amount = (SELECT COALESCE(SUM(total_price), 0) FROM order_items WHERE order_id = $1), updated_at = NOW()
+		WHERE id = $1
+		RETURNING total_amount`
+		err = tx.QueryRowContext(ctx, query, orderID).Scan(scanMoney(&totalAmount))
+	} else {
+		query := `
+		UPDATE orders
+		SET total_amount = $1, updated_at = NOW()
+		WHERE id = $2`
+		_, err = tx.ExecContext(ctx, query, moneyParam(totalAmount), orderID)
+	}
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", orderID).Error("Failed to update order total")
+		return nil, apperrors.NewDatabaseQueryError("Failed to update order total").WithCause(err)
+	}
+
+	if err := commitTx(ctx, tx); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", orderID).Error("Failed to commit item update")
+		return nil, apperrors.NewDatabaseTransactionError("Failed to commit transaction").WithCause(err)
+	}
+
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"order_id":     orderID,
+		"items_count":  len(items),
+		"total_amount": totalAmount,
+	}).Info("Successfully updated order items")
+
+	if dryrun.Enabled(ctx) {
+		// The update was rolled back; show the stored order as it would have become
+		order, err := r.GetOrderByID(ctx, orderID)
+		if err != nil {
+			return nil, err
+		}
+		order.Items = make([]entity.OrderItem, len(items))
+		for i, item := range items {
+			item.OrderID = orderID
+			order.Items[i] = item
+		}
+		order.TotalAmount = totalAmount
+		order.UpdatedAt = time.Now()
+		return order, nil
+	}
+	return r.GetOrderByID(ctx, orderID)
+}
+
+// SoftDeleteOrder marks an order as deleted without removing its rows. The completed-order
+// rule is part of the UPDATE, so an order completed concurrently is never deleted.
+func (r *PostgresOrderRepository) SoftDeleteOrder(ctx context.Context, id int64) (err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "SoftDeleteOrder")
+	defer span.End()
+
+	query := `
+		UPDATE orders
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND deleted_at IS NULL AND status <> 'completed'`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to begin transaction")
+		return apperrors.NewDatabaseConnectionError("Failed to begin transaction").WithCause(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to soft-delete order")
+		return apperrors.NewDatabaseQueryError("Failed to delete order").WithCause(err)
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		r.logger.WithError(err).WithField("order_id", id).Error("Failed to get rows affected")
-		return apperrors.NewDatabaseQueryError("Failed to get rows affected").WithCause(err)
+		return apperrors.NewDatabaseQueryError("Failed to check affected rows").WithCause(err)
+	}
+	if rowsAffected == 0 {
+		return r.undeletedOrderError(ctx, tx, id, `SELECT status FROM orders WHERE id = $1 AND deleted_at IS NULL`)
+	}
+	if err := commitTx(ctx, tx); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to commit order soft-delete")
+		return apperrors.NewDatabaseTransactionError("Failed to commit transaction").WithCause(err)
+	}
+
+	r.logger.WithContext(ctx).WithField("order_id", id).Info("Successfully soft-deleted order")
+	return nil
+}
+
+// DeleteOrder permanently removes an order and its items in one transaction.
+// Status history is removed by its ON DELETE CASCADE foreign key. Completed orders are
+// excluded by the DELETE itself, and their items are restored by the rollback.
+func (r *PostgresOrderRepository) DeleteOrder(ctx context.Context, id int64) (err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "DeleteOrder")
+	defer span.End()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to begin transaction")
+		return apperrors.NewDatabaseConnectionError("Failed to begin transaction").WithCause(err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `DELETE FROM order_items WHERE order_id = $1`, id); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to delete order items")
+		return apperrors.NewDatabaseQueryError("Failed to delete order items").WithCause(err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM orders WHERE id = $1 AND status <> 'completed'`, id)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to delete order")
+		return apperrors.NewDatabaseQueryError("Failed to delete order").WithCause(err)
 	}
 
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return apperrors.NewDatabaseQueryError("Failed to check affected rows").WithCause(err)
+	}
 	if rowsAffected == 0 {
-		r.logger.WithField("order_id", id).Warn("Order not found for status update")
+		return r.undeletedOrderError(ctx, tx, id, `SELECT status FROM orders WHERE id = $1`)
+	}
+
+	if err := commitTx(ctx, tx); err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to commit order deletion")
+		return apperrors.NewDatabaseTransactionError("Failed to commit transaction").WithCause(err)
+	}
+
+	r.logger.WithContext(ctx).WithField("order_id", id).Info("Successfully deleted order")
+	return nil
+}
+
+// undeletedOrderError explains why a delete matched no row: the order, looked up with
+// statusQuery, is missing or completed
+func (r *PostgresOrderRepository) undeletedOrderError(ctx context.Context, tx *sql.Tx, id int64, statusQuery string) error {
+	var status string
+	err := tx.QueryRowContext(ctx, statusQuery, id).Scan(&status)
+	switch {
+	case err == sql.ErrNoRows:
+		r.logger.WithContext(ctx).WithField("order_id", id).Warn("Order not found for deletion")
 		return apperrors.NewNotFoundError("order")
+	case err != nil:
+		return apperrors.NewDatabaseQueryError("Failed to get order status").WithCause(err)
 	}
 
-	r.logger.WithFields(map[string]interface{}{
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"order_id": id,
 		"status":   status,
-	}).Info("Successfully updated order status")
+	}).Warn("Rejected deletion of completed order")
+	return domainerrors.NewCompletedOrderDeletionError(id)
+}
 
-	return nil
+// PurgeDeletedOrders permanently removes orders soft-deleted before the cutoff.
+// Items and status history are removed by their ON DELETE CASCADE foreign keys.
+func (r *PostgresOrderRepository) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (_ int64, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "PurgeDeletedOrders")
+	defer span.End()
+
+	query := `DELETE FROM orders WHERE deleted_at IS NOT NULL AND deleted_at < $1`
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, apperrors.NewDatabaseConnectionError("Failed to begin transaction").WithCause(err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, query, deletedBefore)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("deleted_before", deletedBefore).Error("Failed to purge deleted orders")
+		return 0, apperrors.NewDatabaseQueryError("Failed to purge deleted orders").WithCause(err)
+	}
+
+	purged, err := result.RowsAffected()
+	if err != nil {
+		return 0, apperrors.NewDatabaseQueryError("Failed to check affected rows").WithCause(err)
+	}
+	if err := commitTx(ctx, tx); err != nil {
+		return 0, apperrors.NewDatabaseTransactionError("Failed to commit transaction").WithCause(err)
+	}
+	return purged, nil
+}
+
+// GetStatusHistory retrieves the recorded status transitions of an order, oldest first
+func (r *PostgresOrderRepository) GetStatusHistory(ctx context.Context, orderID int64) (_ []entity.StatusChange, err error) {
+	ctx, done := r.queryContext(ctx)
+	defer done(&err)
+
+	ctx, span := startSpan(ctx, "GetStatusHistory")
+	defer span.End()
+
+	query := `
+		SELECT id, order_id, from_status, to_status, changed_at
+		FROM order_status_history
+		WHERE order_id = $1
+		ORDER BY changed_at, id`
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		r.logger.WithContext(ctx).WithError(err).WithField("order_id", orderID).Error("Failed to get status history")
+		return nil, apperrors.NewDatabaseQueryError("Failed to get status history").WithCause(err)
+	}
+	defer rows.Close()
+
+	var history []entity.StatusChange
+	for rows.Next() {
+		var change entity.StatusChange
+		if err := rows.Scan(
+			&change.ID,
+			&change.OrderID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.ChangedAt,
+		); err != nil {
+			return nil, apperrors.NewDatabaseQueryError("Failed to scan status history").WithCause(err)
+		}
+		history = append(history, change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseQueryError("Error iterating status history").WithCause(err)
+	}
+
+	return history, nil
+}
+
+// reconcileTotal detects drift between the stored total_amount and the sum of item totals.
+// The discrepancy is always logged; the recomputed total is only returned when configured.
+func (r *PostgresOrderRepository) reconcileTotal(ctx context.Context, order *entity.Order) {
+	var itemsTotal entity.Money
+	for _, item := range order.Items {
+		itemsTotal += item.TotalPrice
+	}
+
+	if itemsTotal == order.TotalAmount {
+		return
+	}
+
+	r.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"order_id":         order.ID,
+		"stored_total":     order.TotalAmount,
+		"recomputed_total": itemsTotal,
+		"using_recomputed": r.recomputeTotalOnMismatch,
+	}).Warn("Order total does not match sum of item totals")
+
+	if r.recomputeTotalOnMismatch {
+		order.TotalAmount = itemsTotal
+		warnings.Add(ctx, "stored total did not match the sum of item totals; total_amount was recomputed from the items")
+	}
+}
+
+// streamError wraps a failure of an order stream. When ctx ended it is a CLIENT_CLOSED_REQUEST
+// (499) or TIMEOUT (504) error, as queryContext reports for other calls; otherwise a query error.
+func streamError(ctx context.Context, err error, message string) error {
+	if ctxErr := apperrors.ContextError(ctx.Err(), "Order stream interrupted, the request was cancelled or timed out"); ctxErr != nil {
+		return ctxErr.WithCause(err)
+	}
+	return apperrors.NewDatabaseQueryError(message).WithCause(err)
+}
+
+// StreamOrders emits orders with their items as they are scanned from a single joined query,
+// so large result sets are never held in memory at once
+func (r *PostgresOrderRepository) StreamOrders(ctx context.Context, filter repository.OrderFilter) (<-chan *entity.Order, <-chan error) {
+	orders := make(chan *entity.Order)
+	errs := make(chan error, 1)
+
+	go func() {
+		defer close(errs)
+		defer close(orders)
+
+		whereClause, args := buildOrderFilter(filter)
+		query := fmt.Sprintf(`
+			SELECT o.id, o.customer_name, o.customer_email, o.client_reference, o.total_amount, o.status, o.created_at, o.updated_at, o.deleted_at,
+			       o.estimated_ship_date, o.order_number,
+			       i.id, i.product_name, i.sku, i.quantity, i.unit, i.unit_price, i.discount_percent, i.total_price
+			FROM (SELECT * FROM orders %s) o
+			LEFT JOIN order_items i ON i.order_id = o.id
+			ORDER BY o.created_at DESC, o.id DESC, i.id`, whereClause)
+
+		rows, err := r.db.QueryContext(ctx, query, args...)
+		if err != nil {
+			r.logger.WithContext(ctx).WithError(err).Error("Failed to stream orders")
+			errs <- streamError(ctx, err, "Failed to stream orders")
+			return
+		}
+		defer rows.Close()
+
+		// emit hands an order to the consumer unless the context is cancelled first
+		emit := func(order *entity.Order) bool {
+			select {
+			case orders <- order:
+				return true
+			case <-ctx.Done():
+				errs <- streamError(ctx, ctx.Err(), "Order stream cancelled")
+				return false
+			}
+		}
+
+		var current *entity.Order
+		emitted := 0
+		for rows.Next() {
+			var order entity.Order
+			var (
+				email       sql.NullString
+				clientRef   sql.NullString
+				orderNumber sql.NullString
+				deletedAt   sql.NullTime
+				shipDate    sql.NullTime
+				itemID      sql.NullInt64
+				productName sql.NullString
+				sku         sql.NullString
+				quantity    sql.NullFloat64
+				unit        sql.NullString
+				unitPrice   entity.Money
+				discount    sql.NullFloat64
+				totalPrice  entity.Money
+			)
+			if err := rows.Scan(
+				&order.ID,
+				&order.CustomerName,
+				&email,
+				&clientRef,
+				scanMoney(&order.TotalAmount),
+				&order.Status,
+				&order.CreatedAt,
+				&order.UpdatedAt,
+				&deletedAt,
+				&shipDate,
+				&orderNumber,
+				&itemID,
+				&productName,
+				&sku,
+				&quantity,
+				&unit,
+				scanMoney(&unitPrice),
+				&discount,
+				scanMoney(&totalPrice),
+			); err != nil {
+				r.logger.WithContext(ctx).WithError(err).Error("Failed to scan streamed order")
+				errs <- apperrors.NewDatabaseQueryError("Failed to scan order").WithCause(err)
+				return
+			}
+			order.CustomerEmail = email.String
+			order.ClientReference = clientRef.String
+			order.OrderNumber = orderNumber.String
+			if deletedAt.Valid {
+				order.DeletedAt = &deletedAt.Time
+			}
+			if shipDate.Valid {
+				order.EstimatedShipDate = &shipDate.Time
+			}
+
+			// Rows are ordered by order, so a new ID means the previous order is complete
+			if current == nil || current.ID != order.ID {
+				if current != nil {
+					if !emit(current) {
+						return
+					}
+					emitted++
+				}
+				current = &order
+			}
+
+			if itemID.Valid {
+				current.Items = append(current.Items, entity.OrderItem{
+					ID:              itemID.Int64,
+					OrderID:         current.ID,
+					ProductName:     productName.String,
+					SKU:             sku.String,
+					Quantity:        quantity.Float64,
+					Unit:            unit.String,
+					UnitPrice:       unitPrice,
+					DiscountPercent: discount.Float64,
+					TotalPrice:      totalPrice,
+				})
+			}
+		}
+
+		if err := rows.Err(); err != nil {
+			r.logger.WithContext(ctx).WithError(err).Error("Error iterating streamed orders")
+			errs <- streamError(ctx, err, "Error iterating orders")
+			return
+		}
+
+		if current != nil {
+			if !emit(current) {
+				return
+			}
+			emitted++
+		}
+
+		r.logger.WithContext(ctx).WithField("orders_count", emitted).Debug("Successfully streamed orders")
+	}()
+
+	return orders, errs
+}
+
+// buildOrderFilter renders the WHERE clause and its arguments for an OrderFilter.
+// Placeholders start at $1 so callers can append further arguments after the returned ones.
+func buildOrderFilter(filter repository.OrderFilter) (string, []interface{}) {
+	var conditions []string
+	var args []interface{}
+
+	if !filter.IncludeDeleted {
+		conditions = append(conditions, "deleted_at IS NULL")
+	}
+	if filter.Status != "" {
+		args = append(args, filter.Status)
+		conditions = append(conditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if filter.Customer != "" {
+		args = append(args, escapeLikePattern(filter.Customer))
+		conditions = append(conditions, fmt.Sprintf(`customer_name ILIKE '%%' || $%d || '%%' ESCAPE '\'`, len(args)))
+	}
+	if filter.CreatedFrom != nil {
+		args = append(args, *filter.CreatedFrom)
+		conditions = append(conditions, fmt.Sprintf("created_at >= $%d", len(args)))
+	}
+	if filter.CreatedTo != nil {
+		args = append(args, *filter.CreatedTo)
+		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)))
+	}
+
+	if len(conditions) == 0 {
+		return "", args
+	}
+	return "WHERE " + strings.Join(conditions, " AND "), args
+}
+
+// orderByClause maps a sort onto fixed SQL, so no caller input ever reaches the query.
+// Unknown fields fall back to created_at; ties are broken by id in the same direction.
+func orderByClause(sort repository.OrderSort) string {
+	direction := "DESC"
+	if sort.Ascending {
+		direction = "ASC"
+	}
+
+	switch sort.Field {
+	case repository.SortByID:
+		return "id " + direction
+	case repository.SortByTotalAmount:
+		return "total_amount " + direction + ", id " + direction
+	default:
+		return "created_at " + direction + ", id " + direction
+	}
+}
+
+// likeEscaper escapes the LIKE wildcards and the escape character itself
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// escapeLikePattern makes s match literally inside a LIKE pattern using ESCAPE '\'
+func escapeLikePattern(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// orderColumns lists the orders columns read by scanOrder, in scan order
+const orderColumns = `id, customer_name, customer_email, client_reference, total_amount, status, created_at, updated_at, deleted_at, estimated_ship_date, order_number`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanOrder scans a row selected with orderColumns into an order without items
+func scanOrder(row rowScanner) (*entity.Order, error) {
+	var order entity.Order
+	var customerEmail, clientReference, orderNumber sql.NullString
+	var deletedAt, shipDate sql.NullTime
+	if err := row.Scan(
+		&order.ID,
+		&order.CustomerName,
+		&customerEmail,
+		&clientReference,
+		scanMoney(&order.TotalAmount),
+		&order.Status,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+		&deletedAt,
+		&shipDate,
+		&orderNumber,
+	); err != nil {
+		return nil, err
+	}
+	order.CustomerEmail = customerEmail.String // NULL for orders placed before emails were recorded
+	order.ClientReference = clientReference.String
+	order.OrderNumber = orderNumber.String
+	if deletedAt.Valid {
+		order.DeletedAt = &deletedAt.Time
+	}
+	if shipDate.Valid {
+		order.EstimatedShipDate = &shipDate.Time
+	}
+	return &order, nil
+}
+
+// startSpan starts a client span for a repository operation, as a child of the request's span
+func startSpan(ctx context.Context, operation string) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "postgres."+operation,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("db.system", "postgresql"),
+			attribute.String("db.operation", operation),
+		),
+	)
+}
+
+// commitTx commits tx, or for a dry run (see pkg/dryrun) rolls it back once every statement
+// has run, so the database still checks constraints and triggers but keeps nothing
+func commitTx(ctx context.Context, tx *sql.Tx) error {
+	if dryrun.Enabled(ctx) {
+		return tx.Rollback()
+	}
+	return tx.Commit()
+}
+
+// nullableString maps an empty string to SQL NULL
+func nullableString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// orderItemColumns lists the order_items columns read by scanOrderItem, in scan order
+const orderItemColumns = `id, order_id, product_name, sku, quantity, unit, unit_price, discount_percent, total_price`
+
+// scanOrderItem scans a row selected with orderItemColumns
+func scanOrderItem(row rowScanner) (entity.OrderItem, error) {
+	var item entity.OrderItem
+	var sku, unit sql.NullString
+	if err := row.Scan(
+		&item.ID,
+		&item.OrderID,
+		&item.ProductName,
+		&sku,
+		&item.Quantity,
+		&unit,
+		scanMoney(&item.UnitPrice),
+		&item.DiscountPercent,
+		scanMoney(&item.TotalPrice),
+	); err != nil {
+		return entity.OrderItem{}, err
+	}
+	item.SKU = sku.String
+	item.Unit = unit.String
+	return item, nil
 }
 
 // getOrderItems retrieves order items for a specific order
 func (r *PostgresOrderRepository) getOrderItems(ctx context.Context, orderID int64) ([]entity.OrderItem, error) {
 	itemsQuery := `
-		SELECT id, order_id, product_name, quantity, unit_price, total_price
+		SELECT ` + orderItemColumns + `
 		FROM order_items
 		WHERE order_id = $1
 		ORDER BY id`
@@ -314,15 +1565,7 @@ func (r *PostgresOrderRepository) getOrderItems(ctx context.Context, orderID int
 
 	var items []entity.OrderItem
 	for rows.Next() {
-		var item entity.OrderItem
-		err := rows.Scan(
-			&item.ID,
-			&item.OrderID,
-			&item.ProductName,
-			&item.Quantity,
-			&item.UnitPrice,
-			&item.TotalPrice,
-		)
+		item, err := scanOrderItem(rows)
 		if err != nil {
 			return nil, apperrors.NewDatabaseQueryError("Failed to scan order item").WithCause(err)
 		}
@@ -335,3 +1578,39 @@ func (r *PostgresOrderRepository) getOrderItems(ctx context.Context, orderID int
 
 	return items, nil
 }
+
+// getItemsForOrders retrieves the items of several orders in one query, keyed by order ID
+// and ordered by item ID within each order. List queries use it instead of calling
+// getOrderItems per order, which would cost one round trip per order on the page.
+func (r *PostgresOrderRepository) getItemsForOrders(ctx context.Context, orderIDs []int64) (map[int64][]entity.OrderItem, error) {
+	itemsByOrder := make(map[int64][]entity.OrderItem, len(orderIDs))
+	if len(orderIDs) == 0 {
+		return itemsByOrder, nil
+	}
+
+	itemsQuery := `
+		SELECT ` + orderItemColumns + `
+		FROM order_items
+		WHERE order_id = ANY($1)
+		ORDER BY order_id, id`
+
+	rows, err := r.db.QueryContext(ctx, itemsQuery, pq.Array(orderIDs))
+	if err != nil {
+		return nil, apperrors.NewDatabaseQueryError("Failed to get order items").WithCause(err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		item, err := scanOrderItem(rows)
+		if err != nil {
+			return nil, apperrors.NewDatabaseQueryError("Failed to scan order item").WithCause(err)
+		}
+		itemsByOrder[item.OrderID] = append(itemsByOrder[item.OrderID], item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, apperrors.NewDatabaseQueryError("Error iterating order items").WithCause(err)
+	}
+
+	return itemsByOrder, nil
+}
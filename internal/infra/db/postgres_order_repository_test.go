@@ -0,0 +1,1028 @@
+package db
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/querybudget"
+)
+
+func TestPostgresOrderRepository_StreamOrders(t *testing.T) {
+	repo := newTestRepository(t)
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		seedOrder(t, repo, "Stream Customer", "pending", base.Add(time.Duration(i)*time.Minute),
+			entity.OrderItem{ProductName: "A", Quantity: 1, UnitPrice: entity.NewMoney(1)},
+			entity.OrderItem{ProductName: "B", Quantity: 2, UnitPrice: entity.NewMoney(2)},
+		)
+	}
+
+	orders, errs := repo.StreamOrders(context.Background(), repository.OrderFilter{})
+
+	var received []*entity.Order
+	for order := range orders {
+		received = append(received, order)
+	}
+	if err := <-errs; err != nil {
+		t.Fatalf("unexpected stream error: %v", err)
+	}
+
+	if len(received) != 5 {
+		t.Fatalf("expected 5 orders, got %d", len(received))
+	}
+	for i, order := range received {
+		if len(order.Items) != 2 {
+			t.Errorf("order %d: expected 2 items, got %d", order.ID, len(order.Items))
+		}
+		if i > 0 && order.CreatedAt.After(received[i-1].CreatedAt) {
+			t.Errorf("expected newest-first ordering at index %d", i)
+		}
+	}
+}
+
+func TestPostgresOrderRepository_StreamOrdersCancellation(t *testing.T) {
+	repo := newTestRepository(t)
+	for i := 0; i < 20; i++ {
+		seedOrder(t, repo, "Stream Customer", "pending", time.Now())
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	orders, errs := repo.StreamOrders(ctx, repository.OrderFilter{})
+
+	<-orders
+	cancel()
+
+	// The producer must stop promptly and close both channels
+	deadline := time.After(2 * time.Second)
+	received := 1
+	for {
+		select {
+		case _, ok := <-orders:
+			if !ok {
+				if received >= 20 {
+					t.Errorf("expected cancellation to stop emission early, received all %d orders", received)
+				}
+				if err := <-errs; !errors.Is(err, apperrors.ErrClientClosedRequest) {
+					t.Errorf("expected a CLIENT_CLOSED_REQUEST error, got %v", err)
+				}
+				return
+			}
+			received++
+		case <-deadline:
+			t.Fatal("stream did not stop after cancellation")
+		}
+	}
+}
+
+func TestStreamError_MapsEndedContexts(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancelExpired := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	defer cancelExpired()
+
+	tests := []struct {
+		ctx    context.Context
+		status int
+	}{
+		{cancelled, apperrors.StatusClientClosedRequest},
+		{expired, http.StatusGatewayTimeout},
+		{context.Background(), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		err := streamError(tt.ctx, errors.New("driver error"), "Failed to stream orders")
+		if got := apperrors.GetHTTPStatus(err); got != tt.status {
+			t.Errorf("expected status %d, got %d (%v)", tt.status, got, err)
+		}
+	}
+}
+
+// captureLogs redirects the pkg/logger output for the duration of a test
+func captureLogs(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	t.Cleanup(func() { logger.SetOutput(nil) })
+	return &buf
+}
+
+func mismatchedOrder() *entity.Order {
+	return &entity.Order{
+		ID:          7,
+		TotalAmount: entity.NewMoney(100),
+		Items: []entity.OrderItem{
+			{ProductName: "A", Quantity: 1, UnitPrice: entity.NewMoney(30), TotalPrice: entity.NewMoney(30)},
+			{ProductName: "B", Quantity: 2, UnitPrice: entity.NewMoney(10), TotalPrice: entity.NewMoney(20)},
+		},
+	}
+}
+
+func TestReconcileTotal_KeepsStoredTotalByDefault(t *testing.T) {
+	logs := captureLogs(t)
+	repo := NewPostgresOrderRepository(nil).(*PostgresOrderRepository)
+
+	order := mismatchedOrder()
+	repo.reconcileTotal(context.Background(), order)
+
+	if order.TotalAmount != entity.NewMoney(100) {
+		t.Errorf("expected the stored total to be kept, got %v", order.TotalAmount)
+	}
+	if !strings.Contains(logs.String(), "Order total does not match sum of item totals") {
+		t.Errorf("expected a mismatch warning, got logs: %s", logs.String())
+	}
+}
+
+func TestReconcileTotal_RecomputesWhenEnabled(t *testing.T) {
+	logs := captureLogs(t)
+	repo := NewPostgresOrderRepository(nil, WithRecomputeTotalOnMismatch(true)).(*PostgresOrderRepository)
+
+	order := mismatchedOrder()
+	repo.reconcileTotal(context.Background(), order)
+
+	if order.TotalAmount != entity.NewMoney(50) {
+		t.Errorf("expected the recomputed total 50, got %v", order.TotalAmount)
+	}
+	if !strings.Contains(logs.String(), `"stored_total":100`) || !strings.Contains(logs.String(), `"recomputed_total":50`) {
+		t.Errorf("expected both totals in the warning, got logs: %s", logs.String())
+	}
+}
+
+func TestReconcileTotal_NoWarningWhenConsistent(t *testing.T) {
+	logs := captureLogs(t)
+	repo := NewPostgresOrderRepository(nil).(*PostgresOrderRepository)
+
+	order := mismatchedOrder()
+	order.TotalAmount = entity.NewMoney(50)
+	repo.reconcileTotal(context.Background(), order)
+
+	if strings.Contains(logs.String(), "does not match") {
+		t.Errorf("expected no warning for a consistent order, got logs: %s", logs.String())
+	}
+}
+
+func TestPostgresOrderRepository_GetOrderByIDWithDriftedTotal(t *testing.T) {
+	repo := newTestRepository(t)
+	created := seedOrder(t, repo, "Drift Customer", "pending", time.Now(),
+		entity.OrderItem{ProductName: "A", Quantity: 2, UnitPrice: entity.NewMoney(5)},
+	)
+	if _, err := repo.db.ExecContext(context.Background(), `UPDATE orders SET total_amount = 99 WHERE id = $1`, created.ID); err != nil {
+		t.Fatalf("failed to drift total: %v", err)
+	}
+
+	stored, err := repo.GetOrderByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.TotalAmount != entity.NewMoney(99) {
+		t.Errorf("expected stored total 99 by default, got %v", stored.TotalAmount)
+	}
+
+	repo.recomputeTotalOnMismatch = true
+	recomputed, err := repo.GetOrderByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if recomputed.TotalAmount != entity.NewMoney(10) {
+		t.Errorf("expected recomputed total 10, got %v", recomputed.TotalAmount)
+	}
+}
+
+func TestQueryContext_TimeoutBecomesTimeoutError(t *testing.T) {
+	repo := &PostgresOrderRepository{queryTimeout: time.Millisecond}
+
+	ctx, done := repo.queryContext(context.Background())
+	<-ctx.Done()
+	err := error(apperrors.NewDatabaseQueryError("Failed to get order").WithCause(ctx.Err()))
+	done(&err)
+
+	appErr := apperrors.GetAppError(err)
+	if appErr == nil || appErr.Code != apperrors.ErrCodeTimeout {
+		t.Fatalf("expected a TIMEOUT error, got %v", err)
+	}
+	if got := apperrors.GetHTTPStatus(err); got != http.StatusGatewayTimeout {
+		t.Errorf("expected status 504, got %d", got)
+	}
+}
+
+func TestQueryContext_CallerContextErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		parent func() (context.Context, context.CancelFunc)
+		code   apperrors.ErrorCode
+		status int
+	}{
+		{"cancelled", func() (context.Context, context.CancelFunc) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			return ctx, cancel
+		}, apperrors.ErrCodeClientClosedRequest, apperrors.StatusClientClosedRequest},
+		{"deadline exceeded", func() (context.Context, context.CancelFunc) {
+			return context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+		}, apperrors.ErrCodeTimeout, http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parent, cancel := tt.parent()
+			defer cancel()
+			// Without a query timeout the caller's context alone decides
+			repo := &PostgresOrderRepository{}
+
+			ctx, done := repo.queryContext(parent)
+			err := error(apperrors.NewDatabaseQueryError("Failed to get order").WithCause(ctx.Err()))
+			done(&err)
+
+			if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != tt.code {
+				t.Fatalf("expected %s, got %v", tt.code, err)
+			}
+			if got := apperrors.GetHTTPStatus(err); got != tt.status {
+				t.Errorf("expected status %d, got %d", tt.status, got)
+			}
+		})
+	}
+}
+
+func TestQueryContext_KeepsErrorsOfLiveContexts(t *testing.T) {
+	repo := &PostgresOrderRepository{queryTimeout: time.Minute}
+
+	_, done := repo.queryContext(context.Background())
+	original := error(apperrors.NewNotFoundError("Order not found"))
+	err := original
+	done(&err)
+
+	if err != original {
+		t.Errorf("expected the error to be kept, got %v", err)
+	}
+}
+
+func TestPostgresOrderRepository_CancelledContext(t *testing.T) {
+	repo := newTestRepository(t)
+	repo.queryTimeout = 5 * time.Second
+	created := seedOrder(t, repo, "Cancelled Customer", "pending", time.Now(),
+		entity.OrderItem{ProductName: "A", Quantity: 1, UnitPrice: entity.NewMoney(5)},
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := repo.GetOrderByID(ctx, created.ID); err == nil {
+		t.Fatal("expected an error for a cancelled context")
+	} else if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != apperrors.ErrCodeClientClosedRequest {
+		t.Errorf("expected CLIENT_CLOSED_REQUEST for a cancelled context, got %v", err)
+	}
+
+	retriesBefore := repo.CreateRetries()
+	order, err := entity.NewOrder("Cancelled Customer", []entity.OrderItem{{ProductName: "A", Quantity: 1, UnitPrice: entity.NewMoney(5)}})
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	if _, err := repo.CreateOrderWithItems(ctx, order); err == nil {
+		t.Fatal("expected creating with a cancelled context to fail")
+	}
+	if retries := repo.CreateRetries() - retriesBefore; retries != 0 {
+		t.Errorf("expected a cancelled create not to be retried, got %d retries", retries)
+	}
+}
+
+func TestPostgresOrderRepository_ClientReference(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	newOrder := func() *entity.Order {
+		order, err := entity.NewOrder("Acme Corp", []entity.OrderItem{{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(10)}})
+		if err != nil {
+			t.Fatalf("failed to build order: %v", err)
+		}
+		order.ClientReference = "PO-4001"
+		return order
+	}
+
+	created, err := repo.CreateOrderWithItems(ctx, newOrder())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	found, err := repo.GetOrderByClientReference(ctx, "PO-4001")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if found.ID != created.ID || found.ClientReference != "PO-4001" || len(found.Items) != 1 {
+		t.Errorf("expected order %d with its reference and items, got %+v", created.ID, found)
+	}
+
+	repo.uniqueClientReference = true
+	_, err = repo.CreateOrderWithItems(ctx, newOrder())
+	if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != apperrors.ErrCodeAlreadyExists {
+		t.Fatalf("expected ALREADY_EXISTS, got %v", err)
+	}
+}
+
+func TestPostgresOrderRepository_ListOrdersQueryBudget(t *testing.T) {
+	repo := NewPostgresOrderRepository(openTestDB(t), WithQueryBudget(true)).(*PostgresOrderRepository)
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 10; i++ {
+		seedOrder(t, repo, "Budget Customer", "pending", base.Add(time.Duration(i)*time.Minute))
+	}
+
+	const queryLimit = 5
+
+	// Optimized: count, page and one batched items query regardless of page size
+	ctx, budget := querybudget.WithBudget(context.Background(), queryLimit, false)
+	orders, _, err := repo.ListOrders(ctx, 1, 10, repository.OrderFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orders) != 10 || len(orders[0].Items) != 1 {
+		t.Fatalf("expected 10 orders with items, got %d", len(orders))
+	}
+	if budget.Exceeded() {
+		t.Errorf("expected list to stay within %d queries, issued %d", queryLimit, budget.Count())
+	}
+
+	// Naive: loading items with one query per order blows the budget
+	ctx, budget = querybudget.WithBudget(context.Background(), queryLimit, false)
+	for _, order := range orders {
+		if _, err := repo.getOrderItems(ctx, order.ID); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if !budget.Exceeded() {
+		t.Errorf("expected per-order item loading to exceed %d queries, issued %d", queryLimit, budget.Count())
+	}
+}
+
+func TestPostgresOrderRepository_DatabaseTotals(t *testing.T) {
+	database := openTestDB(t)
+	repo := NewPostgresOrderRepository(database, WithDatabaseTotals(true)).(*PostgresOrderRepository)
+	ctx := context.Background()
+
+	order, err := entity.NewOrder("Acme Corp", []entity.OrderItem{
+		{ProductName: "Widget", Quantity: 2, UnitPrice: entity.NewMoney(10.25)},
+		{ProductName: "Gadget", Quantity: 3, UnitPrice: entity.NewMoney(4.5)},
+	})
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	order.TotalAmount = entity.NewMoney(1) // A drifted Go total must not be trusted
+
+	created, err := repo.CreateOrderWithItems(ctx, order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.TotalAmount != entity.NewMoney(34) {
+		t.Errorf("expected database-maintained total 34, got %v", created.TotalAmount)
+	}
+
+	// Replacing the items sums the stored rows again
+	updated, err := repo.UpdateOrderItems(ctx, created.ID, order.Items[:1])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if updated.TotalAmount != entity.NewMoney(20.5) {
+		t.Errorf("expected total 20.5 after removing an item, got %v", updated.TotalAmount)
+	}
+
+	// Without the option the Go total is stored as given, so drift stays detectable
+	plain := NewPostgresOrderRepository(database).(*PostgresOrderRepository)
+	order.TotalAmount = entity.NewMoney(1)
+	created, err = plain.CreateOrderWithItems(ctx, order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var stored entity.Money
+	if err := plain.db.QueryRowContext(ctx, `SELECT total_amount FROM orders WHERE id = $1`, created.ID).Scan(scanMoney(&stored)); err != nil {
+		t.Fatalf("failed to read total: %v", err)
+	}
+	if stored != entity.NewMoney(1) {
+		t.Errorf("expected the Go total 1 to be stored untouched, got %v", stored)
+	}
+}
+
+func TestPostgresOrderRepository_CountOrders(t *testing.T) {
+	repo := NewPostgresOrderRepository(openTestDB(t), WithQueryBudget(true)).(*PostgresOrderRepository)
+	now := time.Now()
+	seedOrder(t, repo, "Acme Corp", "pending", now)
+	seedOrder(t, repo, "Acme Corp", "paid", now)
+	seedOrder(t, repo, "Globex", "pending", now.Add(-48*time.Hour))
+
+	yesterday := now.Add(-24 * time.Hour)
+	tests := []struct {
+		name   string
+		filter repository.OrderFilter
+		want   int64
+	}{
+		{name: "unfiltered", filter: repository.OrderFilter{}, want: 3},
+		{name: "status", filter: repository.OrderFilter{Status: "pending"}, want: 2},
+		{name: "customer", filter: repository.OrderFilter{Customer: "acme"}, want: 2},
+		{name: "created range", filter: repository.OrderFilter{CreatedFrom: &yesterday}, want: 2},
+		{name: "combined", filter: repository.OrderFilter{Status: "pending", Customer: "globex"}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, budget := querybudget.WithBudget(context.Background(), 1, false)
+			count, err := repo.CountOrders(ctx, tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if count != tt.want {
+				t.Errorf("expected %d, got %d", tt.want, count)
+			}
+			if budget.Count() != 1 {
+				t.Errorf("expected a single query, issued %d", budget.Count())
+			}
+		})
+	}
+}
+
+func TestPostgresOrderRepository_SummarizeOrders(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+	widget := func(price float64) entity.OrderItem {
+		return entity.OrderItem{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(price)}
+	}
+	seedOrder(t, repo, "A", "pending", now, widget(10.10))
+	seedOrder(t, repo, "B", "pending", now, widget(0.20))
+	seedOrder(t, repo, "C", "completed", now, widget(5))
+	deleted := seedOrder(t, repo, "D", "pending", now, widget(100))
+	if err := repo.SoftDeleteOrder(ctx, deleted.ID); err != nil {
+		t.Fatalf("failed to soft-delete order: %v", err)
+	}
+
+	summaries, err := repo.SummarizeOrders(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string]repository.StatusSummary{
+		"pending":   {Count: 2, Revenue: entity.NewMoney(10.30)},
+		"completed": {Count: 1, Revenue: entity.NewMoney(5)},
+	}
+	if !reflect.DeepEqual(summaries, want) {
+		t.Errorf("expected %v, got %v", want, summaries)
+	}
+}
+
+func TestPostgresOrderRepository_DeleteRejectsCompletedOrders(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	completed := seedOrder(t, repo, "Delete Customer", "completed", time.Now())
+
+	for name, remove := range map[string]func(context.Context, int64) error{
+		"soft delete": repo.SoftDeleteOrder,
+		"hard delete": repo.DeleteOrder,
+	} {
+		if err := remove(ctx, completed.ID); !errors.Is(err, apperrors.ErrBusinessRuleViolation) {
+			t.Errorf("%s: expected a business rule violation, got %v", name, err)
+		}
+		if err := remove(ctx, 999999); !errors.Is(err, apperrors.ErrNotFound) {
+			t.Errorf("%s: expected not found for a missing order, got %v", name, err)
+		}
+	}
+
+	order, err := repo.GetOrderByID(ctx, completed.ID)
+	if err != nil || len(order.Items) != 1 {
+		t.Fatalf("expected the completed order kept with its items, got %+v, %v", order, err)
+	}
+}
+
+func TestPostgresOrderRepository_GetStatuses(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	pending := seedOrder(t, repo, "Status Customer", "pending", time.Now())
+	completed := seedOrder(t, repo, "Status Customer", "completed", time.Now())
+	deleted := seedOrder(t, repo, "Status Customer", "pending", time.Now())
+	if err := repo.SoftDeleteOrder(ctx, deleted.ID); err != nil {
+		t.Fatalf("failed to delete order: %v", err)
+	}
+
+	statuses, err := repo.GetStatuses(ctx, []int64{pending.ID, completed.ID, deleted.ID, 999999})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(statuses) != 2 || statuses[pending.ID] != "pending" || statuses[completed.ID] != "completed" {
+		t.Errorf("expected only the two live orders, got %v", statuses)
+	}
+}
+
+func TestPostgresOrderRepository_OrderNumbers(t *testing.T) {
+	repo := NewPostgresOrderRepository(openTestDB(t), WithOrderNumbers(entity.DefaultOrderNumberFormat))
+	ctx := context.Background()
+
+	const creates = 20
+	numbers := make(chan string, creates)
+	var wg sync.WaitGroup
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			order, err := entity.NewOrder("Number Customer", []entity.OrderItem{
+				{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(10)},
+			})
+			if err != nil {
+				t.Errorf("failed to build order: %v", err)
+				return
+			}
+			created, err := repo.CreateOrderWithItems(ctx, order)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			numbers <- created.OrderNumber
+		}()
+	}
+	wg.Wait()
+	close(numbers)
+
+	// Concurrent creates share the year's sequence without duplicates or gaps
+	seen := make(map[string]bool)
+	for number := range numbers {
+		seen[number] = true
+	}
+	year := time.Now().UTC().Year()
+	for sequence := int64(1); sequence <= creates; sequence++ {
+		want := entity.FormatOrderNumber(entity.DefaultOrderNumberFormat, year, sequence)
+		if !seen[want] {
+			t.Errorf("expected order number %s to be assigned, got %v", want, seen)
+		}
+	}
+}
+
+func TestPostgresOrderRepository_ListOrdersAfter(t *testing.T) {
+	repo := newTestRepository(t)
+	for i := 0; i < 5; i++ {
+		seedOrder(t, repo, "Cursor Customer", "pending", time.Now(),
+			entity.OrderItem{ProductName: "A", Quantity: 1, UnitPrice: entity.NewMoney(1)},
+		)
+	}
+	ctx := context.Background()
+
+	var seen []int64
+	var cursor int64
+	for pages := 0; pages < 5; pages++ {
+		orders, next, err := repo.ListOrdersAfter(ctx, cursor, 2, repository.OrderFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, order := range orders {
+			if len(order.Items) != 1 {
+				t.Errorf("order %d: expected 1 item, got %d", order.ID, len(order.Items))
+			}
+			seen = append(seen, order.ID)
+		}
+		if next == 0 {
+			break
+		}
+		cursor = next
+	}
+
+	if len(seen) != 5 {
+		t.Fatalf("expected 5 orders across pages, got %v", seen)
+	}
+	for i := 1; i < len(seen); i++ {
+		if seen[i] >= seen[i-1] {
+			t.Errorf("expected descending IDs without repeats, got %v", seen)
+		}
+	}
+}
+
+// BenchmarkListOrdersDeepPage compares offset and keyset retrieval of a page near the
+// end of a large table. Keyset pages cost the same wherever they start; offset pages
+// grow with their depth.
+func BenchmarkListOrdersDeepPage(b *testing.B) {
+	database := openTestDB(b)
+	repo := NewPostgresOrderRepository(database).(*PostgresOrderRepository)
+
+	const orders = 100000
+	if _, err := database.Exec(`
+		INSERT INTO orders (customer_name, total_amount, status, created_at, updated_at)
+		SELECT 'Bench Customer', 10, 'pending', NOW() - (g || ' seconds')::interval, NOW()
+		FROM generate_series(1, $1) AS g`, orders); err != nil {
+		b.Fatalf("failed to seed orders: %v", err)
+	}
+
+	ctx := context.Background()
+	const limit = 20
+
+	for _, depth := range []int{1, orders / 2, orders - limit} {
+		b.Run(fmt.Sprintf("offset/depth=%d", depth), func(b *testing.B) {
+			page := depth/limit + 1
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.ListOrders(ctx, page, limit, repository.OrderFilter{}); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("keyset/depth=%d", depth), func(b *testing.B) {
+			cursor := int64(orders - depth + 1)
+			for i := 0; i < b.N; i++ {
+				if _, _, err := repo.ListOrdersAfter(ctx, cursor, limit, repository.OrderFilter{}); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestPostgresOrderRepository_GetItemsForOrders(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	now := time.Now()
+
+	first := seedOrder(t, repo, "First", "pending", now,
+		entity.OrderItem{ProductName: "A", Quantity: 1, UnitPrice: entity.NewMoney(1)},
+		entity.OrderItem{ProductName: "B", Quantity: 1, UnitPrice: entity.NewMoney(2)},
+		entity.OrderItem{ProductName: "C", Quantity: 1, UnitPrice: entity.NewMoney(3)},
+	)
+	second := seedOrder(t, repo, "Second", "pending", now)
+
+	itemsByOrder, err := repo.getItemsForOrders(ctx, []int64{second.ID, first.ID, 999999})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(itemsByOrder[first.ID]) != 3 || len(itemsByOrder[second.ID]) != 1 {
+		t.Fatalf("expected 3 and 1 items, got %v", itemsByOrder)
+	}
+	if _, ok := itemsByOrder[999999]; ok {
+		t.Error("expected no entry for an unknown order")
+	}
+	items := itemsByOrder[first.ID]
+	for i := 1; i < len(items); i++ {
+		if items[i].ID <= items[i-1].ID {
+			t.Errorf("expected items ordered by ID, got %v", items)
+		}
+	}
+
+	single, err := repo.getOrderItems(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for i := range single {
+		if single[i].ID != items[i].ID || single[i].ProductName != items[i].ProductName {
+			t.Errorf("expected the batched items to match getOrderItems, got %v and %v", items, single)
+		}
+	}
+}
+
+// BenchmarkLoadPageItems compares loading the items of a 100-order page with one query per
+// order against a single ANY($1) query, as ListOrders does
+func BenchmarkLoadPageItems(b *testing.B) {
+	database := openTestDB(b)
+	repo := NewPostgresOrderRepository(database).(*PostgresOrderRepository)
+
+	const pageSize = 100
+	if _, err := database.Exec(`
+		INSERT INTO orders (customer_name, total_amount, status, created_at, updated_at)
+		SELECT 'Bench Customer', 30, 'pending', NOW(), NOW()
+		FROM generate_series(1, $1)`, pageSize); err != nil {
+		b.Fatalf("failed to seed orders: %v", err)
+	}
+	if _, err := database.Exec(`
+		INSERT INTO order_items (order_id, product_name, quantity, unit_price, total_price)
+		SELECT o.id, 'Widget ' || g, 1, 10, 10
+		FROM orders o CROSS JOIN generate_series(1, 3) AS g`); err != nil {
+		b.Fatalf("failed to seed order items: %v", err)
+	}
+
+	orderIDs := make([]int64, pageSize)
+	for i := range orderIDs {
+		orderIDs[i] = int64(i + 1)
+	}
+	ctx := context.Background()
+
+	b.Run("per-order", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for _, id := range orderIDs {
+				if _, err := repo.getOrderItems(ctx, id); err != nil {
+					b.Fatalf("unexpected error: %v", err)
+				}
+			}
+		}
+	})
+	b.Run("batched", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := repo.getItemsForOrders(ctx, orderIDs); err != nil {
+				b.Fatalf("unexpected error: %v", err)
+			}
+		}
+	})
+}
+
+func TestPostgresOrderRepository_CreatePaidOrder(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	order, err := entity.NewOrder("Prepaid Customer", []entity.OrderItem{
+		{ProductName: "Widget", Quantity: 2, UnitPrice: entity.NewMoney(10)},
+	})
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	created, err := repo.CreatePaidOrder(ctx, order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stored, err := repo.GetOrderByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Status != "paid" || stored.Status != "paid" {
+		t.Errorf("expected status paid, got %s (stored %s)", created.Status, stored.Status)
+	}
+
+	history, err := repo.GetStatusHistory(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].FromStatus != "pending" || history[0].ToStatus != "paid" {
+		t.Errorf("expected a single pending -> paid history row, got %+v", history)
+	}
+}
+
+func TestEscapeLikePattern(t *testing.T) {
+	tests := map[string]string{
+		"Acme":     "Acme",
+		"100%":     `100\%`,
+		"snake_co": `snake\_co`,
+		`back\sl`:  `back\\sl`,
+	}
+	for input, want := range tests {
+		if got := escapeLikePattern(input); got != want {
+			t.Errorf("escapeLikePattern(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestOrderByClause(t *testing.T) {
+	tests := []struct {
+		sort repository.OrderSort
+		want string
+	}{
+		{sort: repository.OrderSort{}, want: "created_at DESC, id DESC"},
+		{sort: repository.OrderSort{Field: repository.SortByCreatedAt, Ascending: true}, want: "created_at ASC, id ASC"},
+		{sort: repository.OrderSort{Field: repository.SortByTotalAmount}, want: "total_amount DESC, id DESC"},
+		{sort: repository.OrderSort{Field: repository.SortByID, Ascending: true}, want: "id ASC"},
+		{sort: repository.OrderSort{Field: "id; DROP TABLE orders"}, want: "created_at DESC, id DESC"},
+	}
+	for _, tt := range tests {
+		if got := orderByClause(tt.sort); got != tt.want {
+			t.Errorf("orderByClause(%+v) = %q, want %q", tt.sort, got, tt.want)
+		}
+	}
+}
+
+func TestPostgresOrderRepository_ListOrdersCustomerFilter(t *testing.T) {
+	repo := newTestRepository(t)
+	base := time.Now().Add(-time.Hour)
+	for i, name := range []string{"Acme Corp", "ACME Industries", "Globex", "100% Cotton Ltd", "1000 Cotton Co", "snake_case Inc", "snakeXcase Inc"} {
+		seedOrder(t, repo, name, "pending", base.Add(time.Duration(i)*time.Minute))
+	}
+	seedOrder(t, repo, "acme outlet", "cancelled", base.Add(time.Hour))
+	ctx := context.Background()
+
+	tests := []struct {
+		name   string
+		filter repository.OrderFilter
+		want   []string
+	}{
+		{name: "case-insensitive", filter: repository.OrderFilter{Customer: "acme"}, want: []string{"acme outlet", "ACME Industries", "Acme Corp"}},
+		{name: "percent is literal", filter: repository.OrderFilter{Customer: "100%"}, want: []string{"100% Cotton Ltd"}},
+		{name: "underscore is literal", filter: repository.OrderFilter{Customer: "snake_"}, want: []string{"snake_case Inc"}},
+		{name: "combined with status", filter: repository.OrderFilter{Customer: "ACME", Status: "pending"}, want: []string{"ACME Industries", "Acme Corp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, pagination, err := repo.ListOrders(ctx, 1, 10, tt.filter)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var names []string
+			for _, order := range orders {
+				names = append(names, order.CustomerName)
+			}
+			if strings.Join(names, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("expected %v, got %v", tt.want, names)
+			}
+			if pagination.TotalCount != int64(len(tt.want)) {
+				t.Errorf("expected total count %d, got %d", len(tt.want), pagination.TotalCount)
+			}
+		})
+	}
+}
+
+func TestPostgresOrderRepository_ListOrdersCreatedRange(t *testing.T) {
+	repo := newTestRepository(t)
+	day := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		seedOrder(t, repo, "Range Customer", "pending", day.AddDate(0, 0, i))
+	}
+	ctx := context.Background()
+
+	from := time.Date(2024, 3, 2, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC).Add(-time.Microsecond)
+	orders, pagination, err := repo.ListOrders(ctx, 1, 10, repository.OrderFilter{CreatedFrom: &from, CreatedTo: &to})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orders) != 2 || pagination.TotalCount != 2 {
+		t.Fatalf("expected the orders of Mar 2 and 3, got %d (total %d)", len(orders), pagination.TotalCount)
+	}
+	for _, order := range orders {
+		if order.CreatedAt.Before(from) || order.CreatedAt.After(to) {
+			t.Errorf("order %d created at %s is outside the range", order.ID, order.CreatedAt)
+		}
+	}
+}
+
+func TestPostgresOrderRepository_MeasuredQuantities(t *testing.T) {
+	repo := newTestRepository(t)
+	created := seedOrder(t, repo, "Weight Customer", "pending", time.Now(),
+		entity.OrderItem{ProductName: "Coffee beans", Quantity: 1.5, Unit: "kg", UnitPrice: entity.NewMoney(24)},
+		entity.OrderItem{ProductName: "Mug", Quantity: 2, UnitPrice: entity.NewMoney(8.5)},
+	)
+
+	stored, err := repo.GetOrderByID(context.Background(), created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored.Items) != 2 {
+		t.Fatalf("expected 2 items, got %d", len(stored.Items))
+	}
+	if item := stored.Items[0]; item.Quantity != 1.5 || item.Unit != "kg" || item.TotalPrice != entity.NewMoney(36) {
+		t.Errorf("expected 1.5 kg totalling 36, got %+v", item)
+	}
+	if item := stored.Items[1]; item.Quantity != 2 || item.Unit != "" {
+		t.Errorf("expected 2 counted mugs, got %+v", item)
+	}
+	if stored.TotalAmount != entity.NewMoney(53) {
+		t.Errorf("expected total 53, got %v", stored.TotalAmount)
+	}
+}
+
+func TestPostgresOrderRepository_UpdateOrderItems(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+	created := seedOrder(t, repo, "Editing Customer", "pending", time.Now())
+
+	revised, err := entity.NewOrder("Editing Customer", []entity.OrderItem{
+		{ProductName: "Widget", Quantity: 3, UnitPrice: entity.NewMoney(10)},
+		{ProductName: "Rope", Quantity: 2.5, Unit: "m", UnitPrice: entity.NewMoney(4)},
+	})
+	if err != nil {
+		t.Fatalf("failed to build items: %v", err)
+	}
+	updated, err := repo.UpdateOrderItems(ctx, created.ID, revised.Items)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(updated.Items) != 2 || updated.TotalAmount != entity.NewMoney(40) {
+		t.Errorf("expected 2 items totalling 40, got %d items totalling %v", len(updated.Items), updated.TotalAmount)
+	}
+	if updated.UpdatedAt.Before(created.UpdatedAt) {
+		t.Errorf("expected updated_at to move forward from %v, got %v", created.UpdatedAt, updated.UpdatedAt)
+	}
+
+	if _, err := repo.UpdateOrderStatus(ctx, created.ID, "processing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := repo.UpdateOrderItems(ctx, created.ID, revised.Items); !errors.Is(err, entity.ErrItemsNotEditable) {
+		t.Errorf("expected ErrItemsNotEditable for a processing order, got %v", err)
+	}
+	stored, err := repo.GetOrderByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stored.Items) != 2 || stored.TotalAmount != entity.NewMoney(40) {
+		t.Errorf("expected the rejected update to leave the order unchanged, got %+v", stored)
+	}
+}
+
+func TestPostgresOrderRepository_CustomerEmail(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	order, err := entity.NewOrder("Email Customer", []entity.OrderItem{
+		{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(10)},
+	}, entity.WithCustomerEmail("email.customer@example.com"))
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	created, err := repo.CreateOrderWithItems(ctx, order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := repo.GetOrderByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.CustomerEmail != "email.customer@example.com" {
+		t.Errorf("expected the email to round-trip, got %q", stored.CustomerEmail)
+	}
+
+	// Orders created before the column existed have NULL emails
+	legacy := seedOrder(t, repo, "Legacy Customer", "pending", time.Now())
+	stored, err = repo.GetOrderByID(ctx, legacy.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.CustomerEmail != "" {
+		t.Errorf("expected a NULL email to scan as empty, got %q", stored.CustomerEmail)
+	}
+}
+
+func TestPostgresOrderRepository_ItemDiscount(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	order, err := entity.NewOrder("Discount Customer", []entity.OrderItem{
+		{ProductName: "Widget", Quantity: 3, UnitPrice: entity.NewMoney(19.99), DiscountPercent: 12.5},
+		{ProductName: "Gadget", Quantity: 1, UnitPrice: entity.NewMoney(10)},
+	})
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	created, err := repo.CreateOrderWithItems(ctx, order)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	stored, err := repo.GetOrderByID(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if stored.Items[0].DiscountPercent != 12.5 || stored.Items[0].TotalPrice != entity.NewMoney(52.47) {
+		t.Errorf("expected the discounted line to round-trip, got %+v", stored.Items[0])
+	}
+	if stored.Items[1].DiscountPercent != 0 {
+		t.Errorf("expected an undiscounted line to store 0, got %v", stored.Items[1].DiscountPercent)
+	}
+	if stored.TotalAmount != entity.NewMoney(62.47) {
+		t.Errorf("expected the total to sum the discounted lines, got %v", stored.TotalAmount)
+	}
+}
+
+func TestPostgresOrderRepository_CreateOrderIdempotentConcurrent(t *testing.T) {
+	repo := newTestRepository(t)
+	ctx := context.Background()
+
+	const callers = 2
+	type result struct {
+		order    *entity.Order
+		replayed bool
+		err      error
+	}
+	results := make(chan result, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			order, err := entity.NewOrder("Retry Customer", []entity.OrderItem{
+				{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(10)},
+			})
+			if err != nil {
+				results <- result{err: err}
+				return
+			}
+			created, replayed, err := repo.CreateOrderIdempotent(ctx, order, false, "checkout-1", time.Hour)
+			results <- result{order: created, replayed: replayed, err: err}
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	var ids []int64
+	replays := 0
+	for res := range results {
+		if res.err != nil {
+			t.Fatalf("unexpected error: %v", res.err)
+		}
+		ids = append(ids, res.order.ID)
+		if res.replayed {
+			replays++
+		}
+	}
+	if ids[0] != ids[1] || replays != 1 {
+		t.Errorf("expected one create and one replay of the same order, got ids %v with %d replays", ids, replays)
+	}
+
+	_, pagination, err := repo.ListOrders(ctx, 1, 10, repository.OrderFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pagination.TotalCount != 1 {
+		t.Errorf("expected exactly one persisted order, got %d", pagination.TotalCount)
+	}
+}
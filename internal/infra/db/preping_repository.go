@@ -0,0 +1,218 @@
+package db
+
+import (
+	"context"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/retryutil"
+)
+
+// Pinger verifies that the database is reachable; *sql.DB satisfies it
+type Pinger interface {
+	PingContext(ctx context.Context) error
+}
+
+// PrePingRepository retries a repository call once, after a successful ping, when it fails
+// with a connection error. database/sql has no validation on checkout, so the first query
+// after a long idle period can pick up a connection the server already closed; the ping
+// flushes such connections out of the pool before the call is repeated. It runs outside
+// the repository's own retry loop, which only covers creates. StreamOrders is passed through
+// unchanged, since a stream may already have emitted orders when it fails.
+type PrePingRepository struct {
+	repository.OrderRepository
+	pinger      Pinger
+	pingTimeout time.Duration
+	logger      *logger.Logger
+}
+
+// NewPrePingRepository wraps repo so calls failing with a connection error are retried once
+// after pinging through pinger. A non-positive pingTimeout defaults to 5 seconds.
+func NewPrePingRepository(repo repository.OrderRepository, pinger Pinger, pingTimeout time.Duration) *PrePingRepository {
+	if pingTimeout <= 0 {
+		pingTimeout = 5 * time.Second
+	}
+	return &PrePingRepository{
+		OrderRepository: repo,
+		pinger:          pinger,
+		pingTimeout:     pingTimeout,
+		logger:          logger.New("preping-repository", "1.0.0"),
+	}
+}
+
+// do runs call, and once more if it failed with a connection error and the ping succeeds
+func (r *PrePingRepository) do(ctx context.Context, operation string, call func() error) error {
+	err := call()
+	if err == nil || !retryutil.IsConnectionError(err) || ctx.Err() != nil {
+		return err
+	}
+
+	pingCtx, cancel := context.WithTimeout(ctx, r.pingTimeout)
+	defer cancel()
+	if pingErr := r.pinger.PingContext(pingCtx); pingErr != nil {
+		r.logger.WithContext(ctx).WithError(pingErr).WithField("operation", operation).Warn("Database ping failed after connection error")
+		return err
+	}
+
+	r.logger.WithContext(ctx).WithError(err).WithField("operation", operation).Info("Retrying after connection error and successful ping")
+	return call()
+}
+
+// CreateOrderWithItems creates an order, retrying once on a stale connection
+func (r *PrePingRepository) CreateOrderWithItems(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	var created *entity.Order
+	err := r.do(ctx, "create_order", func() (err error) {
+		created, err = r.OrderRepository.CreateOrderWithItems(ctx, order)
+		return err
+	})
+	return created, err
+}
+
+// CreatePaidOrder creates a paid order, retrying once on a stale connection
+func (r *PrePingRepository) CreatePaidOrder(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	var created *entity.Order
+	err := r.do(ctx, "create_paid_order", func() (err error) {
+		created, err = r.OrderRepository.CreatePaidOrder(ctx, order)
+		return err
+	})
+	return created, err
+}
+
+// CreateOrderIdempotent creates or replays a keyed order, retrying once on a stale connection
+func (r *PrePingRepository) CreateOrderIdempotent(ctx context.Context, order *entity.Order, paid bool, key string, ttl time.Duration) (*entity.Order, bool, error) {
+	var created *entity.Order
+	var replayed bool
+	err := r.do(ctx, "create_order_idempotent", func() (err error) {
+		created, replayed, err = r.OrderRepository.CreateOrderIdempotent(ctx, order, paid, key, ttl)
+		return err
+	})
+	return created, replayed, err
+}
+
+// GetOrderByID retrieves an order, retrying once on a stale connection
+func (r *PrePingRepository) GetOrderByID(ctx context.Context, id int64) (*entity.Order, error) {
+	var order *entity.Order
+	err := r.do(ctx, "get_order", func() (err error) {
+		order, err = r.OrderRepository.GetOrderByID(ctx, id)
+		return err
+	})
+	return order, err
+}
+
+// GetOrderByClientReference retrieves an order by reference, retrying once on a stale connection
+func (r *PrePingRepository) GetOrderByClientReference(ctx context.Context, reference string) (*entity.Order, error) {
+	var order *entity.Order
+	err := r.do(ctx, "get_order_by_reference", func() (err error) {
+		order, err = r.OrderRepository.GetOrderByClientReference(ctx, reference)
+		return err
+	})
+	return order, err
+}
+
+// GetStatuses retrieves order statuses, retrying once on a stale connection
+func (r *PrePingRepository) GetStatuses(ctx context.Context, ids []int64) (map[int64]string, error) {
+	var statuses map[int64]string
+	err := r.do(ctx, "get_statuses", func() (err error) {
+		statuses, err = r.OrderRepository.GetStatuses(ctx, ids)
+		return err
+	})
+	return statuses, err
+}
+
+// ListOrders lists orders, retrying once on a stale connection
+func (r *PrePingRepository) ListOrders(ctx context.Context, page int, limit int, filter repository.OrderFilter) ([]*entity.Order, *repository.PaginationInfo, error) {
+	var orders []*entity.Order
+	var pagination *repository.PaginationInfo
+	err := r.do(ctx, "list_orders", func() (err error) {
+		orders, pagination, err = r.OrderRepository.ListOrders(ctx, page, limit, filter)
+		return err
+	})
+	return orders, pagination, err
+}
+
+// CountOrders counts matching orders, retrying once on a stale connection
+func (r *PrePingRepository) CountOrders(ctx context.Context, filter repository.OrderFilter) (int64, error) {
+	var count int64
+	err := r.do(ctx, "count_orders", func() (err error) {
+		count, err = r.OrderRepository.CountOrders(ctx, filter)
+		return err
+	})
+	return count, err
+}
+
+// SummarizeOrders aggregates orders per status, retrying once on a stale connection
+func (r *PrePingRepository) SummarizeOrders(ctx context.Context) (map[string]repository.StatusSummary, error) {
+	var summaries map[string]repository.StatusSummary
+	err := r.do(ctx, "summarize_orders", func() (err error) {
+		summaries, err = r.OrderRepository.SummarizeOrders(ctx)
+		return err
+	})
+	return summaries, err
+}
+
+// ListOrdersAfter lists orders after a cursor, retrying once on a stale connection
+func (r *PrePingRepository) ListOrdersAfter(ctx context.Context, cursor int64, limit int, filter repository.OrderFilter) ([]*entity.Order, int64, error) {
+	var orders []*entity.Order
+	var nextCursor int64
+	err := r.do(ctx, "list_orders_after", func() (err error) {
+		orders, nextCursor, err = r.OrderRepository.ListOrdersAfter(ctx, cursor, limit, filter)
+		return err
+	})
+	return orders, nextCursor, err
+}
+
+// SoftDeleteOrder soft-deletes an order, retrying once on a stale connection
+func (r *PrePingRepository) SoftDeleteOrder(ctx context.Context, id int64) error {
+	return r.do(ctx, "soft_delete_order", func() error {
+		return r.OrderRepository.SoftDeleteOrder(ctx, id)
+	})
+}
+
+// DeleteOrder deletes an order, retrying once on a stale connection
+func (r *PrePingRepository) DeleteOrder(ctx context.Context, id int64) error {
+	return r.do(ctx, "delete_order", func() error {
+		return r.OrderRepository.DeleteOrder(ctx, id)
+	})
+}
+
+// PurgeDeletedOrders purges soft-deleted orders, retrying once on a stale connection
+func (r *PrePingRepository) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	var purged int64
+	err := r.do(ctx, "purge_deleted_orders", func() (err error) {
+		purged, err = r.OrderRepository.PurgeDeletedOrders(ctx, deletedBefore)
+		return err
+	})
+	return purged, err
+}
+
+// UpdateOrderStatus updates an order status, retrying once on a stale connection
+func (r *PrePingRepository) UpdateOrderStatus(ctx context.Context, id int64, status string) (bool, error) {
+	var changed bool
+	err := r.do(ctx, "update_order_status", func() (err error) {
+		changed, err = r.OrderRepository.UpdateOrderStatus(ctx, id, status)
+		return err
+	})
+	return changed, err
+}
+
+// UpdateOrderItems replaces order items, retrying once on a stale connection
+func (r *PrePingRepository) UpdateOrderItems(ctx context.Context, orderID int64, items []entity.OrderItem) (*entity.Order, error) {
+	var order *entity.Order
+	err := r.do(ctx, "update_order_items", func() (err error) {
+		order, err = r.OrderRepository.UpdateOrderItems(ctx, orderID, items)
+		return err
+	})
+	return order, err
+}
+
+// GetStatusHistory retrieves status history, retrying once on a stale connection
+func (r *PrePingRepository) GetStatusHistory(ctx context.Context, orderID int64) ([]entity.StatusChange, error) {
+	var history []entity.StatusChange
+	err := r.do(ctx, "get_status_history", func() (err error) {
+		history, err = r.OrderRepository.GetStatusHistory(ctx, orderID)
+		return err
+	})
+	return history, err
+}
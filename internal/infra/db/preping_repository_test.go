@@ -0,0 +1,96 @@
+package db
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/infra/memory"
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// staleConnRepository fails the next lookups with the error database/sql drivers report for
+// a connection the server closed while it sat idle in the pool
+type staleConnRepository struct {
+	*memory.InMemoryOrderRepository
+	failures int
+	calls    int
+}
+
+func (r *staleConnRepository) GetOrderByID(ctx context.Context, id int64) (*entity.Order, error) {
+	r.calls++
+	if r.failures > 0 {
+		r.failures--
+		return nil, apperrors.NewDatabaseQueryError("Failed to get order").WithCause(driver.ErrBadConn)
+	}
+	return r.InMemoryOrderRepository.GetOrderByID(ctx, id)
+}
+
+type fakePinger struct {
+	err   error
+	pings int
+}
+
+func (p *fakePinger) PingContext(ctx context.Context) error {
+	p.pings++
+	return p.err
+}
+
+func TestPrePingRepository(t *testing.T) {
+	ctx := context.Background()
+	newRepo := func(failures int) *staleConnRepository {
+		repo := &staleConnRepository{InMemoryOrderRepository: memory.NewInMemoryOrderRepository(), failures: failures}
+		order, err := entity.NewOrder("Idle Customer", []entity.OrderItem{{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(10)}})
+		if err != nil {
+			t.Fatalf("failed to build order: %v", err)
+		}
+		if _, err := repo.CreateOrderWithItems(ctx, order); err != nil {
+			t.Fatalf("failed to seed order: %v", err)
+		}
+		return repo
+	}
+
+	t.Run("recovers transparently from one stale connection", func(t *testing.T) {
+		repo, pinger := newRepo(1), &fakePinger{}
+		order, err := NewPrePingRepository(repo, pinger, 0).GetOrderByID(ctx, 1)
+		if err != nil {
+			t.Fatalf("expected the retry to succeed, got %v", err)
+		}
+		if order.ID != 1 || repo.calls != 2 || pinger.pings != 1 {
+			t.Errorf("expected order 1 after 2 calls and 1 ping, got order %d, %d calls, %d pings", order.ID, repo.calls, pinger.pings)
+		}
+	})
+
+	t.Run("retries only once", func(t *testing.T) {
+		repo, pinger := newRepo(2), &fakePinger{}
+		if _, err := NewPrePingRepository(repo, pinger, 0).GetOrderByID(ctx, 1); err == nil {
+			t.Fatal("expected the second failure to be returned")
+		}
+		if repo.calls != 2 {
+			t.Errorf("expected 2 calls, got %d", repo.calls)
+		}
+	})
+
+	t.Run("does not retry when the ping fails", func(t *testing.T) {
+		repo, pinger := newRepo(1), &fakePinger{err: errors.New("connection refused")}
+		if _, err := NewPrePingRepository(repo, pinger, 0).GetOrderByID(ctx, 1); err == nil {
+			t.Fatal("expected the original error")
+		}
+		if repo.calls != 1 {
+			t.Errorf("expected 1 call, got %d", repo.calls)
+		}
+	})
+
+	t.Run("does not retry other errors", func(t *testing.T) {
+		repo, pinger := newRepo(0), &fakePinger{}
+		_, err := NewPrePingRepository(repo, pinger, 0).GetOrderByID(ctx, 42)
+		if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != apperrors.ErrCodeNotFound {
+			t.Fatalf("expected not found, got %v", err)
+		}
+		if repo.calls != 1 || pinger.pings != 0 {
+			t.Errorf("expected 1 call and no ping, got %d calls and %d pings", repo.calls, pinger.pings)
+		}
+	})
+}
@@ -0,0 +1,2 @@
+-- Fixture migration used by migrate_test.go
+SELECT 1;
@@ -0,0 +1,69 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+)
+
+// testMigrationsPath points at the repository migrations from this package directory
+const testMigrationsPath = "../../../migrations"
+
+// openTestDB connects to the database in TEST_DATABASE_URL, applies migrations and
+// empties all tables. Tests using it are skipped when the variable is not set.
+func openTestDB(t testing.TB) *sql.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set; skipping database integration test")
+	}
+
+	database, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+
+	if err := NewMigrationManager(database).RunMigrations(testMigrationsPath); err != nil {
+		t.Fatalf("failed to run migrations: %v", err)
+	}
+
+	if _, err := database.Exec(`TRUNCATE orders, order_items, order_status_history, order_number_counters, idempotency_keys, outbox RESTART IDENTITY CASCADE`); err != nil {
+		t.Fatalf("failed to truncate tables: %v", err)
+	}
+
+	return database
+}
+
+// seedOrder persists an order with the given customer, status and creation time
+func seedOrder(t testing.TB, repo *PostgresOrderRepository, customerName string, status string, createdAt time.Time, items ...entity.OrderItem) *entity.Order {
+	t.Helper()
+
+	if len(items) == 0 {
+		items = []entity.OrderItem{{ProductName: "Widget", Quantity: 1, UnitPrice: entity.NewMoney(10)}}
+	}
+	order, err := entity.NewOrder(customerName, items)
+	if err != nil {
+		t.Fatalf("failed to build order: %v", err)
+	}
+	order.Status = status
+	order.CreatedAt = createdAt
+	order.UpdatedAt = createdAt
+
+	created, err := repo.CreateOrderWithItems(context.Background(), order)
+	if err != nil {
+		t.Fatalf("failed to seed order: %v", err)
+	}
+	return created
+}
+
+// newTestRepository returns a PostgresOrderRepository backed by the test database
+func newTestRepository(t testing.TB) *PostgresOrderRepository {
+	t.Helper()
+	return NewPostgresOrderRepository(openTestDB(t)).(*PostgresOrderRepository)
+}
@@ -0,0 +1,628 @@
+package memory
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	domainerrors "online-order-management-system/internal/domain/errors"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/pkg/dryrun"
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// InMemoryOrderRepository implements the OrderRepository interface in memory.
+// It is intended for tests and local experimentation, not for production use.
+type InMemoryOrderRepository struct {
+	mu            sync.RWMutex
+	orders        map[int64]*entity.Order
+	statusHistory map[int64][]entity.StatusChange
+	idemKeys      map[string]idempotencyRecord
+	nextOrderID   int64
+	nextItemID    int64
+	nextChangeID  int64
+
+	uniqueClientReference bool
+	orderNumberFormat     string
+	orderNumberCounters   map[int]int64
+	startID               int64
+	now                   func() time.Time
+	clockInjected         bool
+}
+
+// idempotencyRecord is the order created for an idempotency key and when the key expires
+type idempotencyRecord struct {
+	orderID int64
+	expires time.Time
+}
+
+// Option configures an InMemoryOrderRepository
+type Option func(*InMemoryOrderRepository)
+
+// WithUniqueClientReference rejects creating an order whose client reference is already in use
+func WithUniqueClientReference(enabled bool) Option {
+	return func(r *InMemoryOrderRepository) {
+		r.uniqueClientReference = enabled
+	}
+}
+
+// WithClock replaces time.Now as the source of timestamps. Stored orders also take their
+// created_at from the clock, so tests get deterministic values.
+func WithClock(now func() time.Time) Option {
+	return func(r *InMemoryOrderRepository) {
+		r.now = now
+		r.clockInjected = true
+	}
+}
+
+// WithOrderNumbers assigns each new order a number rendered with format from a per-year
+// sequence (see entity.FormatOrderNumber); an empty format leaves numbers unassigned
+func WithOrderNumbers(format string) Option {
+	return func(r *InMemoryOrderRepository) {
+		r.orderNumberFormat = format
+	}
+}
+
+// WithStartID sets the ID assigned to the first stored order (default 1)
+func WithStartID(id int64) Option {
+	return func(r *InMemoryOrderRepository) {
+		r.startID = id
+	}
+}
+
+// NewInMemoryOrderRepository creates a new empty InMemoryOrderRepository
+func NewInMemoryOrderRepository(opts ...Option) *InMemoryOrderRepository {
+	r := &InMemoryOrderRepository{
+		startID: 1,
+		now:     time.Now,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	r.reset()
+	return r
+}
+
+// Reset removes every stored order and restarts the ID sequence, so a shared repository
+// gives each test the same IDs
+func (r *InMemoryOrderRepository) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.reset()
+}
+
+func (r *InMemoryOrderRepository) reset() {
+	r.orders = make(map[int64]*entity.Order)
+	r.statusHistory = make(map[int64][]entity.StatusChange)
+	r.idemKeys = make(map[string]idempotencyRecord)
+	r.orderNumberCounters = make(map[int]int64)
+	r.nextOrderID = r.startID - 1
+	r.nextItemID = 0
+	r.nextChangeID = 0
+}
+
+// CreateOrderWithItems stores a copy of the order and assigns IDs to it and its items
+func (r *InMemoryOrderRepository) CreateOrderWithItems(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if dryrun.Enabled(ctx) {
+		return r.dryRunCreate(order, order.Status)
+	}
+	return r.create(order)
+}
+
+// CreatePaidOrder stores a copy of the order with status paid and records the pending → paid transition
+func (r *InMemoryOrderRepository) CreatePaidOrder(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if dryrun.Enabled(ctx) {
+		return r.dryRunCreate(order, "paid")
+	}
+	return r.createPaid(order)
+}
+
+// CreateOrderIdempotent stores a copy of the order, or of a paid one, and records key against
+// it until ttl passes; while the key is live the recorded order is returned instead. Expired
+// keys are reaped on every keyed create.
+func (r *InMemoryOrderRepository) CreateOrderIdempotent(ctx context.Context, order *entity.Order, paid bool, key string, ttl time.Duration) (*entity.Order, bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now := r.now()
+	for k, record := range r.idemKeys {
+		if !now.Before(record.expires) {
+			delete(r.idemKeys, k)
+		}
+	}
+	if record, ok := r.idemKeys[key]; ok {
+		existing, ok := r.orders[record.orderID]
+		if !ok || existing.IsDeleted() {
+			return nil, false, apperrors.NewNotFoundError("order")
+		}
+		return copyOrder(existing), true, nil
+	}
+
+	status := order.Status
+	if paid {
+		status = "paid"
+	}
+	if dryrun.Enabled(ctx) {
+		created, err := r.dryRunCreate(order, status)
+		return created, false, err
+	}
+
+	create := r.create
+	if paid {
+		create = r.createPaid
+	}
+	created, err := create(order)
+	if err != nil {
+		return nil, false, err
+	}
+	r.idemKeys[key] = idempotencyRecord{orderID: created.ID, expires: now.Add(ttl)}
+	return created, false, nil
+}
+
+// createPaid stores a paid copy of the order with its pending → paid transition.
+// Callers must hold the write lock.
+func (r *InMemoryOrderRepository) createPaid(order *entity.Order) (*entity.Order, error) {
+	created, err := r.create(order)
+	if err != nil {
+		return nil, err
+	}
+
+	stored := r.orders[created.ID]
+	r.nextChangeID++
+	r.statusHistory[stored.ID] = append(r.statusHistory[stored.ID], entity.StatusChange{
+		ID:         r.nextChangeID,
+		OrderID:    stored.ID,
+		FromStatus: stored.Status,
+		ToStatus:   "paid",
+		ChangedAt:  stored.CreatedAt,
+	})
+	stored.Status = "paid"
+
+	return copyOrder(stored), nil
+}
+
+// create assigns IDs and stores a copy of the order. Callers must hold the write lock.
+func (r *InMemoryOrderRepository) create(order *entity.Order) (*entity.Order, error) {
+	if err := r.checkClientReference(order); err != nil {
+		return nil, err
+	}
+
+	r.nextOrderID++
+	stored := copyOrder(order)
+	stored.ID = r.nextOrderID
+	if r.clockInjected {
+		stored.CreatedAt = r.now()
+		stored.UpdatedAt = stored.CreatedAt
+	}
+	if r.orderNumberFormat != "" {
+		year := stored.CreatedAt.UTC().Year()
+		r.orderNumberCounters[year]++
+		stored.OrderNumber = entity.FormatOrderNumber(r.orderNumberFormat, year, r.orderNumberCounters[year])
+	}
+	for i := range stored.Items {
+		r.nextItemID++
+		stored.Items[i].ID = r.nextItemID
+		stored.Items[i].OrderID = stored.ID
+	}
+	r.orders[stored.ID] = stored
+
+	return copyOrder(stored), nil
+}
+
+// dryRunCreate runs the checks of create and returns the order as it would have been stored
+// with the given status, without assigning IDs or storing anything
+func (r *InMemoryOrderRepository) dryRunCreate(order *entity.Order, status string) (*entity.Order, error) {
+	if err := r.checkClientReference(order); err != nil {
+		return nil, err
+	}
+
+	result := copyOrder(order)
+	result.Status = status
+	if r.clockInjected {
+		result.CreatedAt = r.now()
+		result.UpdatedAt = result.CreatedAt
+	}
+	return result, nil
+}
+
+// checkClientReference rejects an order whose client reference is already in use when
+// references must be unique. Callers must hold at least a read lock.
+func (r *InMemoryOrderRepository) checkClientReference(order *entity.Order) error {
+	if order.ClientReference == "" || !r.uniqueClientReference {
+		return nil
+	}
+	if existing := r.findByClientReference(order.ClientReference); existing != nil {
+		return apperrors.NewAlreadyExistsError("an order with this client reference already exists").WithDetails(map[string]interface{}{
+			"client_reference":  order.ClientReference,
+			"existing_order_id": existing.ID,
+		})
+	}
+	return nil
+}
+
+// GetOrderByID retrieves a copy of the stored order
+func (r *InMemoryOrderRepository) GetOrderByID(ctx context.Context, id int64) (*entity.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.IsDeleted() {
+		return nil, apperrors.NewNotFoundError("order")
+	}
+	return copyOrder(order), nil
+}
+
+// GetOrderByClientReference retrieves a copy of the most recent order carrying the reference
+func (r *InMemoryOrderRepository) GetOrderByClientReference(ctx context.Context, reference string) (*entity.Order, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	order := r.findByClientReference(reference)
+	if order == nil || order.IsDeleted() {
+		return nil, apperrors.NewNotFoundError("order")
+	}
+	return copyOrder(order), nil
+}
+
+// GetStatuses retrieves the statuses of the stored, non-deleted orders among ids
+func (r *InMemoryOrderRepository) GetStatuses(ctx context.Context, ids []int64) (map[int64]string, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	statuses := make(map[int64]string, len(ids))
+	for _, id := range ids {
+		if order, ok := r.orders[id]; ok && !order.IsDeleted() {
+			statuses[id] = order.Status
+		}
+	}
+	return statuses, nil
+}
+
+// ListOrders retrieves orders matching the filter ordered by creation time (newest first) with pagination
+func (r *InMemoryOrderRepository) ListOrders(ctx context.Context, page int, limit int, filter repository.OrderFilter) ([]*entity.Order, *repository.PaginationInfo, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if page < 1 {
+		page = 1
+	}
+
+	all := r.sortedOrders(filter)
+
+	totalCount := int64(len(all))
+	totalPages := int((totalCount + int64(limit) - 1) / int64(limit))
+	if totalPages == 0 {
+		totalPages = 1
+	}
+
+	offset, err := repository.PageOffset(page, limit)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var orders []*entity.Order
+	for i := offset; i < len(all) && i-offset < limit; i++ {
+		orders = append(orders, all[i])
+	}
+
+	return orders, &repository.PaginationInfo{
+		CurrentPage:  page,
+		TotalPages:   totalPages,
+		TotalCount:   totalCount,
+		ItemsPerPage: limit,
+	}, nil
+}
+
+// CountOrders counts the orders matching the filter without copying them
+func (r *InMemoryOrderRepository) CountOrders(ctx context.Context, filter repository.OrderFilter) (int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, order := range r.orders {
+		if matchesFilter(order, filter) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// SummarizeOrders counts the orders and sums their totals per status, skipping deleted ones
+func (r *InMemoryOrderRepository) SummarizeOrders(ctx context.Context) (map[string]repository.StatusSummary, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	summaries := make(map[string]repository.StatusSummary)
+	for _, order := range r.orders {
+		if order.IsDeleted() {
+			continue
+		}
+		summary := summaries[order.Status]
+		summary.Count++
+		summary.Revenue += order.TotalAmount
+		summaries[order.Status] = summary
+	}
+	return summaries, nil
+}
+
+// ListOrdersAfter retrieves up to limit matching orders with an ID below cursor, highest ID first
+func (r *InMemoryOrderRepository) ListOrdersAfter(ctx context.Context, cursor int64, limit int, filter repository.OrderFilter) ([]*entity.Order, int64, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	all := r.sortedOrders(filter)
+	sort.Slice(all, func(i, j int) bool { return all[i].ID > all[j].ID })
+
+	var orders []*entity.Order
+	for _, order := range all {
+		if cursor > 0 && order.ID >= cursor {
+			continue
+		}
+		if len(orders) == limit {
+			return orders, orders[limit-1].ID, nil
+		}
+		orders = append(orders, order)
+	}
+	return orders, 0, nil
+}
+
+// StreamOrders emits copies of the matching orders, newest first, until done or ctx is cancelled
+func (r *InMemoryOrderRepository) StreamOrders(ctx context.Context, filter repository.OrderFilter) (<-chan *entity.Order, <-chan error) {
+	orders := make(chan *entity.Order)
+	errs := make(chan error, 1)
+
+	r.mu.RLock()
+	matching := r.sortedOrders(filter)
+	r.mu.RUnlock()
+
+	go func() {
+		defer close(errs)
+		defer close(orders)
+
+		for _, order := range matching {
+			select {
+			case orders <- order:
+			case <-ctx.Done():
+				errs <- ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return orders, errs
+}
+
+// UpdateOrderStatus updates the status of a stored order and records the transition.
+// Requesting the current status is a no-op and reports false.
+func (r *InMemoryOrderRepository) UpdateOrderStatus(ctx context.Context, id int64, status string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.IsDeleted() {
+		return false, apperrors.NewNotFoundError("order")
+	}
+	if order.Status == status {
+		return false, nil
+	}
+	if err := entity.ValidateStatusTransition(order.Status, status); err != nil {
+		return false, err
+	}
+	if dryrun.Enabled(ctx) {
+		return true, nil
+	}
+
+	now := r.now()
+	r.nextChangeID++
+	r.statusHistory[id] = append(r.statusHistory[id], entity.StatusChange{
+		ID:         r.nextChangeID,
+		OrderID:    id,
+		FromStatus: order.Status,
+		ToStatus:   status,
+		ChangedAt:  now,
+	})
+
+	order.Status = status
+	order.UpdatedAt = now
+	return true, nil
+}
+
+// UpdateOrderItems replaces the items of a stored pending order and recomputes its total
+func (r *InMemoryOrderRepository) UpdateOrderItems(ctx context.Context, orderID int64, items []entity.OrderItem) (*entity.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[orderID]
+	if !ok || order.IsDeleted() {
+		return nil, apperrors.NewNotFoundError("order")
+	}
+	if err := entity.ValidateItemsEditable(order.Status); err != nil {
+		return nil, err
+	}
+	if dryrun.Enabled(ctx) {
+		// Work on a copy so the stored order is left untouched
+		order = copyOrder(order)
+	}
+
+	order.Items = make([]entity.OrderItem, len(items))
+	order.TotalAmount = 0
+	for i, item := range items {
+		if !dryrun.Enabled(ctx) {
+			r.nextItemID++
+			item.ID = r.nextItemID
+		}
+		item.OrderID = orderID
+		order.Items[i] = item
+		order.TotalAmount += item.TotalPrice
+	}
+	order.UpdatedAt = r.now()
+	return copyOrder(order), nil
+}
+
+// SoftDeleteOrder marks a stored order as deleted
+func (r *InMemoryOrderRepository) SoftDeleteOrder(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok || order.IsDeleted() {
+		return apperrors.NewNotFoundError("order")
+	}
+	if order.Status == "completed" {
+		return domainerrors.NewCompletedOrderDeletionError(id)
+	}
+	if dryrun.Enabled(ctx) {
+		return nil
+	}
+
+	now := r.now()
+	order.DeletedAt = &now
+	order.UpdatedAt = now
+	return nil
+}
+
+// DeleteOrder permanently removes a stored order and its history
+func (r *InMemoryOrderRepository) DeleteOrder(ctx context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	order, ok := r.orders[id]
+	if !ok {
+		return apperrors.NewNotFoundError("order")
+	}
+	if order.Status == "completed" {
+		return domainerrors.NewCompletedOrderDeletionError(id)
+	}
+	if dryrun.Enabled(ctx) {
+		return nil
+	}
+	r.deleteStored(id)
+	return nil
+}
+
+// PurgeDeletedOrders removes orders soft-deleted before the cutoff and their status history
+func (r *InMemoryOrderRepository) PurgeDeletedOrders(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var purged int64
+	for id, order := range r.orders {
+		if order.IsDeleted() && order.DeletedAt.Before(deletedBefore) {
+			if !dryrun.Enabled(ctx) {
+				r.deleteStored(id)
+			}
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+// GetStatusHistory retrieves the recorded status transitions of an order, oldest first
+func (r *InMemoryOrderRepository) GetStatusHistory(ctx context.Context, orderID int64) ([]entity.StatusChange, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	history := make([]entity.StatusChange, len(r.statusHistory[orderID]))
+	copy(history, r.statusHistory[orderID])
+	return history, nil
+}
+
+// deleteStored removes an order with its history and idempotency keys, as the database
+// cascades do. Callers must hold the write lock.
+func (r *InMemoryOrderRepository) deleteStored(id int64) {
+	delete(r.orders, id)
+	delete(r.statusHistory, id)
+	for key, record := range r.idemKeys {
+		if record.orderID == id {
+			delete(r.idemKeys, key)
+		}
+	}
+}
+
+// findByClientReference returns the stored order with the highest ID carrying the reference.
+// Callers must hold at least a read lock.
+func (r *InMemoryOrderRepository) findByClientReference(reference string) *entity.Order {
+	var found *entity.Order
+	for _, order := range r.orders {
+		if order.ClientReference == reference && (found == nil || order.ID > found.ID) {
+			found = order
+		}
+	}
+	return found
+}
+
+// matchesFilter reports whether order is in the scope of filter (its sort is ignored)
+func matchesFilter(order *entity.Order, filter repository.OrderFilter) bool {
+	if !filter.IncludeDeleted && order.IsDeleted() {
+		return false
+	}
+	if filter.Status != "" && order.Status != filter.Status {
+		return false
+	}
+	if filter.Customer != "" && !strings.Contains(strings.ToLower(order.CustomerName), strings.ToLower(filter.Customer)) {
+		return false
+	}
+	if filter.CreatedFrom != nil && order.CreatedAt.Before(*filter.CreatedFrom) {
+		return false
+	}
+	if filter.CreatedTo != nil && order.CreatedAt.After(*filter.CreatedTo) {
+		return false
+	}
+	return true
+}
+
+// sortedOrders returns copies of the orders matching the filter in filter.Sort order.
+// Callers must hold at least a read lock.
+func (r *InMemoryOrderRepository) sortedOrders(filter repository.OrderFilter) []*entity.Order {
+	matching := make([]*entity.Order, 0, len(r.orders))
+	for _, order := range r.orders {
+		if matchesFilter(order, filter) {
+			matching = append(matching, copyOrder(order))
+		}
+	}
+	sort.Slice(matching, func(i, j int) bool {
+		// Compare as "a comes before b" in descending order, then flip for ascending
+		a, b := matching[i], matching[j]
+		if filter.Sort.Ascending {
+			a, b = b, a
+		}
+		switch filter.Sort.Field {
+		case repository.SortByID:
+		case repository.SortByTotalAmount:
+			if a.TotalAmount != b.TotalAmount {
+				return a.TotalAmount > b.TotalAmount
+			}
+		default:
+			if !a.CreatedAt.Equal(b.CreatedAt) {
+				return a.CreatedAt.After(b.CreatedAt)
+			}
+		}
+		return a.ID > b.ID
+	})
+	return matching
+}
+
+// copyOrder returns a deep copy so callers cannot mutate stored state
+func copyOrder(order *entity.Order) *entity.Order {
+	copied := *order
+	copied.Items = make([]entity.OrderItem, len(order.Items))
+	copy(copied.Items, order.Items)
+	if order.DeletedAt != nil {
+		deletedAt := *order.DeletedAt
+		copied.DeletedAt = &deletedAt
+	}
+	if order.EstimatedShipDate != nil {
+		shipDate := *order.EstimatedShipDate
+		copied.EstimatedShipDate = &shipDate
+	}
+	return &copied
+}
@@ -0,0 +1,171 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+)
+
+func newTestOrder(t *testing.T) *entity.Order {
+	t.Helper()
+	order, err := entity.NewOrder("Jane Doe", []entity.OrderItem{
+		{ProductName: "Keyboard", Quantity: 1, UnitPrice: entity.NewMoney(49.99)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating order: %v", err)
+	}
+	return order
+}
+
+func TestInMemoryOrderRepository_DeterministicSequence(t *testing.T) {
+	ctx := context.Background()
+	base := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	ticks := 0
+	clock := func() time.Time {
+		ticks++
+		return base.Add(time.Duration(ticks) * time.Second)
+	}
+	repo := NewInMemoryOrderRepository(WithClock(clock))
+
+	var previous time.Time
+	for want := int64(1); want <= 3; want++ {
+		created, err := repo.CreateOrderWithItems(ctx, newTestOrder(t))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created.ID != want {
+			t.Errorf("expected ID %d, got %d", want, created.ID)
+		}
+		if created.CreatedAt.Before(base) || !created.CreatedAt.After(previous) {
+			t.Errorf("expected created_at from the clock after %v, got %v", previous, created.CreatedAt)
+		}
+		previous = created.CreatedAt
+	}
+
+	repo.Reset()
+	created, err := repo.CreateOrderWithItems(ctx, newTestOrder(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID != 1 {
+		t.Errorf("expected the sequence to restart at 1 after Reset, got %d", created.ID)
+	}
+	if _, err := repo.GetOrderByID(ctx, 2); err == nil {
+		t.Error("expected orders from before Reset to be gone")
+	}
+}
+
+func TestInMemoryOrderRepository_StartID(t *testing.T) {
+	repo := NewInMemoryOrderRepository(WithStartID(1000))
+	created, err := repo.CreateOrderWithItems(context.Background(), newTestOrder(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.ID != 1000 {
+		t.Errorf("expected first ID 1000, got %d", created.ID)
+	}
+}
+
+func TestInMemoryOrderRepository_OrderNumbers(t *testing.T) {
+	ctx := context.Background()
+	clock := func() time.Time { return time.Date(2024, 12, 31, 23, 0, 0, 0, time.UTC) }
+	repo := NewInMemoryOrderRepository(WithClock(clock), WithOrderNumbers(entity.DefaultOrderNumberFormat))
+
+	for _, want := range []string{"ORD-2024-000001", "ORD-2024-000002", "ORD-2024-000003"} {
+		created, err := repo.CreateOrderWithItems(ctx, newTestOrder(t))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if created.OrderNumber != want {
+			t.Errorf("expected order number %s, got %s", want, created.OrderNumber)
+		}
+	}
+
+	// Each year has its own sequence
+	repo.now = func() time.Time { return time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC) }
+	created, err := repo.CreateOrderWithItems(ctx, newTestOrder(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.OrderNumber != "ORD-2025-000001" {
+		t.Errorf("expected the sequence to restart for 2025, got %s", created.OrderNumber)
+	}
+}
+
+func TestInMemoryOrderRepository_OrderNumbersUniqueUnderConcurrency(t *testing.T) {
+	repo := NewInMemoryOrderRepository(WithOrderNumbers(entity.DefaultOrderNumberFormat))
+
+	const creates = 50
+	numbers := make(chan string, creates)
+	var wg sync.WaitGroup
+	for i := 0; i < creates; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			created, err := repo.CreateOrderWithItems(context.Background(), newTestOrder(t))
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			numbers <- created.OrderNumber
+		}()
+	}
+	wg.Wait()
+	close(numbers)
+
+	seen := make(map[string]bool)
+	for number := range numbers {
+		if seen[number] {
+			t.Errorf("order number %s assigned twice", number)
+		}
+		seen[number] = true
+	}
+	if len(seen) != creates {
+		t.Errorf("expected %d distinct order numbers, got %d", creates, len(seen))
+	}
+}
+
+func TestInMemoryOrderRepository_CreatePaidOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := NewInMemoryOrderRepository()
+
+	created, err := repo.CreatePaidOrder(ctx, newTestOrder(t))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if created.Status != "paid" {
+		t.Errorf("expected status paid, got %s", created.Status)
+	}
+
+	history, err := repo.GetStatusHistory(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(history) != 1 || history[0].FromStatus != "pending" || history[0].ToStatus != "paid" {
+		t.Errorf("expected a single pending -> paid history row, got %+v", history)
+	}
+}
+
+func TestInMemoryOrderRepository_CreateOrderIdempotentReapsExpiredKeys(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := NewInMemoryOrderRepository(WithClock(func() time.Time { return now }))
+
+	if _, _, err := repo.CreateOrderIdempotent(ctx, newTestOrder(t), false, "checkout-9c1e", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The next keyed create sweeps every expired key, not just its own
+	now = now.Add(2 * time.Hour)
+	if _, _, err := repo.CreateOrderIdempotent(ctx, newTestOrder(t), false, "checkout-4b20", time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	repo.mu.RLock()
+	defer repo.mu.RUnlock()
+	if _, ok := repo.idemKeys["checkout-4b20"]; !ok || len(repo.idemKeys) != 1 {
+		t.Errorf("expected only the fresh key to remain, got %d keys", len(repo.idemKeys))
+	}
+}
@@ -0,0 +1,164 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"online-order-management-system/pkg/events"
+	"online-order-management-system/pkg/retryutil"
+)
+
+// SignatureHeader carries "sha256=" followed by the hex HMAC-SHA256 of the request body,
+// keyed with the shared secret, so merchants can verify notifications came from us
+const SignatureHeader = "X-Webhook-Signature"
+
+// StatusNotification is the JSON body posted for a status change
+type StatusNotification struct {
+	OrderID   int64     `json:"order_id"`
+	Status    string    `json:"status"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// httpStatusError is a non-2xx webhook response
+type httpStatusError struct {
+	code int
+}
+
+func (e httpStatusError) Error() string {
+	return fmt.Sprintf("webhook responded with status %d", e.code)
+}
+
+// isTransient reports whether a failed delivery is worth retrying: transport errors,
+// 429 and 5xx responses. Other 4xx responses will not change on retry.
+func isTransient(err error) bool {
+	var statusErr httpStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.code == http.StatusTooManyRequests || statusErr.code >= 500
+	}
+	return true
+}
+
+// WebhookNotifier posts a signed StatusNotification to a URL when an order reaches one of
+// the watched statuses. It implements events.EventPublisher and ignores other events.
+// Publish waits for the delivery and its retries, so callers on a request path should run
+// it as an events.ChannelPublisher handler instead of publishing to it directly.
+type WebhookNotifier struct {
+	url      string
+	secret   []byte
+	statuses map[string]bool
+	client   *http.Client
+	retry    retryutil.RetryConfig
+}
+
+// Option configures optional behavior of WebhookNotifier
+type Option func(*WebhookNotifier)
+
+// WithStatuses replaces the watched statuses (default: completed); blanks are ignored
+func WithStatuses(statuses ...string) Option {
+	return func(n *WebhookNotifier) {
+		n.statuses = make(map[string]bool, len(statuses))
+		for _, status := range statuses {
+			if status = strings.TrimSpace(status); status != "" {
+				n.statuses[status] = true
+			}
+		}
+	}
+}
+
+// WithTimeout bounds each delivery attempt (default 5s)
+func WithTimeout(timeout time.Duration) Option {
+	return func(n *WebhookNotifier) {
+		n.client.Timeout = timeout
+	}
+}
+
+// WithRetry replaces the retry policy of transient failures (default: 3 attempts in total)
+func WithRetry(config retryutil.RetryConfig) Option {
+	return func(n *WebhookNotifier) {
+		n.retry = config
+		n.retry.RetryCondition = isTransient
+	}
+}
+
+// NewWebhookNotifier creates a notifier posting to url, signing bodies with secret
+func NewWebhookNotifier(url, secret string, opts ...Option) *WebhookNotifier {
+	retry := retryutil.DefaultRetryConfig()
+	retry.BaseDelay = 100 * time.Millisecond
+	retry.MaxDelay = time.Second
+	retry.RetryCondition = isTransient
+
+	n := &WebhookNotifier{
+		url:      url,
+		secret:   []byte(secret),
+		statuses: map[string]bool{"completed": true},
+		client:   &http.Client{Timeout: 5 * time.Second},
+		retry:    retry,
+	}
+	for _, opt := range opts {
+		opt(n)
+	}
+	return n
+}
+
+// Publish notifies the webhook of status changes into a watched status
+func (n *WebhookNotifier) Publish(ctx context.Context, event events.Event) error {
+	if event.Type != events.TypeOrderStatusChanged {
+		return nil
+	}
+	change, ok := event.Data.(events.OrderStatusChanged)
+	if !ok || !n.statuses[change.NewStatus] {
+		return nil
+	}
+
+	body, err := json.Marshal(StatusNotification{
+		OrderID:   change.OrderID,
+		Status:    change.NewStatus,
+		Timestamp: event.Time.UTC(),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode status notification: %w", err)
+	}
+
+	return retryutil.RetryWithBackoff(ctx, n.retry, func() error {
+		return n.post(ctx, body)
+	})
+}
+
+// post makes one delivery attempt
+func (n *WebhookNotifier) post(ctx context.Context, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(SignatureHeader, Sign(n.secret, body))
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post status notification: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // Drain so the connection can be reused
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return httpStatusError{code: resp.StatusCode}
+	}
+	return nil
+}
+
+// Sign returns the SignatureHeader value of body for secret
+func Sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,130 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"online-order-management-system/pkg/events"
+	"online-order-management-system/pkg/retryutil"
+)
+
+var fastRetry = retryutil.RetryConfig{MaxRetries: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond}
+
+func statusChanged(orderID int64, from, to string) events.Event {
+	return events.NewEvent(events.TypeOrderStatusChanged, events.OrderStatusChanged{OrderID: orderID, OldStatus: from, NewStatus: to})
+}
+
+func TestWebhookNotifier_PostsSignedPayload(t *testing.T) {
+	var body []byte
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		signature = r.Header.Get(SignatureHeader)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	event := statusChanged(42, "processing", "completed")
+	if err := NewWebhookNotifier(server.URL, "s3cret").Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var payload StatusNotification
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("failed to decode payload %q: %v", body, err)
+	}
+	if payload.OrderID != 42 || payload.Status != "completed" || !payload.Timestamp.Equal(event.Time) {
+		t.Errorf("unexpected payload %+v", payload)
+	}
+	if signature != Sign([]byte("s3cret"), body) {
+		t.Errorf("expected the body's HMAC-SHA256 signature, got %q", signature)
+	}
+	if signature == Sign([]byte("other"), body) {
+		t.Error("expected the signature to depend on the secret")
+	}
+}
+
+func TestWebhookNotifier_IgnoresUnwatchedStatusesAndEvents(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "s3cret")
+	ctx := context.Background()
+	_ = notifier.Publish(ctx, statusChanged(1, "pending", "paid"))
+	_ = notifier.Publish(ctx, events.NewEvent(events.TypeOrderCreated, events.OrderCreated{OrderID: 1}))
+	if got := atomic.LoadInt32(&calls); got != 0 {
+		t.Errorf("expected no notification, got %d", got)
+	}
+}
+
+func TestWebhookNotifier_RetriesTransientFailures(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "s3cret", WithRetry(fastRetry))
+	if err := notifier.Publish(context.Background(), statusChanged(7, "processing", "completed")); err != nil {
+		t.Fatalf("expected the third attempt to succeed, got %v", err)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+}
+
+func TestWebhookNotifier_DoesNotRetryClientErrors(t *testing.T) {
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL, "s3cret", WithRetry(fastRetry))
+	if err := notifier.Publish(context.Background(), statusChanged(7, "processing", "completed")); err == nil {
+		t.Fatal("expected a 400 to fail the notification")
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("expected a single attempt, got %d", got)
+	}
+}
+
+func TestWebhookNotifier_DeliversInBackgroundFromChannelPublisher(t *testing.T) {
+	release := make(chan struct{})
+	delivered := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		close(delivered)
+	}))
+	defer server.Close()
+
+	publisher := events.NewChannelPublisher(10)
+	publisher.Subscribe(events.TypeOrderStatusChanged, NewWebhookNotifier(server.URL, "s3cret").Publish)
+
+	// The request that published the event is already over when the delivery runs
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := publisher.Publish(ctx, statusChanged(42, "processing", "completed")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cancel()
+
+	close(release)
+	select {
+	case <-delivered:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expected the notification to be delivered after the publishing request ended")
+	}
+	publisher.Close()
+}
@@ -0,0 +1,88 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// APIVersionHeader lets clients pin the response behavior of a dated API version
+const APIVersionHeader = "Api-Version"
+
+// contextKeyAPIVersion stores the resolved APIVersion in the gin context
+const contextKeyAPIVersion = "api_version"
+
+// APIVersion describes the behavior differences of a dated API version
+type APIVersion struct {
+	Name string
+	// BusinessRuleStatus is the HTTP status returned for business-rule violations
+	BusinessRuleStatus int
+}
+
+// apiVersions is the version registry, keyed by version name.
+// Add a new entry (and bump LatestAPIVersion) whenever a response shape or status code changes.
+var apiVersions = map[string]APIVersion{
+	"2024-01": {Name: "2024-01", BusinessRuleStatus: http.StatusBadRequest},
+	"2024-06": {Name: "2024-06", BusinessRuleStatus: http.StatusUnprocessableEntity},
+}
+
+// LatestAPIVersion is used when the client does not send an Api-Version header
+const LatestAPIVersion = "2024-06"
+
+// SupportedAPIVersions returns the registered version names, oldest first
+func SupportedAPIVersions() []string {
+	names := make([]string, 0, len(apiVersions))
+	for name := range apiVersions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// APIVersionMiddleware resolves the Api-Version header against the registry, rejecting
+// unknown versions, and echoes the version that served the request
+func APIVersionMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		name := strings.TrimSpace(c.GetHeader(APIVersionHeader))
+		if name == "" {
+			name = LatestAPIVersion
+		}
+
+		version, ok := apiVersions[name]
+		if !ok {
+			appErr := apperrors.NewBadRequestError("Unsupported API version").WithDetails(map[string]interface{}{
+				"requested_version":  name,
+				"supported_versions": SupportedAPIVersions(),
+			})
+			traceID := c.GetString("trace_id")
+			c.AbortWithStatusJSON(appErr.HTTPStatus, apperrors.ToErrorResponse(appErr, traceID))
+			return
+		}
+
+		c.Set(contextKeyAPIVersion, version)
+		c.Header(APIVersionHeader, version.Name)
+		c.Next()
+	}
+}
+
+// APIVersionFromContext returns the version resolved for the request, or the latest one
+func APIVersionFromContext(c *gin.Context) APIVersion {
+	if value, exists := c.Get(contextKeyAPIVersion); exists {
+		if version, ok := value.(APIVersion); ok {
+			return version
+		}
+	}
+	return apiVersions[LatestAPIVersion]
+}
+
+// StatusForError returns the HTTP status of err as seen by the request's API version
+func StatusForError(c *gin.Context, err error) int {
+	if appErr := apperrors.GetAppError(err); appErr != nil && appErr.Code == apperrors.ErrCodeBusinessRuleViolation {
+		return APIVersionFromContext(c).BusinessRuleStatus
+	}
+	return apperrors.GetHTTPStatus(err)
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestAPIVersionMiddleware_SelectsBusinessRuleStatus(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(APIVersionMiddleware())
+	router.GET("/orders", func(c *gin.Context) {
+		c.Status(StatusForError(c, apperrors.NewBusinessRuleViolationError("rule not satisfied")))
+	})
+
+	tests := []struct {
+		version string
+		status  int
+		echoed  string
+	}{
+		{version: "", status: http.StatusUnprocessableEntity, echoed: LatestAPIVersion},
+		{version: "2024-06", status: http.StatusUnprocessableEntity, echoed: "2024-06"},
+		{version: "2024-01", status: http.StatusBadRequest, echoed: "2024-01"},
+		{version: "1999-01", status: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		if tt.version != "" {
+			req.Header.Set(APIVersionHeader, tt.version)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != tt.status {
+			t.Errorf("version %q: expected %d, got %d", tt.version, tt.status, w.Code)
+		}
+		if got := w.Header().Get(APIVersionHeader); got != tt.echoed {
+			t.Errorf("version %q: expected %s header %q, got %q", tt.version, APIVersionHeader, tt.echoed, got)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ContextKeyUserID holds the authenticated subject in the gin context
+const ContextKeyUserID = "user_id"
+
+// Reasons a bearer token is rejected
+var (
+	ErrMissingToken = errors.New("missing bearer token")
+	ErrInvalidToken = errors.New("invalid token")
+	ErrTokenExpired = errors.New("token has expired")
+)
+
+// jwtClaims are the registered claims the API reads; exp and nbf are seconds since the epoch
+type jwtClaims struct {
+	Subject   string   `json:"sub"`
+	ExpiresAt *float64 `json:"exp"`
+	NotBefore *float64 `json:"nbf"`
+}
+
+// AuthMiddleware requires an HS256-signed JWT in the "Authorization: Bearer <token>" header.
+// Missing, malformed, wrongly signed or expired tokens are rejected with 401; otherwise the
+// token's subject is stored under ContextKeyUserID and added to the request's log context.
+func AuthMiddleware(secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, err := authenticate(c.GetHeader("Authorization"), secret, time.Now())
+		if err != nil {
+			message := "A valid bearer token is required"
+			if errors.Is(err, ErrTokenExpired) {
+				message = "The bearer token has expired"
+			}
+			appErr := apperrors.NewAuthenticationError(message).WithCause(err)
+			c.Header("WWW-Authenticate", `Bearer realm="api"`)
+			c.AbortWithStatusJSON(http.StatusUnauthorized, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+			return
+		}
+
+		c.Set(ContextKeyUserID, claims.Subject)
+		c.Request = c.Request.WithContext(logger.ContextWithUserID(c.Request.Context(), claims.Subject))
+		c.Next()
+	}
+}
+
+// UserID returns the authenticated subject of the request, or "" when there is none
+func UserID(c *gin.Context) string {
+	return c.GetString(ContextKeyUserID)
+}
+
+// authenticate extracts the bearer token from an Authorization header and verifies it
+func authenticate(header string, secret []byte, now time.Time) (*jwtClaims, error) {
+	scheme, token, found := strings.Cut(strings.TrimSpace(header), " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return nil, ErrMissingToken
+	}
+	return verifyHS256(strings.TrimSpace(token), secret, now)
+}
+
+// verifyHS256 checks the signature and time claims of a compact JWT signed with HS256.
+// Only HS256 is accepted, so a token cannot downgrade itself to "none" or another algorithm.
+func verifyHS256(token string, secret []byte, now time.Time) (*jwtClaims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, ErrInvalidToken
+	}
+
+	var header struct {
+		Alg string `json:"alg"`
+	}
+	if err := decodeSegment(parts[0], &header); err != nil || header.Alg != "HS256" {
+		return nil, ErrInvalidToken
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(parts[0] + "." + parts[1]))
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return nil, ErrInvalidToken
+	}
+
+	var claims jwtClaims
+	if err := decodeSegment(parts[1], &claims); err != nil || claims.Subject == "" {
+		return nil, ErrInvalidToken
+	}
+	unixNow := float64(now.Unix())
+	if claims.ExpiresAt != nil && unixNow >= *claims.ExpiresAt {
+		return nil, ErrTokenExpired
+	}
+	if claims.NotBefore != nil && unixNow < *claims.NotBefore {
+		return nil, ErrInvalidToken
+	}
+	return &claims, nil
+}
+
+// decodeSegment decodes one base64url JSON segment of a JWT into v
+func decodeSegment(segment string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
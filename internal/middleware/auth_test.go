@@ -0,0 +1,120 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+var testJWTSecret = []byte("test-secret")
+
+// signToken builds a compact JWT with the given header algorithm and claims
+func signToken(t *testing.T, alg string, claims map[string]interface{}, secret []byte) string {
+	t.Helper()
+	encode := func(v interface{}) string {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("failed to encode token segment: %v", err)
+		}
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	signingInput := encode(map[string]string{"alg": alg, "typ": "JWT"}) + "." + encode(claims)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func newAuthRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(AuthMiddleware(testJWTSecret))
+	router.GET("/orders", func(c *gin.Context) {
+		c.String(http.StatusOK, UserID(c))
+	})
+	return router
+}
+
+func doAuthRequest(router *gin.Engine, authorization string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	if authorization != "" {
+		req.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestAuthMiddleware_ValidToken(t *testing.T) {
+	token := signToken(t, "HS256", map[string]interface{}{
+		"sub": "user-42",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	}, testJWTSecret)
+
+	w := doAuthRequest(newAuthRouter(), "Bearer "+token)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected 200, got %d: %s", w.Code, w.Body.String())
+	}
+	if w.Body.String() != "user-42" {
+		t.Errorf("expected the subject to be stored as user_id, got %q", w.Body.String())
+	}
+}
+
+func TestAuthMiddleware_RejectsBadTokens(t *testing.T) {
+	valid := map[string]interface{}{"sub": "user-42", "exp": time.Now().Add(time.Hour).Unix()}
+
+	tests := []struct {
+		name          string
+		authorization string
+		wantMessage   string
+	}{
+		{
+			name:          "expired token",
+			authorization: "Bearer " + signToken(t, "HS256", map[string]interface{}{"sub": "user-42", "exp": time.Now().Add(-time.Minute).Unix()}, testJWTSecret),
+			wantMessage:   "expired",
+		},
+		{
+			name:          "wrong signature",
+			authorization: "Bearer " + signToken(t, "HS256", valid, []byte("another-secret")),
+		},
+		{
+			name:          "unsigned algorithm",
+			authorization: "Bearer " + signToken(t, "none", valid, testJWTSecret),
+		},
+		{
+			name:          "missing subject",
+			authorization: "Bearer " + signToken(t, "HS256", map[string]interface{}{"exp": time.Now().Add(time.Hour).Unix()}, testJWTSecret),
+		},
+		{name: "missing header"},
+		{name: "wrong scheme", authorization: "Basic dXNlcjpwYXNz"},
+		{name: "bearer without token", authorization: "Bearer "},
+		{name: "not a JWT", authorization: "Bearer not-a-token"},
+		{name: "garbage segments", authorization: "Bearer a.b.c"},
+	}
+
+	router := newAuthRouter()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := doAuthRequest(router, tt.authorization)
+			if w.Code != http.StatusUnauthorized {
+				t.Fatalf("expected 401, got %d: %s", w.Code, w.Body.String())
+			}
+			if !strings.Contains(w.Body.String(), "AUTHENTICATION") {
+				t.Errorf("expected an AUTHENTICATION error, got %s", w.Body.String())
+			}
+			if tt.wantMessage != "" && !strings.Contains(w.Body.String(), tt.wantMessage) {
+				t.Errorf("expected the message to mention %q, got %s", tt.wantMessage, w.Body.String())
+			}
+			if w.Header().Get("WWW-Authenticate") == "" {
+				t.Error("expected a WWW-Authenticate challenge")
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newBodyLimitRouter(maxBytes int64, routeLimits map[string]int64) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimitMiddleware(maxBytes, routeLimits))
+	bind := func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				appErr := BodyTooLargeError(tooLarge.Limit)
+				c.JSON(appErr.HTTPStatus, appErr)
+				return
+			}
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusCreated)
+	}
+	router.POST("/orders", bind)
+	router.POST("/orders/import", bind)
+	return router
+}
+
+func postBody(router *gin.Engine, path string, body string, chunked bool) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	if chunked {
+		// Without a declared length the limit can only be enforced while reading
+		req.ContentLength = -1
+		req.Body = io.NopCloser(strings.NewReader(body))
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestBodyLimitMiddleware(t *testing.T) {
+	router := newBodyLimitRouter(64, map[string]int64{"/orders/import": 256})
+	oversized := `{"customer_name":"` + strings.Repeat("x", 100) + `"}`
+
+	if w := postBody(router, "/orders", `{"customer_name":"Acme"}`, false); w.Code != http.StatusCreated {
+		t.Errorf("expected a small body to pass, got %d", w.Code)
+	}
+
+	for _, chunked := range []bool{false, true} {
+		w := postBody(router, "/orders", oversized, chunked)
+		if w.Code != http.StatusRequestEntityTooLarge {
+			t.Errorf("chunked=%v: expected 413, got %d: %s", chunked, w.Code, w.Body.String())
+		}
+		if !strings.Contains(w.Body.String(), "BAD_REQUEST") || !strings.Contains(w.Body.String(), `"max_bytes":64`) {
+			t.Errorf("chunked=%v: expected a BAD_REQUEST error naming the limit, got %s", chunked, w.Body.String())
+		}
+	}
+
+	if w := postBody(router, "/orders/import", oversized, false); w.Code != http.StatusCreated {
+		t.Errorf("expected a route limit to raise the default, got %d", w.Code)
+	}
+	if w := postBody(router, "/orders/import", `{"customer_name":"`+strings.Repeat("x", 300)+`"}`, true); w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected a route limit to still cap bodies, got %d", w.Code)
+	}
+}
+
+func TestBodyLimitMiddleware_Disabled(t *testing.T) {
+	router := newBodyLimitRouter(0, nil)
+	body := `{"customer_name":"` + strings.Repeat("x", 10000) + `"}`
+	if w := postBody(router, "/orders", body, false); w.Code != http.StatusCreated {
+		t.Errorf("expected no limit when disabled, got %d", w.Code)
+	}
+}
@@ -0,0 +1,136 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newCORSRouter(config CORSConfig) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(CORSMiddleware(config))
+	router.GET("/orders", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func corsRequest(router *gin.Engine, method, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/orders", nil)
+	if origin != "" {
+		req.Header.Set("Origin", origin)
+	}
+	if method == http.MethodOptions {
+		req.Header.Set("Access-Control-Request-Method", http.MethodGet)
+	}
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func TestCORSMiddleware_AllowedOriginIsEchoed(t *testing.T) {
+	router := newCORSRouter(CORSConfig{AllowedOrigins: []string{" https://shop.example.com/ ", "https://admin.example.com"}})
+
+	w := corsRequest(router, http.MethodGet, "https://shop.example.com")
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example.com" {
+		t.Errorf("Expected the origin to be echoed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Expected credentials to be allowed, got %q", got)
+	}
+	if got := w.Header().Get("Vary"); got != "Origin" {
+		t.Errorf("Expected Vary: Origin, got %q", got)
+	}
+}
+
+func TestCORSMiddleware_DisallowedOriginGetsNoHeaders(t *testing.T) {
+	tests := []struct {
+		name   string
+		config CORSConfig
+	}{
+		{"not listed", CORSConfig{AllowedOrigins: []string{"https://shop.example.com"}}},
+		{"empty list is same-origin only", CORSConfig{AllowedOrigins: []string{""}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := corsRequest(newCORSRouter(tt.config), http.MethodGet, "https://evil.example.com")
+
+			if w.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", w.Code)
+			}
+			for _, header := range []string{"Access-Control-Allow-Origin", "Access-Control-Allow-Credentials", "Access-Control-Allow-Methods"} {
+				if got := w.Header().Get(header); got != "" {
+					t.Errorf("Expected no %s header, got %q", header, got)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSMiddleware_Preflight(t *testing.T) {
+	router := newCORSRouter(CORSConfig{
+		AllowedOrigins: []string{"https://shop.example.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+	})
+
+	w := corsRequest(router, http.MethodOptions, "https://shop.example.com")
+
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example.com" {
+		t.Errorf("Expected the origin to be echoed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST" {
+		t.Errorf("Expected the configured methods, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Headers"); got != "Content-Type, Authorization" {
+		t.Errorf("Expected the configured headers, got %q", got)
+	}
+
+	w = corsRequest(router, http.MethodOptions, "https://evil.example.com")
+	if w.Code != http.StatusNoContent {
+		t.Fatalf("Expected status 204, got %d", w.Code)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "" {
+		t.Errorf("Expected no Access-Control-Allow-Origin for a disallowed preflight, got %q", got)
+	}
+}
+
+func TestCORSMiddleware_DefaultMethodsAndHeaders(t *testing.T) {
+	router := newCORSRouter(CORSConfig{AllowedOrigins: []string{"*"}})
+
+	w := corsRequest(router, http.MethodOptions, "https://any.example.com")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "*" {
+		t.Errorf("Expected a literal wildcard, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "" {
+		t.Errorf("Expected no credentials for the wildcard, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Methods"); got != "GET, POST, PUT, PATCH, DELETE, OPTIONS" {
+		t.Errorf("Expected the default methods, got %q", got)
+	}
+}
+
+func TestCORSMiddleware_ListedOriginKeepsCredentialsAlongsideWildcard(t *testing.T) {
+	router := newCORSRouter(CORSConfig{AllowedOrigins: []string{"*", "https://shop.example.com"}})
+
+	w := corsRequest(router, http.MethodGet, "https://shop.example.com")
+
+	if got := w.Header().Get("Access-Control-Allow-Origin"); got != "https://shop.example.com" {
+		t.Errorf("Expected the listed origin to be echoed, got %q", got)
+	}
+	if got := w.Header().Get("Access-Control-Allow-Credentials"); got != "true" {
+		t.Errorf("Expected credentials for the listed origin, got %q", got)
+	}
+}
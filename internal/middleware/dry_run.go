@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+
+	"online-order-management-system/pkg/dryrun"
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// DryRunHeader asks for a write to be checked and rolled back instead of committed
+const DryRunHeader = "X-Dry-Run"
+
+// DryRunMiddleware turns a write request (POST, PUT, PATCH, DELETE) carrying
+// "X-Dry-Run: true" into a dry run: validation, business rules and the database statements
+// all run, but the transaction is rolled back and no event is published. Dry runs are
+// restricted to the admin role, so it must run after AdminKeyMiddleware. The response echoes
+// the header so clients can tell a rehearsal from a real write.
+func DryRunMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(DryRunHeader)
+		if value == "" {
+			c.Next()
+			return
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			appErr := apperrors.NewBadRequestError("X-Dry-Run must be true or false").WithDetails(map[string]interface{}{
+				"header": DryRunHeader,
+				"value":  value,
+			})
+			c.AbortWithStatusJSON(http.StatusBadRequest, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+			return
+		}
+		if !enabled || !isWriteMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if !HasRole(c, RoleAdmin) {
+			appErr := apperrors.NewAuthorizationError("Dry runs require the admin role")
+			c.AbortWithStatusJSON(http.StatusForbidden, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+			return
+		}
+
+		c.Request = c.Request.WithContext(dryrun.With(c.Request.Context()))
+		c.Header(DryRunHeader, "true")
+		c.Next()
+	}
+}
+
+// isWriteMethod reports whether method changes data
+func isWriteMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// errJSONTooComplex is returned when a payload exceeds the configured item or depth limits
+var errJSONTooComplex = errors.New("json payload too complex")
+
+// JSONLimits bounds the shape of JSON request bodies
+type JSONLimits struct {
+	MaxItems int // Maximum number of JSON values (objects, arrays and scalars)
+	MaxDepth int // Maximum nesting depth of objects and arrays
+}
+
+// errReader replays a read error
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
+
+// errRecorder remembers the first error returned by the reader it wraps
+type errRecorder struct {
+	r   io.Reader
+	err error
+}
+
+func (r *errRecorder) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	if err != nil && r.err == nil {
+		r.err = err
+	}
+	return n, err
+}
+
+// jsonFrame tracks an open object or array while streaming tokens
+type jsonFrame struct {
+	isObject  bool
+	expectKey bool
+}
+
+// checkJSONComplexity streams the JSON tokens and stops as soon as a limit is exceeded,
+// so pathological payloads are rejected without being fully unmarshaled.
+// Object keys are not counted as items.
+func checkJSONComplexity(r io.Reader, limits JSONLimits) error {
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+
+	var stack []jsonFrame
+	items := 0
+
+	// valueDone flips the enclosing object back to expecting a key
+	valueDone := func() {
+		if n := len(stack); n > 0 && stack[n-1].isObject {
+			stack[n-1].expectKey = true
+		}
+	}
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			// Malformed JSON is reported by the regular binding step
+			return nil
+		}
+
+		if n := len(stack); n > 0 && stack[n-1].expectKey && token != json.Delim('}') {
+			stack[n-1].expectKey = false
+			continue
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			items++
+			stack = append(stack, jsonFrame{isObject: token == json.Delim('{'), expectKey: token == json.Delim('{')})
+			if limits.MaxDepth > 0 && len(stack) > limits.MaxDepth {
+				return errJSONTooComplex
+			}
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+			valueDone()
+		default:
+			items++
+			valueDone()
+		}
+
+		if limits.MaxItems > 0 && items > limits.MaxItems {
+			return errJSONTooComplex
+		}
+	}
+}
+
+// JSONComplexityMiddleware rejects JSON bodies whose item count or nesting depth exceed
+// the limits before the handler binds them. It complements a body-size limit by catching
+// payloads made of many tiny values. streamedRoutes lists route patterns (as reported by
+// gin's FullPath) whose handlers read the body incrementally; they are never buffered here,
+// whatever Content-Type the client sends.
+func JSONComplexityMiddleware(limits JSONLimits, streamedRoutes ...string) gin.HandlerFunc {
+	streamed := make(map[string]bool, len(streamedRoutes))
+	for _, route := range streamedRoutes {
+		streamed[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if c.Request.Body == nil || streamed[c.FullPath()] || !strings.HasPrefix(c.ContentType(), "application/") ||
+			!strings.Contains(c.ContentType(), "json") || c.ContentType() == "application/x-ndjson" {
+			c.Next()
+			return
+		}
+
+		// Tokenize the body as it arrives, keeping a copy for the handler, so an oversized
+		// payload is rejected without reading the rest of it
+		body := c.Request.Body
+		var buffered bytes.Buffer
+		reader := &errRecorder{r: io.TeeReader(body, &buffered)}
+		if err := checkJSONComplexity(reader, limits); err != nil {
+			appErr := apperrors.NewBadRequestError("Request body is too complex").WithDetails(map[string]interface{}{
+				"max_items": limits.MaxItems,
+				"max_depth": limits.MaxDepth,
+			})
+			traceID, _ := c.Get("trace_id")
+			traceIDStr, _ := traceID.(string)
+			c.AbortWithStatusJSON(http.StatusBadRequest, apperrors.ToErrorResponse(appErr, traceIDStr))
+			return
+		}
+
+		// Hand on what was read followed by whatever the tokenizer left unread. Read errors
+		// (such as an exceeded body limit) are replayed so the handler surfaces them through
+		// its normal binding path.
+		rest := io.Reader(body)
+		if reader.err != nil && reader.err != io.EOF {
+			rest = errReader{reader.err}
+		}
+		c.Request.Body = struct {
+			io.Reader
+			io.Closer
+		}{io.MultiReader(&buffered, rest), body}
+
+		c.Next()
+	}
+}
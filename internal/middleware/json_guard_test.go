@@ -0,0 +1,186 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newJSONGuardRouter(limits JSONLimits, reached *bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(JSONComplexityMiddleware(limits))
+	router.POST("/orders", func(c *gin.Context) {
+		var body map[string]interface{}
+		if err := c.ShouldBindJSON(&body); err != nil {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		*reached = true
+		c.Status(http.StatusCreated)
+	})
+	return router
+}
+
+func TestJSONComplexityMiddleware_RejectsExcessiveItems(t *testing.T) {
+	var reached bool
+	router := newJSONGuardRouter(JSONLimits{MaxItems: 100, MaxDepth: 10}, &reached)
+
+	values := make([]string, 500)
+	for i := range values {
+		values[i] = "1"
+	}
+	body := fmt.Sprintf(`{"customer_name":"x","items":[%s]}`, strings.Join(values, ","))
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest {
+		t.Fatalf("expected 400, got %d", w.Code)
+	}
+	if reached {
+		t.Error("expected the request to be rejected before reaching the handler")
+	}
+	if !strings.Contains(w.Body.String(), "BAD_REQUEST") {
+		t.Errorf("expected a BAD_REQUEST error body, got %s", w.Body.String())
+	}
+}
+
+func TestJSONComplexityMiddleware_RejectsDeepNesting(t *testing.T) {
+	var reached bool
+	router := newJSONGuardRouter(JSONLimits{MaxItems: 1000, MaxDepth: 5}, &reached)
+
+	body := `{"a":` + strings.Repeat("[", 10) + strings.Repeat("]", 10) + `}`
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest || reached {
+		t.Fatalf("expected early rejection, got %d (reached handler: %v)", w.Code, reached)
+	}
+}
+
+func TestJSONComplexityMiddleware_AllowsNormalPayload(t *testing.T) {
+	var reached bool
+	router := newJSONGuardRouter(JSONLimits{MaxItems: 20, MaxDepth: 5}, &reached)
+
+	body := `{"customer_name":"John","items":[{"product_name":"Laptop","quantity":1,"unit_price":999.99}]}`
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusCreated || !reached {
+		t.Fatalf("expected the payload to reach the handler, got %d", w.Code)
+	}
+}
+
+func TestCheckJSONComplexity_DoesNotCountKeys(t *testing.T) {
+	// 1 object + 3 scalar values; the 3 keys must not count
+	body := `{"a":1,"b":"two","c":true}`
+	if err := checkJSONComplexity(strings.NewReader(body), JSONLimits{MaxItems: 4}); err != nil {
+		t.Fatalf("expected 4 items to be within the limit, got %v", err)
+	}
+	if err := checkJSONComplexity(strings.NewReader(body), JSONLimits{MaxItems: 3}); err == nil {
+		t.Fatal("expected 4 items to exceed a limit of 3")
+	}
+}
+
+func TestJSONComplexityMiddleware_PassesBodyLimitErrorsOn(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimitMiddleware(32, nil))
+	router.Use(JSONComplexityMiddleware(JSONLimits{MaxItems: 100, MaxDepth: 10}))
+	router.POST("/orders", func(c *gin.Context) {
+		var body map[string]interface{}
+		var tooLarge *http.MaxBytesError
+		if err := c.ShouldBindJSON(&body); errors.As(err, &tooLarge) {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusBadRequest)
+	})
+
+	req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(`{"customer_name":"`+strings.Repeat("x", 64)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected the handler to see the body limit error, got %d", w.Code)
+	}
+}
+
+func TestJSONComplexityMiddleware_StreamedRoutesKeepTheirBodyLimit(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(BodyLimitMiddleware(32, map[string]int64{"/orders/import": 128}))
+	router.Use(JSONComplexityMiddleware(JSONLimits{MaxItems: 100, MaxDepth: 10}, "/orders/import"))
+	var read int64
+	router.POST("/orders/import", func(c *gin.Context) {
+		// Streams the body like the NDJSON import does
+		n, err := io.Copy(io.Discard, c.Request.Body)
+		read = n
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.Status(http.StatusRequestEntityTooLarge)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+
+	// An application/json body is not buffered by the guard, but the route's cap still applies
+	req := httptest.NewRequest(http.MethodPost, "/orders/import", strings.NewReader(`{"customer_name":"`+strings.Repeat("x", 1024)+`"}`))
+	req.Header.Set("Content-Type", "application/json")
+	req.ContentLength = -1
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("expected an oversized import to be cut off at the route limit, got %d", w.Code)
+	}
+	if read > 128 {
+		t.Errorf("expected at most 128 bytes to reach the handler, got %d", read)
+	}
+}
+
+// countingReader counts the bytes read from it
+type countingReader struct {
+	r    io.Reader
+	read int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.read += n
+	return n, err
+}
+
+func TestJSONComplexityMiddleware_StopsReadingAtTheLimit(t *testing.T) {
+	var reached bool
+	router := newJSONGuardRouter(JSONLimits{MaxItems: 1000, MaxDepth: 5}, &reached)
+
+	tail := strings.Repeat("1,", 1<<20) + "1"
+	body := &countingReader{r: strings.NewReader(`{"a":` + strings.Repeat("[", 10) + tail + strings.Repeat("]", 10) + `}`)}
+	req := httptest.NewRequest(http.MethodPost, "/orders", body)
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	if w.Code != http.StatusBadRequest || reached {
+		t.Fatalf("expected early rejection, got %d (reached handler: %v)", w.Code, reached)
+	}
+	if body.read >= len(tail) {
+		t.Errorf("expected the guard to stop reading once the limit was exceeded, read %d bytes", body.read)
+	}
+}
@@ -2,17 +2,22 @@ package middleware
 
 import (
 	"fmt"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GinLoggingMiddleware returns a Gin middleware for logging HTTP requests.
+// GinLoggingMiddleware returns a Gin middleware for logging HTTP requests, tagged with the
+// trace ID set by TraceIDMiddleware.
 func GinLoggingMiddleware() gin.HandlerFunc {
 	return gin.LoggerWithFormatter(func(param gin.LogFormatterParams) string {
-		return fmt.Sprintf("%s - [%s] \"%s %s %s %d %s \"%s\" %s\"\n",
+		traceID, _ := param.Keys["trace_id"].(string)
+		return fmt.Sprintf("%s - [%s] trace_id=%s \"%s %s %s %d %s \"%s\" %s\"\n",
 			param.ClientIP,
 			param.TimeStamp.Format(time.RFC1123),
+			traceID,
 			param.Method,
 			param.Path,
 			param.Request.Proto,
@@ -24,19 +29,79 @@ func GinLoggingMiddleware() gin.HandlerFunc {
 	})
 }
 
-// CORSMiddleware returns a Gin middleware for handling CORS.
-func CORSMiddleware() gin.HandlerFunc {
+// DefaultCORSMethods are the methods allowed in preflight responses when none are configured
+var DefaultCORSMethods = []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"}
+
+// DefaultCORSHeaders are the request headers allowed in preflight responses when none are configured
+var DefaultCORSHeaders = []string{
+	"Content-Type", "Content-Length", "Accept-Encoding", "X-CSRF-Token", "Authorization", "X-Admin-Key",
+	"X-Dry-Run", "X-Request-ID", "Idempotency-Key", "Api-Version", "Accept", "Origin", "Cache-Control", "X-Requested-With",
+}
+
+// CORSConfig lists the cross-origin callers of the API. Empty methods or headers fall back to
+// DefaultCORSMethods and DefaultCORSHeaders.
+type CORSConfig struct {
+	// AllowedOrigins are exact origins such as https://shop.example.com; "*" allows any
+	// origin, without credentials. Empty allows same-origin requests only.
+	AllowedOrigins []string
+	AllowedMethods []string
+	AllowedHeaders []string
+}
+
+// CORSMiddleware returns a Gin middleware for handling CORS. A listed Origin is echoed with
+// credentials allowed; with "*" any other origin gets a literal wildcard and no credentials,
+// so browsers never send cookies or auth headers to an origin nobody listed. Other origins get
+// no CORS headers and browsers block their requests. OPTIONS requests are answered with 204
+// without reaching the routes.
+func CORSMiddleware(config CORSConfig) gin.HandlerFunc {
+	origins := make(map[string]bool, len(config.AllowedOrigins))
+	for _, origin := range config.AllowedOrigins {
+		if origin = strings.TrimRight(strings.TrimSpace(origin), "/"); origin != "" {
+			origins[strings.ToLower(origin)] = true
+		}
+	}
+	methods := strings.Join(orDefault(config.AllowedMethods, DefaultCORSMethods), ", ")
+	headers := strings.Join(orDefault(config.AllowedHeaders, DefaultCORSHeaders), ", ")
+
 	return func(c *gin.Context) {
-		c.Header("Access-Control-Allow-Origin", "*")
-		c.Header("Access-Control-Allow-Credentials", "true")
-		c.Header("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-		c.Header("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+		origin := c.GetHeader("Origin")
+		c.Writer.Header().Add("Vary", "Origin")
 
-		if c.Request.Method == "OPTIONS" {
-			c.AbortWithStatus(204)
+		allowOrigin := ""
+		switch {
+		case origin == "":
+			// Not a cross-origin request
+		case origins[strings.ToLower(origin)]:
+			allowOrigin = origin
+			c.Header("Access-Control-Allow-Credentials", "true")
+		case origins["*"]:
+			allowOrigin = "*"
+		}
+		if allowOrigin != "" {
+			c.Header("Access-Control-Allow-Origin", allowOrigin)
+			c.Header("Access-Control-Allow-Headers", headers)
+			c.Header("Access-Control-Allow-Methods", methods)
+		}
+
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
 			return
 		}
 
 		c.Next()
 	}
 }
+
+// orDefault returns the trimmed, non-blank values, or fallback when there are none
+func orDefault(values, fallback []string) []string {
+	kept := make([]string, 0, len(values))
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			kept = append(kept, value)
+		}
+	}
+	if len(kept) == 0 {
+		return fallback
+	}
+	return kept
+}
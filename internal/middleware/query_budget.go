@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/querybudget"
+
+	"github.com/gin-gonic/gin"
+)
+
+// QueryBudgetMiddleware counts the database queries each request issues and logs a warning
+// when a request issues more than limit, which usually points at an N+1 query pattern.
+// In strict mode the offending query panics instead; use it in tests and CI only.
+// A non-positive limit disables counting.
+func QueryBudgetMiddleware(limit int, strict bool) gin.HandlerFunc {
+	log := logger.New("query-budget-middleware", "1.0.0")
+
+	return func(c *gin.Context) {
+		if limit <= 0 {
+			c.Next()
+			return
+		}
+
+		ctx, budget := querybudget.WithBudget(c.Request.Context(), limit, strict)
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if budget.Exceeded() {
+			log.WithFields(map[string]interface{}{
+				"trace_id":    c.GetString("trace_id"),
+				"method":      c.Request.Method,
+				"path":        c.FullPath(),
+				"query_count": budget.Count(),
+				"query_limit": budget.Limit(),
+			}).Warn("Request exceeded its database query budget")
+		}
+	}
+}
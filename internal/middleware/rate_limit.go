@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"math"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterIdleTTL is how long an unused client bucket is kept before eviction
+const rateLimiterIdleTTL = 10 * time.Minute
+
+// clientBucket is a token bucket and the last time it was used
+type clientBucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// IPRateLimiter keeps an independent token bucket per client IP
+type IPRateLimiter struct {
+	mu        sync.Mutex
+	buckets   map[string]*clientBucket
+	rate      rate.Limit
+	burst     int
+	lastSweep time.Time
+}
+
+// NewIPRateLimiter creates a limiter allowing rps requests per second per client, with bursts
+// of up to burst requests. A non-positive rps returns nil, which disables limiting.
+func NewIPRateLimiter(rps float64, burst int) *IPRateLimiter {
+	if rps <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &IPRateLimiter{
+		buckets:   make(map[string]*clientBucket),
+		rate:      rate.Limit(rps),
+		burst:     burst,
+		lastSweep: time.Now(),
+	}
+}
+
+// Allow takes a token from the client's bucket. When none is available it returns false
+// and how long the client should wait before retrying.
+func (l *IPRateLimiter) Allow(client string) (bool, time.Duration) {
+	if l == nil {
+		return true, 0
+	}
+
+	now := time.Now()
+	l.mu.Lock()
+	bucket, ok := l.buckets[client]
+	if !ok {
+		bucket = &clientBucket{limiter: rate.NewLimiter(l.rate, l.burst)}
+		l.buckets[client] = bucket
+	}
+	bucket.lastSeen = now
+	l.evictIdle(now)
+	l.mu.Unlock()
+
+	reservation := bucket.limiter.ReserveN(now, 1)
+	if delay := reservation.DelayFrom(now); delay > 0 {
+		reservation.CancelAt(now)
+		return false, delay
+	}
+	return true, 0
+}
+
+// evictIdle drops buckets unused for rateLimiterIdleTTL, at most once per TTL.
+// Callers must hold the lock.
+func (l *IPRateLimiter) evictIdle(now time.Time) {
+	if now.Sub(l.lastSweep) < rateLimiterIdleTTL {
+		return
+	}
+	for client, bucket := range l.buckets {
+		if now.Sub(bucket.lastSeen) > rateLimiterIdleTTL {
+			delete(l.buckets, client)
+		}
+	}
+	l.lastSweep = now
+}
+
+// Middleware rejects requests over the client's allowance with 429 and a Retry-After header.
+// exemptRoutes lists route patterns (as reported by gin's FullPath) that this limiter lets
+// through untouched, typically because a stricter limiter of their own applies; a request
+// then spends a token of exactly one limiter instead of being counted twice.
+func (l *IPRateLimiter) Middleware(exemptRoutes ...string) gin.HandlerFunc {
+	exempt := make(map[string]bool, len(exemptRoutes))
+	for _, route := range exemptRoutes {
+		exempt[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if exempt[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		allowed, retryAfter := l.Allow(c.ClientIP())
+		if allowed {
+			c.Next()
+			return
+		}
+
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		appErr := apperrors.NewRateLimitError("Too many requests, please retry later").WithDetails(map[string]interface{}{
+			"retry_after_seconds": seconds,
+		})
+		c.Header("Retry-After", strconv.Itoa(seconds))
+		c.AbortWithStatusJSON(http.StatusTooManyRequests, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+	}
+}
+
+// RateLimitMiddleware limits every client IP to rps requests per second with bursts of up to
+// burst requests, answering 429 with Retry-After beyond that. Requests to exemptRoutes are
+// not counted (see IPRateLimiter.Middleware). A non-positive rps disables it.
+func RateLimitMiddleware(rps int, burst int, exemptRoutes ...string) gin.HandlerFunc {
+	return NewIPRateLimiter(float64(rps), burst).Middleware(exemptRoutes...)
+}
@@ -0,0 +1,116 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestIPRateLimiter_PerClientBuckets(t *testing.T) {
+	limiter := NewIPRateLimiter(0.001, 2)
+
+	for i := 0; i < 2; i++ {
+		if allowed, _ := limiter.Allow("10.0.0.1"); !allowed {
+			t.Fatalf("request %d within burst was rejected", i+1)
+		}
+	}
+
+	allowed, retryAfter := limiter.Allow("10.0.0.1")
+	if allowed {
+		t.Fatal("expected request over burst to be rejected")
+	}
+	if retryAfter <= 0 {
+		t.Errorf("expected a positive retry delay, got %s", retryAfter)
+	}
+
+	if allowed, _ := limiter.Allow("10.0.0.2"); !allowed {
+		t.Error("expected another client to have its own bucket")
+	}
+}
+
+func TestIPRateLimiter_DisabledWhenRateNotPositive(t *testing.T) {
+	limiter := NewIPRateLimiter(0, 1)
+	if limiter != nil {
+		t.Fatal("expected nil limiter for a non-positive rate")
+	}
+
+	for i := 0; i < 10; i++ {
+		if allowed, _ := limiter.Allow("10.0.0.1"); !allowed {
+			t.Fatal("expected a nil limiter to allow every request")
+		}
+	}
+}
+
+func TestRateLimitMiddleware_RejectsRequestsOverBurst(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimitMiddleware(1, 3))
+	router.GET("/orders", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	send := func(remoteAddr string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+		req.RemoteAddr = remoteAddr
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	for i := 0; i < 3; i++ {
+		if w := send("10.0.0.1:1234"); w.Code != http.StatusOK {
+			t.Fatalf("request %d within burst got %d", i+1, w.Code)
+		}
+	}
+
+	w := send("10.0.0.1:1234")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the burst is spent, got %d", w.Code)
+	}
+	if w.Header().Get("Retry-After") == "" {
+		t.Error("expected a Retry-After header")
+	}
+	if !strings.Contains(w.Body.String(), "RATE_LIMIT") {
+		t.Errorf("expected a RATE_LIMIT error, got %s", w.Body.String())
+	}
+
+	if w := send("10.0.0.2:1234"); w.Code != http.StatusOK {
+		t.Errorf("expected another client to be unaffected, got %d", w.Code)
+	}
+}
+
+func TestRateLimitMiddleware_ExemptRoutesUseOnlyTheirOwnLimiter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(RateLimitMiddleware(1, 1, "/orders/bulk"))
+	ok := func(c *gin.Context) { c.Status(http.StatusOK) }
+	router.GET("/orders", ok)
+	router.POST("/orders/bulk", NewIPRateLimiter(0.001, 2).Middleware(), ok)
+
+	send := func(method, path string) int {
+		req := httptest.NewRequest(method, path, nil)
+		req.RemoteAddr = "10.0.0.1:1234"
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// Bulk requests spend the bulk burst only, leaving the API-wide token untouched
+	for i := 0; i < 2; i++ {
+		if code := send(http.MethodPost, "/orders/bulk"); code != http.StatusOK {
+			t.Fatalf("bulk request %d within the bulk burst got %d", i+1, code)
+		}
+	}
+	if code := send(http.MethodPost, "/orders/bulk"); code != http.StatusTooManyRequests {
+		t.Fatalf("expected 429 once the bulk burst is spent, got %d", code)
+	}
+	if code := send(http.MethodGet, "/orders"); code != http.StatusOK {
+		t.Fatalf("expected bulk requests not to count against the API-wide limit, got %d", code)
+	}
+	if code := send(http.MethodGet, "/orders"); code != http.StatusTooManyRequests {
+		t.Errorf("expected other routes to stay limited, got %d", code)
+	}
+}
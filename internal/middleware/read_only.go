@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ReadOnlyMiddleware rejects write requests (POST, PUT, PATCH, DELETE) with 503 while
+// enabled, keeping reads available during maintenance. readRoutes lists route patterns
+// (as reported by gin's FullPath) that use a write method but only read data.
+func ReadOnlyMiddleware(enabled bool, readRoutes ...string) gin.HandlerFunc {
+	allowed := make(map[string]bool, len(readRoutes))
+	for _, route := range readRoutes {
+		allowed[route] = true
+	}
+
+	return func(c *gin.Context) {
+		if !enabled || allowed[c.FullPath()] {
+			c.Next()
+			return
+		}
+
+		if !isWriteMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+		appErr := apperrors.NewServiceUnavailableError("The API is in read-only mode for maintenance; writes are temporarily disabled").WithDetails(map[string]interface{}{
+			"read_only": true,
+		})
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+	}
+}
@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"crypto/subtle"
+
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Roles stored in the gin context under ContextKeyRole
+const (
+	ContextKeyRole = "role"
+	RoleAdmin      = "admin"
+)
+
+// AdminKeyHeader carries the shared admin key
+const AdminKeyHeader = "X-Admin-Key"
+
+// AdminKeyMiddleware grants the admin role to requests presenting the configured admin key.
+// Requests without a matching key continue without a role; an empty key disables admin access.
+func AdminKeyMiddleware(adminKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(AdminKeyHeader)
+		if adminKey != "" && subtle.ConstantTimeCompare([]byte(provided), []byte(adminKey)) == 1 {
+			c.Set(ContextKeyRole, RoleAdmin)
+		}
+		c.Next()
+	}
+}
+
+// HasRole reports whether the request was granted the role
+func HasRole(c *gin.Context, role string) bool {
+	return c.GetString(ContextKeyRole) == role
+}
+
+// RequireRole aborts with 403 unless an earlier middleware, such as AdminKeyMiddleware,
+// granted the request the role
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !HasRole(c, role) {
+			appErr := apperrors.NewAuthorizationError("this endpoint requires the " + role + " role")
+			c.AbortWithStatusJSON(appErr.HTTPStatus, apperrors.ToErrorResponse(appErr, c.GetString("trace_id")))
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,58 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	apperrors "online-order-management-system/pkg/errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestRequireRole(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TraceIDMiddleware())
+	debug := router.Group("/debug", AdminKeyMiddleware("s3cret"), RequireRole(RoleAdmin))
+	debug.GET("/cache", func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+
+	tests := []struct {
+		name   string
+		key    string
+		status int
+	}{
+		{"admin key", "s3cret", http.StatusOK},
+		{"wrong key", "guess", http.StatusForbidden},
+		{"no key", "", http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/debug/cache", nil)
+			if tt.key != "" {
+				req.Header.Set(AdminKeyHeader, tt.key)
+			}
+			req.Header.Set(RequestIDHeader, "trace-123")
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.status {
+				t.Fatalf("expected %d, got %d: %s", tt.status, w.Code, w.Body.String())
+			}
+			if tt.status == http.StatusOK {
+				return
+			}
+			var body apperrors.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("failed to decode error response: %v", err)
+			}
+			if body.TraceID != "trace-123" {
+				t.Errorf("expected the request's trace ID in the error, got %q", body.TraceID)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package middleware
+
+import (
+	"strings"
+
+	"online-order-management-system/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// RequestIDHeader carries the request's trace ID in both directions
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength caps an inbound request ID; longer values are replaced
+const maxRequestIDLength = 128
+
+// TraceIDMiddleware gives every request a trace ID: the caller's X-Request-ID header when
+// present, otherwise a new UUID. The ID is stored as "trace_id" in the gin context and the
+// request's log context, so every log line of the request carries it, and is echoed in the
+// X-Request-ID response header. Register it before the logging middleware.
+func TraceIDMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		traceID := strings.TrimSpace(c.GetHeader(RequestIDHeader))
+		if traceID == "" || len(traceID) > maxRequestIDLength {
+			traceID = uuid.NewString()
+		}
+
+		c.Set("trace_id", traceID)
+		c.Request = c.Request.WithContext(logger.ContextWithTraceID(c.Request.Context(), traceID))
+		c.Header(RequestIDHeader, traceID)
+		c.Next()
+	}
+}
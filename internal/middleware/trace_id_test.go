@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newTraceIDRouter() *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(TraceIDMiddleware())
+	router.GET("/orders", func(c *gin.Context) {
+		c.String(http.StatusOK, c.GetString("trace_id"))
+	})
+	return router
+}
+
+func TestTraceIDMiddleware_RoundTripsInboundID(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/orders", nil)
+	req.Header.Set(RequestIDHeader, "req-7f3a-42")
+	w := httptest.NewRecorder()
+	newTraceIDRouter().ServeHTTP(w, req)
+
+	if got := w.Header().Get(RequestIDHeader); got != "req-7f3a-42" {
+		t.Errorf("expected the inbound ID to be echoed unchanged, got %q", got)
+	}
+	if w.Body.String() != "req-7f3a-42" {
+		t.Errorf("expected the inbound ID to be stored as trace_id, got %q", w.Body.String())
+	}
+}
+
+func TestTraceIDMiddleware_GeneratesIDWhenMissing(t *testing.T) {
+	router := newTraceIDRouter()
+	seen := map[string]bool{}
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/orders", nil))
+
+		id := w.Header().Get(RequestIDHeader)
+		if len(id) != 36 {
+			t.Fatalf("expected a generated UUID, got %q", id)
+		}
+		if w.Body.String() != id {
+			t.Errorf("expected the generated ID %q to be stored as trace_id, got %q", id, w.Body.String())
+		}
+		seen[id] = true
+	}
+	if len(seen) != 2 {
+		t.Error("expected each request to get its own ID")
+	}
+}
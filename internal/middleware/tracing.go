@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"net/http"
+
+	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/tracing"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracingMiddleware starts a server span for each request, continuing the trace of an
+// incoming W3C traceparent header when present. The span travels in the request context to
+// use cases and repositories. A trace ID already assigned by TraceIDMiddleware is recorded
+// on the span as request.id; otherwise the span's trace ID becomes the request's "trace_id"
+// in the gin context and log context, so logs, error responses and traces correlate.
+func TracingMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := otel.GetTextMapPropagator().Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx, span := tracing.Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+			),
+		)
+		defer span.End()
+
+		if requestID := c.GetString("trace_id"); requestID != "" {
+			span.SetAttributes(attribute.String("request.id", requestID))
+			c.Request = c.Request.WithContext(ctx)
+		} else {
+			traceID := tracing.TraceID(ctx)
+			c.Set("trace_id", traceID)
+			c.Request = c.Request.WithContext(logger.ContextWithTraceID(ctx, traceID))
+		}
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+// MaxBulkOrders is the largest number of orders accepted in one bulk request
+const MaxBulkOrders = 100
+
+// BulkCreateOrdersUseCase creates many orders in one request, reporting each order's outcome
+type BulkCreateOrdersUseCase struct {
+	createOrder *CreateOrderUseCase
+	concurrency int
+	logger      *logger.Logger
+}
+
+// BulkCreateOrdersOption configures optional behavior of BulkCreateOrdersUseCase
+type BulkCreateOrdersOption func(*BulkCreateOrdersUseCase)
+
+// WithBulkConcurrency bounds how many orders of a batch are created at once
+func WithBulkConcurrency(concurrency int) BulkCreateOrdersOption {
+	return func(uc *BulkCreateOrdersUseCase) {
+		if concurrency > 0 {
+			uc.concurrency = concurrency
+		}
+	}
+}
+
+// NewBulkCreateOrdersUseCase creates a new BulkCreateOrdersUseCase that creates each order
+// through createOrder, so single and bulk creates share validation and persistence rules
+func NewBulkCreateOrdersUseCase(createOrder *CreateOrderUseCase, opts ...BulkCreateOrdersOption) *BulkCreateOrdersUseCase {
+	uc := &BulkCreateOrdersUseCase{
+		createOrder: createOrder,
+		concurrency: 4,
+		logger:      logger.New("bulk-create-orders-usecase", "1.0.0"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// BulkCreateOrdersRequest represents the input for creating several orders
+type BulkCreateOrdersRequest struct {
+	Orders []CreateOrderRequest `json:"orders"`
+}
+
+// BulkOrderResult is the outcome of one order of a bulk request
+type BulkOrderResult struct {
+	Index int           // Position of the order in the request
+	Order *entity.Order // Created order, nil on failure
+	Error error         // Failure reason, nil on success
+}
+
+// BulkCreateOrdersResponse represents the outcome of a bulk create, in request order
+type BulkCreateOrdersResponse struct {
+	Results   []BulkOrderResult
+	Succeeded int
+	Failed    int
+
+	DurationMs  float64 // Wall-clock time the whole batch took
+	Concurrency int     // Orders created at once: the configured bound, or fewer for small batches
+}
+
+// Execute creates every order of the request independently; one order failing does not
+// prevent the others from being created
+func (uc *BulkCreateOrdersUseCase) Execute(ctx context.Context, req BulkCreateOrdersRequest) (*BulkCreateOrdersResponse, error) {
+	if len(req.Orders) == 0 {
+		return nil, apperrors.NewInvalidEntityError("at least one order is required")
+	}
+	if len(req.Orders) > MaxBulkOrders {
+		return nil, apperrors.NewInvalidEntityError("too many orders in one request").WithDetails(map[string]interface{}{
+			"orders_count": len(req.Orders),
+			"max_orders":   MaxBulkOrders,
+		})
+	}
+
+	log := uc.logger.WithContext(ctx)
+	log.WithFields(map[string]interface{}{
+		"orders_count": len(req.Orders),
+		"concurrency":  uc.concurrency,
+	}).Info("Starting bulk order creation")
+
+	response := &BulkCreateOrdersResponse{
+		Results:     make([]BulkOrderResult, len(req.Orders)),
+		Concurrency: min(uc.concurrency, len(req.Orders)),
+	}
+	start := time.Now()
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, response.Concurrency)
+	for i, orderReq := range req.Orders {
+		wg.Add(1)
+		slots <- struct{}{}
+		go func(i int, orderReq CreateOrderRequest) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			// Derived from the request context so each order's logs carry its trace ID and
+			// position, while the request's deadline and cancellation still apply
+			orderCtx := logger.ContextWithFields(ctx, map[string]interface{}{"bulk_index": i})
+			created, err := uc.createOrder.Execute(orderCtx, orderReq)
+			response.Results[i] = BulkOrderResult{Index: i, Order: created, Error: err}
+		}(i, orderReq)
+	}
+	wg.Wait()
+	response.DurationMs = float64(time.Since(start).Microseconds()) / 1000
+
+	for _, result := range response.Results {
+		if result.Error != nil {
+			response.Failed++
+		} else {
+			response.Succeeded++
+		}
+	}
+
+	log.WithFields(map[string]interface{}{
+		"orders_count": len(req.Orders),
+		"succeeded":    response.Succeeded,
+		"failed":       response.Failed,
+		"duration_ms":  response.DurationMs,
+	}).Info("Finished bulk order creation")
+
+	return response, nil
+}
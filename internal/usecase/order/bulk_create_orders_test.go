@@ -0,0 +1,140 @@
+package order
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/infra/memory"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+func TestBulkCreateOrdersUseCase_ReportsTiming(t *testing.T) {
+	uc := NewBulkCreateOrdersUseCase(NewCreateOrderUseCase(memory.NewInMemoryOrderRepository()), WithBulkConcurrency(3))
+
+	orders := make([]CreateOrderRequest, 10)
+	for i := range orders {
+		orders[i] = validCreateOrderRequest()
+	}
+	resp, err := uc.Execute(context.Background(), BulkCreateOrdersRequest{Orders: orders})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Succeeded != len(orders) {
+		t.Fatalf("expected %d orders created, got %d", len(orders), resp.Succeeded)
+	}
+	if resp.DurationMs <= 0 {
+		t.Errorf("expected a positive duration, got %v", resp.DurationMs)
+	}
+	if resp.Concurrency != 3 {
+		t.Errorf("expected concurrency 3, got %d", resp.Concurrency)
+	}
+}
+
+func TestBulkCreateOrdersUseCase_ConcurrencyCappedBySmallBatch(t *testing.T) {
+	uc := NewBulkCreateOrdersUseCase(NewCreateOrderUseCase(memory.NewInMemoryOrderRepository()), WithBulkConcurrency(8))
+
+	resp, err := uc.Execute(context.Background(), BulkCreateOrdersRequest{
+		Orders: []CreateOrderRequest{validCreateOrderRequest(), validCreateOrderRequest()},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Concurrency != 2 {
+		t.Errorf("expected concurrency 2 for a batch of 2, got %d", resp.Concurrency)
+	}
+}
+
+func TestBulkCreateOrdersUseCase_PartialFailurePersistsValidOrders(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+	uc := NewBulkCreateOrdersUseCase(NewCreateOrderUseCase(repo))
+
+	noCustomer := validCreateOrderRequest()
+	noCustomer.CustomerName = ""
+	negativePrice := validCreateOrderRequest()
+	negativePrice.Items[0].UnitPrice = -5
+	orders := []CreateOrderRequest{validCreateOrderRequest(), noCustomer, validCreateOrderRequest(), negativePrice}
+
+	resp, err := uc.Execute(ctx, BulkCreateOrdersRequest{Orders: orders})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Succeeded != 2 || resp.Failed != 2 {
+		t.Fatalf("expected 2 created and 2 failed, got %d and %d", resp.Succeeded, resp.Failed)
+	}
+	for i, result := range resp.Results {
+		wantFailure := i == 1 || i == 3
+		if result.Index != i || (result.Error != nil) != wantFailure || (result.Order == nil) != wantFailure {
+			t.Errorf("order %d: unexpected result %+v", i, result)
+		}
+		if wantFailure && apperrors.GetAppError(result.Error) == nil {
+			t.Errorf("order %d: expected a typed error, got %v", i, result.Error)
+		}
+	}
+
+	stored, pagination, err := repo.ListOrders(ctx, 1, 10, repository.OrderFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pagination.TotalCount != 2 {
+		t.Fatalf("expected only the 2 valid orders to be stored, got %d", pagination.TotalCount)
+	}
+	for _, order := range stored {
+		if order.ID != resp.Results[0].Order.ID && order.ID != resp.Results[2].Order.ID {
+			t.Errorf("unexpected stored order %d", order.ID)
+		}
+	}
+}
+
+// lockedBuffer collects log lines written concurrently by bulk workers
+type lockedBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *lockedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func TestBulkCreateOrdersUseCase_WorkerLogsCarryTraceID(t *testing.T) {
+	var out lockedBuffer
+	logger.SetOutput(&out)
+	defer logger.SetOutput(nil)
+
+	noCustomer := validCreateOrderRequest()
+	noCustomer.CustomerName = ""
+	orders := []CreateOrderRequest{validCreateOrderRequest(), noCustomer, validCreateOrderRequest(), noCustomer}
+
+	uc := NewBulkCreateOrdersUseCase(NewCreateOrderUseCase(memory.NewInMemoryOrderRepository()), WithBulkConcurrency(4))
+	ctx := logger.ContextWithTraceID(context.Background(), "trace-bulk-1")
+	if _, err := uc.Execute(ctx, BulkCreateOrdersRequest{Orders: orders}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	failedIndexes := map[float64]bool{}
+	for _, line := range strings.Split(strings.TrimSpace(out.buf.String()), "\n") {
+		var entry logger.LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to decode log line %q: %v", line, err)
+		}
+		if entry.Fields["trace_id"] != "trace-bulk-1" {
+			t.Errorf("expected %q to carry the trace ID, got %v", entry.Message, entry.Fields)
+		}
+		if entry.Level == "WARN" && entry.Service == "create-order-usecase" {
+			index, _ := entry.Fields["bulk_index"].(float64)
+			failedIndexes[index] = true
+		}
+	}
+	if len(failedIndexes) != 2 || !failedIndexes[1] || !failedIndexes[3] {
+		t.Errorf("expected the failures of orders 1 and 3 to be logged with their index, got %v", failedIndexes)
+	}
+}
@@ -0,0 +1,119 @@
+package order
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+)
+
+// contentDedup remembers recently created orders by a hash of their content so an identical
+// resubmission within the window (e.g. a double-click) returns the existing order. Entries
+// live in process memory, so deduplication is per instance.
+type contentDedup struct {
+	window time.Duration
+	now    func() time.Time
+
+	mu        sync.Mutex
+	entries   map[string]*dedupEntry
+	lastSweep time.Time
+}
+
+// dedupEntry is a claimed content hash; done is closed once the create has finished
+type dedupEntry struct {
+	orderID int64
+	expires time.Time
+	done    chan struct{}
+}
+
+func newContentDedup(window time.Duration) *contentDedup {
+	return &contentDedup{
+		window:  window,
+		now:     time.Now,
+		entries: make(map[string]*dedupEntry),
+	}
+}
+
+// contentHash derives an idempotency key from the normalized customer name, email and items.
+// Item order does not matter; the client reference is deliberately left out.
+func contentHash(order *entity.Order) string {
+	lines := make([]string, len(order.Items))
+	for i, item := range order.Items {
+		lines[i] = fmt.Sprintf("%s|%s|%g|%s|%s|%g",
+			strings.ToLower(strings.TrimSpace(item.ProductName)),
+			strings.TrimSpace(item.SKU),
+			item.Quantity,
+			item.Unit,
+			item.UnitPrice,
+			item.DiscountPercent,
+		)
+	}
+	sort.Strings(lines)
+
+	customer := strings.ToLower(strings.TrimSpace(order.CustomerName)) + "|" + strings.ToLower(order.CustomerEmail)
+	sum := sha256.Sum256([]byte(customer + "\n" + strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:])
+}
+
+// claim returns the ID of an order created with the same hash inside the window, waiting for
+// an identical create still in flight. Otherwise it claims the hash and returns a finish func
+// that must be called with the new order's ID, or 0 if the create failed.
+func (d *contentDedup) claim(ctx context.Context, hash string) (int64, func(orderID int64), error) {
+	for {
+		d.mu.Lock()
+		now := d.now()
+		if now.Sub(d.lastSweep) >= d.window {
+			for key, entry := range d.entries {
+				if entry.orderID != 0 && now.After(entry.expires) {
+					delete(d.entries, key)
+				}
+			}
+			d.lastSweep = now
+		}
+
+		entry, ok := d.entries[hash]
+		if ok && entry.orderID != 0 && now.After(entry.expires) {
+			delete(d.entries, hash)
+			ok = false
+		}
+		if !ok {
+			entry = &dedupEntry{done: make(chan struct{})}
+			d.entries[hash] = entry
+			d.mu.Unlock()
+			return 0, d.finish(hash, entry), nil
+		}
+		if entry.orderID != 0 {
+			d.mu.Unlock()
+			return entry.orderID, nil, nil
+		}
+		d.mu.Unlock()
+
+		// An identical create is in flight; use its order, or retry the claim if it failed
+		select {
+		case <-entry.done:
+		case <-ctx.Done():
+			return 0, nil, ctx.Err()
+		}
+	}
+}
+
+func (d *contentDedup) finish(hash string, entry *dedupEntry) func(orderID int64) {
+	return func(orderID int64) {
+		d.mu.Lock()
+		defer d.mu.Unlock()
+
+		if orderID == 0 {
+			delete(d.entries, hash)
+		} else {
+			entry.orderID = orderID
+			entry.expires = d.now().Add(d.window)
+		}
+		close(entry.done)
+	}
+}
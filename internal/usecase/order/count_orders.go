@@ -0,0 +1,39 @@
+package order
+
+import (
+	"context"
+
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/pkg/logger"
+)
+
+// CountOrdersUseCase counts the orders matching a filter without loading them
+type CountOrdersUseCase struct {
+	orderRepo repository.OrderRepository
+	logger    *logger.Logger
+}
+
+// NewCountOrdersUseCase creates a new CountOrdersUseCase
+func NewCountOrdersUseCase(orderRepo repository.OrderRepository) *CountOrdersUseCase {
+	return &CountOrdersUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("count-orders-usecase", "1.0.0"),
+	}
+}
+
+// Execute returns how many orders match the filter
+func (uc *CountOrdersUseCase) Execute(ctx context.Context, filter repository.OrderFilter) (int64, error) {
+	count, err := uc.orderRepo.CountOrders(ctx, filter)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("status", filter.Status).Error("Failed to count orders")
+		return 0, err // Repository errors are already wrapped
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"status":          filter.Status,
+		"include_deleted": filter.IncludeDeleted,
+		"count":           count,
+	}).Debug("Successfully counted orders")
+
+	return count, nil
+}
@@ -2,88 +2,303 @@ package order
 
 import (
 	"context"
+	"errors"
+	"strings"
+	"time"
+
 	"online-order-management-system/internal/domain/entity"
 	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/pkg/concurrency"
 	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/events"
 	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/tracing"
+	"online-order-management-system/pkg/warnings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 )
 
 // CreateOrderUseCase handles the business logic for creating orders
 type CreateOrderUseCase struct {
-	orderRepo repository.OrderRepository
-	logger    *logger.Logger
+	orderRepo  repository.OrderRepository
+	limiter    *concurrency.Limiter
+	skuPolicy  entity.DuplicateSKUPolicy
+	prodPolicy entity.DuplicateProductPolicy
+	maxPrice   float64
+	maxAmount  float64
+	leadTime   entity.LeadTimeModel
+	publisher  events.EventPublisher
+	dedup      *contentDedup
+	idemTTL    time.Duration
+	logger     *logger.Logger
+}
+
+// CreateOrderOption configures optional behavior of CreateOrderUseCase
+type CreateOrderOption func(*CreateOrderUseCase)
+
+// WithCreateConcurrencyLimiter bounds how many creates hit the database at once.
+// The limiter may be shared with other use cases; a nil limiter disables the bound.
+func WithCreateConcurrencyLimiter(limiter *concurrency.Limiter) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		uc.limiter = limiter
+	}
+}
+
+// WithDuplicateSKUPolicy sets how orders with several items sharing a SKU are handled
+func WithDuplicateSKUPolicy(policy entity.DuplicateSKUPolicy) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		uc.skuPolicy = policy
+	}
+}
+
+// WithDuplicateProductPolicy sets how orders naming the same product on several items are
+// handled (default reject)
+func WithDuplicateProductPolicy(policy entity.DuplicateProductPolicy) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		uc.prodPolicy = policy
+	}
+}
+
+// WithMaxUnitPrice rejects orders with an item priced above max (0 disables the cap)
+func WithMaxUnitPrice(max float64) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		uc.maxPrice = max
+	}
+}
+
+// WithMaxOrderAmount rejects orders whose line or order total exceeds max instead of
+// letting the database fail on them (default entity.DefaultMaxAmount). A non-positive max
+// only guards against overflowing int64 cents.
+func WithMaxOrderAmount(max float64) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		uc.maxAmount = max
+	}
+}
+
+// WithLeadTimeModel sets the model used to estimate when new orders ship
+func WithLeadTimeModel(model entity.LeadTimeModel) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		uc.leadTime = model
+	}
+}
+
+// WithCreateEventPublisher publishes an order.created event for every new order
+func WithCreateEventPublisher(publisher events.EventPublisher) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		uc.publisher = publisher
+	}
+}
+
+// WithContentDedup returns the existing order when an order with the same customer and items
+// is submitted again within window, guarding against double submits without an idempotency
+// key. A non-positive window disables deduplication.
+func WithContentDedup(window time.Duration) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		if window > 0 {
+			uc.dedup = newContentDedup(window)
+		}
+	}
+}
+
+// WithIdempotencyKeyTTL replays the original order when a request reuses an idempotency key
+// within ttl; after that the key counts as new and creates another order. Keys are stored by
+// the repository together with the order, so replays work across instances and restarts.
+// A non-positive ttl ignores idempotency keys.
+func WithIdempotencyKeyTTL(ttl time.Duration) CreateOrderOption {
+	return func(uc *CreateOrderUseCase) {
+		if ttl > 0 {
+			uc.idemTTL = ttl
+		}
+	}
 }
 
 // NewCreateOrderUseCase creates a new CreateOrderUseCase
-func NewCreateOrderUseCase(orderRepo repository.OrderRepository) *CreateOrderUseCase {
-	return &CreateOrderUseCase{
-		orderRepo: orderRepo,
-		logger:    logger.New("create-order-usecase", "1.0.0"),
+func NewCreateOrderUseCase(orderRepo repository.OrderRepository, opts ...CreateOrderOption) *CreateOrderUseCase {
+	uc := &CreateOrderUseCase{
+		orderRepo:  orderRepo,
+		skuPolicy:  entity.DuplicateSKUAllow,
+		prodPolicy: entity.DuplicateProductReject,
+		maxAmount:  entity.DefaultMaxAmount,
+		leadTime:   entity.DefaultLeadTimeModel,
+		logger:     logger.New("create-order-usecase", "1.0.0"),
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 // CreateOrderRequest represents the input for creating an order
 type CreateOrderRequest struct {
-	CustomerName string                   `json:"customer_name" binding:"required"`
-	Items        []CreateOrderItemRequest `json:"items" binding:"required,min=1"`
+	CustomerName    string                   `json:"customer_name" binding:"required"`
+	CustomerEmail   string                   `json:"customer_email,omitempty"`
+	ClientReference string                   `json:"client_reference,omitempty"`
+	Items           []CreateOrderItemRequest `json:"items" binding:"required,min=1"`
+	Paid            bool                     `json:"-"` // Create the order already paid
+	IdempotencyKey  string                   `json:"-"` // Replays the order created with the same key
 }
 
 // CreateOrderItemRequest represents an order item in the request
 type CreateOrderItemRequest struct {
-	ProductName string  `json:"product_name" binding:"required"`
-	Quantity    int     `json:"quantity" binding:"required,min=1"`
-	UnitPrice   float64 `json:"unit_price" binding:"required,min=0"`
+	ProductName     string  `json:"product_name" binding:"required"`
+	SKU             string  `json:"sku,omitempty"`
+	Quantity        float64 `json:"quantity" binding:"required,gt=0"`
+	Unit            string  `json:"unit,omitempty"`
+	UnitPrice       float64 `json:"unit_price" binding:"required,min=0"`
+	DiscountPercent float64 `json:"discount_percent,omitempty"`
 }
 
 // Execute creates a new order
 func (uc *CreateOrderUseCase) Execute(ctx context.Context, req CreateOrderRequest) (*entity.Order, error) {
-	uc.logger.WithFields(map[string]interface{}{
+	created, _, err := uc.ExecuteWithReplay(ctx, req)
+	return created, err
+}
+
+// ExecuteWithReplay creates a new order like Execute and also reports whether the order was
+// replayed for a reused idempotency key instead of created
+func (uc *CreateOrderUseCase) ExecuteWithReplay(ctx context.Context, req CreateOrderRequest) (*entity.Order, bool, error) {
+	ctx, span := tracing.Start(ctx, "CreateOrderUseCase.Execute")
+	defer span.End()
+
+	created, replayed, err := uc.execute(ctx, req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return nil, false, err
+	}
+	span.SetAttributes(attribute.Int64("order.id", created.ID), attribute.Bool("order.replayed", replayed))
+	return created, replayed, nil
+}
+
+// execute runs the creation inside the use case's span
+func (uc *CreateOrderUseCase) execute(ctx context.Context, req CreateOrderRequest) (*entity.Order, bool, error) {
+	// Bulk workers and handlers put the trace ID on ctx, tying these lines to the request
+	log := uc.logger.WithContext(ctx)
+	log.WithFields(map[string]interface{}{
 		"customer_name": req.CustomerName,
 		"items_count":   len(req.Items),
 	}).Info("Starting order creation")
 
 	// Validate request
 	if err := uc.validateCreateOrderRequest(req); err != nil {
-		uc.logger.WithError(err).WithField("customer_name", req.CustomerName).Warn("Invalid order creation request")
-		return nil, err
+		log.WithError(err).WithField("customer_name", req.CustomerName).Warn("Invalid order creation request")
+		return nil, false, err
 	}
 
 	// Convert request items to domain entities
 	items := make([]entity.OrderItem, len(req.Items))
 	for i, item := range req.Items {
 		items[i] = entity.OrderItem{
-			ProductName: item.ProductName,
-			Quantity:    item.Quantity,
-			UnitPrice:   item.UnitPrice,
+			ProductName:     item.ProductName,
+			SKU:             strings.TrimSpace(item.SKU),
+			Quantity:        item.Quantity,
+			Unit:            strings.TrimSpace(item.Unit),
+			UnitPrice:       entity.NewMoney(item.UnitPrice),
+			DiscountPercent: item.DiscountPercent,
 		}
 	}
 
 	// Create order domain entity with business rules validation
-	order, err := entity.NewOrder(req.CustomerName, items)
+	order, err := entity.NewOrder(req.CustomerName, items,
+		entity.WithDuplicateSKUPolicy(uc.skuPolicy),
+		entity.WithDuplicateProductPolicy(uc.prodPolicy),
+		entity.WithMaxUnitPrice(uc.maxPrice),
+		entity.WithMaxAmount(uc.maxAmount),
+		entity.WithCustomerEmail(req.CustomerEmail),
+	)
 	if err != nil {
-		uc.logger.WithError(err).WithField("customer_name", req.CustomerName).Error("Failed to create domain order entity")
-		// Wrap domain errors
-		return nil, apperrors.NewBusinessRuleViolationError(err.Error()).WithCause(err)
+		log.WithError(err).WithField("customer_name", req.CustomerName).Error("Failed to create domain order entity")
+		// Domain errors that are already typed keep their code and details
+		if appErr := apperrors.GetAppError(err); appErr != nil {
+			return nil, false, appErr
+		}
+		return nil, false, apperrors.NewBusinessRuleViolationError(err.Error()).WithCause(err)
 	}
+	order.ClientReference = strings.TrimSpace(req.ClientReference)
+	shipDate := entity.EstimateShipDate(order.CreatedAt, len(order.Items), uc.leadTime)
+	order.EstimatedShipDate = &shipDate
+
+	var createdID int64
 
-	// Persist the order
-	createdOrder, err := uc.orderRepo.CreateOrderWithItems(ctx, order)
+	// Return the existing order for an identical resubmission inside the dedup window
+	if uc.dedup != nil {
+		existingID, finish, err := uc.dedup.claim(ctx, contentHash(order))
+		if err != nil {
+			return nil, false, contextError(err, "request cancelled while waiting for an identical order")
+		}
+		if existingID != 0 {
+			log.WithFields(map[string]interface{}{
+				"order_id":      existingID,
+				"customer_name": req.CustomerName,
+			}).Info("Returning existing order for identical resubmission")
+			warnings.Add(ctx, "identical order submitted within %s; returned existing order %d", uc.dedup.window, existingID)
+			existing, err := uc.orderRepo.GetOrderByID(ctx, existingID)
+			return existing, false, err
+		}
+		defer func() { finish(createdID) }()
+	}
+
+	// Wait for a database slot so bursts queue briefly instead of exhausting the pool
+	release, err := uc.limiter.Acquire(ctx)
+	if err != nil {
+		log.WithError(err).WithFields(map[string]interface{}{
+			"customer_name": req.CustomerName,
+			"limit":         uc.limiter.Size(),
+		}).Warn("Shedding order creation, concurrency limit reached")
+		if errors.Is(err, concurrency.ErrLimitExceeded) {
+			return nil, false, apperrors.NewServiceUnavailableError("server is busy, please retry later").WithCause(err)
+		}
+		return nil, false, contextError(err, "request cancelled while waiting for capacity")
+	}
+	defer release()
+
+	// Persist the order; prepaid orders get their paid status in the same transaction
+	persist := uc.orderRepo.CreateOrderWithItems
+	if req.Paid {
+		persist = uc.orderRepo.CreatePaidOrder
+	}
+	var createdOrder *entity.Order
+	var replayed bool
+	if uc.idemTTL > 0 && req.IdempotencyKey != "" {
+		// The key is checked and recorded in the create's transaction, so concurrent
+		// retries sharing it yield one order
+		createdOrder, replayed, err = uc.orderRepo.CreateOrderIdempotent(ctx, order, req.Paid, req.IdempotencyKey, uc.idemTTL)
+	} else {
+		createdOrder, err = persist(ctx, order)
+	}
 	if err != nil {
-		uc.logger.WithError(err).WithFields(map[string]interface{}{
+		log.WithError(err).WithFields(map[string]interface{}{
 			"customer_name": req.CustomerName,
 			"total_amount":  order.TotalAmount,
 		}).Error("Failed to persist order")
-		return nil, err // Repository errors are already wrapped
+		return nil, false, err // Repository errors are already wrapped
+	}
+	createdID = createdOrder.ID
+
+	if replayed {
+		log.WithFields(map[string]interface{}{
+			"order_id":        createdOrder.ID,
+			"idempotency_key": req.IdempotencyKey,
+		}).Info("Replaying order for reused idempotency key")
+		return createdOrder, true, nil
 	}
 
-	uc.logger.WithFields(map[string]interface{}{
+	log.WithFields(map[string]interface{}{
 		"order_id":      createdOrder.ID,
 		"customer_name": createdOrder.CustomerName,
 		"total_amount":  createdOrder.TotalAmount,
 		"items_count":   len(createdOrder.Items),
 	}).Info("Successfully created order")
 
-	return createdOrder, nil
+	publishEvent(ctx, uc.publisher, log, events.NewEvent(events.TypeOrderCreated, events.OrderCreated{
+		OrderID:      createdOrder.ID,
+		CustomerName: createdOrder.CustomerName,
+		Status:       createdOrder.Status,
+		TotalAmount:  createdOrder.TotalAmount.Float64(),
+	}))
+
+	return createdOrder, false, nil
 }
 
 // validateCreateOrderRequest validates the create order request
@@ -118,3 +333,12 @@ func (uc *CreateOrderUseCase) validateCreateOrderRequest(req CreateOrderRequest)
 
 	return nil
 }
+
+// contextError reports a wait ended by the request context as 499 or 504, and any other
+// failure to wait as a timeout
+func contextError(err error, message string) *apperrors.AppError {
+	if appErr := apperrors.ContextError(err, message); appErr != nil {
+		return appErr
+	}
+	return apperrors.NewTimeoutError(message).WithCause(err)
+}
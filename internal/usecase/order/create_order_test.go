@@ -0,0 +1,252 @@
+package order
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/infra/memory"
+	"online-order-management-system/pkg/concurrency"
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+// slowOrderRepository blocks creates until released and tracks peak concurrency
+type slowOrderRepository struct {
+	*memory.InMemoryOrderRepository
+	unblock  chan struct{}
+	inFlight int64
+	peak     int64
+}
+
+func (r *slowOrderRepository) CreateOrderWithItems(ctx context.Context, order *entity.Order) (*entity.Order, error) {
+	current := atomic.AddInt64(&r.inFlight, 1)
+	defer atomic.AddInt64(&r.inFlight, -1)
+	for {
+		peak := atomic.LoadInt64(&r.peak)
+		if current <= peak || atomic.CompareAndSwapInt64(&r.peak, peak, current) {
+			break
+		}
+	}
+	<-r.unblock
+	return r.InMemoryOrderRepository.CreateOrderWithItems(ctx, order)
+}
+
+func validCreateOrderRequest() CreateOrderRequest {
+	return CreateOrderRequest{
+		CustomerName: "Jane Doe",
+		Items: []CreateOrderItemRequest{
+			{ProductName: "Keyboard", Quantity: 1, UnitPrice: 49.99},
+		},
+	}
+}
+
+func TestCreateOrderUseCase_ConcurrencyLimit(t *testing.T) {
+	repo := &slowOrderRepository{
+		InMemoryOrderRepository: memory.NewInMemoryOrderRepository(),
+		unblock:                 make(chan struct{}),
+	}
+	uc := NewCreateOrderUseCase(repo, WithCreateConcurrencyLimiter(concurrency.NewLimiter(2, 30*time.Millisecond)))
+
+	const callers = 5
+	var wg sync.WaitGroup
+	errs := make(chan error, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := uc.Execute(context.Background(), validCreateOrderRequest())
+			errs <- err
+		}()
+	}
+
+	// Hold the admitted creates until the others have been shed
+	time.Sleep(100 * time.Millisecond)
+	close(repo.unblock)
+	wg.Wait()
+	close(errs)
+
+	var succeeded, shed int
+	for err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case apperrors.GetHTTPStatus(err) == http.StatusServiceUnavailable:
+			shed++
+		default:
+			t.Errorf("unexpected error: %v", err)
+		}
+	}
+
+	if peak := atomic.LoadInt64(&repo.peak); peak > 2 {
+		t.Errorf("expected at most 2 concurrent creates, observed %d", peak)
+	}
+	if succeeded != 2 || shed != 3 {
+		t.Errorf("expected 2 successes and 3 shed requests, got %d and %d", succeeded, shed)
+	}
+}
+
+func TestCreateOrderUseCase_ContentDedup(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+	uc := NewCreateOrderUseCase(repo, WithContentDedup(10*time.Second))
+	now := time.Now()
+	uc.dedup.now = func() time.Time { return now }
+
+	first, err := uc.Execute(ctx, validCreateOrderRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	t.Run("rapid identical resubmit returns the existing order", func(t *testing.T) {
+		resubmit := validCreateOrderRequest()
+		resubmit.CustomerName = "  jane doe "
+		again, err := uc.Execute(ctx, resubmit)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.ID != first.ID {
+			t.Errorf("expected the existing order %d, got new order %d", first.ID, again.ID)
+		}
+	})
+
+	t.Run("slightly different order is created", func(t *testing.T) {
+		different := validCreateOrderRequest()
+		different.Items[0].Quantity = 2
+		other, err := uc.Execute(ctx, different)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if other.ID == first.ID {
+			t.Error("expected a different order to get a new ID")
+		}
+	})
+
+	t.Run("identical order after the window is created", func(t *testing.T) {
+		now = now.Add(11 * time.Second)
+		later, err := uc.Execute(ctx, validCreateOrderRequest())
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if later.ID == first.ID {
+			t.Error("expected a new order once the dedup window has passed")
+		}
+	})
+}
+
+func TestCreateOrderUseCase_IdempotencyKeyTTL(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	repo := memory.NewInMemoryOrderRepository(memory.WithClock(func() time.Time { return now }))
+	uc := NewCreateOrderUseCase(repo, WithIdempotencyKeyTTL(time.Hour))
+
+	req := validCreateOrderRequest()
+	req.IdempotencyKey = "checkout-7f3a"
+	first, replayed, err := uc.ExecuteWithReplay(ctx, req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if replayed {
+		t.Error("expected the first request to create its order")
+	}
+
+	t.Run("replay within the TTL returns the original order", func(t *testing.T) {
+		now = now.Add(59 * time.Minute)
+		again, replayed, err := uc.ExecuteWithReplay(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.ID != first.ID || !replayed {
+			t.Errorf("expected the original order %d replayed, got order %d (replayed %v)", first.ID, again.ID, replayed)
+		}
+	})
+
+	t.Run("same key after the TTL creates a new order", func(t *testing.T) {
+		now = now.Add(2 * time.Minute)
+		later, err := uc.Execute(ctx, req)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if later.ID == first.ID {
+			t.Error("expected a new order once the key has expired")
+		}
+	})
+}
+
+func TestCreateOrderUseCase_IdempotencyKeyConcurrentRequests(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	uc := NewCreateOrderUseCase(repo, WithIdempotencyKeyTTL(time.Hour))
+
+	const callers = 2
+	ids := make(chan int64, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := validCreateOrderRequest()
+			req.IdempotencyKey = "checkout-5d21"
+			created, err := uc.Execute(context.Background(), req)
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			ids <- created.ID
+		}()
+	}
+	wg.Wait()
+	close(ids)
+
+	var got []int64
+	for id := range ids {
+		got = append(got, id)
+	}
+	if len(got) != callers || got[0] != got[1] {
+		t.Errorf("expected both requests to return the same order, got %v", got)
+	}
+	_, pagination, err := repo.ListOrders(context.Background(), 1, 10, repository.OrderFilter{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if pagination.TotalCount != 1 {
+		t.Errorf("expected exactly one persisted order, got %d", pagination.TotalCount)
+	}
+}
+
+func TestCreateOrderUseCase_ContentDedupConcurrentDoubleSubmit(t *testing.T) {
+	repo := &slowOrderRepository{
+		InMemoryOrderRepository: memory.NewInMemoryOrderRepository(),
+		unblock:                 make(chan struct{}),
+	}
+	uc := NewCreateOrderUseCase(repo, WithContentDedup(10*time.Second))
+
+	const callers = 3
+	ids := make(chan int64, callers)
+	var wg sync.WaitGroup
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			created, err := uc.Execute(context.Background(), validCreateOrderRequest())
+			if err != nil {
+				t.Errorf("unexpected error: %v", err)
+				return
+			}
+			ids <- created.ID
+		}()
+	}
+	time.Sleep(20 * time.Millisecond) // Let the duplicates queue behind the first create
+	close(repo.unblock)
+	wg.Wait()
+	close(ids)
+
+	for id := range ids {
+		if id != 1 {
+			t.Errorf("expected every submit to return order 1, got %d", id)
+		}
+	}
+}
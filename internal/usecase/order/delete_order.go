@@ -0,0 +1,73 @@
+package order
+
+import (
+	"context"
+	"errors"
+
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+// DeleteOrderUseCase handles the business logic for deleting orders
+type DeleteOrderUseCase struct {
+	orderRepo  repository.OrderRepository
+	hardDelete bool
+	logger     *logger.Logger
+}
+
+// DeleteOrderOption configures optional behavior of DeleteOrderUseCase
+type DeleteOrderOption func(*DeleteOrderUseCase)
+
+// WithHardDelete removes orders and their items immediately instead of soft-deleting them
+func WithHardDelete(enabled bool) DeleteOrderOption {
+	return func(uc *DeleteOrderUseCase) {
+		uc.hardDelete = enabled
+	}
+}
+
+// NewDeleteOrderUseCase creates a new DeleteOrderUseCase
+func NewDeleteOrderUseCase(orderRepo repository.OrderRepository, opts ...DeleteOrderOption) *DeleteOrderUseCase {
+	uc := &DeleteOrderUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("delete-order-usecase", "1.0.0"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute deletes an order. By default it is soft-deleted and kept so admins can recover it;
+// with hard delete it is removed permanently. Completed orders are immutable and cannot be deleted.
+func (uc *DeleteOrderUseCase) Execute(ctx context.Context, id int64) error {
+	if id <= 0 {
+		uc.logger.WithContext(ctx).WithField("order_id", id).Warn("Invalid order ID")
+		return apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
+			"provided_id": id,
+		})
+	}
+
+	// The repository checks the completed-order rule in the same statement as the delete,
+	// so an order completed concurrently cannot slip through
+	var err error
+	if uc.hardDelete {
+		err = uc.orderRepo.DeleteOrder(ctx, id)
+	} else {
+		err = uc.orderRepo.SoftDeleteOrder(ctx, id)
+	}
+	if errors.Is(err, apperrors.ErrBusinessRuleViolation) {
+		uc.logger.WithContext(ctx).WithField("order_id", id).Warn("Rejected deletion of completed order")
+		return err
+	}
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to delete order")
+		return err // Repository errors are already wrapped
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"order_id":    id,
+		"hard_delete": uc.hardDelete,
+	}).Info("Successfully deleted order")
+	return nil
+}
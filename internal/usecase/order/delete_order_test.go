@@ -0,0 +1,59 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/infra/memory"
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func TestDeleteOrderUseCase(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name        string
+		hardDelete  bool
+		statuses    []string // Transitions applied before deleting
+		wantCode    apperrors.ErrorCode
+		wantVisible bool // Still listed with IncludeDeleted after the delete
+	}{
+		{name: "soft delete keeps the row", wantVisible: true},
+		{name: "hard delete removes the row", hardDelete: true, statuses: []string{"processing"}},
+		{name: "completed orders are immutable", statuses: []string{"processing", "completed"}, wantCode: apperrors.ErrCodeBusinessRuleViolation, wantVisible: true},
+		{name: "completed orders are not hard-deleted", hardDelete: true, statuses: []string{"processing", "completed"}, wantCode: apperrors.ErrCodeBusinessRuleViolation, wantVisible: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := memory.NewInMemoryOrderRepository()
+			created, err := NewCreateOrderUseCase(repo).Execute(ctx, validCreateOrderRequest())
+			if err != nil {
+				t.Fatalf("unexpected error creating order: %v", err)
+			}
+			for _, status := range tt.statuses {
+				if _, err := repo.UpdateOrderStatus(ctx, created.ID, status); err != nil {
+					t.Fatalf("unexpected error updating status to %s: %v", status, err)
+				}
+			}
+
+			err = NewDeleteOrderUseCase(repo, WithHardDelete(tt.hardDelete)).Execute(ctx, created.ID)
+			if tt.wantCode != "" {
+				if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != tt.wantCode {
+					t.Fatalf("expected %s, got %v", tt.wantCode, err)
+				}
+			} else if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			orders, _, err := repo.ListOrders(ctx, 1, 10, repository.OrderFilter{IncludeDeleted: true})
+			if err != nil {
+				t.Fatalf("unexpected error listing orders: %v", err)
+			}
+			if visible := len(orders) == 1; visible != tt.wantVisible {
+				t.Errorf("expected order stored=%v after delete, got %v", tt.wantVisible, visible)
+			}
+		})
+	}
+}
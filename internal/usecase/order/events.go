@@ -0,0 +1,24 @@
+package order
+
+import (
+	"context"
+
+	"online-order-management-system/pkg/dryrun"
+	"online-order-management-system/pkg/events"
+	"online-order-management-system/pkg/logger"
+)
+
+// publishEvent hands the event to the publisher, if any. Publishing failures are logged
+// but never fail the order operation that already succeeded. A dry run changed nothing, so
+// it publishes nothing either.
+func publishEvent(ctx context.Context, publisher events.EventPublisher, log *logger.Logger, event events.Event) {
+	if publisher == nil || dryrun.Enabled(ctx) {
+		return
+	}
+	if err := publisher.Publish(ctx, event); err != nil {
+		log.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
+			"event_id":   event.ID,
+			"event_type": event.Type,
+		}).Error("Failed to publish event")
+	}
+}
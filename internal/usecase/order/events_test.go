@@ -0,0 +1,134 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"online-order-management-system/internal/infra/memory"
+	"online-order-management-system/pkg/events"
+)
+
+// recordingPublisher records every published event and fails with err, if set
+type recordingPublisher struct {
+	mu     sync.Mutex
+	events []events.Event
+	err    error
+}
+
+func (p *recordingPublisher) Publish(ctx context.Context, event events.Event) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return p.err
+}
+
+func TestCreateOrderUseCase_PublishesOrderCreated(t *testing.T) {
+	publisher := &recordingPublisher{}
+	uc := NewCreateOrderUseCase(memory.NewInMemoryOrderRepository(), WithCreateEventPublisher(publisher))
+
+	created, err := uc.Execute(context.Background(), validCreateOrderRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(publisher.events))
+	}
+	event := publisher.events[0]
+	want := events.OrderCreated{OrderID: created.ID, CustomerName: "Jane Doe", Status: "pending", TotalAmount: 49.99}
+	if event.Type != events.TypeOrderCreated || event.Data != want || event.ID == "" {
+		t.Errorf("expected %s with %+v, got %s with %+v", events.TypeOrderCreated, want, event.Type, event.Data)
+	}
+}
+
+func TestUpdateOrderStatusUseCase_PublishesOrderStatusChanged(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+	created, err := NewCreateOrderUseCase(repo).Execute(ctx, validCreateOrderRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	publisher := &recordingPublisher{}
+	uc := NewUpdateOrderStatusUseCase(repo, WithStatusEventPublisher(publisher))
+	if _, err := uc.Execute(ctx, created.ID, "processing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Repeating the current status changes nothing and publishes nothing
+	if _, err := uc.Execute(ctx, created.ID, "processing"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(publisher.events) != 1 {
+		t.Fatalf("expected one event, got %d", len(publisher.events))
+	}
+	want := events.OrderStatusChanged{OrderID: created.ID, OldStatus: "pending", NewStatus: "processing"}
+	if event := publisher.events[0]; event.Type != events.TypeOrderStatusChanged || event.Data != want {
+		t.Errorf("expected %s with %+v, got %s with %+v", events.TypeOrderStatusChanged, want, event.Type, event.Data)
+	}
+}
+
+// concurrentStatusRepository applies each status update once on its own first, as a
+// concurrent request would, so the caller's own update finds nothing to change
+type concurrentStatusRepository struct {
+	*memory.InMemoryOrderRepository
+}
+
+func (r concurrentStatusRepository) UpdateOrderStatus(ctx context.Context, id int64, status string) (bool, error) {
+	if _, err := r.InMemoryOrderRepository.UpdateOrderStatus(ctx, id, status); err != nil {
+		return false, err
+	}
+	return r.InMemoryOrderRepository.UpdateOrderStatus(ctx, id, status)
+}
+
+func TestPatchOrderUseCase_PublishesOnlyWhenStatusChanged(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+	created, err := NewCreateOrderUseCase(repo).Execute(ctx, validCreateOrderRequest())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	processing, cancelled := "processing", "cancelled"
+
+	publisher := &recordingPublisher{}
+	result, err := NewPatchOrderUseCase(repo, WithPatchEventPublisher(publisher)).Execute(ctx, created.ID, OrderPatch{Status: &processing})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.ChangedFields) != 1 || len(publisher.events) != 1 {
+		t.Fatalf("expected status changed with one event, got %v and %d events", result.ChangedFields, len(publisher.events))
+	}
+
+	// Another request cancels the order between the patch's read and its write
+	racing := NewPatchOrderUseCase(concurrentStatusRepository{repo}, WithPatchEventPublisher(publisher))
+	result, err = racing.Execute(ctx, created.ID, OrderPatch{Status: &cancelled})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.ChangedFields) != 0 {
+		t.Errorf("expected no changed fields when the status was already set, got %v", result.ChangedFields)
+	}
+	if len(publisher.events) != 1 {
+		t.Errorf("expected no event for a status set by another request, got %d events", len(publisher.events))
+	}
+}
+
+func TestPublishFailureDoesNotFailTheOperation(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+	publisher := &recordingPublisher{err: errors.New("broker unavailable")}
+
+	created, err := NewCreateOrderUseCase(repo, WithCreateEventPublisher(publisher)).Execute(ctx, validCreateOrderRequest())
+	if err != nil {
+		t.Fatalf("expected the create to succeed despite the publish failure, got %v", err)
+	}
+	changed, err := NewUpdateOrderStatusUseCase(repo, WithStatusEventPublisher(publisher)).Execute(ctx, created.ID, "paid")
+	if err != nil || !changed {
+		t.Fatalf("expected the status update to succeed despite the publish failure, got %v", err)
+	}
+	if len(publisher.events) != 2 {
+		t.Errorf("expected both events to be attempted, got %d", len(publisher.events))
+	}
+}
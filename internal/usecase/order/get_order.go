@@ -24,10 +24,10 @@ func NewGetOrderUseCase(orderRepo repository.OrderRepository) *GetOrderUseCase {
 
 // Execute retrieves an order by its ID
 func (uc *GetOrderUseCase) Execute(ctx context.Context, id int64) (*entity.Order, error) {
-	uc.logger.WithField("order_id", id).Debug("Starting order retrieval")
+	uc.logger.WithContext(ctx).WithField("order_id", id).Debug("Starting order retrieval")
 
 	if id <= 0 {
-		uc.logger.WithField("order_id", id).Warn("Invalid order ID")
+		uc.logger.WithContext(ctx).WithField("order_id", id).Warn("Invalid order ID")
 		return nil, apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
 			"provided_id": id,
 		})
@@ -35,11 +35,11 @@ func (uc *GetOrderUseCase) Execute(ctx context.Context, id int64) (*entity.Order
 
 	order, err := uc.orderRepo.GetOrderByID(ctx, id)
 	if err != nil {
-		uc.logger.WithError(err).WithField("order_id", id).Error("Failed to retrieve order")
+		uc.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to retrieve order")
 		return nil, err // Repository errors are already wrapped
 	}
 
-	uc.logger.WithFields(map[string]interface{}{
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"order_id":      order.ID,
 		"customer_name": order.CustomerName,
 		"status":        order.Status,
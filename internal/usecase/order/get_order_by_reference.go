@@ -0,0 +1,47 @@
+package order
+
+import (
+	"context"
+	"strings"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+// GetOrderByReferenceUseCase handles looking up orders by the client's own reference
+type GetOrderByReferenceUseCase struct {
+	orderRepo repository.OrderRepository
+	logger    *logger.Logger
+}
+
+// NewGetOrderByReferenceUseCase creates a new GetOrderByReferenceUseCase
+func NewGetOrderByReferenceUseCase(orderRepo repository.OrderRepository) *GetOrderByReferenceUseCase {
+	return &GetOrderByReferenceUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("get-order-by-reference-usecase", "1.0.0"),
+	}
+}
+
+// Execute retrieves the most recent order carrying the client reference
+func (uc *GetOrderByReferenceUseCase) Execute(ctx context.Context, reference string) (*entity.Order, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		uc.logger.WithContext(ctx).Warn("Empty client reference")
+		return nil, apperrors.NewInvalidOperationError("client reference is required")
+	}
+
+	order, err := uc.orderRepo.GetOrderByClientReference(ctx, reference)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("client_reference", reference).Error("Failed to retrieve order by client reference")
+		return nil, err // Repository errors are already wrapped
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"order_id":         order.ID,
+		"client_reference": reference,
+	}).Debug("Successfully retrieved order by client reference")
+
+	return order, nil
+}
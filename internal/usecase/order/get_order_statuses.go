@@ -0,0 +1,59 @@
+package order
+
+import (
+	"context"
+
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+// MaxStatusLookupIDs is the largest number of order IDs accepted in one status lookup
+const MaxStatusLookupIDs = 1000
+
+// GetOrderStatusesUseCase looks up the statuses of many orders without loading them fully
+type GetOrderStatusesUseCase struct {
+	orderRepo repository.OrderRepository
+	logger    *logger.Logger
+}
+
+// NewGetOrderStatusesUseCase creates a new GetOrderStatusesUseCase
+func NewGetOrderStatusesUseCase(orderRepo repository.OrderRepository) *GetOrderStatusesUseCase {
+	return &GetOrderStatusesUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("get-order-statuses-usecase", "1.0.0"),
+	}
+}
+
+// Execute returns the status of every existing order among ids; missing IDs are omitted
+func (uc *GetOrderStatusesUseCase) Execute(ctx context.Context, ids []int64) (map[int64]string, error) {
+	if len(ids) == 0 {
+		return nil, apperrors.NewInvalidOperationError("at least one order ID is required")
+	}
+	if len(ids) > MaxStatusLookupIDs {
+		return nil, apperrors.NewInvalidOperationError("too many order IDs in one request").WithDetails(map[string]interface{}{
+			"ids_count": len(ids),
+			"max_ids":   MaxStatusLookupIDs,
+		})
+	}
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
+				"provided_id": id,
+			})
+		}
+	}
+
+	statuses, err := uc.orderRepo.GetStatuses(ctx, ids)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("ids_count", len(ids)).Error("Failed to get order statuses")
+		return nil, err // Repository errors are already wrapped
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"ids_count":   len(ids),
+		"found_count": len(statuses),
+	}).Debug("Successfully retrieved order statuses")
+
+	return statuses, nil
+}
@@ -0,0 +1,96 @@
+package order
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+// Timeline event types
+const (
+	TimelineEventCreated       = "created"
+	TimelineEventStatusChanged = "status_changed"
+)
+
+// TimelineEvent represents a single entry in an order's lifecycle timeline
+type TimelineEvent struct {
+	Type      string                 `json:"type"`
+	Timestamp time.Time              `json:"timestamp"`
+	Payload   map[string]interface{} `json:"payload,omitempty"`
+}
+
+// GetOrderTimelineUseCase handles the business logic for building an order's lifecycle timeline
+type GetOrderTimelineUseCase struct {
+	orderRepo repository.OrderRepository
+	logger    *logger.Logger
+}
+
+// NewGetOrderTimelineUseCase creates a new GetOrderTimelineUseCase
+func NewGetOrderTimelineUseCase(orderRepo repository.OrderRepository) *GetOrderTimelineUseCase {
+	return &GetOrderTimelineUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("get-order-timeline-usecase", "1.0.0"),
+	}
+}
+
+// Execute returns every recorded event of an order merged into chronological order
+func (uc *GetOrderTimelineUseCase) Execute(ctx context.Context, id int64) ([]TimelineEvent, error) {
+	uc.logger.WithContext(ctx).WithField("order_id", id).Debug("Starting order timeline retrieval")
+
+	if id <= 0 {
+		uc.logger.WithContext(ctx).WithField("order_id", id).Warn("Invalid order ID")
+		return nil, apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
+			"provided_id": id,
+		})
+	}
+
+	order, err := uc.orderRepo.GetOrderByID(ctx, id)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to retrieve order")
+		return nil, err // Repository errors are already wrapped
+	}
+
+	history, err := uc.orderRepo.GetStatusHistory(ctx, id)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to retrieve status history")
+		return nil, err // Repository errors are already wrapped
+	}
+
+	events := make([]TimelineEvent, 0, len(history)+1)
+	events = append(events, TimelineEvent{
+		Type:      TimelineEventCreated,
+		Timestamp: order.CreatedAt,
+		Payload: map[string]interface{}{
+			"customer_name": order.CustomerName,
+			"total_amount":  order.TotalAmount,
+			"items_count":   len(order.Items),
+		},
+	})
+
+	for _, change := range history {
+		events = append(events, TimelineEvent{
+			Type:      TimelineEventStatusChanged,
+			Timestamp: change.ChangedAt,
+			Payload: map[string]interface{}{
+				"from_status": change.FromStatus,
+				"to_status":   change.ToStatus,
+			},
+		})
+	}
+
+	// Stable sort keeps the source order for events sharing a timestamp
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].Timestamp.Before(events[j].Timestamp)
+	})
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"order_id":     id,
+		"events_count": len(events),
+	}).Debug("Successfully built order timeline")
+
+	return events, nil
+}
@@ -0,0 +1,64 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/infra/memory"
+)
+
+func TestGetOrderTimelineUseCase_MergesEventsInTimeOrder(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+
+	newOrder, err := entity.NewOrder("Jane Doe", []entity.OrderItem{
+		{ProductName: "Keyboard", Quantity: 1, UnitPrice: entity.NewMoney(49.99)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating order: %v", err)
+	}
+	newOrder.CreatedAt = time.Now().Add(-time.Hour)
+
+	created, err := repo.CreateOrderWithItems(ctx, newOrder)
+	if err != nil {
+		t.Fatalf("unexpected error persisting order: %v", err)
+	}
+	if _, err := repo.UpdateOrderStatus(ctx, created.ID, "processing"); err != nil {
+		t.Fatalf("unexpected error updating status: %v", err)
+	}
+	if _, err := repo.UpdateOrderStatus(ctx, created.ID, "completed"); err != nil {
+		t.Fatalf("unexpected error updating status: %v", err)
+	}
+
+	events, err := NewGetOrderTimelineUseCase(repo).Execute(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if len(events) != 3 {
+		t.Fatalf("expected 3 events, got %d", len(events))
+	}
+	if events[0].Type != TimelineEventCreated {
+		t.Errorf("expected first event to be %q, got %q", TimelineEventCreated, events[0].Type)
+	}
+	if events[1].Type != TimelineEventStatusChanged || events[1].Payload["to_status"] != "processing" {
+		t.Errorf("expected second event to be the change to processing, got %+v", events[1])
+	}
+	if events[2].Payload["from_status"] != "processing" || events[2].Payload["to_status"] != "completed" {
+		t.Errorf("expected third event to be processing -> completed, got %+v", events[2])
+	}
+	for i := 1; i < len(events); i++ {
+		if events[i].Timestamp.Before(events[i-1].Timestamp) {
+			t.Errorf("events are not in chronological order at index %d", i)
+		}
+	}
+}
+
+func TestGetOrderTimelineUseCase_OrderNotFound(t *testing.T) {
+	_, err := NewGetOrderTimelineUseCase(memory.NewInMemoryOrderRepository()).Execute(context.Background(), 42)
+	if err == nil {
+		t.Fatal("expected an error for a missing order")
+	}
+}
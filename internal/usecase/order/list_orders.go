@@ -5,20 +5,38 @@ import (
 	"online-order-management-system/internal/domain/entity"
 	"online-order-management-system/internal/domain/repository"
 	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/warnings"
 )
 
 // ListOrdersUseCase handles the business logic for listing orders
 type ListOrdersUseCase struct {
-	orderRepo repository.OrderRepository
-	logger    *logger.Logger
+	orderRepo  repository.OrderRepository
+	itemBudget int
+	logger     *logger.Logger
+}
+
+// ListOrdersOption configures optional behavior of ListOrdersUseCase
+type ListOrdersOption func(*ListOrdersUseCase)
+
+// WithItemBudget caps the total number of items a single page should carry. When the
+// orders of a page exceed it, the effective limit is reduced to the orders that fit
+// (at least one) and a warning is reported. Zero or less disables the budget.
+func WithItemBudget(items int) ListOrdersOption {
+	return func(uc *ListOrdersUseCase) {
+		uc.itemBudget = items
+	}
 }
 
 // NewListOrdersUseCase creates a new ListOrdersUseCase
-func NewListOrdersUseCase(orderRepo repository.OrderRepository) *ListOrdersUseCase {
-	return &ListOrdersUseCase{
+func NewListOrdersUseCase(orderRepo repository.OrderRepository, opts ...ListOrdersOption) *ListOrdersUseCase {
+	uc := &ListOrdersUseCase{
 		orderRepo: orderRepo,
 		logger:    logger.New("list-orders-usecase", "1.0.0"),
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 // ListOrdersResponse represents the response for listing orders
@@ -27,11 +45,20 @@ type ListOrdersResponse struct {
 	Pagination *repository.PaginationInfo `json:"pagination"`
 }
 
-// Execute retrieves orders with pagination
-func (uc *ListOrdersUseCase) Execute(ctx context.Context, page int, limit int) (*ListOrdersResponse, error) {
-	uc.logger.WithFields(map[string]interface{}{
-		"page":  page,
-		"limit": limit,
+// ListOrdersCursorResponse represents one keyset page of orders
+type ListOrdersCursorResponse struct {
+	Orders     []*entity.Order
+	Limit      int
+	NextCursor int64 // 0 when no orders remain
+}
+
+// Execute retrieves orders matching the filter with pagination
+func (uc *ListOrdersUseCase) Execute(ctx context.Context, page int, limit int, filter repository.OrderFilter) (*ListOrdersResponse, error) {
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"page":            page,
+		"limit":           limit,
+		"status":          filter.Status,
+		"include_deleted": filter.IncludeDeleted,
 	}).Debug("Starting orders listing")
 
 	// Validate and normalize pagination parameters
@@ -42,20 +69,11 @@ func (uc *ListOrdersUseCase) Execute(ctx context.Context, page int, limit int) (
 		page = 1
 	}
 
-	// Set default limit if not provided or invalid
-	if limit <= 0 {
-		limit = 10
-	}
-
-	// Set maximum limit to prevent abuse
-	const maxLimit = 100
-	if limit > maxLimit {
-		limit = maxLimit
-	}
+	limit = normalizeListLimit(ctx, limit)
 
 	// Log parameter adjustments if any
 	if page != originalPage || limit != originalLimit {
-		uc.logger.WithFields(map[string]interface{}{
+		uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
 			"original_page":  originalPage,
 			"original_limit": originalLimit,
 			"adjusted_page":  page,
@@ -63,21 +81,36 @@ func (uc *ListOrdersUseCase) Execute(ctx context.Context, page int, limit int) (
 		}).Debug("Adjusted pagination parameters")
 	}
 
-	orders, paginationInfo, err := uc.orderRepo.ListOrders(ctx, page, limit)
+	orders, paginationInfo, err := uc.orderRepo.ListOrders(ctx, page, limit, filter)
 	if err != nil {
-		uc.logger.WithError(err).WithFields(map[string]interface{}{
+		uc.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
 			"page":  page,
 			"limit": limit,
 		}).Error("Failed to list orders")
 		return nil, err // Repository errors are already wrapped
 	}
 
+	// Refetch the page with the reduced limit so pagination stays consistent with it
+	if fitted := uc.fitItemBudget(orders); fitted < len(orders) {
+		uc.warnItemBudget(ctx, limit, fitted)
+		limit = fitted
+
+		orders, paginationInfo, err = uc.orderRepo.ListOrders(ctx, page, limit, filter)
+		if err != nil {
+			uc.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
+				"page":  page,
+				"limit": limit,
+			}).Error("Failed to list orders")
+			return nil, err
+		}
+	}
+
 	response := &ListOrdersResponse{
 		Orders:     orders,
 		Pagination: paginationInfo,
 	}
 
-	uc.logger.WithFields(map[string]interface{}{
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"page":         page,
 		"limit":        limit,
 		"orders_count": len(orders),
@@ -87,3 +120,87 @@ func (uc *ListOrdersUseCase) Execute(ctx context.Context, page int, limit int) (
 
 	return response, nil
 }
+
+// ExecuteAfter retrieves the orders following cursor using keyset pagination.
+// A cursor of 0 starts from the newest order.
+func (uc *ListOrdersUseCase) ExecuteAfter(ctx context.Context, cursor int64, limit int, filter repository.OrderFilter) (*ListOrdersCursorResponse, error) {
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"cursor":          cursor,
+		"limit":           limit,
+		"status":          filter.Status,
+		"include_deleted": filter.IncludeDeleted,
+	}).Debug("Starting cursor orders listing")
+
+	limit = normalizeListLimit(ctx, limit)
+
+	orders, nextCursor, err := uc.orderRepo.ListOrdersAfter(ctx, cursor, limit, filter)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
+			"cursor": cursor,
+			"limit":  limit,
+		}).Error("Failed to list orders after cursor")
+		return nil, err // Repository errors are already wrapped
+	}
+
+	// Keyset pages can simply be cut short; the cursor resumes after the last order kept
+	if fitted := uc.fitItemBudget(orders); fitted < len(orders) {
+		uc.warnItemBudget(ctx, limit, fitted)
+		limit = fitted
+		orders = orders[:fitted]
+		nextCursor = orders[fitted-1].ID
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"cursor":       cursor,
+		"limit":        limit,
+		"next_cursor":  nextCursor,
+		"orders_count": len(orders),
+	}).Debug("Successfully listed orders after cursor")
+
+	return &ListOrdersCursorResponse{
+		Orders:     orders,
+		Limit:      limit,
+		NextCursor: nextCursor,
+	}, nil
+}
+
+// normalizeListLimit applies the default page size and caps it to prevent abuse
+func normalizeListLimit(ctx context.Context, limit int) int {
+	// Set default limit if not provided or invalid
+	if limit <= 0 {
+		return 10
+	}
+
+	const maxLimit = 100
+	if limit > maxLimit {
+		warnings.Add(ctx, "limit %d exceeds the maximum of %d; at most %d orders are returned", limit, maxLimit, maxLimit)
+		return maxLimit
+	}
+	return limit
+}
+
+// fitItemBudget returns how many leading orders fit in the item budget, at least one
+func (uc *ListOrdersUseCase) fitItemBudget(orders []*entity.Order) int {
+	if uc.itemBudget <= 0 {
+		return len(orders)
+	}
+
+	items := 0
+	for i, order := range orders {
+		items += len(order.Items)
+		if items > uc.itemBudget {
+			return max(i, 1)
+		}
+	}
+	return len(orders)
+}
+
+// warnItemBudget reports a limit reduced because of item fan-out
+func (uc *ListOrdersUseCase) warnItemBudget(ctx context.Context, limit int, fitted int) {
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"item_budget":     uc.itemBudget,
+		"requested_limit": limit,
+		"effective_limit": fitted,
+	}).Info("Reduced list limit to stay within the item budget")
+	warnings.Add(ctx, "orders exceed the budget of %d items per page; limit reduced from %d to %d", uc.itemBudget, limit, fitted)
+}
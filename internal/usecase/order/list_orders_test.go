@@ -0,0 +1,88 @@
+package order
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/infra/memory"
+	"online-order-management-system/pkg/warnings"
+)
+
+// seedLargeOrders stores count orders carrying itemsPerOrder items each
+func seedLargeOrders(t *testing.T, repo repository.OrderRepository, count int, itemsPerOrder int) {
+	t.Helper()
+
+	createUC := NewCreateOrderUseCase(repo)
+	for i := 0; i < count; i++ {
+		req := CreateOrderRequest{CustomerName: "Bulk Buyer"}
+		for j := 0; j < itemsPerOrder; j++ {
+			req.Items = append(req.Items, CreateOrderItemRequest{ProductName: fmt.Sprintf("Part %d", j), Quantity: 1, UnitPrice: 1})
+		}
+		if _, err := createUC.Execute(context.Background(), req); err != nil {
+			t.Fatalf("unexpected error creating order: %v", err)
+		}
+	}
+}
+
+func TestListOrdersUseCase_ItemBudget(t *testing.T) {
+	repo := memory.NewInMemoryOrderRepository()
+	seedLargeOrders(t, repo, 10, 50)
+	uc := NewListOrdersUseCase(repo, WithItemBudget(120))
+
+	t.Run("page limit reduced", func(t *testing.T) {
+		ctx, collected := warnings.WithCollector(context.Background())
+		result, err := uc.Execute(ctx, 1, 10, repository.OrderFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Orders) != 2 || result.Pagination.ItemsPerPage != 2 || result.Pagination.TotalPages != 5 {
+			t.Errorf("expected 2 orders per page over 5 pages, got %d orders, %d per page, %d pages",
+				len(result.Orders), result.Pagination.ItemsPerPage, result.Pagination.TotalPages)
+		}
+		if list := collected.List(); len(list) != 1 || !strings.Contains(list[0], "limit reduced from 10 to 2") {
+			t.Errorf("expected a reduced-limit warning, got %v", list)
+		}
+	})
+
+	t.Run("cursor page cut short", func(t *testing.T) {
+		ctx, collected := warnings.WithCollector(context.Background())
+		result, err := uc.ExecuteAfter(ctx, 0, 10, repository.OrderFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Orders) != 2 || result.NextCursor != result.Orders[1].ID {
+			t.Errorf("expected 2 orders resuming after the last one, got %d orders and cursor %d", len(result.Orders), result.NextCursor)
+		}
+		if len(collected.List()) != 1 {
+			t.Errorf("expected a reduced-limit warning, got %v", collected.List())
+		}
+	})
+
+	t.Run("at least one order per page", func(t *testing.T) {
+		ctx, collected := warnings.WithCollector(context.Background())
+		result, err := NewListOrdersUseCase(repo, WithItemBudget(10)).Execute(ctx, 1, 10, repository.OrderFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Orders) != 1 || len(collected.List()) != 1 {
+			t.Errorf("expected a single order and a warning, got %d orders and %v", len(result.Orders), collected.List())
+		}
+	})
+
+	t.Run("small orders untouched", func(t *testing.T) {
+		repo := memory.NewInMemoryOrderRepository()
+		seedLargeOrders(t, repo, 10, 1)
+
+		ctx, collected := warnings.WithCollector(context.Background())
+		result, err := NewListOrdersUseCase(repo, WithItemBudget(120)).Execute(ctx, 1, 10, repository.OrderFilter{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Orders) != 10 || len(collected.List()) != 0 {
+			t.Errorf("expected the full page without warnings, got %d orders and %v", len(result.Orders), collected.List())
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package order
+
+import (
+	"context"
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/events"
+	"online-order-management-system/pkg/logger"
+)
+
+// PatchOrderUseCase applies partial updates to the mutable fields of an order
+type PatchOrderUseCase struct {
+	orderRepo repository.OrderRepository
+	publisher events.EventPublisher
+	logger    *logger.Logger
+}
+
+// PatchOrderOption configures optional behavior of PatchOrderUseCase
+type PatchOrderOption func(*PatchOrderUseCase)
+
+// WithPatchEventPublisher publishes an order.status_changed event when a patch changes the status
+func WithPatchEventPublisher(publisher events.EventPublisher) PatchOrderOption {
+	return func(uc *PatchOrderUseCase) {
+		uc.publisher = publisher
+	}
+}
+
+// NewPatchOrderUseCase creates a new PatchOrderUseCase
+func NewPatchOrderUseCase(orderRepo repository.OrderRepository, opts ...PatchOrderOption) *PatchOrderUseCase {
+	uc := &PatchOrderUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("patch-order-usecase", "1.0.0"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// OrderPatch holds the mutable order fields a client asked to change; nil means omitted
+type OrderPatch struct {
+	Status *string
+}
+
+// PatchOrderResult is the order after the patch and the names of the fields that changed
+type PatchOrderResult struct {
+	Order         *entity.Order
+	ChangedFields []string
+}
+
+// Execute applies the patch. Fields already holding the requested value are not rewritten
+// and are not reported as changed.
+func (uc *PatchOrderUseCase) Execute(ctx context.Context, id int64, patch OrderPatch) (*PatchOrderResult, error) {
+	if id <= 0 {
+		uc.logger.WithContext(ctx).WithField("order_id", id).Warn("Invalid order ID")
+		return nil, apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
+			"provided_id": id,
+		})
+	}
+
+	order, err := uc.orderRepo.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err // Repository errors are already wrapped
+	}
+
+	var changed []string
+	if patch.Status != nil && *patch.Status != order.Status {
+		if !entity.IsValidStatus(*patch.Status) {
+			return nil, apperrors.NewBusinessRuleViolationError("invalid order status").WithDetails(map[string]interface{}{
+				"provided_status": *patch.Status,
+				"valid_statuses":  entity.ValidStatuses,
+			})
+		}
+		statusChanged, err := uc.orderRepo.UpdateOrderStatus(ctx, id, *patch.Status)
+		if err != nil {
+			uc.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
+				"order_id": id,
+				"status":   *patch.Status,
+			}).Error("Failed to patch order status")
+			return nil, err
+		}
+		// A concurrent request may have set the status since the read; then nothing changed here
+		if statusChanged {
+			changed = append(changed, "status")
+			publishEvent(ctx, uc.publisher, uc.logger, events.NewEvent(events.TypeOrderStatusChanged, events.OrderStatusChanged{
+				OrderID:   id,
+				OldStatus: order.Status,
+				NewStatus: *patch.Status,
+			}))
+		}
+	}
+
+	if len(changed) > 0 {
+		// Re-read so the caller sees the timestamps written by the repository
+		if order, err = uc.orderRepo.GetOrderByID(ctx, id); err != nil {
+			return nil, err
+		}
+		uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"order_id":       id,
+			"changed_fields": changed,
+		}).Info("Successfully patched order")
+	}
+
+	return &PatchOrderResult{Order: order, ChangedFields: changed}, nil
+}
@@ -0,0 +1,112 @@
+package order
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/pkg/logger"
+)
+
+// DeletedOrderReaper periodically hard-deletes orders that have been soft-deleted for
+// longer than the retention period, giving a grace window in which they can be recovered
+type DeletedOrderReaper struct {
+	orderRepo repository.OrderRepository
+	retention time.Duration
+	interval  time.Duration
+	now       func() time.Time
+	logger    *logger.Logger
+
+	cancel context.CancelFunc
+	done   chan struct{}
+	once   sync.Once
+}
+
+// ReaperOption configures optional behavior of DeletedOrderReaper
+type ReaperOption func(*DeletedOrderReaper)
+
+// WithReaperClock replaces time.Now when computing the purge cutoff
+func WithReaperClock(now func() time.Time) ReaperOption {
+	return func(r *DeletedOrderReaper) {
+		r.now = now
+	}
+}
+
+// NewDeletedOrderReaper creates a reaper purging orders soft-deleted more than retention ago,
+// checking every interval. A non-positive retention returns nil, which disables purging.
+func NewDeletedOrderReaper(orderRepo repository.OrderRepository, retention, interval time.Duration, opts ...ReaperOption) *DeletedOrderReaper {
+	if retention <= 0 {
+		return nil
+	}
+	if interval <= 0 {
+		interval = time.Hour
+	}
+	r := &DeletedOrderReaper{
+		orderRepo: orderRepo,
+		retention: retention,
+		interval:  interval,
+		now:       time.Now,
+		logger:    logger.New("deleted-order-reaper", "1.0.0"),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+	return r
+}
+
+// RunOnce purges the orders whose grace period has expired and returns how many were removed
+func (r *DeletedOrderReaper) RunOnce(ctx context.Context) (int64, error) {
+	cutoff := r.now().Add(-r.retention)
+
+	purged, err := r.orderRepo.PurgeDeletedOrders(ctx, cutoff)
+	if err != nil {
+		r.logger.WithError(err).WithField("deleted_before", cutoff).Error("Failed to purge deleted orders")
+		return 0, err
+	}
+
+	if purged > 0 {
+		r.logger.WithFields(map[string]interface{}{
+			"purged_count":   purged,
+			"deleted_before": cutoff,
+		}).Info("Purged soft-deleted orders")
+	}
+	return purged, nil
+}
+
+// Start purges immediately and then every interval until Stop is called or ctx is cancelled
+func (r *DeletedOrderReaper) Start(ctx context.Context) {
+	if r == nil {
+		return
+	}
+
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.done = make(chan struct{})
+
+	go func() {
+		defer close(r.done)
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			_, _ = r.RunOnce(ctx) // Failures are logged and retried on the next tick
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the background loop and waits for an in-flight purge to finish
+func (r *DeletedOrderReaper) Stop() {
+	if r == nil || r.cancel == nil {
+		return
+	}
+	r.once.Do(func() {
+		r.cancel()
+		<-r.done
+	})
+}
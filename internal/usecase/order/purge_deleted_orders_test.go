@@ -0,0 +1,70 @@
+package order
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/internal/infra/memory"
+)
+
+func TestDeletedOrderReaper_PurgesOnlyExpiredOrders(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+	clock := func() time.Time { return now }
+	repo := memory.NewInMemoryOrderRepository(memory.WithClock(clock))
+
+	createDeleted := func() int64 {
+		newOrder, err := entity.NewOrder("Jane Doe", []entity.OrderItem{
+			{ProductName: "Keyboard", Quantity: 1, UnitPrice: entity.NewMoney(49.99)},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error creating order: %v", err)
+		}
+		created, err := repo.CreateOrderWithItems(ctx, newOrder)
+		if err != nil {
+			t.Fatalf("unexpected error persisting order: %v", err)
+		}
+		if err := repo.SoftDeleteOrder(ctx, created.ID); err != nil {
+			t.Fatalf("unexpected error deleting order: %v", err)
+		}
+		return created.ID
+	}
+
+	oldID := createDeleted()
+	now = now.Add(48 * time.Hour)
+	recentID := createDeleted()
+
+	reaper := NewDeletedOrderReaper(repo, 72*time.Hour, time.Hour, WithReaperClock(clock))
+
+	// Advance past the old order's retention but not the recent one's
+	now = now.Add(25 * time.Hour)
+	purged, err := reaper.RunOnce(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if purged != 1 {
+		t.Fatalf("expected 1 purged order, got %d", purged)
+	}
+
+	remaining, _, err := repo.ListOrders(ctx, 1, 10, repository.OrderFilter{IncludeDeleted: true})
+	if err != nil {
+		t.Fatalf("unexpected error listing orders: %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].ID != recentID {
+		t.Fatalf("expected order %d to be purged and only order %d to survive, got %+v", oldID, recentID, remaining)
+	}
+}
+
+func TestDeletedOrderReaper_DisabledWithoutRetention(t *testing.T) {
+	reaper := NewDeletedOrderReaper(memory.NewInMemoryOrderRepository(), 0, time.Hour)
+	if reaper != nil {
+		t.Fatal("expected nil reaper when retention is not set")
+	}
+
+	// Start and Stop are safe on a disabled reaper
+	reaper.Start(context.Background())
+	reaper.Stop()
+}
@@ -0,0 +1,44 @@
+package order
+
+import (
+	"context"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+// StreamOrdersUseCase emits matching orders one at a time for exports that must not hold the
+// whole result set in memory
+type StreamOrdersUseCase struct {
+	orderRepo repository.OrderRepository
+	logger    *logger.Logger
+}
+
+// NewStreamOrdersUseCase creates a new StreamOrdersUseCase
+func NewStreamOrdersUseCase(orderRepo repository.OrderRepository) *StreamOrdersUseCase {
+	return &StreamOrdersUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("stream-orders-usecase", "1.0.0"),
+	}
+}
+
+// Execute starts streaming the orders matching filter, newest first. Cancelling ctx stops the
+// stream and releases the underlying cursor; see repository.OrderRepository.StreamOrders.
+func (uc *StreamOrdersUseCase) Execute(ctx context.Context, filter repository.OrderFilter) (<-chan *entity.Order, <-chan error, error) {
+	if filter.Status != "" && !entity.IsValidStatus(filter.Status) {
+		return nil, nil, apperrors.NewBusinessRuleViolationError("invalid order status").WithDetails(map[string]interface{}{
+			"provided_status": filter.Status,
+			"valid_statuses":  entity.ValidStatuses,
+		})
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"status":          filter.Status,
+		"include_deleted": filter.IncludeDeleted,
+	}).Debug("Starting order stream")
+
+	orders, errs := uc.orderRepo.StreamOrders(ctx, filter)
+	return orders, errs, nil
+}
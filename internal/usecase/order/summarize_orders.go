@@ -0,0 +1,56 @@
+package order
+
+import (
+	"context"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	"online-order-management-system/pkg/logger"
+)
+
+// OrderSummary breaks orders down by status. ByStatus has an entry for every valid status,
+// zero for statuses without orders, so its shape does not depend on the data.
+type OrderSummary struct {
+	ByStatus map[string]repository.StatusSummary
+	Total    repository.StatusSummary
+}
+
+// SummarizeOrdersUseCase reports the order count and revenue per status
+type SummarizeOrdersUseCase struct {
+	orderRepo repository.OrderRepository
+	logger    *logger.Logger
+}
+
+// NewSummarizeOrdersUseCase creates a new SummarizeOrdersUseCase
+func NewSummarizeOrdersUseCase(orderRepo repository.OrderRepository) *SummarizeOrdersUseCase {
+	return &SummarizeOrdersUseCase{
+		orderRepo: orderRepo,
+		logger:    logger.New("summarize-orders-usecase", "1.0.0"),
+	}
+}
+
+// Execute returns the per-status breakdown of all orders and its grand total
+func (uc *SummarizeOrdersUseCase) Execute(ctx context.Context) (*OrderSummary, error) {
+	summaries, err := uc.orderRepo.SummarizeOrders(ctx)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).Error("Failed to summarize orders")
+		return nil, err // Repository errors are already wrapped
+	}
+
+	result := &OrderSummary{ByStatus: make(map[string]repository.StatusSummary, len(entity.ValidStatuses))}
+	for _, status := range entity.ValidStatuses {
+		result.ByStatus[status] = repository.StatusSummary{}
+	}
+	for status, summary := range summaries {
+		result.ByStatus[status] = summary
+		result.Total.Count += summary.Count
+		result.Total.Revenue += summary.Revenue
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"total_count":   result.Total.Count,
+		"total_revenue": result.Total.Revenue.String(),
+	}).Debug("Successfully summarized orders")
+
+	return result, nil
+}
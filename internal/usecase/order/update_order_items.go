@@ -0,0 +1,134 @@
+package order
+
+import (
+	"context"
+	"strings"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
+	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/logger"
+)
+
+// UpdateOrderItemsUseCase replaces the items of an order that has not been processed yet
+type UpdateOrderItemsUseCase struct {
+	orderRepo  repository.OrderRepository
+	skuPolicy  entity.DuplicateSKUPolicy
+	prodPolicy entity.DuplicateProductPolicy
+	maxPrice   float64
+	maxAmount  float64
+	logger     *logger.Logger
+}
+
+// UpdateOrderItemsOption configures optional behavior of UpdateOrderItemsUseCase
+type UpdateOrderItemsOption func(*UpdateOrderItemsUseCase)
+
+// WithItemsDuplicateSKUPolicy sets how new items sharing a SKU are handled; use the same
+// policy as creation so an edit cannot produce an order a create would reject
+func WithItemsDuplicateSKUPolicy(policy entity.DuplicateSKUPolicy) UpdateOrderItemsOption {
+	return func(uc *UpdateOrderItemsUseCase) {
+		uc.skuPolicy = policy
+	}
+}
+
+// WithItemsDuplicateProductPolicy sets how new items naming the same product are handled;
+// like the SKU policy, it should match creation
+func WithItemsDuplicateProductPolicy(policy entity.DuplicateProductPolicy) UpdateOrderItemsOption {
+	return func(uc *UpdateOrderItemsUseCase) {
+		uc.prodPolicy = policy
+	}
+}
+
+// WithItemsMaxUnitPrice rejects new items priced above max (0 disables the cap)
+func WithItemsMaxUnitPrice(max float64) UpdateOrderItemsOption {
+	return func(uc *UpdateOrderItemsUseCase) {
+		uc.maxPrice = max
+	}
+}
+
+// WithItemsMaxOrderAmount rejects edits whose line or order total exceeds max
+// (default entity.DefaultMaxAmount)
+func WithItemsMaxOrderAmount(max float64) UpdateOrderItemsOption {
+	return func(uc *UpdateOrderItemsUseCase) {
+		uc.maxAmount = max
+	}
+}
+
+// NewUpdateOrderItemsUseCase creates a new UpdateOrderItemsUseCase
+func NewUpdateOrderItemsUseCase(orderRepo repository.OrderRepository, opts ...UpdateOrderItemsOption) *UpdateOrderItemsUseCase {
+	uc := &UpdateOrderItemsUseCase{
+		orderRepo:  orderRepo,
+		skuPolicy:  entity.DuplicateSKUAllow,
+		prodPolicy: entity.DuplicateProductReject,
+		maxAmount:  entity.DefaultMaxAmount,
+		logger:     logger.New("update-order-items-usecase", "1.0.0"),
+	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
+}
+
+// Execute replaces the items of a pending order and returns the order with its recomputed
+// total. The new items go through the same rules as a new order.
+func (uc *UpdateOrderItemsUseCase) Execute(ctx context.Context, id int64, reqItems []CreateOrderItemRequest) (*entity.Order, error) {
+	if id <= 0 {
+		uc.logger.WithContext(ctx).WithField("order_id", id).Warn("Invalid order ID")
+		return nil, apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
+			"provided_id": id,
+		})
+	}
+
+	current, err := uc.orderRepo.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, err // Repository errors are already wrapped
+	}
+	if err := entity.ValidateItemsEditable(current.Status); err != nil {
+		uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"order_id": id,
+			"status":   current.Status,
+		}).Warn("Rejected item update for non-pending order")
+		return nil, err
+	}
+
+	items := make([]entity.OrderItem, len(reqItems))
+	for i, item := range reqItems {
+		items[i] = entity.OrderItem{
+			ProductName:     item.ProductName,
+			SKU:             strings.TrimSpace(item.SKU),
+			Quantity:        item.Quantity,
+			Unit:            strings.TrimSpace(item.Unit),
+			UnitPrice:       entity.NewMoney(item.UnitPrice),
+			DiscountPercent: item.DiscountPercent,
+		}
+	}
+
+	// Build a throwaway order to apply the creation rules and compute the line totals
+	revised, err := entity.NewOrder(current.CustomerName, items,
+		entity.WithDuplicateSKUPolicy(uc.skuPolicy),
+		entity.WithDuplicateProductPolicy(uc.prodPolicy),
+		entity.WithMaxUnitPrice(uc.maxPrice),
+		entity.WithMaxAmount(uc.maxAmount),
+	)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Warn("Rejected invalid order items")
+		if appErr := apperrors.GetAppError(err); appErr != nil {
+			return nil, appErr
+		}
+		return nil, apperrors.NewBusinessRuleViolationError(err.Error()).WithCause(err)
+	}
+
+	updated, err := uc.orderRepo.UpdateOrderItems(ctx, id, revised.Items)
+	if err != nil {
+		uc.logger.WithContext(ctx).WithError(err).WithField("order_id", id).Error("Failed to update order items")
+		return nil, err
+	}
+
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+		"order_id":     id,
+		"items_count":  len(updated.Items),
+		"total_amount": updated.TotalAmount,
+	}).Info("Successfully updated order items")
+
+	return updated, nil
+}
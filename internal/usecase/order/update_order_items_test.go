@@ -0,0 +1,90 @@
+package order
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/infra/memory"
+	apperrors "online-order-management-system/pkg/errors"
+)
+
+func TestUpdateOrderItemsUseCase(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+	uc := NewUpdateOrderItemsUseCase(repo, WithItemsDuplicateSKUPolicy(entity.DuplicateSKUMerge))
+
+	newOrder, err := entity.NewOrder("Jane Doe", []entity.OrderItem{
+		{ProductName: "Keyboard", Quantity: 1, UnitPrice: entity.NewMoney(49.99)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating order: %v", err)
+	}
+	created, err := repo.CreateOrderWithItems(ctx, newOrder)
+	if err != nil {
+		t.Fatalf("unexpected error persisting order: %v", err)
+	}
+
+	t.Run("pending order gets new items and a recomputed total", func(t *testing.T) {
+		updated, err := uc.Execute(ctx, created.ID, []CreateOrderItemRequest{
+			{ProductName: "Keyboard", SKU: "KB-1", Quantity: 2, UnitPrice: 49.99},
+			{ProductName: "Keyboard", SKU: "KB-1", Quantity: 1, UnitPrice: 49.99},
+			{ProductName: "Coffee beans", Quantity: 1.5, Unit: "kg", UnitPrice: 24},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(updated.Items) != 2 {
+			t.Fatalf("expected the duplicate SKU to be merged into 2 items, got %d", len(updated.Items))
+		}
+		var sum entity.Money
+		for _, item := range updated.Items {
+			sum += item.TotalPrice
+		}
+		if updated.TotalAmount != sum || sum != entity.NewMoney(185.97) {
+			t.Errorf("expected total 185.97 matching the line totals, got total %v and lines %v", updated.TotalAmount, sum)
+		}
+
+		stored, err := repo.GetOrderByID(ctx, created.ID)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if stored.TotalAmount != updated.TotalAmount || len(stored.Items) != 2 {
+			t.Errorf("expected the change to be persisted, got %+v", stored)
+		}
+	})
+
+	t.Run("invalid items are rejected", func(t *testing.T) {
+		_, err := uc.Execute(ctx, created.ID, []CreateOrderItemRequest{
+			{ProductName: "Keyboard", Quantity: 1.5, UnitPrice: 49.99},
+		})
+		if !errors.Is(err, entity.ErrInvalidQuantity) {
+			t.Errorf("expected a fractional counted quantity to be rejected, got %v", err)
+		}
+	})
+
+	t.Run("non-pending order is rejected", func(t *testing.T) {
+		if _, err := repo.UpdateOrderStatus(ctx, created.ID, "processing"); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		_, err := uc.Execute(ctx, created.ID, []CreateOrderItemRequest{
+			{ProductName: "Mouse", Quantity: 1, UnitPrice: 19.99},
+		})
+		if !errors.Is(err, entity.ErrItemsNotEditable) {
+			t.Fatalf("expected ErrItemsNotEditable, got %v", err)
+		}
+		if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != apperrors.ErrCodeBusinessRuleViolation {
+			t.Errorf("expected a business rule violation, got %v", err)
+		}
+	})
+
+	t.Run("unknown order is not found", func(t *testing.T) {
+		_, err := uc.Execute(ctx, 9999, []CreateOrderItemRequest{
+			{ProductName: "Mouse", Quantity: 1, UnitPrice: 19.99},
+		})
+		if appErr := apperrors.GetAppError(err); appErr == nil || appErr.Code != apperrors.ErrCodeNotFound {
+			t.Errorf("expected not found, got %v", err)
+		}
+	})
+}
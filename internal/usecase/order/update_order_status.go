@@ -5,69 +5,110 @@ import (
 	"online-order-management-system/internal/domain/entity"
 	"online-order-management-system/internal/domain/repository"
 	apperrors "online-order-management-system/pkg/errors"
+	"online-order-management-system/pkg/events"
 	"online-order-management-system/pkg/logger"
 )
 
 // UpdateOrderStatusUseCase handles the business logic for updating order status
 type UpdateOrderStatusUseCase struct {
 	orderRepo repository.OrderRepository
+	publisher events.EventPublisher
 	logger    *logger.Logger
 }
 
+// UpdateOrderStatusOption configures optional behavior of UpdateOrderStatusUseCase
+type UpdateOrderStatusOption func(*UpdateOrderStatusUseCase)
+
+// WithStatusEventPublisher publishes an order.status_changed event for every status change
+func WithStatusEventPublisher(publisher events.EventPublisher) UpdateOrderStatusOption {
+	return func(uc *UpdateOrderStatusUseCase) {
+		uc.publisher = publisher
+	}
+}
+
 // NewUpdateOrderStatusUseCase creates a new UpdateOrderStatusUseCase
-func NewUpdateOrderStatusUseCase(orderRepo repository.OrderRepository) *UpdateOrderStatusUseCase {
-	return &UpdateOrderStatusUseCase{
+func NewUpdateOrderStatusUseCase(orderRepo repository.OrderRepository, opts ...UpdateOrderStatusOption) *UpdateOrderStatusUseCase {
+	uc := &UpdateOrderStatusUseCase{
 		orderRepo: orderRepo,
 		logger:    logger.New("update-order-status-usecase", "1.0.0"),
 	}
+	for _, opt := range opts {
+		opt(uc)
+	}
+	return uc
 }
 
 // UpdateOrderStatusRequest represents the input for updating order status
 type UpdateOrderStatusRequest struct {
-	Status string `json:"status" binding:"required,oneof=pending processing completed cancelled"`
+	Status string `json:"status" binding:"required,oneof=pending paid processing shipped completed cancelled"`
 }
 
-// Execute updates the status of an order
-func (uc *UpdateOrderStatusUseCase) Execute(ctx context.Context, id int64, status string) error {
-	uc.logger.WithFields(map[string]interface{}{
+// Execute updates the status of an order and reports whether it changed. Requesting the
+// status the order already has succeeds without a write, so retried requests are safe.
+func (uc *UpdateOrderStatusUseCase) Execute(ctx context.Context, id int64, status string) (bool, error) {
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"order_id": id,
 		"status":   status,
 	}).Info("Starting order status update")
 
 	// Validate inputs
 	if id <= 0 {
-		uc.logger.WithField("order_id", id).Warn("Invalid order ID")
-		return apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
+		uc.logger.WithContext(ctx).WithField("order_id", id).Warn("Invalid order ID")
+		return false, apperrors.NewInvalidOperationError("order ID must be greater than 0").WithDetails(map[string]interface{}{
 			"provided_id": id,
 		})
 	}
 
 	if !entity.IsValidStatus(status) {
-		uc.logger.WithFields(map[string]interface{}{
+		uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
 			"order_id":       id,
 			"invalid_status": status,
 			"valid_statuses": entity.ValidStatuses,
 		}).Warn("Invalid order status")
-		return apperrors.NewBusinessRuleViolationError("invalid order status").WithDetails(map[string]interface{}{
+		return false, apperrors.NewBusinessRuleViolationError("invalid order status").WithDetails(map[string]interface{}{
 			"provided_status": status,
 			"valid_statuses":  entity.ValidStatuses,
 		})
 	}
 
+	// The previous status is only needed for the event payload
+	var oldStatus string
+	if uc.publisher != nil {
+		current, err := uc.orderRepo.GetOrderByID(ctx, id)
+		if err != nil {
+			return false, err
+		}
+		oldStatus = current.Status
+	}
+
 	// Update the order status
-	err := uc.orderRepo.UpdateOrderStatus(ctx, id, status)
+	changed, err := uc.orderRepo.UpdateOrderStatus(ctx, id, status)
 	if err != nil {
-		uc.logger.WithError(err).WithFields(map[string]interface{}{
+		uc.logger.WithContext(ctx).WithError(err).WithFields(map[string]interface{}{
 			"order_id": id,
 			"status":   status,
 		}).Error("Failed to update order status")
-		return err // Repository errors are already wrapped
+		return false, err // Repository errors are already wrapped
+	}
+
+	if !changed {
+		uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
+			"order_id": id,
+			"status":   status,
+		}).Info("Order already has the requested status")
+		return false, nil
 	}
 
-	uc.logger.WithFields(map[string]interface{}{
+	uc.logger.WithContext(ctx).WithFields(map[string]interface{}{
 		"order_id": id,
 		"status":   status,
 	}).Info("Successfully updated order status")
 
-	return nil
+	publishEvent(ctx, uc.publisher, uc.logger, events.NewEvent(events.TypeOrderStatusChanged, events.OrderStatusChanged{
+		OrderID:   id,
+		OldStatus: oldStatus,
+		NewStatus: status,
+	}))
+
+	return true, nil
 }
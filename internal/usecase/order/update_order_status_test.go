@@ -0,0 +1,55 @@
+package order
+
+import (
+	"context"
+	"testing"
+
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/infra/memory"
+)
+
+func TestUpdateOrderStatusUseCase_RedundantUpdateIsNoOp(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewInMemoryOrderRepository()
+
+	newOrder, err := entity.NewOrder("Jane Doe", []entity.OrderItem{
+		{ProductName: "Keyboard", Quantity: 1, UnitPrice: entity.NewMoney(49.99)},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error creating order: %v", err)
+	}
+	created, err := repo.CreateOrderWithItems(ctx, newOrder)
+	if err != nil {
+		t.Fatalf("unexpected error persisting order: %v", err)
+	}
+
+	uc := NewUpdateOrderStatusUseCase(repo)
+
+	changed, err := uc.Execute(ctx, created.ID, "processing")
+	if err != nil {
+		t.Fatalf("unexpected error on real transition: %v", err)
+	}
+	if !changed {
+		t.Error("expected a real transition to report changed")
+	}
+
+	// A retry of the same request must succeed without writing another history row
+	changed, err = uc.Execute(ctx, created.ID, "processing")
+	if err != nil {
+		t.Fatalf("unexpected error on redundant update: %v", err)
+	}
+	if changed {
+		t.Error("expected a redundant update to report unchanged")
+	}
+
+	history, err := repo.GetStatusHistory(ctx, created.ID)
+	if err != nil {
+		t.Fatalf("unexpected error reading history: %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("expected exactly 1 history row, got %d: %+v", len(history), history)
+	}
+	if history[0].FromStatus != "pending" || history[0].ToStatus != "processing" {
+		t.Errorf("expected pending -> processing, got %s -> %s", history[0].FromStatus, history[0].ToStatus)
+	}
+}
@@ -1,14 +1,29 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"online-order-management-system/config"
 	"online-order-management-system/internal/api/http/handler"
 	"online-order-management-system/internal/api/validation"
+	"online-order-management-system/internal/domain/entity"
+	"online-order-management-system/internal/domain/repository"
 	"online-order-management-system/internal/infra/db"
+	"online-order-management-system/internal/infra/webhook"
 	"online-order-management-system/internal/middleware"
 	"online-order-management-system/internal/usecase/order"
+	"online-order-management-system/pkg/concurrency"
+	"online-order-management-system/pkg/errorlog"
+	"online-order-management-system/pkg/events"
 	"online-order-management-system/pkg/logger"
+	"online-order-management-system/pkg/tracing"
 	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/joho/godotenv"
@@ -21,6 +36,11 @@ import (
 	ginSwagger "github.com/swaggo/gin-swagger"
 )
 
+// expectedSchemaVersion is the migration version this build expects, set at build time with
+// -ldflags "-X main.expectedSchemaVersion=N" (see make build). When empty, the newest
+// migration in MIGRATIONS_PATH is expected.
+var expectedSchemaVersion string
+
 // @title           Online Order Management System API
 // @version         1.0
 // @description     A high-performance order management system built with Go, featuring concurrent order processing and Clean Architecture.
@@ -51,8 +71,38 @@ func main() {
 		appLogger.Info("Loaded configuration from .env file")
 	}
 
-	// Database connection using environment-based configuration
-	database, err := db.NewPostgresDB()
+	// Optionally batch log lines to cut per-entry write syscalls (0 keeps synchronous logging)
+	if size := config.GetEnvInt("LOG_BUFFER_SIZE", 0); size > 0 {
+		logBuffer := logger.NewBufferedWriter(os.Stderr, size, config.GetEnvDuration("LOG_FLUSH_INTERVAL", time.Second))
+		logger.SetOutput(logBuffer)
+		defer logBuffer.Close()
+	}
+
+	// Tracing: spans are exported over OTLP when OTEL_EXPORTER_OTLP_ENDPOINT is set
+	shutdownTracing, err := tracing.Setup(context.Background(), config.GetEnvString("OTEL_SERVICE_NAME", "order-management-system"))
+	if err != nil {
+		appLogger.WithError(err).Fatal("Failed to set up tracing")
+	}
+
+	// Settings the server cannot start without; every problem is reported at once
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid configuration")
+	}
+
+	// The API requires bearer tokens; serving it unauthenticated takes an explicit opt-out
+	jwtSecret := config.GetEnvString("JWT_SECRET", "")
+	authDisabled := config.GetEnvBool("AUTH_DISABLED", false)
+	if jwtSecret == "" && !authDisabled {
+		appLogger.Fatal("JWT_SECRET is not set; set it, or set AUTH_DISABLED=true to serve the API without authentication")
+	}
+
+	// Database connection; the pool is tuned by the DB_* variables
+	dbConfig, err := db.GetDatabaseConfig(cfg.PostgresDSN)
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid database configuration")
+	}
+	database, err := db.NewPostgresDBWithConfig(dbConfig)
 	if err != nil {
 		appLogger.WithError(err).Fatal("Failed to connect to database")
 	}
@@ -64,39 +114,247 @@ func main() {
 
 	appLogger.Info("Successfully connected to database")
 
-	// Run database migrations
+	// Run database migrations before serving, unless MIGRATE_ON_STARTUP=false (then use
+	// cmd/migrate). With MIGRATIONS_REQUIRED=false a missing migrations directory is only
+	// logged, for deployments that migrate the schema out of band.
+	migrationsPath := config.GetEnvString("MIGRATIONS_PATH", "migrations")
 	migrationManager := db.NewMigrationManager(database)
-	if err := migrationManager.RunMigrations("migrations"); err != nil {
-		appLogger.WithError(err).Fatal("Failed to run database migrations")
+	if config.GetEnvBool("MIGRATE_ON_STARTUP", true) {
+		err = migrationManager.RunMigrations(migrationsPath)
+		switch {
+		case errors.Is(err, db.ErrMigrationsNotFound) && !config.GetEnvBool("MIGRATIONS_REQUIRED", true):
+			appLogger.WithError(err).Warn("Skipping database migrations")
+		case err != nil:
+			appLogger.WithError(err).Fatal("Failed to run database migrations")
+		}
 	}
 
 	// Log current migration version
-	if version, dirty, err := migrationManager.GetMigrationVersion("migrations"); err != nil {
+	if version, dirty, err := migrationManager.GetMigrationVersion(migrationsPath); err != nil {
 		appLogger.WithError(err).Warn("Failed to get migration version")
+	} else if dirty {
+		appLogger.WithField("version", version).Warn("Database schema is dirty: a failed migration left it partially applied")
 	} else {
-		appLogger.WithFields(map[string]interface{}{
-			"version": version,
-			"dirty":   dirty,
-		}).Info("Database migration status")
+		appLogger.WithField("version", version).Info("Database migration status")
 	}
 
 	// Initialize repository
-	orderRepo := db.NewPostgresOrderRepository(database)
+	// Per-request database query cap to surface N+1 regressions (0 disables counting)
+	queryBudget := config.GetEnvInt("QUERY_BUDGET_PER_REQUEST", 0)
+
+	// Human-readable per-year order numbers, e.g. ORD-%d-%06d (empty disables them)
+	orderNumberFormat := config.GetEnvString("ORDER_NUMBER_FORMAT", "")
+	if orderNumberFormat != "" {
+		if err := entity.ValidateOrderNumberFormat(orderNumberFormat); err != nil {
+			appLogger.WithError(err).Fatal("Invalid ORDER_NUMBER_FORMAT")
+		}
+	}
+
+	// Transactional outbox: creates record an order.created row that the dispatcher posts
+	// to the webhook, surviving crashes between the commit and the delivery
+	outboxWebhookURL := config.GetEnvString("OUTBOX_WEBHOOK_URL", "")
+	var outboxDispatcher *db.OutboxDispatcher
+	if outboxWebhookURL != "" {
+		sink := events.NewWebhookSink(outboxWebhookURL,
+			config.GetEnvString("CLOUDEVENTS_SOURCE", "/online-order-management-system"),
+			config.GetEnvDuration("OUTBOX_WEBHOOK_TIMEOUT", 10*time.Second))
+		outboxDispatcher = db.NewOutboxDispatcher(database, sink, config.GetEnvDuration("OUTBOX_POLL_INTERVAL", time.Second),
+			db.WithOutboxMaxAttempts(config.GetEnvInt("OUTBOX_MAX_ATTEMPTS", db.DefaultOutboxMaxAttempts)),
+			db.WithOutboxClaimTTL(config.GetEnvDuration("OUTBOX_CLAIM_TTL", db.DefaultOutboxClaimTTL)),
+		)
+	}
+
+	postgresRepo := db.NewPostgresOrderRepository(database,
+		db.WithRecomputeTotalOnMismatch(config.GetEnvBool("RECOMPUTE_TOTAL_ON_MISMATCH", false)),
+		db.WithUniqueClientReference(config.GetEnvBool("UNIQUE_CLIENT_REFERENCE", false)),
+		db.WithQueryBudget(queryBudget > 0),
+		db.WithDatabaseTotals(config.GetEnvBool("DATABASE_TOTALS", false)),
+		db.WithOrderNumbers(orderNumberFormat),
+		db.WithOutbox(outboxDispatcher != nil),
+		// Shorter than the handlers' 30s so a slow query gives its connection back early
+		db.WithQueryTimeout(cfg.DBQueryTimeout),
+	)
+	var orderRepo repository.OrderRepository = postgresRepo
+	if config.GetEnvBool("DB_PRE_PING", false) {
+		// Retry once after a ping when a pooled connection turns out to be dead after idling
+		orderRepo = db.NewPrePingRepository(postgresRepo, database, config.GetEnvDuration("DB_PRE_PING_TIMEOUT", 2*time.Second))
+	}
+	// Serve hot GetOrderByID reads from memory; writes through this instance evict their order
+	var orderCache *db.CachingOrderRepository
+	if config.GetEnvBool("ORDER_CACHE", false) {
+		orderCache = db.NewCachingOrderRepository(orderRepo,
+			config.GetEnvInt("ORDER_CACHE_SIZE", 1000),
+			config.GetEnvDuration("ORDER_CACHE_TTL", 30*time.Second))
+		orderRepo = orderCache
+	}
+
+	// Bound concurrent database writes at the application level (0 disables the limit)
+	dbLimiter := concurrency.NewLimiter(
+		config.GetEnvInt("DB_CONCURRENCY_LIMIT", 0),
+		config.GetEnvDuration("DB_CONCURRENCY_WAIT", 200*time.Millisecond),
+	)
+	if dbLimiter != nil {
+		appLogger.WithField("limit", dbLimiter.Size()).Info("Database concurrency limit enabled")
+	}
+
+	skuPolicy, err := entity.ParseDuplicateSKUPolicy(config.GetEnvString("DUPLICATE_SKU_POLICY", "allow"))
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid duplicate SKU policy")
+	}
+	productPolicy, err := entity.ParseDuplicateProductPolicy(config.GetEnvString("DUPLICATE_PRODUCT_POLICY", "reject"))
+	if err != nil {
+		appLogger.WithError(err).Fatal("Invalid duplicate product policy")
+	}
+
+	// Per-item price cap against typos such as 9.99 entered as 999000 (0 disables it)
+	maxUnitPrice := config.GetEnvFloat("MAX_UNIT_PRICE", 0)
+	validation.MaxUnitPrice = maxUnitPrice
+	maxOrderAmount := config.GetEnvFloat("MAX_ORDER_AMOUNT", entity.DefaultMaxAmount)
+
+	// One precision rule for every money input; amounts are kept in whole cents
+	validation.MoneyScale = config.GetEnvInt("MONEY_SCALE", validation.DefaultMoneyScale)
+	if validation.MoneyScale > validation.DefaultMoneyScale {
+		appLogger.WithField("money_scale", validation.MoneyScale).Warn("MONEY_SCALE allows more decimal places than cents; amounts will be rounded to the cent")
+	}
+
+	// Order lifecycle events, emitted as CloudEvents JSON lines apart from the logs, or
+	// delivered in process to handlers subscribed on the channel publisher
+	var eventPublisher events.EventPublisher
+	var channelPublisher *events.ChannelPublisher
+	switch output := config.GetEnvString("EVENTS_OUTPUT", ""); output {
+	case "":
+	case "stdout":
+		eventPublisher = events.NewCloudEventsPublisher(os.Stdout,
+			config.GetEnvString("CLOUDEVENTS_SOURCE", "/online-order-management-system"))
+		appLogger.Info("Publishing order events as CloudEvents on stdout")
+	case "inprocess":
+		channelPublisher = events.NewChannelPublisher(config.GetEnvInt("EVENTS_BUFFER", 1000),
+			events.WithHandlerErrors(func(event events.Event, err error) {
+				appLogger.WithError(err).WithFields(map[string]interface{}{
+					"event_id":   event.ID,
+					"event_type": event.Type,
+				}).Error("Order event handler failed")
+			}),
+		)
+		channelPublisher.Subscribe("", func(ctx context.Context, event events.Event) error {
+			appLogger.WithContext(ctx).WithFields(map[string]interface{}{
+				"event_id":   event.ID,
+				"event_type": event.Type,
+				"data":       event.Data,
+			}).Info("Order event")
+			return nil
+		})
+		eventPublisher = channelPublisher
+		appLogger.Info("Delivering order events to in-process handlers")
+	default:
+		appLogger.WithField("events_output", output).Fatal("Unsupported EVENTS_OUTPUT; use stdout, inprocess or leave empty")
+	}
+
+	// Signed merchant webhook for orders reaching STATUS_WEBHOOK_STATUSES (completed by default).
+	// Deliveries run on their own queue, detached from the request, so a slow merchant
+	// endpoint never holds up the status update.
+	statusPublisher := eventPublisher
+	var webhookPublisher *events.ChannelPublisher
+	if webhookURL := config.GetEnvString("STATUS_WEBHOOK_URL", ""); webhookURL != "" {
+		notifier := webhook.NewWebhookNotifier(webhookURL, config.GetEnvString("STATUS_WEBHOOK_SECRET", ""),
+			webhook.WithStatuses(strings.Split(config.GetEnvString("STATUS_WEBHOOK_STATUSES", "completed"), ",")...),
+			webhook.WithTimeout(config.GetEnvDuration("STATUS_WEBHOOK_TIMEOUT", 5*time.Second)),
+		)
+		webhookPublisher = events.NewChannelPublisher(config.GetEnvInt("STATUS_WEBHOOK_BUFFER", 1000),
+			events.WithHandlerErrors(func(event events.Event, err error) {
+				appLogger.WithError(err).WithFields(map[string]interface{}{
+					"event_id":   event.ID,
+					"event_type": event.Type,
+				}).Error("Status webhook delivery failed")
+			}),
+		)
+		webhookPublisher.Subscribe(events.TypeOrderStatusChanged, notifier.Publish)
+		statusPublisher = events.NewMultiPublisher(eventPublisher, webhookPublisher)
+		appLogger.WithField("url", webhookURL).Info("Notifying status changes by webhook")
+	}
 
 	// Initialize use cases
-	createOrderUC := order.NewCreateOrderUseCase(orderRepo)
+	createOrderUC := order.NewCreateOrderUseCase(orderRepo,
+		order.WithCreateConcurrencyLimiter(dbLimiter),
+		order.WithDuplicateSKUPolicy(skuPolicy),
+		order.WithDuplicateProductPolicy(productPolicy),
+		order.WithMaxUnitPrice(maxUnitPrice),
+		order.WithMaxOrderAmount(maxOrderAmount),
+		order.WithLeadTimeModel(entity.LeadTimeModel{
+			BaseDays:     config.GetEnvInt("LEAD_TIME_BASE_DAYS", entity.DefaultLeadTimeModel.BaseDays),
+			PerItemDays:  config.GetEnvFloat("LEAD_TIME_PER_ITEM_DAYS", entity.DefaultLeadTimeModel.PerItemDays),
+			SkipWeekends: config.GetEnvBool("LEAD_TIME_SKIP_WEEKENDS", entity.DefaultLeadTimeModel.SkipWeekends),
+		}),
+		order.WithCreateEventPublisher(eventPublisher),
+		order.WithContentDedup(config.GetEnvDuration("ORDER_DEDUP_WINDOW", 0)),
+		order.WithIdempotencyKeyTTL(config.GetEnvDuration("IDEMPOTENCY_KEY_TTL", 24*time.Hour)),
+	)
+	bulkCreateOrdersUC := order.NewBulkCreateOrdersUseCase(createOrderUC,
+		order.WithBulkConcurrency(config.GetEnvInt("BULK_CONCURRENCY", 4)),
+	)
 	getOrderUC := order.NewGetOrderUseCase(orderRepo)
-	listOrdersUC := order.NewListOrdersUseCase(orderRepo)
-	updateOrderStatusUC := order.NewUpdateOrderStatusUseCase(orderRepo)
+	getOrderByReferenceUC := order.NewGetOrderByReferenceUseCase(orderRepo)
+	getOrderStatusesUC := order.NewGetOrderStatusesUseCase(orderRepo)
+	countOrdersUC := order.NewCountOrdersUseCase(orderRepo)
+	summarizeOrdersUC := order.NewSummarizeOrdersUseCase(orderRepo)
+	listOrdersUC := order.NewListOrdersUseCase(orderRepo, order.WithItemBudget(config.GetEnvInt("LIST_ITEM_BUDGET", 0)))
+	updateOrderStatusUC := order.NewUpdateOrderStatusUseCase(orderRepo, order.WithStatusEventPublisher(statusPublisher))
+	updateOrderItemsUC := order.NewUpdateOrderItemsUseCase(orderRepo,
+		order.WithItemsDuplicateSKUPolicy(skuPolicy),
+		order.WithItemsDuplicateProductPolicy(productPolicy),
+		order.WithItemsMaxUnitPrice(maxUnitPrice),
+		order.WithItemsMaxOrderAmount(maxOrderAmount),
+	)
+	patchOrderUC := order.NewPatchOrderUseCase(orderRepo, order.WithPatchEventPublisher(statusPublisher))
+	deleteOrderUC := order.NewDeleteOrderUseCase(orderRepo, order.WithHardDelete(config.GetEnvBool("ORDER_HARD_DELETE", false)))
+	getOrderTimelineUC := order.NewGetOrderTimelineUseCase(orderRepo)
+	streamOrdersUC := order.NewStreamOrdersUseCase(orderRepo)
+
+	// Hard-delete soft-deleted orders once their grace period expires (0 disables purging)
+	reaper := order.NewDeletedOrderReaper(orderRepo,
+		config.GetEnvDuration("ORDER_PURGE_RETENTION", 0),
+		config.GetEnvDuration("ORDER_PURGE_INTERVAL", time.Hour),
+	)
 
 	appLogger.Info("Initialized all use cases")
 
+	// Ring buffer of the last errors returned to clients, for GET /debug/errors (0 disables it)
+	errorLog := errorlog.NewRing(config.GetEnvInt("ERROR_LOG_SIZE", 0))
+
+	// Bulk creates are expensive, so they get their own, stricter per-IP limit (0 disables it)
+	bulkRateLimiter := middleware.NewIPRateLimiter(
+		config.GetEnvFloat("BULK_RATE_LIMIT_RPS", 1),
+		config.GetEnvInt("BULK_RATE_LIMIT_BURST", 2),
+	)
+	// Routes counted by their own limiter only, not also by the API-wide one
+	var ownRateLimitRoutes []string
+	if bulkRateLimiter != nil {
+		ownRateLimitRoutes = []string{"/api/v1/orders/bulk", "/api/v1/orders/import"}
+	}
+
 	// Initialize handler
 	orderHandler := handler.NewOrderHandler(
-		createOrderUC,
-		getOrderUC,
-		listOrdersUC,
-		updateOrderStatusUC,
+		handler.OrderUseCases{
+			CreateOrder:         createOrderUC,
+			BulkCreateOrders:    bulkCreateOrdersUC,
+			GetOrder:            getOrderUC,
+			GetOrderByReference: getOrderByReferenceUC,
+			GetOrderStatuses:    getOrderStatusesUC,
+			ListOrders:          listOrdersUC,
+			CountOrders:         countOrdersUC,
+			SummarizeOrders:     summarizeOrdersUC,
+			UpdateOrderStatus:   updateOrderStatusUC,
+			UpdateOrderItems:    updateOrderItemsUC,
+			PatchOrder:          patchOrderUC,
+			DeleteOrder:         deleteOrderUC,
+			GetOrderTimeline:    getOrderTimelineUC,
+			StreamOrders:        streamOrdersUC,
+		},
+		handler.WithRetryHeader(config.GetEnvBool("DB_RETRIES_HEADER", false)),
+		handler.WithStrictPagination(config.GetEnvBool("STRICT_PAGINATION", false)),
+		handler.WithStreamWriteTimeout(config.GetEnvDuration("STREAM_WRITE_TIMEOUT", 10*time.Second)),
+		handler.WithErrorLog(errorLog),
+		handler.WithBulkRateLimiter(bulkRateLimiter),
 	)
 
 	appLogger.Info("Initialized handlers")
@@ -108,10 +366,17 @@ func main() {
 	validation.RegisterCustomValidations()
 
 	// Middleware
+	router.Use(middleware.TraceIDMiddleware())
 	router.Use(middleware.GinLoggingMiddleware())
-	router.Use(middleware.CORSMiddleware())
+	router.Use(middleware.TracingMiddleware())
+	// Cross-origin callers must be listed; without CORS_ALLOWED_ORIGINS only same-origin requests work
+	router.Use(middleware.CORSMiddleware(middleware.CORSConfig{
+		AllowedOrigins: strings.Split(config.GetEnvString("CORS_ALLOWED_ORIGINS", ""), ","),
+		AllowedMethods: strings.Split(config.GetEnvString("CORS_ALLOWED_METHODS", ""), ","),
+		AllowedHeaders: strings.Split(config.GetEnvString("CORS_ALLOWED_HEADERS", ""), ","),
+	}))
 
-	// Health check endpoint
+	// Liveness check: the process is up, whatever the state of its dependencies
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{
 			"status":  "healthy",
@@ -119,28 +384,159 @@ func main() {
 			"version": "1.0.0",
 		})
 	})
+	// Readiness check: 503 while the database is unreachable or its schema is behind this build
+	router.GET("/ready", handler.ReadinessHandler(database, config.GetEnvDuration("READY_TIMEOUT", 2*time.Second),
+		schemaCheck(appLogger, migrationManager, migrationsPath)))
+
+	adminKey := config.GetEnvString("ADMIN_API_KEY", "")
+
+	// Diagnostics under /debug are admin only
+	debug := router.Group("/debug", middleware.AdminKeyMiddleware(adminKey), middleware.RequireRole(middleware.RoleAdmin))
+
+	// Connection-pool sizing advisor, fed by periodic sql.DBStats samples
+	var poolStats *db.PoolStatsCollector
+	if interval := config.GetEnvDuration("POOL_ADVISOR_INTERVAL", 10*time.Second); interval > 0 {
+		poolSettings := db.PoolSettings{
+			MaxOpenConns:     dbConfig.MaxOpenConns,
+			MaxIdleConns:     dbConfig.MaxIdleConns,
+			ConnectionBudget: config.GetEnvInt("DB_CONNECTION_BUDGET", 0),
+		}
+		poolStats = db.NewPoolStatsCollector(database, interval, config.GetEnvInt("POOL_ADVISOR_WINDOW", 60))
+
+		debug.GET("/pool-advice", func(c *gin.Context) {
+			c.JSON(http.StatusOK, db.AdvisePoolSize(poolStats.Samples(), poolSettings))
+		})
+	}
+
+	// Recent error responses, to correlate client reports with trace IDs
+	if errorLog != nil {
+		debug.GET("/errors", func(c *gin.Context) {
+			c.JSON(http.StatusOK, gin.H{
+				"size":   errorLog.Size(),
+				"errors": errorLog.Entries(),
+			})
+		})
+	}
+
+	// Order cache hit/miss counters
+	if orderCache != nil {
+		debug.GET("/cache", func(c *gin.Context) {
+			c.JSON(http.StatusOK, orderCache.Stats())
+		})
+	}
 
 	// Swagger documentation endpoint
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 
 	// API routes - use the handler's RegisterRoutes method
 	api := router.Group("/api/v1")
+	api.Use(middleware.APIVersionMiddleware())
+	api.Use(middleware.RateLimitMiddleware(
+		config.GetEnvInt("RATE_LIMIT_RPS", 100),
+		config.GetEnvInt("RATE_LIMIT_BURST", 200),
+		ownRateLimitRoutes...,
+	))
+	// Bearer JWT auth for the whole API; /health, /ready and /swagger stay public
+	if jwtSecret != "" {
+		api.Use(middleware.AuthMiddleware([]byte(jwtSecret)))
+	} else {
+		appLogger.Warn("AUTH_DISABLED is set: the API accepts unauthenticated requests")
+	}
+	api.Use(middleware.QueryBudgetMiddleware(queryBudget, config.GetEnvBool("QUERY_BUDGET_STRICT", false)))
+	// Cap request bodies before anything reads them; NDJSON imports stream line by line, so
+	// they get their own, larger cap and are never buffered by the JSON guard
+	const importRoute = "/api/v1/orders/import"
+	api.Use(middleware.BodyLimitMiddleware(
+		int64(config.GetEnvInt("MAX_BODY_BYTES", int(middleware.DefaultMaxBodyBytes))),
+		map[string]int64{importRoute: int64(config.GetEnvInt("MAX_IMPORT_BYTES", int(middleware.DefaultMaxImportBytes)))},
+	))
+	api.Use(middleware.JSONComplexityMiddleware(middleware.JSONLimits{
+		MaxItems: config.GetEnvInt("JSON_MAX_ITEMS", 10000),
+		MaxDepth: config.GetEnvInt("JSON_MAX_DEPTH", 20),
+	}, importRoute))
+	api.Use(middleware.AdminKeyMiddleware(adminKey))
+	api.Use(middleware.DryRunMiddleware())
+	readOnly := config.GetEnvBool("READ_ONLY", false)
+	if readOnly {
+		appLogger.Warn("READ-ONLY MODE: order writes are disabled and will be rejected with 503")
+	}
+	api.Use(middleware.ReadOnlyMiddleware(readOnly, "/api/v1/orders/statuses"))
 	orderHandler.RegisterRoutes(api)
 
 	appLogger.Info("Registered all routes and middleware")
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	port := cfg.Port
 
 	appLogger.WithFields(map[string]interface{}{
 		"port":        port,
+		"log_level":   cfg.LogLevel.String(),
 		"swagger_url": "http://localhost:" + port + "/swagger/index.html",
 	}).Info("Starting server")
 
-	if err := router.Run(":" + port); err != nil {
-		appLogger.WithError(err).WithField("port", port).Fatal("Failed to start server")
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	reaper.Start(ctx)
+	outboxDispatcher.Start(ctx)
+	poolStats.Start(ctx)
+
+	server := &http.Server{
+		Addr:              ":" + port,
+		Handler:           router,
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		ReadTimeout:       cfg.ReadTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+		IdleTimeout:       cfg.IdleTimeout,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			appLogger.WithError(err).WithField("port", port).Fatal("Failed to start server")
+		}
+	}()
+
+	<-ctx.Done()
+	appLogger.Info("Shutting down server")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		appLogger.WithError(err).Error("Server shutdown did not complete cleanly")
 	}
+	reaper.Stop()
+	outboxDispatcher.Stop()
+	poolStats.Stop()
+	if channelPublisher != nil {
+		// Deliver the events of the requests that just finished
+		channelPublisher.Close()
+	}
+	if webhookPublisher != nil {
+		webhookPublisher.Close()
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		appLogger.WithError(err).Error("Failed to flush pending spans")
+	}
+}
+
+// schemaCheck builds the readiness schema check against expectedSchemaVersion, or returns nil
+// (no check) when the expected version cannot be determined
+func schemaCheck(appLogger *logger.Logger, manager *db.MigrationManager, migrationsPath string) *handler.SchemaCheck {
+	var expected uint
+	if expectedSchemaVersion != "" {
+		version, err := strconv.ParseUint(expectedSchemaVersion, 10, 64)
+		if err != nil {
+			appLogger.WithField("expected_schema_version", expectedSchemaVersion).Fatal("Invalid build-time schema version")
+		}
+		expected = uint(version)
+	} else {
+		version, err := db.LatestMigrationVersion(migrationsPath)
+		if err != nil {
+			appLogger.WithError(err).Warn("Readiness will not check the schema version: no expected version")
+			return nil
+		}
+		expected = version
+	}
+
+	appLogger.WithField("expected_schema_version", expected).Info("Readiness checks the schema version")
+	return &handler.SchemaCheck{Source: manager, MigrationsPath: migrationsPath, ExpectedVersion: expected}
 }
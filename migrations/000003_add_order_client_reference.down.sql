@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_orders_client_reference;
+
+-- Drop columns
+ALTER TABLE orders DROP COLUMN IF EXISTS client_reference;
@@ -0,0 +1,5 @@
+-- Add optional client-supplied reference (e.g. a B2B purchase order number)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS client_reference VARCHAR(100);
+
+-- Create index for lookups by client reference
+CREATE INDEX IF NOT EXISTS idx_orders_client_reference ON orders(client_reference) WHERE client_reference IS NOT NULL;
@@ -0,0 +1,5 @@
+-- Drop indexes
+DROP INDEX IF EXISTS idx_orders_live_created_at_id;
+
+-- Drop columns
+ALTER TABLE orders DROP COLUMN IF EXISTS deleted_at;
@@ -0,0 +1,5 @@
+-- Add soft-delete marker; deleted orders stay in the table for recovery
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Create partial index so default listings only scan live orders
+CREATE INDEX IF NOT EXISTS idx_orders_live_created_at_id ON orders(created_at DESC, id DESC) WHERE deleted_at IS NULL;
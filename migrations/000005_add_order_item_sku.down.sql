@@ -0,0 +1,2 @@
+-- Drop columns
+ALTER TABLE order_items DROP COLUMN IF EXISTS sku;
@@ -0,0 +1,2 @@
+-- Add optional stock keeping unit to order items
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
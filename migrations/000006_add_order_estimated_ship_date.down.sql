@@ -0,0 +1,2 @@
+-- Drop columns
+ALTER TABLE orders DROP COLUMN IF EXISTS estimated_ship_date;
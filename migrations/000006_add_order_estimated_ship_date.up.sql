@@ -0,0 +1,2 @@
+-- Add estimated ship date computed from the lead-time model at creation
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS estimated_ship_date DATE;
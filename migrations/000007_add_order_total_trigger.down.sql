@@ -0,0 +1,3 @@
+-- Drop trigger-maintained order totals
+DROP TRIGGER IF EXISTS trg_order_items_total ON order_items;
+DROP FUNCTION IF EXISTS recompute_order_total();
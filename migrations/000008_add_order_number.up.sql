@@ -0,0 +1,8 @@
+-- Add human-readable order numbers allocated gaplessly per year
+CREATE TABLE IF NOT EXISTS order_number_counters (
+    year INTEGER PRIMARY KEY,
+    last_value BIGINT NOT NULL
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS order_number VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_order_number ON orders(order_number);
@@ -0,0 +1,7 @@
+-- Paid orders fall back to pending, the status they were paid from
+UPDATE orders SET status = 'pending' WHERE status = 'paid';
+
+-- Restore the original status constraint
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('pending', 'processing', 'completed', 'cancelled'));
@@ -0,0 +1,4 @@
+-- Allow orders to be marked paid before processing
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('pending', 'paid', 'processing', 'completed', 'cancelled'));
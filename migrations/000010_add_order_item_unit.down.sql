@@ -0,0 +1,5 @@
+-- Drop the unit of measure
+ALTER TABLE order_items DROP COLUMN IF EXISTS unit;
+
+-- Fractional quantities are rounded up to whole units
+ALTER TABLE order_items ALTER COLUMN quantity TYPE INTEGER USING CEIL(quantity);
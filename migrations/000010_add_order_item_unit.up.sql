@@ -0,0 +1,3 @@
+-- Allow items sold by weight or length: fractional quantities with a unit of measure
+ALTER TABLE order_items ALTER COLUMN quantity TYPE NUMERIC(12,3);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit VARCHAR(20);
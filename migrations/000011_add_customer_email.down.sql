@@ -0,0 +1,2 @@
+-- Drop the customer email
+ALTER TABLE orders DROP COLUMN IF EXISTS customer_email;
@@ -0,0 +1,2 @@
+-- Record the customer's email; existing orders keep NULL
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_email VARCHAR(254);
@@ -0,0 +1,2 @@
+-- Drop the line discount
+ALTER TABLE order_items DROP COLUMN IF EXISTS discount_percent;
@@ -0,0 +1,3 @@
+-- Line-level discount as a percentage; existing items are undiscounted
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0
+    CHECK (discount_percent >= 0 AND discount_percent <= 100);
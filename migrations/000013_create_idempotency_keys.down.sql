@@ -0,0 +1,2 @@
+-- Drop the stored idempotency keys
+DROP TABLE IF EXISTS idempotency_keys;
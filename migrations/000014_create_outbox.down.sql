@@ -0,0 +1,2 @@
+-- Drop the event outbox
+DROP TABLE IF EXISTS outbox;
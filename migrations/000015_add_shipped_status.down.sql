@@ -0,0 +1,7 @@
+-- Shipped orders fall back to processing, the closest status that still exists
+UPDATE orders SET status = 'processing' WHERE status = 'shipped';
+
+-- Restore the previous status constraint
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('pending', 'paid', 'processing', 'completed', 'cancelled'));
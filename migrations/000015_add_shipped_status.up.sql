@@ -0,0 +1,4 @@
+-- Allow orders to be marked shipped between payment or processing and completion
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('pending', 'paid', 'processing', 'shipped', 'completed', 'cancelled'));
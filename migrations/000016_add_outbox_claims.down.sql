@@ -0,0 +1,6 @@
+-- Drop outbox claims and parking; parked events become pending again
+DROP INDEX IF EXISTS idx_outbox_pending;
+CREATE INDEX IF NOT EXISTS idx_outbox_unsent ON outbox(id) WHERE sent_at IS NULL;
+
+ALTER TABLE outbox DROP COLUMN IF EXISTS parked_at;
+ALTER TABLE outbox DROP COLUMN IF EXISTS claimed_until;
@@ -0,0 +1,7 @@
+-- Let dispatchers claim outbox rows for a lease instead of holding row locks while they
+-- deliver, and park events that keep failing so they are no longer retried
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS parked_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_outbox_unsent;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE sent_at IS NULL AND parked_at IS NULL;
@@ -0,0 +1,21 @@
+-- Restore trigger-maintained order totals
+CREATE OR REPLACE FUNCTION recompute_order_total() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE orders
+        SET total_amount = (SELECT COALESCE(SUM(total_price), 0) FROM order_items WHERE order_id = OLD.order_id)
+        WHERE id = OLD.order_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE orders
+        SET total_amount = (SELECT COALESCE(SUM(total_price), 0) FROM order_items WHERE order_id = NEW.order_id)
+        WHERE id = NEW.order_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_order_items_total ON order_items;
+CREATE TRIGGER trg_order_items_total
+    AFTER INSERT OR UPDATE OF order_id, total_price OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION recompute_order_total();
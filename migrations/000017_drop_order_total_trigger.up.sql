@@ -0,0 +1,5 @@
+-- Stop maintaining order totals with a per-row trigger. It ran for every deployment and
+-- re-summed the order on each item insert; DATABASE_TOTALS now computes the total once per
+-- write in the statement that stores it.
+DROP TRIGGER IF EXISTS trg_order_items_total ON order_items;
+DROP FUNCTION IF EXISTS recompute_order_total();
@@ -0,0 +1,75 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrLimitExceeded is returned when a slot could not be acquired within the wait timeout
+var ErrLimitExceeded = errors.New("concurrency limit exceeded")
+
+// Limiter is a counting semaphore that bounds how many operations run at once.
+// Callers that cannot get a slot within MaxWait are shed with ErrLimitExceeded.
+type Limiter struct {
+	slots   chan struct{}
+	maxWait time.Duration
+}
+
+// NewLimiter creates a limiter allowing size concurrent operations.
+// A non-positive size returns nil, which Acquire treats as unlimited.
+func NewLimiter(size int, maxWait time.Duration) *Limiter {
+	if size <= 0 {
+		return nil
+	}
+	return &Limiter{
+		slots:   make(chan struct{}, size),
+		maxWait: maxWait,
+	}
+}
+
+// Acquire waits up to MaxWait for a free slot and returns a function that releases it
+func (l *Limiter) Acquire(ctx context.Context) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// Fast path when a slot is free
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	default:
+	}
+
+	timer := time.NewTimer(l.maxWait)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.release, nil
+	case <-timer.C:
+		return nil, ErrLimitExceeded
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// InUse returns the number of slots currently held
+func (l *Limiter) InUse() int {
+	if l == nil {
+		return 0
+	}
+	return len(l.slots)
+}
+
+// Size returns the maximum number of concurrent operations
+func (l *Limiter) Size() int {
+	if l == nil {
+		return 0
+	}
+	return cap(l.slots)
+}
+
+func (l *Limiter) release() {
+	<-l.slots
+}
@@ -0,0 +1,45 @@
+package concurrency
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestLimiter_ShedsAfterWaitTimeout(t *testing.T) {
+	limiter := NewLimiter(2, 20*time.Millisecond)
+
+	releaseA, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	start := time.Now()
+	if _, err := limiter.Acquire(context.Background()); !errors.Is(err, ErrLimitExceeded) {
+		t.Fatalf("expected ErrLimitExceeded, got %v", err)
+	}
+	if waited := time.Since(start); waited < 20*time.Millisecond {
+		t.Errorf("expected to wait for the timeout before shedding, waited %v", waited)
+	}
+
+	releaseA()
+	if _, err := limiter.Acquire(context.Background()); err != nil {
+		t.Fatalf("expected a slot after release, got %v", err)
+	}
+}
+
+func TestLimiter_NilIsUnlimited(t *testing.T) {
+	limiter := NewLimiter(0, time.Millisecond)
+	if limiter != nil {
+		t.Fatal("expected a nil limiter for size 0")
+	}
+	for i := 0; i < 100; i++ {
+		if _, err := limiter.Acquire(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
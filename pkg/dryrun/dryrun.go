@@ -0,0 +1,17 @@
+package dryrun
+
+import "context"
+
+type dryRunKey struct{}
+
+// With returns a context whose writes are rolled back instead of committed, so a create,
+// update or delete runs every check and reports its outcome without changing any data
+func With(ctx context.Context) context.Context {
+	return context.WithValue(ctx, dryRunKey{}, true)
+}
+
+// Enabled reports whether writes made with ctx must be rolled back
+func Enabled(ctx context.Context) bool {
+	enabled, _ := ctx.Value(dryRunKey{}).(bool)
+	return enabled
+}
@@ -0,0 +1,72 @@
+package errorlog
+
+import (
+	"sync"
+	"time"
+)
+
+// Entry is one error surfaced to a client. It deliberately omits error details and
+// request data so that no customer information is retained.
+type Entry struct {
+	Code      string    `json:"code"`
+	Message   string    `json:"message"`
+	TraceID   string    `json:"trace_id,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Ring keeps the most recent entries in a fixed-size ring buffer. A nil *Ring records
+// nothing, so callers need not check whether capturing is enabled.
+type Ring struct {
+	mu      sync.Mutex
+	entries []Entry
+	next    int  // Index the next entry is written to
+	full    bool // Whether the buffer has wrapped
+	now     func() time.Time
+}
+
+// NewRing returns a ring holding the last size entries, or nil (disabled) when size <= 0
+func NewRing(size int) *Ring {
+	if size <= 0 {
+		return nil
+	}
+	return &Ring{entries: make([]Entry, size), now: time.Now}
+}
+
+// Record stores an entry, evicting the oldest one when the ring is full
+func (r *Ring) Record(code string, message string, traceID string) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.entries[r.next] = Entry{Code: code, Message: message, TraceID: traceID, Timestamp: r.now().UTC()}
+	r.next = (r.next + 1) % len(r.entries)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Entries returns the recorded entries, oldest first
+func (r *Ring) Entries() []Entry {
+	if r == nil {
+		return []Entry{}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]Entry{}, r.entries[:r.next]...)
+	}
+	return append(append([]Entry{}, r.entries[r.next:]...), r.entries[:r.next]...)
+}
+
+// Size returns the number of entries the ring keeps (0 when disabled)
+func (r *Ring) Size() int {
+	if r == nil {
+		return 0
+	}
+	return len(r.entries)
+}
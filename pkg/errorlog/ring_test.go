@@ -0,0 +1,42 @@
+package errorlog
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestRing_KeepsLastEntriesInOrder(t *testing.T) {
+	ring := NewRing(3)
+	for i := 1; i <= 5; i++ {
+		ring.Record("VALIDATION", fmt.Sprintf("error %d", i), fmt.Sprintf("trace-%d", i))
+	}
+
+	entries := ring.Entries()
+	if len(entries) != 3 {
+		t.Fatalf("expected the ring to cap at 3 entries, got %d", len(entries))
+	}
+	for i, entry := range entries {
+		if want := fmt.Sprintf("error %d", i+3); entry.Message != want {
+			t.Errorf("entry %d: expected %q, got %q", i, want, entry.Message)
+		}
+	}
+}
+
+func TestRing_PartiallyFilled(t *testing.T) {
+	ring := NewRing(3)
+	ring.Record("NOT_FOUND", "order", "trace-1")
+
+	entries := ring.Entries()
+	if len(entries) != 1 || entries[0].Code != "NOT_FOUND" || entries[0].TraceID != "trace-1" {
+		t.Errorf("expected the single recorded entry, got %+v", entries)
+	}
+}
+
+func TestRing_DisabledWhenSizeIsZero(t *testing.T) {
+	ring := NewRing(0)
+	ring.Record("NOT_FOUND", "order", "")
+
+	if ring != nil || len(ring.Entries()) != 0 || ring.Size() != 0 {
+		t.Errorf("expected a disabled ring to record nothing")
+	}
+}
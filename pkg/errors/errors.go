@@ -1,6 +1,7 @@
 package errors
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"net/http"
@@ -43,7 +44,9 @@ const (
 	ErrCodeDatabaseTransaction ErrorCode = "DATABASE_TRANSACTION"
 	ErrCodeExternalService     ErrorCode = "EXTERNAL_SERVICE"
 	ErrCodeTimeout             ErrorCode = "TIMEOUT"
+	ErrCodeClientClosedRequest ErrorCode = "CLIENT_CLOSED_REQUEST"
 	ErrCodeNetworkError        ErrorCode = "NETWORK_ERROR"
+	ErrCodeServiceUnavailable  ErrorCode = "SERVICE_UNAVAILABLE"
 
 	// Generic API errors
 	ErrCodeValidation     ErrorCode = "VALIDATION"
@@ -62,6 +65,8 @@ type AppError struct {
 	Details    map[string]interface{} `json:"details,omitempty"`
 	Cause      error                  `json:"-"`
 	HTTPStatus int                    `json:"-"`
+	// Retryable tells clients whether sending the same request again may succeed
+	Retryable bool `json:"-"`
 }
 
 func (e *AppError) Error() string {
@@ -75,19 +80,52 @@ func (e *AppError) Unwrap() error {
 	return e.Cause
 }
 
-// Is implements error matching
+// Is implements error matching for errors.Is. An AppError matches one of the package's
+// sentinel errors (ErrNotFound, ErrValidation, ...) when their codes are equal, whichever
+// layer created it; any other AppError target must match both Type and Code.
 func (e *AppError) Is(target error) bool {
 	if target == nil {
 		return false
 	}
 
 	if appErr, ok := target.(*AppError); ok {
+		if sentinels[appErr] {
+			return e.Code == appErr.Code
+		}
 		return e.Code == appErr.Code && e.Type == appErr.Type
 	}
 
 	return false
 }
 
+// Sentinel errors for errors.Is, matching AppErrors by code alone
+var (
+	ErrInvalidEntity         = newSentinel(ErrorTypeDomain, ErrCodeInvalidEntity)
+	ErrBusinessRuleViolation = newSentinel(ErrorTypeDomain, ErrCodeBusinessRuleViolation)
+	ErrNotFound              = newSentinel(ErrorTypeUseCase, ErrCodeNotFound)
+	ErrAlreadyExists         = newSentinel(ErrorTypeUseCase, ErrCodeAlreadyExists)
+	ErrInvalidOperation      = newSentinel(ErrorTypeUseCase, ErrCodeInvalidOperation)
+	ErrPermissionDenied      = newSentinel(ErrorTypeUseCase, ErrCodePermissionDenied)
+	ErrTimeout               = newSentinel(ErrorTypeInfrastructure, ErrCodeTimeout)
+	ErrClientClosedRequest   = newSentinel(ErrorTypeInfrastructure, ErrCodeClientClosedRequest)
+	ErrServiceUnavailable    = newSentinel(ErrorTypeInfrastructure, ErrCodeServiceUnavailable)
+	ErrValidation            = newSentinel(ErrorTypeAPI, ErrCodeValidation)
+	ErrAuthentication        = newSentinel(ErrorTypeAPI, ErrCodeAuthentication)
+	ErrAuthorization         = newSentinel(ErrorTypeAPI, ErrCodeAuthorization)
+	ErrRateLimit             = newSentinel(ErrorTypeAPI, ErrCodeRateLimit)
+	ErrBadRequest            = newSentinel(ErrorTypeAPI, ErrCodeBadRequest)
+)
+
+// sentinels holds the sentinel errors by identity, so copies made by WithDetails or
+// WithCause are matched strictly again
+var sentinels = map[*AppError]bool{}
+
+func newSentinel(errorType ErrorType, code ErrorCode) *AppError {
+	sentinel := NewAppError(errorType, code, string(code))
+	sentinels[sentinel] = true
+	return sentinel
+}
+
 // WithDetails adds details to the error
 func (e *AppError) WithDetails(details map[string]interface{}) *AppError {
 	newErr := *e
@@ -100,6 +138,13 @@ func (e *AppError) WithDetails(details map[string]interface{}) *AppError {
 	return &newErr
 }
 
+// WithRetryable overrides whether the error is worth retrying
+func (e *AppError) WithRetryable(retryable bool) *AppError {
+	newErr := *e
+	newErr.Retryable = retryable
+	return &newErr
+}
+
 // WithCause adds a cause to the error
 func (e *AppError) WithCause(cause error) *AppError {
 	newErr := *e
@@ -115,9 +160,26 @@ func NewAppError(errorType ErrorType, code ErrorCode, message string) *AppError
 		Code:       code,
 		Message:    message,
 		HTTPStatus: httpStatus,
+		Retryable:  isRetryableCode(code),
 	}
 }
 
+// isRetryableCode reports whether errors with code are transient, so that sending the same
+// request again may succeed
+func isRetryableCode(code ErrorCode) bool {
+	switch code {
+	case ErrCodeDatabaseConnection, ErrCodeDatabaseTransaction, ErrCodeTimeout,
+		ErrCodeNetworkError, ErrCodeServiceUnavailable, ErrCodeRateLimit:
+		return true
+	default:
+		return false
+	}
+}
+
+// StatusClientClosedRequest is the non-standard status (from nginx) recorded for requests
+// whose client went away before the response; the client never sees it
+const StatusClientClosedRequest = 499
+
 // getHTTPStatusFromCode maps error codes to HTTP status codes
 func getHTTPStatusFromCode(code ErrorCode) int {
 	switch code {
@@ -125,8 +187,11 @@ func getHTTPStatusFromCode(code ErrorCode) int {
 		return http.StatusNotFound
 	case ErrCodeAlreadyExists:
 		return http.StatusConflict
-	case ErrCodeValidation, ErrCodeInvalidEntity, ErrCodeBusinessRuleViolation, ErrCodeBadRequest:
+	case ErrCodeValidation, ErrCodeInvalidEntity, ErrCodeBadRequest:
 		return http.StatusBadRequest
+	case ErrCodeBusinessRuleViolation:
+		// Well-formed requests that the business rules do not allow
+		return http.StatusUnprocessableEntity
 	case ErrCodeAuthentication:
 		return http.StatusUnauthorized
 	case ErrCodeAuthorization, ErrCodePermissionDenied:
@@ -134,7 +199,12 @@ func getHTTPStatusFromCode(code ErrorCode) int {
 	case ErrCodeRateLimit:
 		return http.StatusTooManyRequests
 	case ErrCodeTimeout:
-		return http.StatusRequestTimeout
+		// A dependency, such as the database, did not answer in time
+		return http.StatusGatewayTimeout
+	case ErrCodeClientClosedRequest:
+		return StatusClientClosedRequest
+	case ErrCodeServiceUnavailable:
+		return http.StatusServiceUnavailable
 	case ErrCodeDatabaseConnection, ErrCodeDatabaseQuery, ErrCodeDatabaseTransaction,
 		ErrCodeExternalService, ErrCodeNetworkError, ErrCodeInternalError:
 		return http.StatusInternalServerError
@@ -205,10 +275,32 @@ func NewTimeoutError(message string) *AppError {
 	return NewInfrastructureError(ErrCodeTimeout, message)
 }
 
+func NewClientClosedRequestError(message string) *AppError {
+	return NewInfrastructureError(ErrCodeClientClosedRequest, message)
+}
+
+// ContextError converts an error caused by the cancellation or deadline of a context into a
+// CLIENT_CLOSED_REQUEST (499) or TIMEOUT (504) error with message, keeping err as the cause.
+// Other errors give nil.
+func ContextError(err error, message string) *AppError {
+	switch {
+	case errors.Is(err, context.Canceled):
+		return NewClientClosedRequestError(message).WithCause(err)
+	case errors.Is(err, context.DeadlineExceeded):
+		return NewTimeoutError(message).WithCause(err)
+	default:
+		return nil
+	}
+}
+
 func NewNetworkError(message string) *AppError {
 	return NewInfrastructureError(ErrCodeNetworkError, message)
 }
 
+func NewServiceUnavailableError(message string) *AppError {
+	return NewInfrastructureError(ErrCodeServiceUnavailable, message)
+}
+
 func NewValidationError(message string) *AppError {
 	return NewAPIError(ErrCodeValidation, message)
 }
@@ -247,10 +339,24 @@ func GetAppError(err error) *AppError {
 	return nil
 }
 
+// IsRetryable reports whether sending the request that failed with err again may succeed
+func IsRetryable(err error) bool {
+	if appErr := GetAppError(err); appErr != nil {
+		return appErr.Retryable
+	}
+	if ctxErr := ContextError(err, ""); ctxErr != nil {
+		return ctxErr.Retryable
+	}
+	return false
+}
+
 func GetHTTPStatus(err error) int {
 	if appErr := GetAppError(err); appErr != nil {
 		return appErr.HTTPStatus
 	}
+	if ctxErr := ContextError(err, ""); ctxErr != nil {
+		return ctxErr.HTTPStatus
+	}
 	return http.StatusInternalServerError
 }
 
@@ -264,11 +370,17 @@ type ErrorInfo struct {
 	Code    ErrorCode              `json:"code"`
 	Message string                 `json:"message"`
 	Details map[string]interface{} `json:"details,omitempty"`
+	// Retryable tells clients whether sending the same request again may succeed
+	Retryable bool `json:"retryable"`
 }
 
-// ToErrorResponse converts an error to API error response
+// ToErrorResponse converts an error to API error response. Its details merge those of every
+// AppError in the cause chain (see chainDetails).
 func ToErrorResponse(err error, traceID string) ErrorResponse {
 	appErr := GetAppError(err)
+	if appErr == nil {
+		appErr = ContextError(err, "The request was cancelled or timed out")
+	}
 	if appErr == nil {
 		// Handle non-app errors
 		return ErrorResponse{
@@ -282,10 +394,35 @@ func ToErrorResponse(err error, traceID string) ErrorResponse {
 
 	return ErrorResponse{
 		Error: ErrorInfo{
-			Code:    appErr.Code,
-			Message: appErr.Message,
-			Details: appErr.Details,
+			Code:      appErr.Code,
+			Message:   appErr.Message,
+			Details:   chainDetails(appErr),
+			Retryable: appErr.Retryable,
 		},
 		TraceID: traceID,
 	}
 }
+
+// chainDetails merges the details of appErr with those of the AppErrors wrapped in its
+// causes, so details such as item_index survive being re-wrapped by another layer. Outer
+// errors win on key conflicts. The errors' own maps are never modified.
+func chainDetails(appErr *AppError) map[string]interface{} {
+	var details map[string]interface{}
+	for current := appErr; current != nil; {
+		for key, value := range current.Details {
+			if details == nil {
+				details = make(map[string]interface{}, len(current.Details))
+			}
+			if _, ok := details[key]; !ok {
+				details[key] = value
+			}
+		}
+
+		var next *AppError
+		if current.Cause == nil || !errors.As(current.Cause, &next) {
+			break
+		}
+		current = next
+	}
+	return details
+}
@@ -0,0 +1,186 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+)
+
+func TestGetHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err    error
+		status int
+	}{
+		{NewValidationError("missing field"), http.StatusBadRequest},
+		{NewInvalidEntityError("bad entity"), http.StatusBadRequest},
+		{NewBusinessRuleViolationError("transition not allowed"), http.StatusUnprocessableEntity},
+		{NewAlreadyExistsError("duplicate"), http.StatusConflict},
+		{NewDatabaseQueryError("query failed"), http.StatusInternalServerError},
+		{NewTimeoutError("query timed out"), http.StatusGatewayTimeout},
+		{NewClientClosedRequestError("client went away"), StatusClientClosedRequest},
+		{context.DeadlineExceeded, http.StatusGatewayTimeout},
+		{fmt.Errorf("query failed: %w", context.Canceled), StatusClientClosedRequest},
+		{ContextError(context.Canceled, "cancelled"), StatusClientClosedRequest},
+		{ContextError(context.DeadlineExceeded, "timed out"), http.StatusGatewayTimeout},
+	}
+
+	for _, tt := range tests {
+		if got := GetHTTPStatus(tt.err); got != tt.status {
+			t.Errorf("%v: expected %d, got %d", tt.err, tt.status, got)
+		}
+	}
+}
+
+func TestContextError(t *testing.T) {
+	if err := ContextError(fmt.Errorf("wrapped: %w", context.Canceled), "cancelled"); err == nil || err.Code != ErrCodeClientClosedRequest {
+		t.Errorf("expected CLIENT_CLOSED_REQUEST for a cancellation, got %v", err)
+	}
+	if err := ContextError(context.DeadlineExceeded, "timed out"); err == nil || err.Code != ErrCodeTimeout {
+		t.Errorf("expected TIMEOUT for a deadline, got %v", err)
+	}
+	if err := ContextError(fmt.Errorf("syntax error"), "failed"); err != nil {
+		t.Errorf("expected nil for other errors, got %v", err)
+	}
+
+	response := ToErrorResponse(fmt.Errorf("query failed: %w", context.Canceled), "trace-1")
+	if response.Error.Code != ErrCodeClientClosedRequest {
+		t.Errorf("expected a bare cancellation to render as CLIENT_CLOSED_REQUEST, got %s", response.Error.Code)
+	}
+}
+
+func TestIs_SentinelsMatchByCodeAcrossLayers(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		sentinel error
+	}{
+		{"use case not found", NewNotFoundError("order 1 not found"), ErrNotFound},
+		{"domain not found", NewDomainError(ErrCodeNotFound, "order 1 not found"), ErrNotFound},
+		{"API validation", NewValidationError("bad input"), ErrValidation},
+		{"domain validation", NewDomainError(ErrCodeValidation, "bad input"), ErrValidation},
+		{"wrapped timeout", fmt.Errorf("create: %w", NewTimeoutError("slow query").WithDetails(map[string]interface{}{"timeout": "5s"})), ErrTimeout},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !errors.Is(tt.err, tt.sentinel) {
+				t.Errorf("expected %v to match %v", tt.err, tt.sentinel)
+			}
+		})
+	}
+
+	if errors.Is(NewNotFoundError("missing"), ErrValidation) {
+		t.Error("expected sentinels with another code not to match")
+	}
+}
+
+func TestIs_NonSentinelsMatchStrictly(t *testing.T) {
+	target := NewNotFoundError("order not found")
+
+	if !errors.Is(NewNotFoundError("another order not found"), target) {
+		t.Error("expected the same type and code to match")
+	}
+	if errors.Is(NewDomainError(ErrCodeNotFound, "order not found"), target) {
+		t.Error("expected another layer not to match a non-sentinel target")
+	}
+	// Copies of a sentinel are ordinary errors again
+	if errors.Is(NewDomainError(ErrCodeNotFound, "order not found"), ErrNotFound.WithCause(nil)) {
+		t.Error("expected a copy of a sentinel to match strictly")
+	}
+}
+
+func TestToErrorResponse_MergesDetailsThroughTheChain(t *testing.T) {
+	// An entity validation error, as built by entity.NewOrder
+	entityErr := NewInvalidEntityError("item quantity must be greater than 0").WithDetails(map[string]interface{}{
+		"item_index": 2,
+		"quantity":   0,
+	})
+	// Re-wrapped by a use case, then by a plain fmt wrapper
+	useCaseErr := NewBusinessRuleViolationError("cannot create order").WithDetails(map[string]interface{}{
+		"customer_name": "Acme Corp",
+		"quantity":      "outer",
+	}).WithCause(fmt.Errorf("validate: %w", entityErr))
+
+	response := ToErrorResponse(useCaseErr, "trace-1")
+
+	if response.Error.Code != ErrCodeBusinessRuleViolation {
+		t.Errorf("expected the outer code, got %s", response.Error.Code)
+	}
+	want := map[string]interface{}{
+		"item_index":    2,
+		"customer_name": "Acme Corp",
+		"quantity":      "outer",
+	}
+	if len(response.Error.Details) != len(want) {
+		t.Fatalf("expected details %v, got %v", want, response.Error.Details)
+	}
+	for key, value := range want {
+		if response.Error.Details[key] != value {
+			t.Errorf("details[%s]: expected %v, got %v", key, value, response.Error.Details[key])
+		}
+	}
+	if _, ok := useCaseErr.Details["item_index"]; ok {
+		t.Error("expected the outer error's own details to be left unchanged")
+	}
+}
+
+func TestToErrorResponse_NoDetails(t *testing.T) {
+	response := ToErrorResponse(NewNotFoundError("order not found").WithCause(NewDatabaseQueryError("no rows")), "")
+	if response.Error.Details != nil {
+		t.Errorf("expected no details, got %v", response.Error.Details)
+	}
+}
+
+func TestRetryable_SetByConstructors(t *testing.T) {
+	tests := []struct {
+		err       *AppError
+		retryable bool
+	}{
+		{NewDatabaseConnectionError("pool exhausted"), true},
+		{NewDatabaseTransactionError("serialization failure"), true},
+		{NewTimeoutError("query timed out"), true},
+		{NewNetworkError("connection reset"), true},
+		{NewServiceUnavailableError("busy"), true},
+		{NewRateLimitError("slow down"), true},
+		{NewValidationError("missing field"), false},
+		{NewNotFoundError("order not found"), false},
+		{NewInvalidEntityError("bad entity"), false},
+		{NewBusinessRuleViolationError("transition not allowed"), false},
+		{NewAlreadyExistsError("duplicate"), false},
+		{NewDatabaseQueryError("syntax error"), false},
+		{NewClientClosedRequestError("client went away"), false},
+		{NewInternalError("bug"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.err.Code), func(t *testing.T) {
+			if tt.err.Retryable != tt.retryable {
+				t.Errorf("expected Retryable %v, got %v", tt.retryable, tt.err.Retryable)
+			}
+			if got := IsRetryable(fmt.Errorf("wrapped: %w", tt.err)); got != tt.retryable {
+				t.Errorf("expected IsRetryable %v, got %v", tt.retryable, got)
+			}
+			if got := ToErrorResponse(tt.err, "").Error.Retryable; got != tt.retryable {
+				t.Errorf("expected retryable %v in the response, got %v", tt.retryable, got)
+			}
+		})
+	}
+}
+
+func TestRetryable_Overrides(t *testing.T) {
+	err := NewDatabaseTransactionError("constraint violated").WithRetryable(false)
+	if IsRetryable(err) {
+		t.Error("expected WithRetryable(false) to win over the code")
+	}
+	if !NewDatabaseTransactionError("deadlock").WithDetails(map[string]interface{}{"attempt": 3}).Retryable {
+		t.Error("expected builders to keep the flag")
+	}
+	if !IsRetryable(context.DeadlineExceeded) {
+		t.Error("expected a deadline to be retryable")
+	}
+	if IsRetryable(fmt.Errorf("plain failure")) {
+		t.Error("expected unknown errors not to be retryable")
+	}
+}
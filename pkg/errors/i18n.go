@@ -0,0 +1,141 @@
+package errors
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Supported locales for error messages
+const (
+	LocaleEnglish = "en"
+	LocaleThai    = "th"
+	LocaleSpanish = "es"
+)
+
+// DefaultLocale is used when the client does not request a supported locale.
+// English responses keep the original, most detailed error message.
+const DefaultLocale = LocaleEnglish
+
+// messageCatalog holds translated messages keyed by locale and error code.
+// Codes missing from a catalog fall back to the original English message.
+var messageCatalog = map[string]map[ErrorCode]string{
+	LocaleThai: {
+		ErrCodeInvalidEntity:         "ข้อมูลไม่ถูกต้อง",
+		ErrCodeBusinessRuleViolation: "คำขอขัดกับกฎทางธุรกิจ",
+		ErrCodeNotFound:              "ไม่พบข้อมูลที่ร้องขอ",
+		ErrCodeAlreadyExists:         "ข้อมูลนี้มีอยู่แล้ว",
+		ErrCodeInvalidOperation:      "ไม่สามารถดำเนินการนี้ได้",
+		ErrCodePermissionDenied:      "ไม่มีสิทธิ์ดำเนินการ",
+		ErrCodeDatabaseConnection:    "ไม่สามารถเชื่อมต่อฐานข้อมูลได้",
+		ErrCodeDatabaseQuery:         "เกิดข้อผิดพลาดในการดึงข้อมูล",
+		ErrCodeDatabaseTransaction:   "ไม่สามารถบันทึกข้อมูลได้",
+		ErrCodeExternalService:       "บริการภายนอกขัดข้อง",
+		ErrCodeTimeout:               "หมดเวลาในการดำเนินการ",
+		ErrCodeClientClosedRequest:   "คำขอถูกยกเลิก",
+		ErrCodeNetworkError:          "เกิดข้อผิดพลาดของเครือข่าย",
+		ErrCodeServiceUnavailable:    "ระบบไม่ว่าง กรุณาลองใหม่อีกครั้ง",
+		ErrCodeValidation:            "ข้อมูลที่ส่งมาไม่ถูกต้อง",
+		ErrCodeAuthentication:        "กรุณายืนยันตัวตน",
+		ErrCodeAuthorization:         "ไม่ได้รับอนุญาต",
+		ErrCodeRateLimit:             "มีคำขอมากเกินไป กรุณาลองใหม่ภายหลัง",
+		ErrCodeBadRequest:            "คำขอไม่ถูกต้อง",
+		ErrCodeInternalError:         "เกิดข้อผิดพลาดภายในระบบ",
+	},
+	LocaleSpanish: {
+		ErrCodeInvalidEntity:         "Los datos no son válidos",
+		ErrCodeBusinessRuleViolation: "La solicitud infringe una regla de negocio",
+		ErrCodeNotFound:              "No se encontró el recurso solicitado",
+		ErrCodeAlreadyExists:         "El recurso ya existe",
+		ErrCodeInvalidOperation:      "Operación no válida",
+		ErrCodePermissionDenied:      "Permiso denegado",
+		ErrCodeDatabaseConnection:    "No se pudo conectar a la base de datos",
+		ErrCodeDatabaseQuery:         "Error al consultar los datos",
+		ErrCodeDatabaseTransaction:   "No se pudieron guardar los datos",
+		ErrCodeExternalService:       "Un servicio externo falló",
+		ErrCodeTimeout:               "La operación excedió el tiempo de espera",
+		ErrCodeClientClosedRequest:   "La solicitud fue cancelada",
+		ErrCodeNetworkError:          "Error de red",
+		ErrCodeServiceUnavailable:    "El servidor está ocupado, inténtelo de nuevo más tarde",
+		ErrCodeValidation:            "Los datos de la solicitud no son válidos",
+		ErrCodeAuthentication:        "Se requiere autenticación",
+		ErrCodeAuthorization:         "No autorizado",
+		ErrCodeRateLimit:             "Demasiadas solicitudes, inténtelo más tarde",
+		ErrCodeBadRequest:            "Solicitud incorrecta",
+		ErrCodeInternalError:         "Ocurrió un error interno",
+	},
+}
+
+// IsSupportedLocale reports whether messages are available for the locale
+func IsSupportedLocale(locale string) bool {
+	if locale == DefaultLocale {
+		return true
+	}
+	_, ok := messageCatalog[locale]
+	return ok
+}
+
+// ResolveLocale picks the best supported locale from an Accept-Language header value,
+// honoring quality weights and falling back to DefaultLocale
+func ResolveLocale(acceptLanguage string) string {
+	type candidate struct {
+		locale  string
+		quality float64
+	}
+
+	var candidates []candidate
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		tag := strings.ToLower(strings.TrimSpace(fields[0]))
+		if tag == "" {
+			continue
+		}
+
+		quality := 1.0
+		for _, param := range fields[1:] {
+			param = strings.TrimSpace(param)
+			if strings.HasPrefix(param, "q=") {
+				if q, err := strconv.ParseFloat(strings.TrimPrefix(param, "q="), 64); err == nil {
+					quality = q
+				}
+			}
+		}
+
+		// Match on the primary language subtag, e.g. "th-TH" -> "th"
+		base := strings.SplitN(tag, "-", 2)[0]
+		candidates = append(candidates, candidate{locale: base, quality: quality})
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].quality > candidates[j].quality
+	})
+
+	for _, c := range candidates {
+		if c.quality > 0 && IsSupportedLocale(c.locale) {
+			return c.locale
+		}
+	}
+	return DefaultLocale
+}
+
+// LocalizedMessage returns the catalog message for a code in the given locale
+func LocalizedMessage(code ErrorCode, locale string) (string, bool) {
+	messages, ok := messageCatalog[locale]
+	if !ok {
+		return "", false
+	}
+	message, ok := messages[code]
+	return message, ok
+}
+
+// ToLocalizedErrorResponse converts an error to an API error response whose message
+// is translated according to the Accept-Language header. The code is never translated.
+func ToLocalizedErrorResponse(err error, traceID string, acceptLanguage string) ErrorResponse {
+	response := ToErrorResponse(err, traceID)
+
+	locale := ResolveLocale(acceptLanguage)
+	if message, ok := LocalizedMessage(response.Error.Code, locale); ok {
+		response.Error.Message = message
+	}
+	return response
+}
@@ -0,0 +1,49 @@
+package errors
+
+import "testing"
+
+func TestResolveLocale(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", LocaleEnglish},
+		{"th", LocaleThai},
+		{"th-TH,th;q=0.9,en;q=0.8", LocaleThai},
+		{"fr-FR, es;q=0.7", LocaleSpanish},
+		{"en;q=0.5, es;q=0.9", LocaleSpanish},
+		{"de-DE, fr;q=0.8", LocaleEnglish},
+		{"es;q=0", LocaleEnglish},
+	}
+
+	for _, tt := range tests {
+		if got := ResolveLocale(tt.header); got != tt.expected {
+			t.Errorf("ResolveLocale(%q) = %q, expected %q", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestToLocalizedErrorResponse(t *testing.T) {
+	err := NewValidationError("Customer name is required")
+
+	thai := ToLocalizedErrorResponse(err, "trace-1", "th-TH")
+	if thai.Error.Message != messageCatalog[LocaleThai][ErrCodeValidation] {
+		t.Errorf("expected Thai message, got %q", thai.Error.Message)
+	}
+	if thai.Error.Code != ErrCodeValidation {
+		t.Errorf("expected code to stay %s, got %s", ErrCodeValidation, thai.Error.Code)
+	}
+
+	spanish := ToLocalizedErrorResponse(err, "trace-1", "es")
+	if spanish.Error.Message != "Los datos de la solicitud no son válidos" {
+		t.Errorf("expected Spanish message, got %q", spanish.Error.Message)
+	}
+
+	fallback := ToLocalizedErrorResponse(err, "trace-1", "de-DE")
+	if fallback.Error.Message != "Customer name is required" {
+		t.Errorf("expected the original English message for an unsupported locale, got %q", fallback.Error.Message)
+	}
+	if fallback.TraceID != "trace-1" {
+		t.Errorf("expected trace id to be preserved, got %q", fallback.TraceID)
+	}
+}
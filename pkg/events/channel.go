@@ -0,0 +1,128 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrBufferFull is returned by ChannelPublisher.Publish when the event buffer is full
+var ErrBufferFull = errors.New("event buffer is full")
+
+// ErrPublisherClosed is returned by ChannelPublisher.Publish after Close
+var ErrPublisherClosed = errors.New("event publisher is closed")
+
+// Handler reacts to a published event, e.g. by sending an email
+type Handler func(ctx context.Context, event Event) error
+
+// queuedEvent is an event waiting in the buffer with the context it was published with
+type queuedEvent struct {
+	ctx   context.Context
+	event Event
+}
+
+// ChannelPublisher delivers events in process: Publish queues them on a buffered channel
+// and a single goroutine hands them to the registered handlers in publish order, so slow
+// handlers never delay the order operation that published the event.
+type ChannelPublisher struct {
+	queue   chan queuedEvent
+	onError func(event Event, err error)
+	done    chan struct{}
+
+	mu       sync.RWMutex
+	handlers map[string][]Handler
+	closed   bool
+}
+
+// ChannelPublisherOption configures optional behavior of ChannelPublisher
+type ChannelPublisherOption func(*ChannelPublisher)
+
+// WithHandlerErrors calls onError with every error returned (or panic raised) by a handler.
+// Handler errors are dropped otherwise.
+func WithHandlerErrors(onError func(event Event, err error)) ChannelPublisherOption {
+	return func(p *ChannelPublisher) {
+		p.onError = onError
+	}
+}
+
+// NewChannelPublisher creates a publisher buffering up to buffer events and starts its
+// delivery goroutine. Call Close to stop it.
+func NewChannelPublisher(buffer int, opts ...ChannelPublisherOption) *ChannelPublisher {
+	if buffer < 0 {
+		buffer = 0
+	}
+	p := &ChannelPublisher{
+		queue:    make(chan queuedEvent, buffer),
+		done:     make(chan struct{}),
+		handlers: make(map[string][]Handler),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	go p.deliver()
+	return p
+}
+
+// Subscribe registers handler for events of eventType, or for every event when eventType
+// is empty
+func (p *ChannelPublisher) Subscribe(eventType string, handler Handler) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.handlers[eventType] = append(p.handlers[eventType], handler)
+}
+
+// Publish queues the event without waiting for its handlers. It fails with ErrBufferFull
+// instead of blocking when the buffer is full, and with ErrPublisherClosed after Close.
+// Handlers get ctx without its cancellation, so they still run after the request ends.
+func (p *ChannelPublisher) Publish(ctx context.Context, event Event) error {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.closed {
+		return ErrPublisherClosed
+	}
+
+	select {
+	case p.queue <- queuedEvent{ctx: context.WithoutCancel(ctx), event: event}:
+		return nil
+	default:
+		return fmt.Errorf("failed to queue event %s: %w", event.ID, ErrBufferFull)
+	}
+}
+
+// Close stops accepting events and waits until the queued ones have been delivered
+func (p *ChannelPublisher) Close() {
+	p.mu.Lock()
+	if !p.closed {
+		p.closed = true
+		close(p.queue)
+	}
+	p.mu.Unlock()
+	<-p.done
+}
+
+// deliver hands queued events to their handlers until the queue is closed and drained
+func (p *ChannelPublisher) deliver() {
+	defer close(p.done)
+	for queued := range p.queue {
+		p.mu.RLock()
+		handlers := append(append([]Handler(nil), p.handlers[queued.event.Type]...), p.handlers[""]...)
+		p.mu.RUnlock()
+
+		for _, handler := range handlers {
+			if err := callHandler(queued.ctx, handler, queued.event); err != nil && p.onError != nil {
+				p.onError(queued.event, err)
+			}
+		}
+	}
+}
+
+// callHandler runs handler, turning a panic into an error so one handler cannot stop delivery
+func callHandler(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("event handler panicked: %v", r)
+		}
+	}()
+	return handler(ctx, event)
+}
@@ -0,0 +1,94 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestChannelPublisher_DeliversToSubscribedHandlers(t *testing.T) {
+	publisher := NewChannelPublisher(10)
+
+	var mu sync.Mutex
+	var created, all []Event
+	publisher.Subscribe(TypeOrderCreated, func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		created = append(created, event)
+		return nil
+	})
+	publisher.Subscribe("", func(ctx context.Context, event Event) error {
+		mu.Lock()
+		defer mu.Unlock()
+		all = append(all, event)
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	first := NewEvent(TypeOrderCreated, OrderCreated{OrderID: 1})
+	second := NewEvent(TypeOrderStatusChanged, OrderStatusChanged{OrderID: 1, OldStatus: "pending", NewStatus: "paid"})
+	for _, event := range []Event{first, second} {
+		if err := publisher.Publish(ctx, event); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	// Handlers must still run after the publishing request is over
+	cancel()
+	publisher.Close()
+
+	if len(created) != 1 || created[0].ID != first.ID {
+		t.Errorf("expected only the created event for the typed handler, got %v", created)
+	}
+	if len(all) != 2 || all[0].ID != first.ID || all[1].ID != second.ID {
+		t.Errorf("expected both events in publish order for the catch-all handler, got %v", all)
+	}
+}
+
+func TestChannelPublisher_ReportsHandlerErrorsAndPanics(t *testing.T) {
+	var reported []error
+	publisher := NewChannelPublisher(10, WithHandlerErrors(func(event Event, err error) {
+		reported = append(reported, err)
+	}))
+	boom := errors.New("smtp unavailable")
+	publisher.Subscribe("", func(ctx context.Context, event Event) error { return boom })
+	publisher.Subscribe("", func(ctx context.Context, event Event) error { panic("bad handler") })
+
+	if err := publisher.Publish(context.Background(), NewEvent(TypeOrderCreated, OrderCreated{OrderID: 1})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	publisher.Close()
+
+	if len(reported) != 2 || !errors.Is(reported[0], boom) {
+		t.Errorf("expected the handler error and the panic to be reported, got %v", reported)
+	}
+}
+
+func TestChannelPublisher_FullBufferAndClose(t *testing.T) {
+	publisher := NewChannelPublisher(1)
+	release := make(chan struct{})
+	started := make(chan struct{}, 1)
+	publisher.Subscribe("", func(ctx context.Context, event Event) error {
+		started <- struct{}{}
+		<-release
+		return nil
+	})
+
+	// The first event occupies the handler, the second the buffer
+	if err := publisher.Publish(context.Background(), NewEvent(TypeOrderCreated, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	<-started
+	if err := publisher.Publish(context.Background(), NewEvent(TypeOrderCreated, nil)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := publisher.Publish(context.Background(), NewEvent(TypeOrderCreated, nil)); !errors.Is(err, ErrBufferFull) {
+		t.Errorf("expected ErrBufferFull, got %v", err)
+	}
+
+	close(release)
+	publisher.Close()
+	if err := publisher.Publish(context.Background(), NewEvent(TypeOrderCreated, nil)); !errors.Is(err, ErrPublisherClosed) {
+		t.Errorf("expected ErrPublisherClosed, got %v", err)
+	}
+}
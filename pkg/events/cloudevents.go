@@ -0,0 +1,71 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+)
+
+// cloudEventsSpecVersion is the CloudEvents specification version emitted
+const cloudEventsSpecVersion = "1.0"
+
+// cloudEventTypePrefix namespaces event types in reverse-DNS style, as CloudEvents recommends
+const cloudEventTypePrefix = "com.online-order-management."
+
+// CloudEvent is the structured-mode JSON envelope of a CloudEvents 1.0 event
+type CloudEvent struct {
+	SpecVersion     string      `json:"specversion"`
+	ID              string      `json:"id"`
+	Source          string      `json:"source"`
+	Type            string      `json:"type"`
+	Time            string      `json:"time"`
+	DataContentType string      `json:"datacontenttype"`
+	Data            interface{} `json:"data"`
+}
+
+// NewCloudEvent wraps event in a CloudEvents envelope identifying source
+func NewCloudEvent(event Event, source string) CloudEvent {
+	return CloudEvent{
+		SpecVersion:     cloudEventsSpecVersion,
+		ID:              event.ID,
+		Source:          source,
+		Type:            cloudEventTypePrefix + event.Type,
+		Time:            event.Time.UTC().Format(time.RFC3339Nano),
+		DataContentType: "application/json",
+		Data:            event.Data,
+	}
+}
+
+// CloudEventsPublisher writes each event as one line of CloudEvents JSON, separate from the
+// application logs, so a sidecar can forward the stream to an event platform
+type CloudEventsPublisher struct {
+	mu     sync.Mutex
+	writer io.Writer
+	source string
+}
+
+// NewCloudEventsPublisher creates a publisher writing to w, identifying itself as source
+func NewCloudEventsPublisher(w io.Writer, source string) *CloudEventsPublisher {
+	return &CloudEventsPublisher{
+		writer: w,
+		source: source,
+	}
+}
+
+// Publish writes the event's CloudEvents envelope followed by a newline
+func (p *CloudEventsPublisher) Publish(ctx context.Context, event Event) error {
+	line, err := json.Marshal(NewCloudEvent(event, p.source))
+	if err != nil {
+		return fmt.Errorf("failed to encode cloud event %s: %w", event.ID, err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, err := p.writer.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write cloud event %s: %w", event.ID, err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCloudEventsPublisher_EmitsRequiredAttributes(t *testing.T) {
+	var out bytes.Buffer
+	publisher := NewCloudEventsPublisher(&out, "/orders-api")
+
+	event := NewEvent(TypeOrderStatusChanged, OrderStatusChanged{OrderID: 7, OldStatus: "pending", NewStatus: "processing"})
+	if err := publisher.Publish(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := publisher.Publish(context.Background(), NewEvent(TypeOrderCreated, OrderCreated{OrderID: 8})); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per event, got %d: %q", len(lines), out.String())
+	}
+
+	var envelope map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &envelope); err != nil {
+		t.Fatalf("failed to decode envelope: %v", err)
+	}
+	for _, attr := range []string{"specversion", "id", "source", "type", "time", "data"} {
+		if _, ok := envelope[attr]; !ok {
+			t.Errorf("expected required attribute %q in %v", attr, envelope)
+		}
+	}
+	if envelope["specversion"] != "1.0" || envelope["id"] != event.ID || envelope["source"] != "/orders-api" {
+		t.Errorf("unexpected envelope attributes: %v", envelope)
+	}
+	if envelope["type"] != "com.online-order-management.order.status_changed" {
+		t.Errorf("unexpected type %v", envelope["type"])
+	}
+	if _, err := time.Parse(time.RFC3339Nano, envelope["time"].(string)); err != nil {
+		t.Errorf("expected RFC 3339 time, got %v", envelope["time"])
+	}
+
+	data, _ := envelope["data"].(map[string]interface{})
+	if data["order_id"] != float64(7) || data["old_status"] != "pending" || data["new_status"] != "processing" {
+		t.Errorf("unexpected data %v", envelope["data"])
+	}
+}
@@ -0,0 +1,65 @@
+package events
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Sink delivers an event to an external system. Unlike EventPublisher it is used by the
+// outbox dispatcher, which retries an event until Send succeeds, so sinks should be safe
+// to call more than once for the same event ID.
+type Sink interface {
+	Send(ctx context.Context, event Event) error
+}
+
+// WebhookSink POSTs each event as a structured-mode CloudEvents JSON body to a URL.
+// Any 2xx response counts as delivered.
+type WebhookSink struct {
+	url    string
+	source string
+	client *http.Client
+}
+
+// NewWebhookSink creates a sink posting to url, identifying itself as source. Requests
+// time out after timeout (10s when not positive).
+func NewWebhookSink(url, source string, timeout time.Duration) *WebhookSink {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &WebhookSink{
+		url:    url,
+		source: source,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+// Send posts the event and fails on transport errors and non-2xx responses
+func (s *WebhookSink) Send(ctx context.Context, event Event) error {
+	body, err := json.Marshal(NewCloudEvent(event, s.source))
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", event.ID, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/cloudevents+json")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post event %s: %w", event.ID, err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body) // Drain so the connection can be reused
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook rejected event %s with status %d", event.ID, resp.StatusCode)
+	}
+	return nil
+}
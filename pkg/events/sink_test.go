@@ -0,0 +1,47 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWebhookSink_PostsCloudEvent(t *testing.T) {
+	var received CloudEvent
+	var contentType string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&received); err != nil {
+			t.Errorf("failed to decode body: %v", err)
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer server.Close()
+
+	event := NewEvent(TypeOrderCreated, OrderCreated{OrderID: 5})
+	if err := NewWebhookSink(server.URL, "/orders-api", time.Second).Send(context.Background(), event); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if contentType != "application/cloudevents+json" {
+		t.Errorf("unexpected content type %q", contentType)
+	}
+	if received.ID != event.ID || received.Type != "com.online-order-management.order.created" || received.Source != "/orders-api" {
+		t.Errorf("unexpected envelope %+v", received)
+	}
+}
+
+func TestWebhookSink_FailsOnErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	err := NewWebhookSink(server.URL, "/orders-api", time.Second).Send(context.Background(), NewEvent(TypeOrderCreated, nil))
+	if err == nil {
+		t.Fatal("expected a 503 to fail delivery")
+	}
+}
@@ -0,0 +1,89 @@
+package logger
+
+import (
+	"bufio"
+	"io"
+	"sync"
+	"time"
+)
+
+// BufferedWriter batches log lines in memory and writes them to the destination when the
+// buffer fills, on every flush interval, and on Close, trading a short delay for far fewer
+// write syscalls under load
+type BufferedWriter struct {
+	mu     sync.Mutex
+	dst    io.Writer
+	buf    *bufio.Writer
+	closed bool
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// NewBufferedWriter creates a writer buffering up to size bytes and flushing every interval.
+// A non-positive interval disables the periodic flush, leaving buffer-full and Close.
+func NewBufferedWriter(dst io.Writer, size int, interval time.Duration) *BufferedWriter {
+	w := &BufferedWriter{
+		dst:  dst,
+		buf:  bufio.NewWriterSize(dst, size),
+		stop: make(chan struct{}),
+		done: make(chan struct{}),
+	}
+
+	if interval <= 0 {
+		close(w.done)
+		return w
+	}
+
+	go func() {
+		defer close(w.done)
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+				_ = w.Flush()
+			}
+		}
+	}()
+	return w
+}
+
+// Write buffers p. After Close, writes go straight to the destination so no line is lost.
+func (w *BufferedWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.closed {
+		return w.dst.Write(p)
+	}
+	return w.buf.Write(p)
+}
+
+// Flush writes all buffered lines to the destination
+func (w *BufferedWriter) Flush() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.buf.Flush()
+}
+
+// Close stops the periodic flush and writes out everything still buffered
+func (w *BufferedWriter) Close() error {
+	var err error
+	w.once.Do(func() {
+		close(w.stop)
+		<-w.done
+
+		w.mu.Lock()
+		defer w.mu.Unlock()
+		w.closed = true
+		err = w.buf.Flush()
+	})
+	return err
+}
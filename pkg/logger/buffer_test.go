@@ -0,0 +1,85 @@
+package logger
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// syncBuffer is a bytes.Buffer safe to read while the flush goroutine writes to it
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) lines() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return strings.Split(strings.TrimSuffix(b.buf.String(), "\n"), "\n")
+}
+
+func (b *syncBuffer) empty() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Len() == 0
+}
+
+func TestBufferedWriter_FlushesOnInterval(t *testing.T) {
+	dst := &syncBuffer{}
+	w := NewBufferedWriter(dst, 64*1024, 10*time.Millisecond)
+	defer w.Close()
+
+	fmt.Fprintln(w, `{"message":"first"}`)
+
+	deadline := time.Now().Add(time.Second)
+	for dst.empty() {
+		if time.Now().After(deadline) {
+			t.Fatal("expected the buffered line to be flushed on the interval")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+	if lines := dst.lines(); len(lines) != 1 || lines[0] != `{"message":"first"}` {
+		t.Errorf("unexpected flushed output: %q", lines)
+	}
+}
+
+func TestBufferedWriter_CloseFlushesEverything(t *testing.T) {
+	const writers, perWriter = 8, 250
+
+	dst := &syncBuffer{}
+	w := NewBufferedWriter(dst, 4096, 0) // No interval: only buffer-full and Close flush
+
+	var wg sync.WaitGroup
+	for i := 0; i < writers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < perWriter; j++ {
+				fmt.Fprintf(w, "{\"writer\":%d,\"n\":%d}\n", i, j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error closing: %v", err)
+	}
+	if got := len(dst.lines()); got != writers*perWriter {
+		t.Fatalf("expected %d lines after close, got %d", writers*perWriter, got)
+	}
+
+	// Lines logged after Close still reach the destination
+	fmt.Fprintln(w, `{"message":"late"}`)
+	if got := len(dst.lines()); got != writers*perWriter+1 {
+		t.Errorf("expected a write after close to pass through, got %d lines", got)
+	}
+}
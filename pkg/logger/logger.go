@@ -4,10 +4,12 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"runtime"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -39,12 +41,43 @@ func (l LogLevel) String() string {
 	}
 }
 
+// ParseLevel parses a level name such as "debug" or "WARN", ignoring case; empty gives INFO
+func ParseLevel(name string) (LogLevel, error) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "DEBUG":
+		return DEBUG, nil
+	case "", "INFO":
+		return INFO, nil
+	case "WARN", "WARNING":
+		return WARN, nil
+	case "ERROR":
+		return ERROR, nil
+	case "FATAL":
+		return FATAL, nil
+	default:
+		return INFO, fmt.Errorf("unknown log level %q: must be one of debug, info, warn, error, fatal", name)
+	}
+}
+
 // Logger represents a structured logger
 type Logger struct {
 	level      LogLevel
 	service    string
 	version    string
 	withFields map[string]interface{}
+	output     io.Writer // nil writes to the package output, see SetOutput
+	sampler    *sampler  // nil emits every entry, see WithSampling
+}
+
+// Option configures a Logger created by New
+type Option func(*Logger)
+
+// WithOutput sends the logger's lines, and those of every logger derived from it, to w
+// instead of the package output
+func WithOutput(w io.Writer) Option {
+	return func(l *Logger) {
+		l.output = w
+	}
 }
 
 // LogEntry represents a single log entry
@@ -59,30 +92,24 @@ type LogEntry struct {
 	Error     string                 `json:"error,omitempty"`
 }
 
-// New creates a new logger instance
-func New(service, version string) *Logger {
+// New creates a new logger instance writing to the package output (os.Stdout by default)
+func New(service, version string, opts ...Option) *Logger {
 	level := INFO
-	if levelStr := os.Getenv("LOG_LEVEL"); levelStr != "" {
-		switch strings.ToUpper(levelStr) {
-		case "DEBUG":
-			level = DEBUG
-		case "INFO":
-			level = INFO
-		case "WARN", "WARNING":
-			level = WARN
-		case "ERROR":
-			level = ERROR
-		case "FATAL":
-			level = FATAL
-		}
+	if parsed, err := ParseLevel(os.Getenv("LOG_LEVEL")); err == nil {
+		level = parsed
 	}
 
-	return &Logger{
+	l := &Logger{
 		level:      level,
 		service:    service,
 		version:    version,
 		withFields: make(map[string]interface{}),
+		sampler:    samplingFromEnv(os.Getenv("LOG_SAMPLING")),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	return l
 }
 
 // WithFields returns a new logger with additional fields
@@ -92,6 +119,8 @@ func (l *Logger) WithFields(fields map[string]interface{}) *Logger {
 		service:    l.service,
 		version:    l.version,
 		withFields: make(map[string]interface{}),
+		output:     l.output,
+		sampler:    l.sampler,
 	}
 
 	// Copy existing fields
@@ -120,13 +149,131 @@ func (l *Logger) WithError(err error) *Logger {
 	return l.WithField("error", err.Error())
 }
 
-// WithContext returns a new logger with context information
+// contextFieldsKey is the context key of the fields added by ContextWithFields
+type contextFieldsKey struct{}
+
+// ContextWithFields returns a copy of ctx carrying fields that WithContext adds to every
+// entry, on top of any the parent already carries. Cancellation and deadlines are unchanged.
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	merged := make(map[string]interface{}, len(fields))
+	if parent, ok := ctx.Value(contextFieldsKey{}).(map[string]interface{}); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// ctxKey is the type of the request-scoped values WithContext logs; being unexported, it
+// cannot collide with keys set by other packages.
+type ctxKey string
+
+const (
+	// TraceIDKey is the context key of the request's trace ID
+	TraceIDKey ctxKey = "trace_id"
+	// UserIDKey is the context key of the authenticated user's ID
+	UserIDKey ctxKey = "user_id"
+)
+
+// ContextWithTraceID returns a copy of ctx whose loggers (via WithContext) log traceID.
+// An empty traceID returns ctx unchanged.
+func ContextWithTraceID(ctx context.Context, traceID string) context.Context {
+	if traceID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, TraceIDKey, traceID)
+}
+
+// ContextWithUserID returns a copy of ctx whose loggers (via WithContext) log userID.
+// An empty userID returns ctx unchanged.
+func ContextWithUserID(ctx context.Context, userID string) context.Context {
+	if userID == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, UserIDKey, userID)
+}
+
+// TraceIDFromContext returns the trace ID stored by ContextWithTraceID, or ""
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	traceID, _ := ctx.Value(TraceIDKey).(string)
+	return traceID
+}
+
+// UserIDFromContext returns the user ID stored by ContextWithUserID, or ""
+func UserIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	userID, _ := ctx.Value(UserIDKey).(string)
+	return userID
+}
+
+// WithContext returns a new logger with the trace ID, user ID and fields carried by ctx.
+// It returns l itself when ctx is nil or carries none of them.
 func (l *Logger) WithContext(ctx context.Context) *Logger {
-	// Extract trace ID or other context values if available
-	if traceID := ctx.Value("trace_id"); traceID != nil {
-		return l.WithField("trace_id", traceID)
+	if ctx == nil {
+		return l
 	}
-	return l
+	fields, _ := ctx.Value(contextFieldsKey{}).(map[string]interface{})
+	traceID := TraceIDFromContext(ctx)
+	userID := UserIDFromContext(ctx)
+	if traceID == "" && userID == "" && len(fields) == 0 {
+		return l
+	}
+
+	merged := make(map[string]interface{}, len(fields)+2)
+	if traceID != "" {
+		merged["trace_id"] = traceID
+	}
+	if userID != "" {
+		merged["user_id"] = userID
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	return l.WithFields(merged)
+}
+
+// output receives the lines of loggers without their own writer; nil means os.Stdout
+var (
+	outputMu sync.RWMutex
+	output   io.Writer
+)
+
+// SetOutput sends the lines of loggers created without WithOutput to w, e.g. a
+// BufferedWriter. Passing nil restores the os.Stdout default.
+func SetOutput(w io.Writer) {
+	outputMu.Lock()
+	defer outputMu.Unlock()
+	output = w
+}
+
+// Flush writes out buffered log lines if the current output buffers them
+func Flush() {
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	if f, ok := output.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+}
+
+// writer returns where the logger's lines go: its own output, else the package output
+func (l *Logger) writer() io.Writer {
+	if l.output != nil {
+		return l.output
+	}
+	outputMu.RLock()
+	defer outputMu.RUnlock()
+	if output != nil {
+		return output
+	}
+	return os.Stdout
 }
 
 // getCaller returns the file and line number of the caller
@@ -145,7 +292,8 @@ func getCaller(skip int) string {
 
 // log outputs a log entry at the specified level
 func (l *Logger) log(level LogLevel, msg string, err error) {
-	if level < l.level {
+	// Dropped entries skip the caller lookup and JSON encoding below
+	if level < l.level || l.sampledOut(level) {
 		return
 	}
 
@@ -170,10 +318,14 @@ func (l *Logger) log(level LogLevel, msg string, err error) {
 		return
 	}
 
-	log.Println(string(jsonBytes))
+	_, _ = l.writer().Write(append(jsonBytes, '\n'))
 
-	// Exit for fatal logs
+	// Exit for fatal logs, without losing buffered lines
 	if level == FATAL {
+		Flush()
+		if f, ok := l.output.(interface{ Flush() error }); ok {
+			_ = f.Flush()
+		}
 		os.Exit(1)
 	}
 }
@@ -0,0 +1,98 @@
+package logger
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func decodeEntries(t *testing.T, out *bytes.Buffer) []LogEntry {
+	t.Helper()
+	var entries []LogEntry
+	for _, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var entry LogEntry
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("failed to decode log line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestLogger_WithOutput(t *testing.T) {
+	var out bytes.Buffer
+	log := New("orders", "1.2.3", WithOutput(&out))
+
+	log.WithFields(map[string]interface{}{"order_id": 42}).WithError(errors.New("boom")).Warn("Order failed")
+
+	entries := decodeEntries(t, &out)
+	if len(entries) != 1 {
+		t.Fatalf("expected one line, got %d", len(entries))
+	}
+	entry := entries[0]
+	if entry.Level != "WARN" || entry.Message != "Order failed" || entry.Service != "orders" || entry.Version != "1.2.3" {
+		t.Errorf("unexpected entry: %+v", entry)
+	}
+	if entry.Fields["order_id"] != float64(42) || entry.Fields["error"] != "boom" {
+		t.Errorf("expected the derived fields to be logged, got %v", entry.Fields)
+	}
+}
+
+func TestLogger_DerivedLoggersInheritOutput(t *testing.T) {
+	var own, shared bytes.Buffer
+	SetOutput(&shared)
+	defer SetOutput(nil)
+
+	log := New("orders", "1.0.0", WithOutput(&own))
+	log.WithField("step", 1).Info("first")
+	log.WithError(errors.New("boom")).Error("second")
+	log.WithFields(map[string]interface{}{"step": 3}).WithField("extra", true).Info("third")
+
+	if got := len(decodeEntries(t, &own)); got != 3 {
+		t.Errorf("expected every derived logger to write to the logger's own output, got %d lines", got)
+	}
+	if shared.Len() != 0 {
+		t.Errorf("expected nothing on the package output, got %q", shared.String())
+	}
+
+	New("other", "1.0.0").Info("shared")
+	if entries := decodeEntries(t, &shared); len(entries) != 1 || entries[0].Message != "shared" {
+		t.Errorf("expected a logger without its own output to use the package output, got %q", shared.String())
+	}
+}
+
+func TestLogger_WithContextLogsTraceAndUserID(t *testing.T) {
+	var out bytes.Buffer
+	log := New("orders", "1.0.0", WithOutput(&out))
+
+	ctx := ContextWithUserID(ContextWithTraceID(context.Background(), "trace-123"), "user-42")
+	ctx = ContextWithFields(ctx, map[string]interface{}{"bulk_index": 2})
+	log.WithContext(ctx).Info("handled")
+
+	entries := decodeEntries(t, &out)
+	if len(entries) != 1 {
+		t.Fatalf("expected one line, got %d", len(entries))
+	}
+	fields := entries[0].Fields
+	if fields["trace_id"] != "trace-123" || fields["user_id"] != "user-42" || fields["bulk_index"] != float64(2) {
+		t.Errorf("expected trace_id, user_id and bulk_index from the context, got %v", fields)
+	}
+}
+
+func TestLogger_WithContextWithoutValuesIsANoOp(t *testing.T) {
+	log := New("orders", "1.0.0")
+
+	if got := log.WithContext(context.Background()); got != log {
+		t.Error("expected a context without values to return the logger itself")
+	}
+	if got := log.WithContext(nil); got != log {
+		t.Error("expected a nil context to return the logger itself")
+	}
+	// A bare string key is not one of ours and must be ignored
+	if got := log.WithContext(context.WithValue(context.Background(), "trace_id", "x")); got != log {
+		t.Error("expected an untyped trace_id key to be ignored")
+	}
+}
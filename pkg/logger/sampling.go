@@ -0,0 +1,49 @@
+package logger
+
+import (
+	"strconv"
+	"sync/atomic"
+)
+
+// sampler lets through one of every n entries it is asked about. It is shared by a logger
+// and every logger derived from it, so the count spans all of them.
+type sampler struct {
+	n     uint64
+	count atomic.Uint64
+}
+
+// allow reports whether the current entry is emitted: the 1st, (n+1)th, (2n+1)th, ...
+func (s *sampler) allow() bool {
+	return (s.count.Add(1)-1)%s.n == 0
+}
+
+// WithSampling emits only every nth DEBUG and INFO entry, dropping the rest before they are
+// serialized, to keep high-volume paths from flooding the output. WARN and above are always
+// emitted. Loggers derived from this one share the count. n <= 1 disables sampling; New
+// applies the LOG_SAMPLING environment variable the same way unless this option is given.
+func WithSampling(n int) Option {
+	return func(l *Logger) {
+		l.sampler = newSampler(n)
+	}
+}
+
+func newSampler(n int) *sampler {
+	if n <= 1 {
+		return nil
+	}
+	return &sampler{n: uint64(n)}
+}
+
+// samplingFromEnv parses a LOG_SAMPLING value; invalid values disable sampling
+func samplingFromEnv(value string) *sampler {
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return nil
+	}
+	return newSampler(n)
+}
+
+// sampledOut reports whether an entry at level is dropped by the logger's sampler
+func (l *Logger) sampledOut(level LogLevel) bool {
+	return l.sampler != nil && level < WARN && !l.sampler.allow()
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestWithSampling_EmitsEveryNthDebugLine(t *testing.T) {
+	t.Setenv("LOG_LEVEL", "DEBUG")
+	var out syncBuffer
+	log := New("orders", "1.0.0", WithOutput(&out), WithSampling(10))
+
+	var wg sync.WaitGroup
+	for g := 0; g < 10; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				// Derived loggers share the sampler, as in the repository's per-call WithField
+				log.WithField("i", i).Debug("scanned row")
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := len(out.lines()); got < 95 || got > 105 {
+		t.Errorf("expected about 100 of 1000 lines, got %d", got)
+	}
+}
+
+func TestWithSampling_AlwaysKeepsWarnings(t *testing.T) {
+	var out bytes.Buffer
+	log := New("orders", "1.0.0", WithOutput(&out), WithSampling(10))
+
+	for i := 0; i < 20; i++ {
+		log.Warn("slow query")
+		log.Error("query failed")
+	}
+	if got := strings.Count(out.String(), "\n"); got != 40 {
+		t.Errorf("expected every WARN and ERROR line, got %d", got)
+	}
+}
+
+func TestWithSampling_DisabledForSmallN(t *testing.T) {
+	var out bytes.Buffer
+	log := New("orders", "1.0.0", WithOutput(&out), WithSampling(1))
+
+	for i := 0; i < 5; i++ {
+		log.Info("created order")
+	}
+	if got := strings.Count(out.String(), "\n"); got != 5 {
+		t.Errorf("expected all 5 lines without sampling, got %d", got)
+	}
+}
@@ -0,0 +1,62 @@
+package querybudget
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+)
+
+type budgetKey struct{}
+
+// Budget counts the database queries issued while serving one request
+type Budget struct {
+	count  int64
+	limit  int
+	strict bool
+}
+
+// Count returns the number of queries recorded so far
+func (b *Budget) Count() int {
+	if b == nil {
+		return 0
+	}
+	return int(atomic.LoadInt64(&b.count))
+}
+
+// Limit returns the number of queries allowed before the budget is exceeded (0 = unlimited)
+func (b *Budget) Limit() int {
+	if b == nil {
+		return 0
+	}
+	return b.limit
+}
+
+// Exceeded reports whether more queries were recorded than the limit allows
+func (b *Budget) Exceeded() bool {
+	return b != nil && b.limit > 0 && b.Count() > b.limit
+}
+
+// WithBudget returns a context carrying a fresh Budget. In strict mode, recording a query
+// over the limit panics so that tests fail at the offending call site.
+func WithBudget(ctx context.Context, limit int, strict bool) (context.Context, *Budget) {
+	budget := &Budget{limit: limit, strict: strict}
+	return context.WithValue(ctx, budgetKey{}, budget), budget
+}
+
+// FromContext returns the context's Budget, or nil when queries are not being counted
+func FromContext(ctx context.Context) *Budget {
+	budget, _ := ctx.Value(budgetKey{}).(*Budget)
+	return budget
+}
+
+// Record counts one query against the context's Budget, if any
+func Record(ctx context.Context) {
+	budget := FromContext(ctx)
+	if budget == nil {
+		return
+	}
+	count := atomic.AddInt64(&budget.count, 1)
+	if budget.strict && budget.limit > 0 && count > int64(budget.limit) {
+		panic(fmt.Sprintf("query budget exceeded: %d queries issued, limit is %d", count, budget.limit))
+	}
+}
@@ -0,0 +1,45 @@
+package querybudget
+
+import (
+	"context"
+	"testing"
+)
+
+func TestBudget_CountsAndDetectsExceeded(t *testing.T) {
+	ctx, budget := WithBudget(context.Background(), 3, false)
+
+	for i := 0; i < 3; i++ {
+		Record(ctx)
+	}
+	if budget.Exceeded() {
+		t.Fatal("expected budget not to be exceeded at the limit")
+	}
+
+	Record(ctx)
+	if !budget.Exceeded() {
+		t.Fatal("expected budget to be exceeded over the limit")
+	}
+	if budget.Count() != 4 {
+		t.Errorf("expected 4 queries, got %d", budget.Count())
+	}
+}
+
+func TestBudget_StrictModePanics(t *testing.T) {
+	ctx, _ := WithBudget(context.Background(), 1, true)
+	Record(ctx)
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected strict budget to panic over the limit")
+		}
+	}()
+	Record(ctx)
+}
+
+func TestRecord_WithoutBudget(t *testing.T) {
+	// Must be a no-op when the context carries no budget
+	Record(context.Background())
+	if FromContext(context.Background()).Exceeded() {
+		t.Error("expected a missing budget never to be exceeded")
+	}
+}
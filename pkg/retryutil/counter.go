@@ -0,0 +1,34 @@
+package retryutil
+
+import (
+	"context"
+	"sync/atomic"
+)
+
+type retryCounterKey struct{}
+
+// RetryCounter aggregates the retries made by every operation sharing a request context
+type RetryCounter struct {
+	count int64
+}
+
+// Count returns the number of retries recorded so far
+func (c *RetryCounter) Count() int {
+	if c == nil {
+		return 0
+	}
+	return int(atomic.LoadInt64(&c.count))
+}
+
+// WithRetryCounter returns a context carrying a fresh RetryCounter
+func WithRetryCounter(ctx context.Context) (context.Context, *RetryCounter) {
+	counter := &RetryCounter{}
+	return context.WithValue(ctx, retryCounterKey{}, counter), counter
+}
+
+// RecordRetry increments the context's RetryCounter, if any
+func RecordRetry(ctx context.Context) {
+	if counter, ok := ctx.Value(retryCounterKey{}).(*RetryCounter); ok {
+		atomic.AddInt64(&counter.count, 1)
+	}
+}
@@ -2,9 +2,16 @@ package retryutil
 
 import (
 	"context"
+	"database/sql/driver"
+	"errors"
 	"fmt"
+	"math"
+	"math/rand"
 	"strings"
+	"sync"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 // ConnectionError represents database connection related errors
@@ -20,26 +27,66 @@ func (e ConnectionError) Unwrap() error {
 	return e.Err
 }
 
-// IsConnectionError checks if the error is related to database connection limits
+// IsConnectionError checks if the error is related to database connection limits or to a
+// pooled connection that is no longer usable
 func IsConnectionError(err error) bool {
 	if err == nil {
 		return false
 	}
+	if errors.Is(err, driver.ErrBadConn) {
+		return true
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Class 08 is connection exceptions; 53300 is too_many_connections
+		if pqErr.Code.Class() == "08" || pqErr.Code == "53300" {
+			return true
+		}
+	}
 	errStr := err.Error()
 	return strings.Contains(errStr, "too many clients already") ||
 		strings.Contains(errStr, "connection refused") ||
 		strings.Contains(errStr, "connection reset") ||
+		strings.Contains(errStr, "broken pipe") ||
 		strings.Contains(errStr, "server closed the connection unexpectedly") ||
 		strings.Contains(errStr, "no connection to the server")
 }
 
+// IsSerializationError checks if the error is a serialization failure (40001) or a
+// deadlock (40P01): the transaction was rolled back and can safely be run again
+func IsSerializationError(err error) bool {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return false
+	}
+	return pqErr.Code == "40001" || pqErr.Code == "40P01"
+}
+
+// CombineRetryConditions returns a condition that holds when any of conditions does
+func CombineRetryConditions(conditions ...func(error) bool) func(error) bool {
+	return func(err error) bool {
+		for _, condition := range conditions {
+			if condition != nil && condition(err) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
 // RetryConfig contains configuration for retry logic
 type RetryConfig struct {
-	MaxRetries     int
-	BaseDelay      time.Duration
-	MaxDelay       time.Duration
-	BackoffFactor  float64
+	MaxRetries    int
+	BaseDelay     time.Duration
+	MaxDelay      time.Duration
+	BackoffFactor float64
+	// Jitter, when true, waits a random duration between 0 and the computed backoff so
+	// that callers failing together do not retry in lockstep
+	Jitter         bool
 	RetryCondition func(error) bool
+	// OnRetry, if set, is called before each retry's backoff sleep with the attempt number
+	// (starting at 1) and the error that caused it, e.g. to count or log retries
+	OnRetry func(attempt int, err error)
 }
 
 // DefaultRetryConfig returns default retry configuration for database operations
@@ -49,24 +96,28 @@ func DefaultRetryConfig() RetryConfig {
 		BaseDelay:      10 * time.Millisecond,
 		MaxDelay:       500 * time.Millisecond,
 		BackoffFactor:  2.0,
-		RetryCondition: IsConnectionError,
+		Jitter:         true,
+		RetryCondition: CombineRetryConditions(IsConnectionError, IsSerializationError),
 	}
 }
 
-// RetryWithBackoff executes a function with exponential backoff retry logic
+// RetryWithBackoff executes a function with exponential backoff retry logic. Errors caused by
+// the cancellation or deadline of ctx are returned without retrying.
 func RetryWithBackoff(ctx context.Context, config RetryConfig, fn func() error) error {
 	var lastErr error
 
-	for attempt := 0; attempt < config.MaxRetries; attempt++ {
+	attempts := config.MaxRetries
+	if attempts < 1 {
+		attempts = 1
+	}
+	for attempt := 0; attempt < attempts; attempt++ {
 		if attempt > 0 {
-			// Exponential backoff with configurable factor
-			backoff := time.Duration(float64(config.BaseDelay) *
-				(config.BackoffFactor * float64(attempt)))
-
-			if backoff > config.MaxDelay {
-				backoff = config.MaxDelay
+			if config.OnRetry != nil {
+				config.OnRetry(attempt, lastErr)
 			}
 
+			backoff := backoffDelay(config, attempt)
+
 			select {
 			case <-ctx.Done():
 				return fmt.Errorf("retry cancelled: %w", ctx.Err())
@@ -81,11 +132,45 @@ func RetryWithBackoff(ctx context.Context, config RetryConfig, fn func() error)
 
 		lastErr = err
 
+		// A cancelled or timed-out caller gets no retries, even when the driver reports the
+		// interrupted query as a broken connection
+		if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("retry cancelled: %w", err)
+		}
+
 		// Check retry condition
 		if config.RetryCondition != nil && !config.RetryCondition(err) {
 			return fmt.Errorf("retry condition not met: %w", err)
 		}
 	}
 
-	return fmt.Errorf("max retries (%d) exceeded: %w", config.MaxRetries, lastErr)
+	return fmt.Errorf("max retries (%d) exceeded: %w", attempts, lastErr)
+}
+
+// jitterRand is the random source of backoff jitter; rand.Rand is not safe for concurrent use
+var (
+	jitterMu   sync.Mutex
+	jitterRand = rand.New(rand.NewSource(time.Now().UnixNano()))
+)
+
+// backoffDelay returns how long to wait before the given retry attempt (starting at 1)
+func backoffDelay(config RetryConfig, attempt int) time.Duration {
+	// Exponential backoff: BaseDelay, BaseDelay*factor, BaseDelay*factor^2, ...; computed
+	// in float64 so large attempts saturate at MaxDelay instead of overflowing
+	backoff := float64(config.BaseDelay) * math.Pow(config.BackoffFactor, float64(attempt-1))
+	if backoff > float64(config.MaxDelay) {
+		backoff = float64(config.MaxDelay)
+	}
+	return applyJitter(config, time.Duration(backoff))
+}
+
+// applyJitter returns a random duration between 0 and backoff when config.Jitter is set
+func applyJitter(config RetryConfig, backoff time.Duration) time.Duration {
+	if !config.Jitter || backoff <= 0 {
+		return backoff
+	}
+
+	jitterMu.Lock()
+	defer jitterMu.Unlock()
+	return time.Duration(jitterRand.Int63n(int64(backoff) + 1))
 }
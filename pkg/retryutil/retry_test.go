@@ -0,0 +1,262 @@
+package retryutil
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+func TestBackoffDelay_JitterIsDistributedAndBounded(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.BaseDelay = 100 * time.Millisecond
+	config.MaxDelay = 150 * time.Millisecond
+
+	seen := make(map[time.Duration]bool)
+	for i := 0; i < 1000; i++ {
+		for attempt := 1; attempt <= 5; attempt++ {
+			delay := backoffDelay(config, attempt)
+			if delay < 0 || delay > config.MaxDelay {
+				t.Fatalf("attempt %d: delay %v outside [0, %v]", attempt, delay, config.MaxDelay)
+			}
+			seen[delay] = true
+		}
+	}
+	if len(seen) < 100 {
+		t.Errorf("expected jittered delays to be distributed, got only %d distinct values", len(seen))
+	}
+}
+
+func TestBackoffDelay_WithoutJitterIsDeterministic(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.Jitter = false
+
+	first := backoffDelay(config, 2)
+	for i := 0; i < 100; i++ {
+		if got := backoffDelay(config, 2); got != first {
+			t.Fatalf("expected identical delays without jitter, got %v and %v", first, got)
+		}
+	}
+}
+
+func TestRetryWithBackoff_JitteredRetriesSucceed(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.BaseDelay = time.Millisecond
+	config.MaxDelay = 5 * time.Millisecond
+
+	calls := 0
+	err := RetryWithBackoff(context.Background(), config, func() error {
+		calls++
+		if calls < config.MaxRetries {
+			return ConnectionError{Err: errors.New("too many clients already")}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected the last attempt to succeed, got %v", err)
+	}
+	if calls != config.MaxRetries {
+		t.Errorf("expected %d calls, got %d", config.MaxRetries, calls)
+	}
+}
+
+func TestBackoffDelay_GrowsExponentially(t *testing.T) {
+	config := RetryConfig{
+		BaseDelay:     10 * time.Millisecond,
+		MaxDelay:      500 * time.Millisecond,
+		BackoffFactor: 2.0,
+	}
+
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{attempt: 1, want: 10 * time.Millisecond},
+		{attempt: 2, want: 20 * time.Millisecond},
+		{attempt: 3, want: 40 * time.Millisecond},
+		{attempt: 4, want: 80 * time.Millisecond},
+		{attempt: 5, want: 160 * time.Millisecond},
+		{attempt: 6, want: 320 * time.Millisecond},
+		{attempt: 7, want: 500 * time.Millisecond},
+		{attempt: 100, want: 500 * time.Millisecond},
+	}
+	for _, tt := range tests {
+		if got := backoffDelay(config, tt.attempt); got != tt.want {
+			t.Errorf("attempt %d: expected %v, got %v", tt.attempt, tt.want, got)
+		}
+	}
+}
+
+func TestRetryWithBackoff_MaxRetriesIsTotalAttempts(t *testing.T) {
+	tests := []struct {
+		maxRetries int
+		wantCalls  int
+	}{
+		{maxRetries: 0, wantCalls: 1},
+		{maxRetries: 1, wantCalls: 1},
+		{maxRetries: 3, wantCalls: 3},
+	}
+	for _, tt := range tests {
+		config := DefaultRetryConfig()
+		config.MaxRetries = tt.maxRetries
+		config.BaseDelay = time.Millisecond
+		config.MaxDelay = time.Millisecond
+
+		calls := 0
+		err := RetryWithBackoff(context.Background(), config, func() error {
+			calls++
+			return ConnectionError{Err: errors.New("connection refused")}
+		})
+		if err == nil {
+			t.Errorf("MaxRetries=%d: expected an error", tt.maxRetries)
+		}
+		if calls != tt.wantCalls {
+			t.Errorf("MaxRetries=%d: expected %d calls, got %d", tt.maxRetries, tt.wantCalls, calls)
+		}
+	}
+}
+
+func TestRetryConditions_RecognizePostgresErrors(t *testing.T) {
+	condition := DefaultRetryConfig().RetryCondition
+
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "deadlock", err: &pq.Error{Code: "40P01"}, want: true},
+		{name: "serialization failure", err: &pq.Error{Code: "40001"}, want: true},
+		{name: "wrapped deadlock", err: fmt.Errorf("insert order: %w", &pq.Error{Code: "40P01"}), want: true},
+		{name: "too many connections", err: &pq.Error{Code: "53300"}, want: true},
+		{name: "connection failure", err: &pq.Error{Code: "08006"}, want: true},
+		{name: "connection refused", err: errors.New("dial tcp: connection refused"), want: true},
+		{name: "unique violation", err: &pq.Error{Code: "23505"}, want: false},
+		{name: "plain error", err: errors.New("boom"), want: false},
+		{name: "nil", err: nil, want: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := condition(tt.err); got != tt.want {
+				t.Errorf("expected %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCombineRetryConditions(t *testing.T) {
+	isA := func(err error) bool { return err != nil && err.Error() == "a" }
+	isB := func(err error) bool { return err != nil && err.Error() == "b" }
+	condition := CombineRetryConditions(isA, nil, isB)
+
+	if !condition(errors.New("a")) || !condition(errors.New("b")) {
+		t.Error("expected either condition to match")
+	}
+	if condition(errors.New("c")) {
+		t.Error("expected no condition to match")
+	}
+	if CombineRetryConditions()(errors.New("a")) {
+		t.Error("expected an empty combination to match nothing")
+	}
+}
+
+func TestRetryWithBackoff_OnRetryFiresOncePerRetry(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.MaxRetries = 4
+	config.BaseDelay = time.Millisecond
+
+	failures := []error{
+		ConnectionError{Err: errors.New("connection refused")},
+		&pq.Error{Code: "40P01"},
+	}
+	var attempts []int
+	var received []error
+	config.OnRetry = func(attempt int, err error) {
+		attempts = append(attempts, attempt)
+		received = append(received, err)
+	}
+
+	calls := 0
+	err := RetryWithBackoff(context.Background(), config, func() error {
+		calls++
+		if calls <= len(failures) {
+			return failures[calls-1]
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("expected success after the failures, got %v", err)
+	}
+	if len(attempts) != len(failures) || attempts[0] != 1 || attempts[1] != 2 {
+		t.Fatalf("expected OnRetry for attempts [1 2], got %v", attempts)
+	}
+	for i, want := range failures {
+		if received[i] != want {
+			t.Errorf("retry %d: expected error %v, got %v", i+1, want, received[i])
+		}
+	}
+}
+
+func TestRetryWithBackoff_NilOnRetryAndNoRetryAfterLastAttempt(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.MaxRetries = 2
+	config.BaseDelay = time.Millisecond
+
+	fired := 0
+	config.OnRetry = func(int, error) { fired++ }
+	_ = RetryWithBackoff(context.Background(), config, func() error {
+		return ConnectionError{Err: errors.New("connection refused")}
+	})
+	if fired != 1 {
+		t.Errorf("expected OnRetry only between attempts, fired %d times", fired)
+	}
+
+	config.OnRetry = nil
+	if err := RetryWithBackoff(context.Background(), config, func() error { return nil }); err != nil {
+		t.Errorf("expected a nil OnRetry to be ignored, got %v", err)
+	}
+}
+
+func TestRetryWithBackoff_CancelledContextIsNotRetried(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.BaseDelay = time.Millisecond
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	calls := 0
+	err := RetryWithBackoff(ctx, config, func() error {
+		calls++
+		// database/sql may report a query interrupted by cancellation as a bad connection
+		return driver.ErrBadConn
+	})
+	if calls != 1 {
+		t.Errorf("expected a cancelled call to run once, ran %d times", calls)
+	}
+	if !errors.Is(err, driver.ErrBadConn) {
+		t.Errorf("expected the attempt's error, got %v", err)
+	}
+}
+
+func TestRetryWithBackoff_ContextErrorsAreNotRetried(t *testing.T) {
+	config := DefaultRetryConfig()
+	config.BaseDelay = time.Millisecond
+	config.RetryCondition = func(error) bool { return true }
+
+	for _, ctxErr := range []error{context.Canceled, context.DeadlineExceeded} {
+		calls := 0
+		err := RetryWithBackoff(context.Background(), config, func() error {
+			calls++
+			return fmt.Errorf("query failed: %w", ctxErr)
+		})
+		if calls != 1 {
+			t.Errorf("%v: expected one attempt, got %d", ctxErr, calls)
+		}
+		if !errors.Is(err, ctxErr) {
+			t.Errorf("expected %v, got %v", ctxErr, err)
+		}
+	}
+}
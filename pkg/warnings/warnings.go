@@ -0,0 +1,47 @@
+package warnings
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type collectorKey struct{}
+
+// Collector gathers the non-fatal adjustments made while serving one request,
+// such as a capped page size, so they can be reported back to the client
+type Collector struct {
+	mu       sync.Mutex
+	messages []string
+}
+
+// List returns the collected warnings in the order they were added, or nil when there are none
+func (c *Collector) List() []string {
+	if c == nil {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.messages) == 0 {
+		return nil
+	}
+	list := make([]string, len(c.messages))
+	copy(list, c.messages)
+	return list
+}
+
+// WithCollector returns a context carrying a fresh Collector
+func WithCollector(ctx context.Context) (context.Context, *Collector) {
+	collector := &Collector{}
+	return context.WithValue(ctx, collectorKey{}, collector), collector
+}
+
+// Add records a warning on the context's Collector, if any
+func Add(ctx context.Context, format string, args ...interface{}) {
+	if collector, ok := ctx.Value(collectorKey{}).(*Collector); ok {
+		collector.mu.Lock()
+		collector.messages = append(collector.messages, fmt.Sprintf(format, args...))
+		collector.mu.Unlock()
+	}
+}
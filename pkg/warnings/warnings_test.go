@@ -0,0 +1,21 @@
+package warnings
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCollector(t *testing.T) {
+	ctx, collected := WithCollector(context.Background())
+	if collected.List() != nil {
+		t.Fatal("expected no warnings before any were added")
+	}
+
+	Add(ctx, "limit %d capped", 500)
+	Add(context.Background(), "dropped without a collector")
+
+	list := collected.List()
+	if len(list) != 1 || list[0] != "limit 500 capped" {
+		t.Errorf("expected one formatted warning, got %v", list)
+	}
+}
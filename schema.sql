@@ -30,4 +30,121 @@ ALTER TABLE orders ADD CONSTRAINT chk_orders_status
     CHECK (status IN ('pending', 'processing', 'completed', 'cancelled'));
 
 ALTER TABLE orders ADD CONSTRAINT chk_orders_total_amount 
-    CHECK (total_amount >= 0); 
\ No newline at end of file
+    CHECK (total_amount >= 0); 
+
+-- Create order_status_history table to record every status transition
+CREATE TABLE IF NOT EXISTS order_status_history (
+    id BIGSERIAL PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    changed_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_order_status_history_order_id ON order_status_history(order_id, changed_at);
+
+-- Add optional client-supplied reference (e.g. a B2B purchase order number)
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS client_reference VARCHAR(100);
+
+CREATE INDEX IF NOT EXISTS idx_orders_client_reference ON orders(client_reference) WHERE client_reference IS NOT NULL;
+
+-- Add soft-delete marker; deleted orders stay in the table for recovery
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS deleted_at TIMESTAMP WITH TIME ZONE;
+
+CREATE INDEX IF NOT EXISTS idx_orders_live_created_at_id ON orders(created_at DESC, id DESC) WHERE deleted_at IS NULL;
+
+-- Add optional stock keeping unit to order items
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS sku VARCHAR(64);
+
+-- Add estimated ship date computed from the lead-time model at creation
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS estimated_ship_date DATE;
+
+-- Keep orders.total_amount equal to the sum of its items' total_price
+CREATE OR REPLACE FUNCTION recompute_order_total() RETURNS TRIGGER AS $$
+BEGIN
+    IF TG_OP IN ('UPDATE', 'DELETE') THEN
+        UPDATE orders
+        SET total_amount = (SELECT COALESCE(SUM(total_price), 0) FROM order_items WHERE order_id = OLD.order_id)
+        WHERE id = OLD.order_id;
+    END IF;
+    IF TG_OP IN ('INSERT', 'UPDATE') THEN
+        UPDATE orders
+        SET total_amount = (SELECT COALESCE(SUM(total_price), 0) FROM order_items WHERE order_id = NEW.order_id)
+        WHERE id = NEW.order_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+DROP TRIGGER IF EXISTS trg_order_items_total ON order_items;
+CREATE TRIGGER trg_order_items_total
+    AFTER INSERT OR UPDATE OF order_id, total_price OR DELETE ON order_items
+    FOR EACH ROW EXECUTE FUNCTION recompute_order_total();
+
+-- Add human-readable order numbers allocated gaplessly per year
+CREATE TABLE IF NOT EXISTS order_number_counters (
+    year INTEGER PRIMARY KEY,
+    last_value BIGINT NOT NULL
+);
+
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS order_number VARCHAR(64);
+CREATE UNIQUE INDEX IF NOT EXISTS idx_orders_order_number ON orders(order_number);
+
+-- Allow orders to be marked paid before processing
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('pending', 'paid', 'processing', 'completed', 'cancelled'));
+
+-- Allow items sold by weight or length: fractional quantities with a unit of measure
+ALTER TABLE order_items ALTER COLUMN quantity TYPE NUMERIC(12,3);
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS unit VARCHAR(20);
+
+-- Record the customer's email; existing orders keep NULL
+ALTER TABLE orders ADD COLUMN IF NOT EXISTS customer_email VARCHAR(254);
+
+-- Line-level discount as a percentage; existing items are undiscounted
+ALTER TABLE order_items ADD COLUMN IF NOT EXISTS discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0
+    CHECK (discount_percent >= 0 AND discount_percent <= 100);
+
+-- Idempotency-Key of each keyed create and the order it produced; a key is replayed until
+-- expires_at, then the next create using it takes the row over
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    idempotency_key VARCHAR(255) PRIMARY KEY,
+    order_id BIGINT NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_order_id ON idempotency_keys(order_id);
+
+-- Events recorded in the same transaction as the write they describe, delivered to the
+-- configured sink by the outbox dispatcher; sent_at stays NULL until delivery succeeds
+CREATE TABLE IF NOT EXISTS outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_id VARCHAR(64) NOT NULL UNIQUE,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    attempts INTEGER NOT NULL DEFAULT 0,
+    last_error TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_unsent ON outbox(id) WHERE sent_at IS NULL;
+
+-- Allow orders to be marked shipped between payment or processing and completion
+ALTER TABLE orders DROP CONSTRAINT IF EXISTS chk_orders_status;
+ALTER TABLE orders ADD CONSTRAINT chk_orders_status
+    CHECK (status IN ('pending', 'paid', 'processing', 'shipped', 'completed', 'cancelled'));
+
+-- Let dispatchers claim outbox rows for a lease instead of holding row locks while they
+-- deliver, and park events that keep failing so they are no longer retried
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS claimed_until TIMESTAMP WITH TIME ZONE;
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS parked_at TIMESTAMP WITH TIME ZONE;
+
+DROP INDEX IF EXISTS idx_outbox_unsent;
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox(id) WHERE sent_at IS NULL AND parked_at IS NULL;
+
+-- Order totals are no longer maintained by a trigger; DATABASE_TOTALS sums the items in SQL
+DROP TRIGGER IF EXISTS trg_order_items_total ON order_items;
+DROP FUNCTION IF EXISTS recompute_order_total();
@@ -0,0 +1,38 @@
+package test
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCalculateStressTestResults_ClassifiesFailures(t *testing.T) {
+	metrics := []OrderMetrics{
+		{OrderID: 1, Success: true, Latency: 10 * time.Millisecond},
+		{OrderID: 2, Error: `request error: Post "http://localhost:8080/api/v1/orders": context deadline exceeded`, Latency: 30 * time.Second},
+		{OrderID: 3, Error: `request error: Post "http://localhost:8080/api/v1/orders": net/http: request canceled (Client.Timeout exceeded while awaiting headers)`, Latency: 30 * time.Second},
+		{OrderID: 4, Error: "HTTP 500", Latency: 20 * time.Millisecond},
+		{OrderID: 5, Error: "HTTP 503", Latency: 20 * time.Millisecond},
+		{OrderID: 6, Error: `request error: Post "http://localhost:8080/api/v1/orders": dial tcp 127.0.0.1:8080: connect: connection refused`, Latency: time.Millisecond},
+		{OrderID: 7, Error: "HTTP 400", Latency: 5 * time.Millisecond},
+		{OrderID: 8, Error: "marshal error: unsupported value", Latency: time.Millisecond},
+	}
+
+	result := calculateStressTestResults(metrics, time.Second, 4)
+
+	if result.SuccessfulOrders != 1 || result.FailedOrders != 7 {
+		t.Fatalf("expected 1 success and 7 failures, got %d and %d", result.SuccessfulOrders, result.FailedOrders)
+	}
+
+	expected := map[FailureCategory]int64{
+		FailureTimeout:           2,
+		FailureServerError:       2,
+		FailureConnectionRefused: 1,
+		FailureUnexpectedStatus:  1,
+		FailureOther:             1,
+	}
+	for category, count := range expected {
+		if got := result.FailuresByType[category]; got != count {
+			t.Errorf("category %s: expected %d, got %d", category, count, got)
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"net/http"
 	"os"
+	"strings"
 	"sync"
 	"sync/atomic"
 	"testing"
@@ -38,9 +39,30 @@ type StressTestResult struct {
 	MaxLatency       time.Duration
 	SuccessRate      float64
 	Errors           []string
+	FailuresByType   map[FailureCategory]int64
 	PeakConcurrency  int
 }
 
+// FailureCategory classifies why an order creation failed
+type FailureCategory string
+
+const (
+	FailureTimeout           FailureCategory = "timeout"
+	FailureServerError       FailureCategory = "server_error"
+	FailureConnectionRefused FailureCategory = "connection_refused"
+	FailureUnexpectedStatus  FailureCategory = "unexpected_status"
+	FailureOther             FailureCategory = "other"
+)
+
+// failureCategories lists the categories in report order
+var failureCategories = []FailureCategory{
+	FailureTimeout,
+	FailureServerError,
+	FailureConnectionRefused,
+	FailureUnexpectedStatus,
+	FailureOther,
+}
+
 // OrderMetrics tracks individual order creation performance
 type OrderMetrics struct {
 	OrderID   int
@@ -212,10 +234,35 @@ func runStressTest(config StressTestConfig) StressTestResult {
 	return result
 }
 
+// classifyFailure maps the error text recorded in OrderMetrics to a failure category
+// so that pool exhaustion (5xx) can be told apart from client-side timeouts
+func classifyFailure(errMsg string) FailureCategory {
+	lower := strings.ToLower(errMsg)
+
+	var statusCode int
+	if _, err := fmt.Sscanf(errMsg, "HTTP %d", &statusCode); err == nil {
+		if statusCode >= 500 {
+			return FailureServerError
+		}
+		return FailureUnexpectedStatus
+	}
+
+	switch {
+	case strings.Contains(lower, "connection refused"):
+		return FailureConnectionRefused
+	case strings.Contains(lower, "deadline exceeded"),
+		strings.Contains(lower, "timeout"):
+		return FailureTimeout
+	default:
+		return FailureOther
+	}
+}
+
 func calculateStressTestResults(metrics []OrderMetrics, testDuration time.Duration, peakConcurrency int) StressTestResult {
 	result := StressTestResult{
 		TotalDuration:   testDuration,
 		MinLatency:      time.Hour, // Start with a very high value
+		FailuresByType:  make(map[FailureCategory]int64),
 		PeakConcurrency: peakConcurrency,
 	}
 
@@ -230,6 +277,7 @@ func calculateStressTestResults(metrics []OrderMetrics, testDuration time.Durati
 			result.SuccessfulOrders++
 		} else {
 			result.FailedOrders++
+			result.FailuresByType[classifyFailure(metric.Error)]++
 			if len(errors) < 20 { // Collect more errors for stress test
 				errors = append(errors, fmt.Sprintf("Order %d: %s", metric.OrderID, metric.Error))
 			}
@@ -253,6 +301,19 @@ func calculateStressTestResults(metrics []OrderMetrics, testDuration time.Durati
 	return result
 }
 
+// logFailureBreakdown reports failure counts per category
+func logFailureBreakdown(t *testing.T, result StressTestResult) {
+	if result.FailedOrders == 0 {
+		return
+	}
+	t.Logf("  Failure Breakdown:")
+	for _, category := range failureCategories {
+		if count := result.FailuresByType[category]; count > 0 {
+			t.Logf("    %s: %d", category, count)
+		}
+	}
+}
+
 // getStressTestBaseURL returns the base URL for stress testing
 // Supports both regular and isolated stress testing
 func getStressTestBaseURL() string {
@@ -300,6 +361,7 @@ func TestStressTest_1000Orders(t *testing.T) {
 	t.Logf("  Average Latency: %v", result.AverageLatency)
 	t.Logf("  Min Latency: %v", result.MinLatency)
 	t.Logf("  Max Latency: %v", result.MaxLatency)
+	logFailureBreakdown(t, result)
 
 	if len(result.Errors) > 0 {
 		t.Logf("  Sample Errors:")
@@ -365,6 +427,7 @@ func TestStressTest_10000Orders(t *testing.T) {
 	t.Logf("  Average Latency: %v", result.AverageLatency)
 	t.Logf("  Min Latency: %v", result.MinLatency)
 	t.Logf("  Max Latency: %v", result.MaxLatency)
+	logFailureBreakdown(t, result)
 
 	if len(result.Errors) > 0 {
 		t.Logf("  Sample Errors:")